@@ -5,6 +5,7 @@ package main
 
 import (
 	"flag"
+	"path/filepath"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/internal/mcp"
 	"github.com/jmrplens/portainer-mcp-enhanced/internal/tooldef"
@@ -14,6 +15,10 @@ import (
 // defaultToolsPath is the default file path for the tools YAML configuration.
 const defaultToolsPath = "tools.yaml"
 
+// defaultPromptsFile is the default file name of the prompts YAML
+// configuration, created next to the tools YAML file.
+const defaultPromptsFile = "prompts.yaml"
+
 var (
 	// Version is the version of the portainer-mcp application, set at build time.
 	Version string
@@ -33,15 +38,44 @@ func main() {
 	serverFlag := flag.String("server", "", "The Portainer server URL")
 	tokenFlag := flag.String("token", "", "The authentication token for the Portainer server")
 	toolsFlag := flag.String("tools", "", "The path to the tools YAML file")
+	promptsFlag := flag.String("prompts", "", "The path to the prompts YAML file (defaults to prompts.yaml next to the tools YAML file)")
 	readOnlyFlag := flag.Bool("read-only", false, "Run in read-only mode")
+	dryRunFlag := flag.Bool("dry-run", false, "Return a plan of the changes write tools would make instead of applying them")
+	requireConfirmationFlag := flag.Bool("require-confirmation", false, "Require destructive tools to be called twice, the second time with the confirmation token returned by the first")
+	policyFlag := flag.String("policy", "", "The path of a YAML policy file that allows or denies individual tools and actions")
+	maxInFlightFlag := flag.Int("max-in-flight", 0, "The maximum number of tool calls running at once; further calls are rejected with a retry hint (0 means unlimited)")
+	cacheTTLFlag := flag.Duration("cache-ttl", 0, "How long to cache the lists of environments, tags, groups, users, teams, roles, and registries (0 disables the cache)")
+	cacheMethodTTLFlag := flag.String("cache-method-ttl", "", "Per-method cache TTLs overriding -cache-ttl, as method=duration pairs such as GetRoles=10m,GetEnvironments=15s")
 	granularToolsFlag := flag.Bool("granular-tools", false, "Register all individual tools instead of grouped meta-tools")
 	disableVersionCheckFlag := flag.Bool("disable-version-check", false, "Disable Portainer server version check")
 	skipTLSVerifyFlag := flag.Bool("skip-tls-verify", false, "Skip TLS certificate verification (insecure, use only for self-signed certs)")
+	transportFlag := flag.String("transport", mcp.TransportStdio, "The MCP transport to serve: stdio, http (streamable HTTP), or sse")
+	addrFlag := flag.String("addr", mcp.DefaultListenAddr, "The listen address for the http and sse transports")
+	sessionAuthFlag := flag.Bool("session-auth", false, "Require each http/sse client to send its own Portainer API key or JWT and use it for tool calls")
+	auditLogFlag := flag.String("audit-log", "", "The path of a JSON Lines file recording every tool call (disabled when empty)")
+	auditLogMaxSizeFlag := flag.Int("audit-log-max-size", mcp.DefaultAuditLogMaxSizeMB, "The size in megabytes at which the audit log is rotated (0 disables rotation)")
+	auditLogMaxBackupsFlag := flag.Int("audit-log-max-backups", mcp.DefaultAuditLogMaxBackups, "The number of rotated audit log files to keep")
+	metricsAddrFlag := flag.String("metrics-addr", "", "The listen address of the Prometheus /metrics endpoint, such as :9090 (disabled when empty)")
+	watchIntervalFlag := flag.Duration("watch-interval", 0, "How often to poll Portainer and notify clients of environment, stack, and Helm release status changes (0 disables watching)")
+	watchScopeFlag := flag.String("watch-scope", mcp.DefaultWatchScope, "The kinds of objects to watch, as a comma-separated list of environments, stacks, and helm")
 
 	flag.Parse()
 
-	if *serverFlag == "" || *tokenFlag == "" {
-		log.Fatal().Msg("Both -server and -token flags are required")
+	if *serverFlag == "" {
+		log.Fatal().Msg("The -server flag is required")
+	}
+	if *tokenFlag == "" && !*sessionAuthFlag {
+		log.Fatal().Msg("The -token flag is required unless -session-auth is set")
+	}
+
+	cacheMethodTTLs, err := mcp.ParseCacheMethodTTLs(*cacheMethodTTLFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid -cache-method-ttl flag")
+	}
+
+	watchScope, err := mcp.ParseWatchScope(*watchScopeFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid -watch-scope flag")
 	}
 
 	toolsPath := *toolsFlag
@@ -62,16 +96,48 @@ func main() {
 		log.Info().Msg("created tools.yaml file")
 	}
 
+	promptsPath := *promptsFlag
+	if promptsPath == "" {
+		promptsPath = filepath.Join(filepath.Dir(toolsPath), defaultPromptsFile)
+	}
+
+	// Like the tools.yaml file, the prompts.yaml file is created from the
+	// embedded version if it doesn't exist
+	exists, err = tooldef.CreatePromptsFileIfNotExists(promptsPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create prompts.yaml file")
+	}
+
+	if exists {
+		log.Info().Msg("using existing prompts.yaml file")
+	} else {
+		log.Info().Msg("created prompts.yaml file")
+	}
+
 	log.Info().
 		Str("portainer-host", *serverFlag).
 		Str("tools-path", toolsPath).
+		Str("prompts-path", promptsPath).
 		Bool("read-only", *readOnlyFlag).
+		Bool("dry-run", *dryRunFlag).
+		Bool("require-confirmation", *requireConfirmationFlag).
+		Str("policy", *policyFlag).
+		Int("max-in-flight", *maxInFlightFlag).
+		Dur("cache-ttl", *cacheTTLFlag).
+		Str("cache-method-ttl", *cacheMethodTTLFlag).
 		Bool("granular-tools", *granularToolsFlag).
 		Bool("disable-version-check", *disableVersionCheckFlag).
 		Bool("skip-tls-verify", *skipTLSVerifyFlag).
+		Str("transport", *transportFlag).
+		Str("addr", *addrFlag).
+		Bool("session-auth", *sessionAuthFlag).
+		Str("audit-log", *auditLogFlag).
+		Str("metrics-addr", *metricsAddrFlag).
+		Dur("watch-interval", *watchIntervalFlag).
+		Str("watch-scope", *watchScopeFlag).
 		Msg("starting MCP server")
 
-	server, err := mcp.NewPortainerMCPServer(*serverFlag, *tokenFlag, toolsPath, mcp.WithReadOnly(*readOnlyFlag), mcp.WithGranularTools(*granularToolsFlag), mcp.WithDisableVersionCheck(*disableVersionCheckFlag), mcp.WithSkipTLSVerify(*skipTLSVerifyFlag))
+	server, err := mcp.NewPortainerMCPServer(*serverFlag, *tokenFlag, toolsPath, mcp.WithReadOnly(*readOnlyFlag), mcp.WithDryRun(*dryRunFlag), mcp.WithRequireConfirmation(*requireConfirmationFlag), mcp.WithPolicyFile(*policyFlag), mcp.WithMaxInFlight(*maxInFlightFlag), mcp.WithCache(*cacheTTLFlag, cacheMethodTTLs), mcp.WithGranularTools(*granularToolsFlag), mcp.WithDisableVersionCheck(*disableVersionCheckFlag), mcp.WithSkipTLSVerify(*skipTLSVerifyFlag), mcp.WithTransport(*transportFlag), mcp.WithListenAddr(*addrFlag), mcp.WithSessionAuth(*sessionAuthFlag), mcp.WithAuditLog(*auditLogFlag), mcp.WithAuditLogRotation(*auditLogMaxSizeFlag, *auditLogMaxBackupsFlag), mcp.WithMetricsAddr(*metricsAddrFlag), mcp.WithPromptsFile(promptsPath), mcp.WithWatch(*watchIntervalFlag, watchScope))
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to create server")
 	}
@@ -87,6 +153,7 @@ func main() {
 		server.AddTeamFeatures()
 		server.AddAccessGroupFeatures()
 		server.AddDockerProxyFeatures()
+		server.AddDockerContainerFeatures()
 		server.AddKubernetesProxyFeatures()
 		server.AddKubernetesNativeFeatures()
 		server.AddSystemFeatures()
@@ -104,6 +171,8 @@ func main() {
 	} else {
 		server.RegisterMetaTools()
 	}
+	server.RegisterResources()
+	server.RegisterPrompts()
 
 	err = server.Start()
 	if err != nil {
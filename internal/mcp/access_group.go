@@ -31,9 +31,9 @@ func (s *PortainerMCPServer) AddAccessGroupFeatures() {
 // HandleGetAccessGroups returns an MCP tool handler that retrieves access groups.
 func (s *PortainerMCPServer) HandleGetAccessGroups() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		accessGroups, err := s.cli.GetAccessGroups()
+		accessGroups, err := s.client(ctx).GetAccessGroups(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get access groups", err), nil
+			return toolErrorFromErr("failed to get access groups", err), nil
 		}
 
 		return jsonResult(accessGroups, "failed to marshal access groups")
@@ -47,7 +47,7 @@ func (s *PortainerMCPServer) HandleCreateAccessGroup() server.ToolHandlerFunc {
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -55,12 +55,12 @@ func (s *PortainerMCPServer) HandleCreateAccessGroup() server.ToolHandlerFunc {
 
 		environmentIds, err := parser.GetArrayOfIntegers("environmentIds", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentIds parameter", err), nil
+			return toolErrorFromErr("invalid environmentIds parameter", err), nil
 		}
 
-		groupID, err := s.cli.CreateAccessGroup(name, environmentIds)
+		groupID, err := s.client(ctx).CreateAccessGroup(ctx, name, environmentIds)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create access group", err), nil
+			return toolErrorFromErr("failed to create access group", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Access group created successfully with ID: %d", groupID)), nil
@@ -74,20 +74,20 @@ func (s *PortainerMCPServer) HandleUpdateAccessGroupName() server.ToolHandlerFun
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateAccessGroupName(id, name)
+		err = s.client(ctx).UpdateAccessGroupName(ctx, id, name)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update access group name", err), nil
+			return toolErrorFromErr("failed to update access group name", err), nil
 		}
 
 		return mcp.NewToolResultText("Access group name updated successfully"), nil
@@ -101,22 +101,22 @@ func (s *PortainerMCPServer) HandleUpdateAccessGroupUserAccesses() server.ToolHa
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		userAccesses, err := parser.GetArrayOfObjects("userAccesses", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userAccesses parameter", err), nil
+			return toolErrorFromErr("invalid userAccesses parameter", err), nil
 		}
 
 		userAccessesMap, err := parseAccessMap(userAccesses)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid user accesses", err), nil
+			return toolErrorFromErr("invalid user accesses", err), nil
 		}
 
-		err = s.cli.UpdateAccessGroupUserAccesses(id, userAccessesMap)
+		err = s.client(ctx).UpdateAccessGroupUserAccesses(ctx, id, userAccessesMap)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update access group user accesses", err), nil
+			return toolErrorFromErr("failed to update access group user accesses", err), nil
 		}
 
 		return mcp.NewToolResultText("Access group user accesses updated successfully"), nil
@@ -130,22 +130,22 @@ func (s *PortainerMCPServer) HandleUpdateAccessGroupTeamAccesses() server.ToolHa
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		teamAccesses, err := parser.GetArrayOfObjects("teamAccesses", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid teamAccesses parameter", err), nil
+			return toolErrorFromErr("invalid teamAccesses parameter", err), nil
 		}
 
 		teamAccessesMap, err := parseAccessMap(teamAccesses)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid team accesses", err), nil
+			return toolErrorFromErr("invalid team accesses", err), nil
 		}
 
-		err = s.cli.UpdateAccessGroupTeamAccesses(id, teamAccessesMap)
+		err = s.client(ctx).UpdateAccessGroupTeamAccesses(ctx, id, teamAccessesMap)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update access group team accesses", err), nil
+			return toolErrorFromErr("failed to update access group team accesses", err), nil
 		}
 
 		return mcp.NewToolResultText("Access group team accesses updated successfully"), nil
@@ -159,17 +159,17 @@ func (s *PortainerMCPServer) HandleAddEnvironmentToAccessGroup() server.ToolHand
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 
-		err = s.cli.AddEnvironmentToAccessGroup(id, environmentId)
+		err = s.client(ctx).AddEnvironmentToAccessGroup(ctx, id, environmentId)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to add environment to access group", err), nil
+			return toolErrorFromErr("failed to add environment to access group", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment added to access group successfully"), nil
@@ -183,17 +183,17 @@ func (s *PortainerMCPServer) HandleRemoveEnvironmentFromAccessGroup() server.Too
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 
-		err = s.cli.RemoveEnvironmentFromAccessGroup(id, environmentId)
+		err = s.client(ctx).RemoveEnvironmentFromAccessGroup(ctx, id, environmentId)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to remove environment from access group", err), nil
+			return toolErrorFromErr("failed to remove environment from access group", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment removed from access group successfully"), nil
@@ -18,9 +18,9 @@ func (s *PortainerMCPServer) AddAppTemplateFeatures() {
 // HandleListAppTemplates handles the listAppTemplates tool call.
 func (s *PortainerMCPServer) HandleListAppTemplates() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		templates, err := s.cli.GetAppTemplates()
+		templates, err := s.client(ctx).GetAppTemplates(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list app templates", err), nil
+			return toolErrorFromErr("failed to list app templates", err), nil
 		}
 
 		return jsonResult(templates, "failed to marshal app templates")
@@ -34,12 +34,12 @@ func (s *PortainerMCPServer) HandleGetAppTemplateFile() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
-		content, err := s.cli.GetAppTemplateFile(id)
+		content, err := s.client(ctx).GetAppTemplateFile(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr(fmt.Sprintf("failed to get app template file for template %d", id), err), nil
+			return toolErrorFromErr(fmt.Sprintf("failed to get app template file for template %d", id), err), nil
 		}
 
 		return mcp.NewToolResultText(content), nil
@@ -0,0 +1,355 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultAuditLogMaxSizeMB is the size in megabytes at which the audit log is rotated by default.
+	DefaultAuditLogMaxSizeMB = 100
+	// DefaultAuditLogMaxBackups is the number of rotated audit log files kept by default.
+	DefaultAuditLogMaxBackups = 5
+
+	// auditRedacted replaces the value of sensitive arguments in the audit log.
+	auditRedacted = "[REDACTED]"
+	// maxAuditStringLength bounds the length of a string argument in the audit log,
+	// so that stack files and proxy bodies do not bloat the trail.
+	maxAuditStringLength = 1024
+
+	// Identity types recorded in the audit log.
+	auditIdentityServerToken = "server_token"
+	auditIdentityAPIKey      = "api_key"
+	auditIdentityJWT         = "jwt"
+)
+
+// sensitiveArgumentKeys are the fragments of argument names whose values are
+// redacted. Names are compared in lower case with '_' and '-' removed.
+var sensitiveArgumentKeys = []string{
+	"password",
+	"passphrase",
+	"secret",
+	"token",
+	"apikey",
+	"accesskey",
+	"privatekey",
+	"credential",
+	"authorization",
+	"cookie",
+}
+
+// auditEntry is a single line of the audit log, written for every tool call.
+type auditEntry struct {
+	Time       time.Time      `json:"time"`
+	Tool       string         `json:"tool"`
+	Action     string         `json:"action,omitempty"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Success    bool           `json:"success"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs float64        `json:"durationMs"`
+	Identity   auditIdentity  `json:"identity"`
+}
+
+// auditIdentity describes the Portainer identity a tool call was executed with.
+// Tokens are never logged; the fingerprint is a truncated SHA-256 digest that
+// allows correlating calls made with the same credential.
+type auditIdentity struct {
+	Type        string `json:"type"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Username    string `json:"username,omitempty"`
+	UserID      int    `json:"userId,omitempty"`
+	SessionID   string `json:"sessionId,omitempty"`
+}
+
+// auditLogger appends audit entries as JSON lines to a file and rotates the
+// file once it would grow beyond maxSize. Rotated files are renamed to
+// path.1 (newest) through path.maxBackups (oldest). It is safe for concurrent use.
+type auditLogger struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	now        func() time.Time
+
+	// serverIdentity is recorded for calls executed with the server token.
+	serverIdentity auditIdentity
+}
+
+// newAuditLogger opens the audit log at path for appending, creating it if needed.
+// A maxSize of zero or less disables rotation.
+func newAuditLogger(path string, maxSize int64, maxBackups int) (*auditLogger, error) {
+	l := &auditLogger{
+		path:           path,
+		maxSize:        maxSize,
+		maxBackups:     maxBackups,
+		now:            time.Now,
+		serverIdentity: auditIdentity{Type: auditIdentityServerToken},
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// open opens the log file and records its current size.
+func (l *auditLogger) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// write appends an entry to the log, rotating the file first if needed.
+func (l *auditLogger) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return errors.New("audit log is closed")
+	}
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts the backups by one, moves the current file to path.1, and
+// opens a new empty file. The oldest backup is overwritten. The caller must hold l.mu.
+func (l *auditLogger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	l.file = nil
+
+	if l.maxBackups > 0 {
+		for i := l.maxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to rotate audit log: %w", err)
+			}
+		}
+		if err := os.Rename(l.path, l.backupPath(1)); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	} else if err := os.Remove(l.path); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	return l.open()
+}
+
+// backupPath returns the name of the n-th rotated file.
+func (l *auditLogger) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// close closes the log file. Entries written afterwards are rejected.
+func (l *auditLogger) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// withAudit wraps a tool handler so that every call is recorded in the audit
+// log. It returns the handler unchanged when audit logging is disabled. A
+// failure to write the log is reported but does not fail the tool call.
+func (s *PortainerMCPServer) withAudit(toolName string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if s.audit == nil {
+		return next
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		duration := time.Since(start)
+
+		args := request.GetArguments()
+		action, _ := args["action"].(string)
+		errMessage := toolCallError(result, err)
+
+		entry := auditEntry{
+			Time:       s.audit.now().UTC(),
+			Tool:       toolName,
+			Action:     action,
+			Arguments:  redactArguments(args),
+			Success:    errMessage == "",
+			Error:      errMessage,
+			DurationMs: float64(duration.Microseconds()) / 1000,
+			Identity:   s.auditIdentity(ctx),
+		}
+		if writeErr := s.audit.write(entry); writeErr != nil {
+			log.Error().Err(writeErr).Str("tool", toolName).Msg("Failed to write audit log entry")
+		}
+
+		return result, err
+	}
+}
+
+// auditIdentity returns the identity a tool call is executed with, following
+// the same rules as [PortainerMCPServer.client].
+func (s *PortainerMCPServer) auditIdentity(ctx context.Context) auditIdentity {
+	identity := s.audit.serverIdentity
+	if s.sessionClients != nil {
+		if cred, ok := credentialFromContext(ctx); ok {
+			identity = credentialIdentity(cred)
+		}
+	}
+
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		identity.SessionID = session.SessionID()
+	}
+
+	return identity
+}
+
+// credentialIdentity describes a per-session credential. For a JWT, the user
+// name and ID are read from its claims without verifying the signature;
+// Portainer verifies the token when it is used.
+func credentialIdentity(cred portainerCredential) auditIdentity {
+	if !cred.jwt {
+		return auditIdentity{Type: auditIdentityAPIKey, Fingerprint: tokenFingerprint(cred.token)}
+	}
+
+	identity := auditIdentity{Type: auditIdentityJWT, Fingerprint: tokenFingerprint(cred.token)}
+	parts := strings.Split(cred.token, ".")
+	if len(parts) != 3 {
+		return identity
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return identity
+	}
+	var claims struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal(payload, &claims); err == nil {
+		identity.Username = claims.Username
+		identity.UserID = claims.ID
+	}
+	return identity
+}
+
+// tokenFingerprint returns a short, non-reversible identifier of a token.
+func tokenFingerprint(token string) string {
+	if token == "" {
+		return ""
+	}
+	digest := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(digest[:6])
+}
+
+// toolCallError returns the error message of a failed tool call, or an empty
+// string if it succeeded. Handlers report most failures as error results.
+func toolCallError(result *mcp.CallToolResult, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if result == nil || !result.IsError {
+		return ""
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "tool returned an error"
+}
+
+// redactArguments returns a copy of the tool arguments that is safe to log:
+// values of sensitive arguments are replaced, at any depth, and long strings are truncated.
+func redactArguments(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+	redacted, _ := redactValue(args).(map[string]any)
+	return redacted
+}
+
+// redactValue returns a redacted copy of an argument value.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				out[key] = auditRedacted
+			} else {
+				out[key] = redactValue(item)
+			}
+		}
+		// Headers and query parameters are passed as {key, value} pairs.
+		if name, ok := v["key"].(string); ok && isSensitiveKey(name) {
+			if _, ok := v["value"]; ok {
+				out["value"] = auditRedacted
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item)
+		}
+		return out
+	case string:
+		if len(v) > maxAuditStringLength {
+			return fmt.Sprintf("%s... (%d bytes truncated)", v[:maxAuditStringLength], len(v)-maxAuditStringLength)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isSensitiveKey reports whether an argument or header name holds a secret.
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, fragment := range sensitiveArgumentKeys {
+		if strings.Contains(normalized, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,329 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAuditEntries returns the entries written to an audit log file.
+func readAuditEntries(t *testing.T, path string) []auditEntry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry auditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+// newTestAuditServer returns a server with an audit log in a temporary directory.
+func newTestAuditServer(t *testing.T) (*PortainerMCPServer, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := newAuditLogger(path, 0, 0)
+	require.NoError(t, err)
+	audit.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+	audit.serverIdentity.Fingerprint = tokenFingerprint("server-token")
+	t.Cleanup(func() { audit.close() })
+
+	s := newTestMetaServer(false)
+	s.audit = audit
+	return s, path
+}
+
+// TestWithAudit verifies the entry written for successful and failed tool calls.
+func TestWithAudit(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       server.ToolHandlerFunc
+		args          map[string]any
+		expectSuccess bool
+		expectError   string
+	}{
+		{
+			name: "successful call",
+			handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			},
+			args:          map[string]any{"id": float64(1)},
+			expectSuccess: true,
+		},
+		{
+			name: "error result",
+			handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultError("failed to get user: not found"), nil
+			},
+			args:        map[string]any{"id": float64(1)},
+			expectError: "failed to get user: not found",
+		},
+		{
+			name: "handler error",
+			handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return nil, errors.New("boom")
+			},
+			expectError: "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, path := newTestAuditServer(t)
+
+			_, _ = s.withAudit("getUser", tt.handler)(context.Background(), CreateMCPRequest(tt.args))
+
+			entries := readAuditEntries(t, path)
+			require.Len(t, entries, 1)
+			entry := entries[0]
+			assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), entry.Time)
+			assert.Equal(t, "getUser", entry.Tool)
+			assert.Empty(t, entry.Action)
+			assert.Equal(t, tt.expectSuccess, entry.Success)
+			assert.Equal(t, tt.expectError, entry.Error)
+			assert.GreaterOrEqual(t, entry.DurationMs, float64(0))
+			assert.Equal(t, auditIdentity{Type: auditIdentityServerToken, Fingerprint: tokenFingerprint("server-token")}, entry.Identity)
+			if tt.args == nil {
+				assert.Nil(t, entry.Arguments)
+			} else {
+				assert.Equal(t, tt.args, entry.Arguments)
+			}
+		})
+	}
+}
+
+// TestWithAuditDisabled verifies that handlers are not wrapped without an audit log.
+func TestWithAuditDisabled(t *testing.T) {
+	s := newTestMetaServer(false)
+	called := false
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	_, err := s.withAudit("getUser", handler)(context.Background(), CreateMCPRequest(nil))
+
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+// TestAuditMetaToolCall verifies that a meta-tool call is recorded with its action.
+func TestAuditMetaToolCall(t *testing.T) {
+	s, path := newTestAuditServer(t)
+	s.cli.(*MockPortainerClient).On("GetUsers").Return([]models.User{}, nil)
+	s.RegisterMetaTools()
+
+	reqBytes, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "manage_users",
+			"arguments": map[string]any{"action": "list_users"},
+		},
+	})
+	require.NoError(t, err)
+	s.srv.HandleMessage(context.Background(), reqBytes)
+
+	entries := readAuditEntries(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "manage_users", entries[0].Tool)
+	assert.Equal(t, "list_users", entries[0].Action)
+	assert.True(t, entries[0].Success)
+}
+
+// TestAuditIdentity verifies the identity recorded with and without session authentication.
+func TestAuditIdentity(t *testing.T) {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"id":7,"username":"alice","role":1}`))
+	jwt := "eyJhbGciOiJIUzI1NiJ9." + claims + ".signature"
+
+	tests := []struct {
+		name           string
+		sessionAuth    bool
+		ctx            context.Context
+		expectIdentity auditIdentity
+	}{
+		{
+			name:           "server token",
+			ctx:            context.Background(),
+			expectIdentity: auditIdentity{Type: auditIdentityServerToken, Fingerprint: tokenFingerprint("server-token")},
+		},
+		{
+			name:           "credential ignored without session authentication",
+			ctx:            withCredential(context.Background(), portainerCredential{token: "ptr_abc"}),
+			expectIdentity: auditIdentity{Type: auditIdentityServerToken, Fingerprint: tokenFingerprint("server-token")},
+		},
+		{
+			name:        "session api key",
+			sessionAuth: true,
+			ctx: server.NewMCPServer("test", "0.0.1").WithContext(
+				withCredential(context.Background(), portainerCredential{token: "ptr_abc"}),
+				testSession{id: "session-1"},
+			),
+			expectIdentity: auditIdentity{Type: auditIdentityAPIKey, Fingerprint: tokenFingerprint("ptr_abc"), SessionID: "session-1"},
+		},
+		{
+			name:           "session jwt",
+			sessionAuth:    true,
+			ctx:            withCredential(context.Background(), portainerCredential{token: jwt, jwt: true}),
+			expectIdentity: auditIdentity{Type: auditIdentityJWT, Fingerprint: tokenFingerprint(jwt), Username: "alice", UserID: 7},
+		},
+		{
+			name:           "session jwt with unreadable claims",
+			sessionAuth:    true,
+			ctx:            withCredential(context.Background(), portainerCredential{token: "not-a-jwt", jwt: true}),
+			expectIdentity: auditIdentity{Type: auditIdentityJWT, Fingerprint: tokenFingerprint("not-a-jwt")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestAuditServer(t)
+			if tt.sessionAuth {
+				s.sessionClients = newSessionClientCache(func(token string, jwt bool) PortainerClient { return nil })
+			}
+
+			assert.Equal(t, tt.expectIdentity, s.auditIdentity(tt.ctx))
+		})
+	}
+}
+
+// TestRedactArguments verifies that secrets are removed at any depth and long strings are truncated.
+func TestRedactArguments(t *testing.T) {
+	args := map[string]any{
+		"id":              float64(3),
+		"password":        "hunter2",
+		"secretAccessKey": "s3cr3t",
+		"accessKeyID":     "AKIA",
+		"settings": map[string]any{
+			"LDAPSettings": map[string]any{"Password": "ldap", "URL": "ldap://example"},
+		},
+		"headers": []any{
+			map[string]any{"key": "Authorization", "value": "Bearer abc"},
+			map[string]any{"key": "Content-Type", "value": "application/json"},
+		},
+		"body": strings.Repeat("a", maxAuditStringLength+10),
+	}
+
+	redacted := redactArguments(args)
+
+	assert.Equal(t, map[string]any{
+		"id":              float64(3),
+		"password":        auditRedacted,
+		"secretAccessKey": auditRedacted,
+		"accessKeyID":     auditRedacted,
+		"settings": map[string]any{
+			"LDAPSettings": map[string]any{"Password": auditRedacted, "URL": "ldap://example"},
+		},
+		"headers": []any{
+			map[string]any{"key": "Authorization", "value": auditRedacted},
+			map[string]any{"key": "Content-Type", "value": "application/json"},
+		},
+		"body": strings.Repeat("a", maxAuditStringLength) + "... (10 bytes truncated)",
+	}, redacted)
+	assert.Equal(t, "hunter2", args["password"], "the original arguments must not be modified")
+	assert.Nil(t, redactArguments(nil))
+}
+
+// TestAuditLoggerRotation verifies size-based rotation and the number of backups kept.
+func TestAuditLoggerRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	entry := auditEntry{Tool: "listUsers", Success: true}
+	line, err := json.Marshal(entry)
+	require.NoError(t, err)
+
+	// Each file holds two entries.
+	l, err := newAuditLogger(path, int64(2*(len(line)+1)), 2)
+	require.NoError(t, err)
+	defer l.close()
+
+	for i := 0; i < 7; i++ {
+		require.NoError(t, l.write(entry))
+	}
+
+	assert.Len(t, readAuditEntries(t, path), 1)
+	assert.Len(t, readAuditEntries(t, path+".1"), 2)
+	assert.Len(t, readAuditEntries(t, path+".2"), 2)
+	assert.NoFileExists(t, path+".3")
+}
+
+// TestAuditLoggerAppends verifies that an existing log is appended to and counts towards rotation.
+func TestAuditLoggerAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	l, err := newAuditLogger(path, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, l.write(auditEntry{Tool: "first"}))
+	require.NoError(t, l.close())
+
+	l, err = newAuditLogger(path, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, l.write(auditEntry{Tool: "second"}))
+	require.NoError(t, l.close())
+
+	entries := readAuditEntries(t, path)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "first", entries[0].Tool)
+	assert.Equal(t, "second", entries[1].Tool)
+
+	assert.EqualError(t, l.write(auditEntry{Tool: "third"}), "audit log is closed")
+}
+
+// TestNewPortainerMCPServerAuditLog verifies audit log options.
+func TestNewPortainerMCPServerAuditLog(t *testing.T) {
+	options := []ServerOption{WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true)}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		s, err := NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml", options...)
+		require.NoError(t, err)
+		assert.Nil(t, s.audit)
+	})
+
+	t.Run("enabled with rotation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		s, err := NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+			append(options, WithAuditLog(path), WithAuditLogRotation(10, 3))...)
+		require.NoError(t, err)
+		defer s.audit.close()
+
+		assert.Equal(t, int64(10<<20), s.audit.maxSize)
+		assert.Equal(t, 3, s.audit.maxBackups)
+		assert.Equal(t, tokenFingerprint("token"), s.audit.serverIdentity.Fingerprint)
+		assert.FileExists(t, path)
+	})
+
+	t.Run("unwritable path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "audit.jsonl")
+		_, err := NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+			append(options, WithAuditLog(path))...)
+		assert.ErrorContains(t, err, "failed to open audit log")
+	})
+
+	t.Run("negative rotation settings", func(t *testing.T) {
+		_, err := NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+			append(options, WithAuditLog("audit.jsonl"), WithAuditLogRotation(-1, 0))...)
+		assert.EqualError(t, err, "audit log max size and max backups cannot be negative")
+	})
+}
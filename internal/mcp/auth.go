@@ -24,17 +24,17 @@ func (s *PortainerMCPServer) HandleAuthenticateUser() server.ToolHandlerFunc {
 
 		username, err := parser.GetString("username", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid username parameter", err), nil
+			return toolErrorFromErr("invalid username parameter", err), nil
 		}
 
 		password, err := parser.GetString("password", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+			return toolErrorFromErr("invalid password parameter", err), nil
 		}
 
-		authResponse, err := s.cli.AuthenticateUser(username, password)
+		authResponse, err := s.client(ctx).AuthenticateUser(ctx, username, password)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to authenticate user", err), nil
+			return toolErrorFromErr("failed to authenticate user", err), nil
 		}
 
 		return jsonResult(authResponse, "failed to marshal authentication response")
@@ -44,9 +44,9 @@ func (s *PortainerMCPServer) HandleAuthenticateUser() server.ToolHandlerFunc {
 // HandleLogout returns an MCP tool handler that logs out authentication.
 func (s *PortainerMCPServer) HandleLogout() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		err := s.cli.Logout()
+		err := s.client(ctx).Logout(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to logout", err), nil
+			return toolErrorFromErr("failed to logout", err), nil
 		}
 
 		return mcp.NewToolResultText("Logged out successfully"), nil
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -24,9 +25,9 @@ func (s *PortainerMCPServer) AddBackupFeatures() {
 // HandleGetBackupStatus returns an MCP tool handler that retrieves backup status.
 func (s *PortainerMCPServer) HandleGetBackupStatus() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		status, err := s.cli.GetBackupStatus()
+		status, err := s.client(ctx).GetBackupStatus(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get backup status", err), nil
+			return toolErrorFromErr("failed to get backup status", err), nil
 		}
 
 		return jsonResult(status, "failed to marshal backup status")
@@ -36,9 +37,9 @@ func (s *PortainerMCPServer) HandleGetBackupStatus() server.ToolHandlerFunc {
 // HandleGetBackupS3Settings returns an MCP tool handler that retrieves backup s3 settings.
 func (s *PortainerMCPServer) HandleGetBackupS3Settings() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		settings, err := s.cli.GetBackupS3Settings()
+		settings, err := s.client(ctx).GetBackupS3Settings(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get backup S3 settings", err), nil
+			return toolErrorFromErr("failed to get backup S3 settings", err), nil
 		}
 
 		return jsonResult(settings, "failed to marshal backup S3 settings")
@@ -52,12 +53,15 @@ func (s *PortainerMCPServer) HandleCreateBackup() server.ToolHandlerFunc {
 
 		password, err := parser.GetString("password", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+			return toolErrorFromErr("invalid password parameter", err), nil
 		}
 
-		err = s.cli.CreateBackup(password)
+		progress := s.newProgressReporter(ctx, request)
+		err = progress.run("Creating backup", func() error {
+			return s.client(ctx).CreateBackup(ctx, password)
+		})
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create backup", err), nil
+			return toolErrorFromErr("failed to create backup", err), nil
 		}
 
 		return mcp.NewToolResultText("Backup created successfully"), nil
@@ -71,37 +75,37 @@ func (s *PortainerMCPServer) HandleBackupToS3() server.ToolHandlerFunc {
 
 		accessKeyID, err := parser.GetString("accessKeyID", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid accessKeyID parameter", err), nil
+			return toolErrorFromErr("invalid accessKeyID parameter", err), nil
 		}
 
 		secretAccessKey, err := parser.GetString("secretAccessKey", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid secretAccessKey parameter", err), nil
+			return toolErrorFromErr("invalid secretAccessKey parameter", err), nil
 		}
 
 		bucketName, err := parser.GetString("bucketName", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid bucketName parameter", err), nil
+			return toolErrorFromErr("invalid bucketName parameter", err), nil
 		}
 
 		region, err := parser.GetString("region", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid region parameter", err), nil
+			return toolErrorFromErr("invalid region parameter", err), nil
 		}
 
 		s3CompatibleHost, err := parser.GetString("s3CompatibleHost", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid s3CompatibleHost parameter", err), nil
+			return toolErrorFromErr("invalid s3CompatibleHost parameter", err), nil
 		}
 
 		password, err := parser.GetString("password", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+			return toolErrorFromErr("invalid password parameter", err), nil
 		}
 
 		cronRule, err := parser.GetString("cronRule", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid cronRule parameter", err), nil
+			return toolErrorFromErr("invalid cronRule parameter", err), nil
 		}
 
 		settings := models.S3BackupSettings{
@@ -114,9 +118,12 @@ func (s *PortainerMCPServer) HandleBackupToS3() server.ToolHandlerFunc {
 			CronRule:         cronRule,
 		}
 
-		err = s.cli.BackupToS3(settings)
+		progress := s.newProgressReporter(ctx, request)
+		err = progress.run(fmt.Sprintf("Backing up to S3 bucket %s", bucketName), func() error {
+			return s.client(ctx).BackupToS3(ctx, settings)
+		})
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to backup to S3", err), nil
+			return toolErrorFromErr("failed to backup to S3", err), nil
 		}
 
 		return mcp.NewToolResultText("Backup to S3 completed successfully"), nil
@@ -130,42 +137,42 @@ func (s *PortainerMCPServer) HandleRestoreFromS3() server.ToolHandlerFunc {
 
 		accessKeyID, err := parser.GetString("accessKeyID", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid accessKeyID parameter", err), nil
+			return toolErrorFromErr("invalid accessKeyID parameter", err), nil
 		}
 
 		secretAccessKey, err := parser.GetString("secretAccessKey", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid secretAccessKey parameter", err), nil
+			return toolErrorFromErr("invalid secretAccessKey parameter", err), nil
 		}
 
 		bucketName, err := parser.GetString("bucketName", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid bucketName parameter", err), nil
+			return toolErrorFromErr("invalid bucketName parameter", err), nil
 		}
 
 		filename, err := parser.GetString("filename", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid filename parameter", err), nil
+			return toolErrorFromErr("invalid filename parameter", err), nil
 		}
 
 		password, err := parser.GetString("password", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+			return toolErrorFromErr("invalid password parameter", err), nil
 		}
 
 		region, err := parser.GetString("region", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid region parameter", err), nil
+			return toolErrorFromErr("invalid region parameter", err), nil
 		}
 
 		s3CompatibleHost, err := parser.GetString("s3CompatibleHost", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid s3CompatibleHost parameter", err), nil
+			return toolErrorFromErr("invalid s3CompatibleHost parameter", err), nil
 		}
 
-		err = s.cli.RestoreFromS3(accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey)
+		err = s.client(ctx).RestoreFromS3(ctx, accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to restore from S3", err), nil
+			return toolErrorFromErr("failed to restore from S3", err), nil
 		}
 
 		return mcp.NewToolResultText("Restore from S3 completed successfully"), nil
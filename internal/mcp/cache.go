@@ -0,0 +1,460 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// refreshParam is the argument that makes a read-only call bypass the client cache.
+	refreshParam = "refresh"
+
+	// refreshParamDescription documents the refresh argument.
+	refreshParamDescription = "Read fresh data from Portainer instead of the server's cache of recent reads (default: false)"
+)
+
+// cacheDomain is a group of Portainer resources. A write in a domain clears
+// the cached reads of that domain.
+type cacheDomain string
+
+const (
+	cacheDomainEnvironments      cacheDomain = "environments"
+	cacheDomainEnvironmentTags   cacheDomain = "environment tags"
+	cacheDomainEnvironmentGroups cacheDomain = "environment groups"
+	cacheDomainAccessGroups      cacheDomain = "access groups"
+	cacheDomainUsers             cacheDomain = "users"
+	cacheDomainTeams             cacheDomain = "teams"
+	cacheDomainRoles             cacheDomain = "roles"
+	cacheDomainRegistries        cacheDomain = "registries"
+)
+
+// cachedMethods maps the PortainerClient methods whose results can be cached
+// to the domain of the resources they read.
+var cachedMethods = map[string]cacheDomain{
+	"GetEnvironments":      cacheDomainEnvironments,
+	"GetEnvironmentTags":   cacheDomainEnvironmentTags,
+	"GetEnvironmentGroups": cacheDomainEnvironmentGroups,
+	"GetAccessGroups":      cacheDomainAccessGroups,
+	"GetUsers":             cacheDomainUsers,
+	"GetTeams":             cacheDomainTeams,
+	"GetRoles":             cacheDomainRoles,
+	"GetRegistries":        cacheDomainRegistries,
+}
+
+// cacheConfig holds the time to live of the cached results of each method.
+// A method without a positive TTL is not cached.
+type cacheConfig struct {
+	ttls map[string]time.Duration
+}
+
+// newCacheConfig returns the configuration that caches every cacheable method
+// for ttl, except those given their own TTL in methodTTLs.
+func newCacheConfig(ttl time.Duration, methodTTLs map[string]time.Duration) (*cacheConfig, error) {
+	if ttl < 0 {
+		return nil, fmt.Errorf("invalid cache TTL %s: must not be negative", ttl)
+	}
+	config := &cacheConfig{ttls: make(map[string]time.Duration, len(cachedMethods))}
+	for method := range cachedMethods {
+		config.ttls[method] = ttl
+	}
+	for method, methodTTL := range methodTTLs {
+		if _, ok := cachedMethods[method]; !ok {
+			return nil, fmt.Errorf("invalid cache TTL for %s: the method is not cacheable, must be one of %s", method, strings.Join(slices.Sorted(maps.Keys(cachedMethods)), ", "))
+		}
+		if methodTTL < 0 {
+			return nil, fmt.Errorf("invalid cache TTL %s for %s: must not be negative", methodTTL, method)
+		}
+		config.ttls[method] = methodTTL
+	}
+	if !slices.ContainsFunc(slices.Collect(maps.Values(config.ttls)), func(d time.Duration) bool { return d > 0 }) {
+		return nil, nil
+	}
+	return config, nil
+}
+
+// cacheStats counts the hits and misses of the cache of each method. It is
+// shared by the caches of every client of the server.
+type cacheStats struct {
+	hits   map[string]*atomic.Int64
+	misses map[string]*atomic.Int64
+}
+
+// newCacheStats returns zeroed counters for every cacheable method.
+func newCacheStats() *cacheStats {
+	stats := &cacheStats{hits: map[string]*atomic.Int64{}, misses: map[string]*atomic.Int64{}}
+	for method := range cachedMethods {
+		stats.hits[method] = &atomic.Int64{}
+		stats.misses[method] = &atomic.Int64{}
+	}
+	return stats
+}
+
+// methodCacheStats are the cache hits and misses of one method.
+type methodCacheStats struct {
+	Method string
+	Hits   int64
+	Misses int64
+}
+
+// snapshot returns the counters of the methods that were called, sorted by method.
+func (s *cacheStats) snapshot() []methodCacheStats {
+	var snapshot []methodCacheStats
+	for method := range s.hits {
+		hits, misses := s.hits[method].Load(), s.misses[method].Load()
+		if hits+misses > 0 {
+			snapshot = append(snapshot, methodCacheStats{Method: method, Hits: hits, Misses: misses})
+		}
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Method < snapshot[j].Method })
+	return snapshot
+}
+
+// logSummary logs the hits and misses of each cached method.
+func (s *cacheStats) logSummary() {
+	for _, stats := range s.snapshot() {
+		log.Info().
+			Str("method", stats.Method).
+			Int64("hits", stats.Hits).
+			Int64("misses", stats.Misses).
+			Msg("client cache statistics")
+	}
+}
+
+// ParseCacheMethodTTLs parses per-method cache TTLs given as a comma-separated
+// list of method=duration pairs, such as "GetRoles=10m,GetEnvironments=15s".
+func ParseCacheMethodTTLs(value string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		method, duration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cache TTL %q: must be method=duration", pair)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache TTL %q: %w", pair, err)
+		}
+		ttls[strings.TrimSpace(method)] = ttl
+	}
+	return ttls, nil
+}
+
+// cacheEntry is a cached result and the time it expires.
+type cacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// cachingClient is a PortainerClient that keeps the results of frequently
+// repeated reads, such as the lists of environments, tags, and users, for
+// their configured TTL. A write through the client clears the cached reads of
+// the domains it changes, so CreateEnvironmentTag clears the tag list.
+//
+// Every write method of [PortainerClient] that changes a cached domain must be
+// overridden here; methods that are only promoted from the embedded client
+// leave the cache untouched.
+type cachingClient struct {
+	PortainerClient
+	config *cacheConfig
+	stats  *cacheStats
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// generation is incremented by every invalidation, so that a read that
+	// started before a write does not cache its outdated result.
+	generation uint64
+}
+
+// newCachingClient returns a client that caches the reads of cli.
+func newCachingClient(cli PortainerClient, config *cacheConfig, stats *cacheStats) *cachingClient {
+	return &cachingClient{
+		PortainerClient: cli,
+		config:          config,
+		stats:           stats,
+		now:             time.Now,
+		entries:         make(map[string]cacheEntry),
+	}
+}
+
+// refreshContextKey is the context key that marks a call asking for fresh data.
+type refreshContextKey struct{}
+
+// withCacheRefresh returns a context in which cached reads are fetched again from Portainer.
+func withCacheRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshContextKey{}, true)
+}
+
+// cacheRefreshRequested reports whether the call asked for fresh data.
+func cacheRefreshRequested(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshContextKey{}).(bool)
+	return refresh
+}
+
+// cached returns the cached result of a method if it has not expired, and
+// otherwise calls fetch and caches its result. Errors are not cached, and
+// neither are results fetched while a write cleared the cache.
+func cached[T any](ctx context.Context, c *cachingClient, method string, fetch func() ([]T, error)) ([]T, error) {
+	ttl := c.config.ttls[method]
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[method]
+	generation := c.generation
+	c.mu.Unlock()
+	if !cacheRefreshRequested(ctx) {
+		if ok && c.now().Before(entry.expiresAt) {
+			c.stats.hits[method].Add(1)
+			// Return a copy so that a handler cannot change the cached list.
+			return slices.Clone(entry.value.([]T)), nil
+		}
+	}
+
+	c.stats.misses[method].Add(1)
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[method] = cacheEntry{value: slices.Clone(value), expiresAt: c.now().Add(ttl)}
+	}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// invalidate clears the cached reads of the given domains.
+func (c *cachingClient) invalidate(domains ...cacheDomain) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for method := range c.entries {
+		if slices.Contains(domains, cachedMethods[method]) {
+			delete(c.entries, method)
+		}
+	}
+}
+
+// withCacheRefreshArgument wraps a read-only tool handler so that the refresh
+// argument makes the call read fresh data from Portainer.
+func withCacheRefreshArgument(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if refresh, _ := request.GetArguments()[refreshParam].(bool); refresh {
+			ctx = withCacheRefresh(ctx)
+		}
+		return next(ctx, request)
+	}
+}
+
+// withRefreshParameter returns a copy of a tool whose input schema accepts the refresh argument.
+func withRefreshParameter(tool mcp.Tool) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	properties[refreshParam] = map[string]any{
+		"type":        "boolean",
+		"description": refreshParamDescription,
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// Cached reads.
+
+func (c *cachingClient) GetEnvironments(ctx context.Context) ([]models.Environment, error) {
+	return cached(ctx, c, "GetEnvironments", func() ([]models.Environment, error) { return c.PortainerClient.GetEnvironments(ctx) })
+}
+
+func (c *cachingClient) GetEnvironmentTags(ctx context.Context) ([]models.EnvironmentTag, error) {
+	return cached(ctx, c, "GetEnvironmentTags", func() ([]models.EnvironmentTag, error) { return c.PortainerClient.GetEnvironmentTags(ctx) })
+}
+
+func (c *cachingClient) GetEnvironmentGroups(ctx context.Context) ([]models.Group, error) {
+	return cached(ctx, c, "GetEnvironmentGroups", func() ([]models.Group, error) { return c.PortainerClient.GetEnvironmentGroups(ctx) })
+}
+
+func (c *cachingClient) GetAccessGroups(ctx context.Context) ([]models.AccessGroup, error) {
+	return cached(ctx, c, "GetAccessGroups", func() ([]models.AccessGroup, error) { return c.PortainerClient.GetAccessGroups(ctx) })
+}
+
+func (c *cachingClient) GetUsers(ctx context.Context) ([]models.User, error) {
+	return cached(ctx, c, "GetUsers", func() ([]models.User, error) { return c.PortainerClient.GetUsers(ctx) })
+}
+
+func (c *cachingClient) GetTeams(ctx context.Context) ([]models.Team, error) {
+	return cached(ctx, c, "GetTeams", func() ([]models.Team, error) { return c.PortainerClient.GetTeams(ctx) })
+}
+
+func (c *cachingClient) GetRoles(ctx context.Context) ([]models.Role, error) {
+	return cached(ctx, c, "GetRoles", func() ([]models.Role, error) { return c.PortainerClient.GetRoles(ctx) })
+}
+
+func (c *cachingClient) GetRegistries(ctx context.Context) ([]models.Registry, error) {
+	return cached(ctx, c, "GetRegistries", func() ([]models.Registry, error) { return c.PortainerClient.GetRegistries(ctx) })
+}
+
+// Writes that clear cached reads. The cache is cleared even when the write
+// fails, since a failed request may still have been applied.
+
+func (c *cachingClient) CreateEnvironmentTag(ctx context.Context, name string) (int, error) {
+	defer c.invalidate(cacheDomainEnvironmentTags)
+	return c.PortainerClient.CreateEnvironmentTag(ctx, name)
+}
+
+func (c *cachingClient) DeleteEnvironmentTag(ctx context.Context, id int) error {
+	defer c.invalidate(cacheDomainEnvironmentTags, cacheDomainEnvironments, cacheDomainEnvironmentGroups)
+	return c.PortainerClient.DeleteEnvironmentTag(ctx, id)
+}
+
+func (c *cachingClient) DeleteEnvironment(ctx context.Context, id int) error {
+	defer c.invalidate(cacheDomainEnvironments, cacheDomainEnvironmentTags, cacheDomainEnvironmentGroups, cacheDomainAccessGroups)
+	return c.PortainerClient.DeleteEnvironment(ctx, id)
+}
+
+func (c *cachingClient) SnapshotEnvironment(ctx context.Context, id int) error {
+	defer c.invalidate(cacheDomainEnvironments)
+	return c.PortainerClient.SnapshotEnvironment(ctx, id)
+}
+
+func (c *cachingClient) SnapshotAllEnvironments(ctx context.Context) error {
+	defer c.invalidate(cacheDomainEnvironments)
+	return c.PortainerClient.SnapshotAllEnvironments(ctx)
+}
+
+func (c *cachingClient) UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error {
+	defer c.invalidate(cacheDomainEnvironments, cacheDomainEnvironmentTags)
+	return c.PortainerClient.UpdateEnvironmentTags(ctx, id, tagIds)
+}
+
+func (c *cachingClient) UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
+	defer c.invalidate(cacheDomainEnvironments)
+	return c.PortainerClient.UpdateEnvironmentUserAccesses(ctx, id, userAccesses)
+}
+
+func (c *cachingClient) UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
+	defer c.invalidate(cacheDomainEnvironments)
+	return c.PortainerClient.UpdateEnvironmentTeamAccesses(ctx, id, teamAccesses)
+}
+
+func (c *cachingClient) CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	defer c.invalidate(cacheDomainEnvironmentGroups, cacheDomainEnvironments)
+	return c.PortainerClient.CreateEnvironmentGroup(ctx, name, environmentIds)
+}
+
+func (c *cachingClient) UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error {
+	defer c.invalidate(cacheDomainEnvironmentGroups)
+	return c.PortainerClient.UpdateEnvironmentGroupName(ctx, id, name)
+}
+
+func (c *cachingClient) UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error {
+	defer c.invalidate(cacheDomainEnvironmentGroups, cacheDomainEnvironments)
+	return c.PortainerClient.UpdateEnvironmentGroupEnvironments(ctx, id, environmentIds)
+}
+
+func (c *cachingClient) UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error {
+	defer c.invalidate(cacheDomainEnvironmentGroups, cacheDomainEnvironmentTags)
+	return c.PortainerClient.UpdateEnvironmentGroupTags(ctx, id, tagIds)
+}
+
+func (c *cachingClient) CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	defer c.invalidate(cacheDomainAccessGroups, cacheDomainEnvironments)
+	return c.PortainerClient.CreateAccessGroup(ctx, name, environmentIds)
+}
+
+func (c *cachingClient) UpdateAccessGroupName(ctx context.Context, id int, name string) error {
+	defer c.invalidate(cacheDomainAccessGroups)
+	return c.PortainerClient.UpdateAccessGroupName(ctx, id, name)
+}
+
+func (c *cachingClient) UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
+	defer c.invalidate(cacheDomainAccessGroups)
+	return c.PortainerClient.UpdateAccessGroupUserAccesses(ctx, id, userAccesses)
+}
+
+func (c *cachingClient) UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
+	defer c.invalidate(cacheDomainAccessGroups)
+	return c.PortainerClient.UpdateAccessGroupTeamAccesses(ctx, id, teamAccesses)
+}
+
+func (c *cachingClient) AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error {
+	defer c.invalidate(cacheDomainAccessGroups, cacheDomainEnvironments)
+	return c.PortainerClient.AddEnvironmentToAccessGroup(ctx, id, environmentId)
+}
+
+func (c *cachingClient) RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error {
+	defer c.invalidate(cacheDomainAccessGroups, cacheDomainEnvironments)
+	return c.PortainerClient.RemoveEnvironmentFromAccessGroup(ctx, id, environmentId)
+}
+
+func (c *cachingClient) CreateTeam(ctx context.Context, name string) (int, error) {
+	defer c.invalidate(cacheDomainTeams)
+	return c.PortainerClient.CreateTeam(ctx, name)
+}
+
+func (c *cachingClient) DeleteTeam(ctx context.Context, id int) error {
+	defer c.invalidate(cacheDomainTeams, cacheDomainEnvironments, cacheDomainAccessGroups)
+	return c.PortainerClient.DeleteTeam(ctx, id)
+}
+
+func (c *cachingClient) UpdateTeamName(ctx context.Context, id int, name string) error {
+	defer c.invalidate(cacheDomainTeams)
+	return c.PortainerClient.UpdateTeamName(ctx, id, name)
+}
+
+func (c *cachingClient) UpdateTeamMembers(ctx context.Context, id int, userIds []int) error {
+	defer c.invalidate(cacheDomainTeams, cacheDomainUsers)
+	return c.PortainerClient.UpdateTeamMembers(ctx, id, userIds)
+}
+
+func (c *cachingClient) CreateUser(ctx context.Context, username, password, role string) (int, error) {
+	defer c.invalidate(cacheDomainUsers)
+	return c.PortainerClient.CreateUser(ctx, username, password, role)
+}
+
+func (c *cachingClient) DeleteUser(ctx context.Context, id int) error {
+	defer c.invalidate(cacheDomainUsers, cacheDomainTeams, cacheDomainEnvironments, cacheDomainAccessGroups)
+	return c.PortainerClient.DeleteUser(ctx, id)
+}
+
+func (c *cachingClient) UpdateUserRole(ctx context.Context, id int, role string) error {
+	defer c.invalidate(cacheDomainUsers)
+	return c.PortainerClient.UpdateUserRole(ctx, id, role)
+}
+
+func (c *cachingClient) CreateRegistry(ctx context.Context, name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error) {
+	defer c.invalidate(cacheDomainRegistries)
+	return c.PortainerClient.CreateRegistry(ctx, name, registryType, url, authentication, username, password, baseURL)
+}
+
+func (c *cachingClient) UpdateRegistry(ctx context.Context, id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error {
+	defer c.invalidate(cacheDomainRegistries)
+	return c.PortainerClient.UpdateRegistry(ctx, id, name, url, authentication, username, password, baseURL)
+}
+
+func (c *cachingClient) DeleteRegistry(ctx context.Context, id int) error {
+	defer c.invalidate(cacheDomainRegistries)
+	return c.PortainerClient.DeleteRegistry(ctx, id)
+}
+
+func (c *cachingClient) RestoreFromS3(ctx context.Context, accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error {
+	defer c.invalidate(slices.Collect(maps.Values(cachedMethods))...)
+	return c.PortainerClient.RestoreFromS3(ctx, accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey)
+}
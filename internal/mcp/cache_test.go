@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCachingClient returns a caching client around a mock client, with a
+// clock that only moves when the returned function is called.
+func newTestCachingClient(t *testing.T, ttl time.Duration, methodTTLs map[string]time.Duration) (*cachingClient, *MockPortainerClient, func(time.Duration)) {
+	t.Helper()
+
+	config, err := newCacheConfig(ttl, methodTTLs)
+	require.NoError(t, err)
+	mockClient := &MockPortainerClient{}
+	c := newCachingClient(mockClient, config, newCacheStats())
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, mockClient, func(d time.Duration) { now = now.Add(d) }
+}
+
+// TestCachingClientTTL verifies that reads are served from the cache until their TTL expires.
+func TestCachingClientTTL(t *testing.T) {
+	c, mockClient, advance := newTestCachingClient(t, time.Minute, map[string]time.Duration{"GetRoles": time.Hour, "GetUsers": 0})
+	mockClient.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local"}}, nil)
+	mockClient.On("GetRoles").Return([]models.Role{{ID: 1, Name: "admin"}}, nil)
+	mockClient.On("GetUsers").Return([]models.User{{ID: 1, Username: "admin"}}, nil)
+	ctx := context.Background()
+
+	for range 3 {
+		environments, err := c.GetEnvironments(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "local", environments[0].Name)
+		_, err = c.GetRoles(ctx)
+		require.NoError(t, err)
+		_, err = c.GetUsers(ctx)
+		require.NoError(t, err)
+	}
+	mockClient.AssertNumberOfCalls(t, "GetEnvironments", 1)
+	mockClient.AssertNumberOfCalls(t, "GetRoles", 1)
+	mockClient.AssertNumberOfCalls(t, "GetUsers", 3)
+
+	advance(2 * time.Minute)
+	_, err := c.GetEnvironments(ctx)
+	require.NoError(t, err)
+	_, err = c.GetRoles(ctx)
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetEnvironments", 2)
+	mockClient.AssertNumberOfCalls(t, "GetRoles", 1)
+
+	assert.Equal(t, []methodCacheStats{
+		{Method: "GetEnvironments", Hits: 2, Misses: 2},
+		{Method: "GetRoles", Hits: 3, Misses: 1},
+	}, c.stats.snapshot())
+}
+
+// TestCachingClientInvalidation verifies that writes clear the cached reads of
+// the domains they change, and only those.
+func TestCachingClientInvalidation(t *testing.T) {
+	c, mockClient, _ := newTestCachingClient(t, time.Minute, nil)
+	mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{{ID: 1, Name: "production"}}, nil)
+	mockClient.On("GetUsers").Return([]models.User{{ID: 1, Username: "admin"}}, nil)
+	mockClient.On("CreateEnvironmentTag", "staging").Return(2, nil)
+	mockClient.On("DeleteEnvironmentTag", 1).Return(errors.New("tag in use"))
+	ctx := context.Background()
+
+	read := func() {
+		_, err := c.GetEnvironmentTags(ctx)
+		require.NoError(t, err)
+		_, err = c.GetUsers(ctx)
+		require.NoError(t, err)
+	}
+
+	read()
+	read()
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 1)
+
+	_, err := c.CreateEnvironmentTag(ctx, "staging")
+	require.NoError(t, err)
+	read()
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 2)
+	mockClient.AssertNumberOfCalls(t, "GetUsers", 1)
+
+	// A failed write may still have changed something, so it clears the cache too.
+	require.Error(t, c.DeleteEnvironmentTag(ctx, 1))
+	read()
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 3)
+}
+
+// TestCachingClientGroupMembershipInvalidation verifies that the writes that
+// move environments into a group also clear the cached environments, whose
+// group IDs the environment scope reads.
+func TestCachingClientGroupMembershipInvalidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		mockSetup func(*MockPortainerClient)
+		write     func(context.Context, *cachingClient) error
+	}{
+		{
+			name: "create environment group",
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("CreateEnvironmentGroup", "production", []int{1}).Return(2, nil)
+			},
+			write: func(ctx context.Context, c *cachingClient) error {
+				_, err := c.CreateEnvironmentGroup(ctx, "production", []int{1})
+				return err
+			},
+		},
+		{
+			name: "update environment group environments",
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("UpdateEnvironmentGroupEnvironments", 2, []int{1}).Return(nil)
+			},
+			write: func(ctx context.Context, c *cachingClient) error {
+				return c.UpdateEnvironmentGroupEnvironments(ctx, 2, []int{1})
+			},
+		},
+		{
+			name: "create access group",
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("CreateAccessGroup", "production", []int{1}).Return(2, nil)
+			},
+			write: func(ctx context.Context, c *cachingClient) error {
+				_, err := c.CreateAccessGroup(ctx, "production", []int{1})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mockClient, _ := newTestCachingClient(t, time.Minute, nil)
+			mockClient.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local"}}, nil)
+			tt.mockSetup(mockClient)
+			ctx := context.Background()
+
+			_, err := c.GetEnvironments(ctx)
+			require.NoError(t, err)
+			_, err = c.GetEnvironments(ctx)
+			require.NoError(t, err)
+			mockClient.AssertNumberOfCalls(t, "GetEnvironments", 1)
+
+			require.NoError(t, tt.write(ctx, c))
+			_, err = c.GetEnvironments(ctx)
+			require.NoError(t, err)
+			mockClient.AssertNumberOfCalls(t, "GetEnvironments", 2)
+		})
+	}
+}
+
+// TestCachingClientRefreshAndErrors verifies that a refresh bypasses the cache
+// and that errors are not cached.
+func TestCachingClientRefreshAndErrors(t *testing.T) {
+	c, mockClient, _ := newTestCachingClient(t, time.Minute, nil)
+	mockClient.On("GetTeams").Return(nil, errors.New("connection refused")).Once()
+	mockClient.On("GetTeams").Return([]models.Team{{ID: 1, Name: "ops"}}, nil)
+	ctx := context.Background()
+
+	_, err := c.GetTeams(ctx)
+	require.Error(t, err)
+	teams, err := c.GetTeams(ctx)
+	require.NoError(t, err)
+	assert.Len(t, teams, 1)
+
+	teams[0].Name = "changed"
+	teams, err = c.GetTeams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ops", teams[0].Name, "callers cannot change the cached list")
+	mockClient.AssertNumberOfCalls(t, "GetTeams", 2)
+
+	_, err = c.GetTeams(withCacheRefresh(ctx))
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetTeams", 3)
+}
+
+// TestNewCacheConfig verifies cache TTL validation.
+func TestNewCacheConfig(t *testing.T) {
+	config, err := newCacheConfig(0, nil)
+	require.NoError(t, err)
+	assert.Nil(t, config, "the cache is disabled without a positive TTL")
+
+	config, err = newCacheConfig(0, map[string]time.Duration{"GetRoles": time.Hour})
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, time.Hour, config.ttls["GetRoles"])
+	assert.Zero(t, config.ttls["GetEnvironments"])
+
+	_, err = newCacheConfig(time.Minute, map[string]time.Duration{"GetStacks": time.Hour})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GetStacks: the method is not cacheable")
+
+	_, err = newCacheConfig(-time.Minute, nil)
+	require.Error(t, err)
+}
+
+// TestParseCacheMethodTTLs verifies parsing of the -cache-method-ttl flag.
+func TestParseCacheMethodTTLs(t *testing.T) {
+	ttls, err := ParseCacheMethodTTLs("GetRoles=10m, GetEnvironments=15s,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"GetRoles": 10 * time.Minute, "GetEnvironments": 15 * time.Second}, ttls)
+
+	ttls, err = ParseCacheMethodTTLs("")
+	require.NoError(t, err)
+	assert.Empty(t, ttls)
+
+	_, err = ParseCacheMethodTTLs("GetRoles")
+	assert.Error(t, err)
+	_, err = ParseCacheMethodTTLs("GetRoles=soon")
+	assert.Error(t, err)
+}
+
+// TestCacheRefreshRegistration verifies that read-only tools and actions
+// accept the refresh argument when caching is enabled.
+func TestCacheRefreshRegistration(t *testing.T) {
+	t.Run("meta-tools", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		config, err := newCacheConfig(time.Minute, nil)
+		require.NoError(t, err)
+		s.cacheStats = newCacheStats()
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetUsers").Return([]models.User{}, nil)
+		s.cli = newCachingClient(mockClient, config, s.cacheStats)
+		s.RegisterMetaTools()
+
+		properties := listToolProperties(t, s.srv)
+		assert.Contains(t, properties["manage_users"], refreshParam)
+
+		call := func(arguments string) {
+			resp := s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"manage_users","arguments":`+arguments+`}}`))
+			_, err := json.Marshal(resp)
+			require.NoError(t, err)
+		}
+		call(`{"action":"list_users"}`)
+		call(`{"action":"list_users"}`)
+		mockClient.AssertNumberOfCalls(t, "GetUsers", 1)
+		call(`{"action":"list_users","refresh":true}`)
+		mockClient.AssertNumberOfCalls(t, "GetUsers", 2)
+	})
+
+	t.Run("granular tools", func(t *testing.T) {
+		readOnly := true
+		s := newTestMetaServer(false)
+		s.cacheStats = newCacheStats()
+		s.tools = map[string]mcp.Tool{
+			ToolListRoles:  {Name: ToolListRoles, Annotations: mcp.ToolAnnotation{ReadOnlyHint: &readOnly}},
+			ToolDeleteUser: {Name: ToolDeleteUser},
+		}
+		s.addToolIfExists(ToolListRoles, okHandler)
+		s.addToolIfExists(ToolDeleteUser, okHandler)
+
+		properties := listToolProperties(t, s.srv)
+		assert.Contains(t, properties[ToolListRoles], refreshParam)
+		assert.NotContains(t, properties[ToolDeleteUser], refreshParam)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.RegisterMetaTools()
+
+		properties := listToolProperties(t, s.srv)
+		assert.NotContains(t, properties["manage_users"], refreshParam)
+	})
+}
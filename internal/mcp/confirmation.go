@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// confirmationTokenParam is the argument that carries a confirmation token.
+	confirmationTokenParam = "confirmationToken"
+
+	// confirmationTokenParamDescription documents the confirmationToken argument added to destructive tools.
+	confirmationTokenParamDescription = "Confirmation token returned by a first call without it. Destructive actions only run when called again with the same arguments and this token"
+
+	// confirmationTokenTTL is how long a confirmation token can be used after it is issued.
+	confirmationTokenTTL = 2 * time.Minute
+)
+
+// confirmationRequest is returned by the first call of a destructive action
+// when confirmation is required. It describes what the call would do and
+// carries the token that allows the second call to run.
+type confirmationRequest struct {
+	ConfirmationRequired bool               `json:"confirmationRequired"`
+	ConfirmationToken    string             `json:"confirmationToken"`
+	ExpiresAt            time.Time          `json:"expiresAt"`
+	Message              string             `json:"message"`
+	Tool                 string             `json:"tool,omitempty"`
+	Action               string             `json:"action,omitempty"`
+	Operations           []plannedOperation `json:"operations"`
+	Warnings             []string           `json:"warnings,omitempty"`
+}
+
+// pendingConfirmation is an issued confirmation token that has not been used yet.
+type pendingConfirmation struct {
+	digest    string
+	expiresAt time.Time
+}
+
+// confirmationStore holds the issued confirmation tokens. Each token is bound
+// to the digest of one tool call and can be used once before it expires.
+type confirmationStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	pending map[string]pendingConfirmation
+}
+
+// newConfirmationStore creates an empty store whose tokens expire after ttl.
+func newConfirmationStore(ttl time.Duration) *confirmationStore {
+	return &confirmationStore{
+		ttl:     ttl,
+		now:     time.Now,
+		pending: make(map[string]pendingConfirmation),
+	}
+}
+
+// issue creates a token for the tool call with the given digest.
+func (c *confirmationStore) issue(digest string) (string, time.Time, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, pending := range c.pending {
+		if now.After(pending.expiresAt) {
+			delete(c.pending, key)
+		}
+	}
+
+	expiresAt := now.Add(c.ttl)
+	c.pending[token] = pendingConfirmation{digest: digest, expiresAt: expiresAt}
+	return token, expiresAt, nil
+}
+
+// consume checks that token was issued for the tool call with the given digest
+// and has not expired, and invalidates it. A token presented with different
+// arguments stays valid for the call it was issued for.
+func (c *confirmationStore) consume(token, digest string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.pending[token]
+	if !ok || c.now().After(pending.expiresAt) {
+		delete(c.pending, token)
+		return errors.New("confirmation token is invalid, expired, or already used")
+	}
+	if pending.digest != digest {
+		return errors.New("confirmation token was issued for a call with different arguments")
+	}
+
+	delete(c.pending, token)
+	return nil
+}
+
+// withConfirmation wraps a destructive tool handler so that, when confirmation
+// is required, it runs in two phases. A call without a confirmation token is
+// planned as in a dry run and returns the planned operations with a token; a
+// call with a valid token for the same tool, arguments, and caller runs the
+// handler. Dry runs, and calls that plan no write such as a GET through a
+// proxy tool, need no token.
+func (s *PortainerMCPServer) withConfirmation(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.isDryRun(request) {
+			return next(ctx, request)
+		}
+
+		token, err := toolgen.NewParameterParser(request).GetString(confirmationTokenParam, false)
+		if err != nil {
+			return toolErrorFromErr("invalid confirmationToken parameter", err), nil
+		}
+
+		digest, err := s.confirmationDigest(ctx, request)
+		if err != nil {
+			return toolErrorFromErr("failed to confirm destructive action", err), nil
+		}
+
+		if token != "" {
+			if err := s.confirmations.consume(token, digest); err != nil {
+				return toolErrorFromErr("destructive action not confirmed; call again without confirmationToken to get a new token", err), nil
+			}
+			return next(ctx, request)
+		}
+
+		plan, result, err := planToolCall(ctx, next, request)
+		if plan == nil {
+			return result, err
+		}
+
+		token, expiresAt, err := s.confirmations.issue(digest)
+		if err != nil {
+			return toolErrorFromErr("failed to confirm destructive action", err), nil
+		}
+
+		return jsonResult(confirmationRequest{
+			ConfirmationRequired: true,
+			ConfirmationToken:    token,
+			ExpiresAt:            expiresAt.UTC(),
+			Message:              fmt.Sprintf("This action is destructive and was not executed. Review the operations below, then call the tool again with the same arguments and confirmationToken set to this token before %s to execute it.", expiresAt.UTC().Format(time.RFC3339)),
+			Tool:                 plan.Tool,
+			Action:               plan.Action,
+			Operations:           plan.Operations,
+			Warnings:             plan.Warnings,
+		}, "failed to marshal confirmation request")
+	}
+}
+
+// confirmationDigest identifies a tool call by its tool name, its arguments
+// other than the confirmation token, and the caller's session and credential,
+// so that a token cannot be replayed with other arguments or by another client.
+func (s *PortainerMCPServer) confirmationDigest(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	args := maps.Clone(request.GetArguments())
+	delete(args, confirmationTokenParam)
+	delete(args, dryRunParam)
+
+	caller := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		caller = session.SessionID()
+	}
+	if cred, ok := credentialFromContext(ctx); ok {
+		caller += ":" + tokenFingerprint(cred.token)
+	}
+
+	// Maps are marshalled with sorted keys, so equal arguments give equal digests.
+	data, err := json.Marshal(map[string]any{
+		"tool":      request.Params.Name,
+		"arguments": args,
+		"caller":    caller,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// isDestructiveTool reports whether a tool is annotated as destructive.
+func isDestructiveTool(tool mcp.Tool) bool {
+	return tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint
+}
+
+// requiresConfirmation reports whether calls of the named granular tool, or of
+// the meta-tool actions that map to it, need a confirmation token.
+func (s *PortainerMCPServer) requiresConfirmation(toolName string) bool {
+	if s.confirmations == nil {
+		return false
+	}
+	tool, ok := s.tools[toolName]
+	return ok && isDestructiveTool(tool)
+}
+
+// withConfirmationTokenParameter returns a copy of a tool whose input schema accepts the confirmationToken argument.
+func withConfirmationTokenParameter(tool mcp.Tool) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	properties[confirmationTokenParam] = map[string]any{
+		"type":        "string",
+		"description": confirmationTokenParamDescription,
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
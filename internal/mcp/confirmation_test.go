@@ -0,0 +1,291 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestConfirmationServer returns a test server that requires confirmation of destructive tools.
+func newTestConfirmationServer() *PortainerMCPServer {
+	s := newTestMetaServer(false)
+	s.confirmations = newConfirmationStore(confirmationTokenTTL)
+	return s
+}
+
+// requestConfirmation calls a handler wrapped by withConfirmation without a
+// token and decodes the confirmation request it returns.
+func requestConfirmation(t *testing.T, s *PortainerMCPServer, handler server.ToolHandlerFunc, args map[string]any) confirmationRequest {
+	t.Helper()
+
+	result, err := s.withConfirmation(handler)(context.Background(), CreateMCPRequest(args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error result: %v", result.Content)
+
+	var confirmation confirmationRequest
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+	return confirmation
+}
+
+// withToken returns a copy of args with the confirmation token set.
+func withToken(args map[string]any, token string) map[string]any {
+	confirmed := map[string]any{confirmationTokenParam: token}
+	for key, value := range args {
+		confirmed[key] = value
+	}
+	return confirmed
+}
+
+// TestConfirmationTwoPhase verifies that a destructive call only runs when
+// called again with the token returned by the first call.
+func TestConfirmationTwoPhase(t *testing.T) {
+	s := newTestConfirmationServer()
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("InspectStack", 7).Return(models.RegularStack{ID: 7, Name: "web", EndpointID: 2}, nil)
+	mockClient.On("DeleteStack", 7, 2, true).Return(nil).Once()
+	args := map[string]any{"id": float64(7), "environmentId": float64(2), "removeVolumes": true}
+	handler := s.withConfirmation(s.HandleDeleteStack())
+
+	confirmation := requestConfirmation(t, s, s.HandleDeleteStack(), args)
+
+	assert.True(t, confirmation.ConfirmationRequired)
+	assert.Len(t, confirmation.ConfirmationToken, 32)
+	assert.WithinDuration(t, time.Now().Add(confirmationTokenTTL), confirmation.ExpiresAt, time.Minute)
+	require.Len(t, confirmation.Operations, 1)
+	assert.Equal(t, "DeleteStack", confirmation.Operations[0].Operation)
+	assert.Equal(t, "web", confirmation.Operations[0].Current.(map[string]any)["name"])
+	mockClient.AssertNotCalled(t, "DeleteStack", mock.Anything, mock.Anything, mock.Anything)
+
+	result, err := handler(context.Background(), CreateMCPRequest(withToken(args, confirmation.ConfirmationToken)))
+	require.NoError(t, err)
+	assert.False(t, result.IsError, "unexpected error result: %v", result.Content)
+	mockClient.AssertCalled(t, "DeleteStack", 7, 2, true)
+
+	t.Run("token is single use", func(t *testing.T) {
+		result, err := handler(context.Background(), CreateMCPRequest(withToken(args, confirmation.ConfirmationToken)))
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid, expired, or already used")
+		mockClient.AssertNumberOfCalls(t, "DeleteStack", 1)
+	})
+}
+
+// TestConfirmationRejectsInvalidTokens verifies that tokens are bound to the
+// arguments and the caller of the call they were issued for, and expire.
+func TestConfirmationRejectsInvalidTokens(t *testing.T) {
+	args := map[string]any{"id": float64(7), "environmentId": float64(2), "removeVolumes": true}
+
+	tests := []struct {
+		name          string
+		call          func(s *PortainerMCPServer, token string) map[string]any
+		ctx           func(s *PortainerMCPServer) context.Context
+		expire        bool
+		errorContains string
+	}{
+		{
+			name: "different arguments",
+			call: func(s *PortainerMCPServer, token string) map[string]any {
+				return withToken(map[string]any{"id": float64(8), "environmentId": float64(2), "removeVolumes": true}, token)
+			},
+			errorContains: "issued for a call with different arguments",
+		},
+		{
+			name: "different session",
+			call: func(s *PortainerMCPServer, token string) map[string]any {
+				return withToken(args, token)
+			},
+			ctx: func(s *PortainerMCPServer) context.Context {
+				return s.srv.WithContext(context.Background(), testSession{id: "other"})
+			},
+			errorContains: "issued for a call with different arguments",
+		},
+		{
+			name: "different credential",
+			call: func(s *PortainerMCPServer, token string) map[string]any {
+				return withToken(args, token)
+			},
+			ctx: func(s *PortainerMCPServer) context.Context {
+				return withCredential(context.Background(), portainerCredential{token: "other-token"})
+			},
+			errorContains: "issued for a call with different arguments",
+		},
+		{
+			name: "unknown token",
+			call: func(s *PortainerMCPServer, token string) map[string]any {
+				return withToken(args, "0123456789abcdef0123456789abcdef")
+			},
+			errorContains: "invalid, expired, or already used",
+		},
+		{
+			name: "expired token",
+			call: func(s *PortainerMCPServer, token string) map[string]any {
+				return withToken(args, token)
+			},
+			expire:        true,
+			errorContains: "invalid, expired, or already used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestConfirmationServer()
+			now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			s.confirmations.now = func() time.Time { return now }
+			s.cli.(*MockPortainerClient).On("InspectStack", mock.Anything).Return(models.RegularStack{ID: 7}, nil)
+
+			confirmation := requestConfirmation(t, s, s.HandleDeleteStack(), args)
+			if tt.expire {
+				now = now.Add(confirmationTokenTTL + time.Second)
+			}
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(s)
+			}
+
+			result, err := s.withConfirmation(s.HandleDeleteStack())(ctx, CreateMCPRequest(tt.call(s, confirmation.ConfirmationToken)))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.errorContains)
+			s.cli.(*MockPortainerClient).AssertNotCalled(t, "DeleteStack", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestConfirmationTokenSurvivesMismatch verifies that presenting a token with
+// other arguments does not invalidate it for the call it was issued for.
+func TestConfirmationTokenSurvivesMismatch(t *testing.T) {
+	store := newConfirmationStore(confirmationTokenTTL)
+	token, _, err := store.issue("digest-a")
+	require.NoError(t, err)
+
+	assert.Error(t, store.consume(token, "digest-b"))
+	assert.NoError(t, store.consume(token, "digest-a"))
+	assert.Error(t, store.consume(token, "digest-a"))
+}
+
+// TestConfirmationPassThrough verifies the calls that need no token.
+func TestConfirmationPassThrough(t *testing.T) {
+	t.Run("dry run", func(t *testing.T) {
+		s := newTestConfirmationServer()
+		s.cli.(*MockPortainerClient).On("InspectStack", 7).Return(models.RegularStack{ID: 7}, nil)
+
+		result, err := s.withConfirmation(s.withDryRun(s.HandleDeleteStack()))(context.Background(), CreateMCPRequest(map[string]any{
+			"id":            float64(7),
+			"environmentId": float64(2),
+			"dryRun":        true,
+		}))
+
+		require.NoError(t, err)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"dryRun":true`)
+		assert.NotContains(t, result.Content[0].(mcp.TextContent).Text, confirmationTokenParam)
+	})
+
+	t.Run("proxy read", func(t *testing.T) {
+		s := newTestConfirmationServer()
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("ProxyDockerRequest", mock.Anything).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       http.NoBody,
+		}, nil)
+
+		result, err := s.withConfirmation(s.HandleDockerProxy())(context.Background(), CreateMCPRequest(map[string]any{
+			"environmentId": float64(1),
+			"method":        "GET",
+			"dockerAPIPath": "/containers/json",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, "unexpected error result: %v", result.Content)
+		mockClient.AssertCalled(t, "ProxyDockerRequest", mock.Anything)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		s := newTestConfirmationServer()
+
+		result, err := s.withConfirmation(s.HandleDeleteStack())(context.Background(), CreateMCPRequest(map[string]any{
+			"id": float64(-1),
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Empty(t, s.confirmations.pending)
+	})
+}
+
+// TestConfirmationTokenRegistration verifies that only destructive tools, and
+// meta-tools with destructive actions, accept confirmationToken, and only when
+// confirmation is required.
+func TestConfirmationTokenRegistration(t *testing.T) {
+	destructive, safe := true, false
+	tools := map[string]mcp.Tool{
+		"writeTool": {
+			Name:        "writeTool",
+			Annotations: mcp.ToolAnnotation{ReadOnlyHint: &safe, DestructiveHint: &safe},
+		},
+		ToolDeleteStack: {
+			Name:        ToolDeleteStack,
+			Annotations: mcp.ToolAnnotation{ReadOnlyHint: &safe, DestructiveHint: &destructive},
+		},
+	}
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	t.Run("confirmation required", func(t *testing.T) {
+		s := newTestConfirmationServer()
+		s.tools = tools
+		s.addToolIfExists("writeTool", handler)
+		s.addToolIfExists(ToolDeleteStack, handler)
+		s.RegisterMetaTools()
+
+		properties := listToolProperties(t, s.srv)
+		assert.NotContains(t, properties["writeTool"], confirmationTokenParam)
+		assert.Contains(t, properties[ToolDeleteStack], confirmationTokenParam)
+		assert.Contains(t, properties["manage_stacks"], confirmationTokenParam)
+		assert.NotContains(t, properties["manage_settings"], confirmationTokenParam)
+		assert.NotContains(t, s.tools[ToolDeleteStack].InputSchema.Properties, confirmationTokenParam, "the loaded tool definition must not be modified")
+	})
+
+	t.Run("confirmation not required", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.tools = tools
+		s.addToolIfExists(ToolDeleteStack, handler)
+		s.RegisterMetaTools()
+
+		properties := listToolProperties(t, s.srv)
+		assert.NotContains(t, properties[ToolDeleteStack], confirmationTokenParam)
+		assert.NotContains(t, properties["manage_stacks"], confirmationTokenParam)
+	})
+}
+
+// TestMetaActionToolMapping verifies that every meta-tool action names a
+// granular tool of tools.yaml with the same read-only annotation, so that
+// the destructive annotations apply to meta-tool actions too.
+func TestMetaActionToolMapping(t *testing.T) {
+	tools, err := toolgen.LoadToolsFromYAML("../../tools.yaml", MinimumToolsVersion)
+	require.NoError(t, err)
+
+	mapped := map[string]bool{}
+	for _, def := range metaToolDefinitions() {
+		for _, action := range def.actions {
+			tool, ok := tools[action.tool]
+			if !assert.True(t, ok, "%s action %s maps to unknown tool %q", def.name, action.name, action.tool) {
+				continue
+			}
+			assert.Equal(t, isReadOnlyTool(tool), action.readOnly, "%s action %s", def.name, action.name)
+			mapped[action.tool] = true
+		}
+	}
+	assert.Len(t, mapped, len(tools), "every tool must be reachable through exactly one meta-tool action")
+}
@@ -0,0 +1,371 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AddDockerContainerFeatures registers the Docker container management tools on the MCP server.
+func (s *PortainerMCPServer) AddDockerContainerFeatures() {
+	s.addToolIfExists(ToolListDockerContainers, s.HandleListDockerContainers())
+	s.addToolIfExists(ToolInspectDockerContainer, s.HandleInspectDockerContainer())
+	s.addToolIfExists(ToolGetDockerContainerLogs, s.HandleGetDockerContainerLogs())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolStartDockerContainer, s.HandleStartDockerContainer())
+		s.addToolIfExists(ToolStopDockerContainer, s.HandleStopDockerContainer())
+		s.addToolIfExists(ToolRestartDockerContainer, s.HandleRestartDockerContainer())
+		s.addToolIfExists(ToolKillDockerContainer, s.HandleKillDockerContainer())
+		s.addToolIfExists(ToolRemoveDockerContainer, s.HandleRemoveDockerContainer())
+		s.addToolIfExists(ToolRenameDockerContainer, s.HandleRenameDockerContainer())
+		s.addToolIfExists(ToolExecDockerContainer, s.HandleExecDockerContainer())
+	}
+}
+
+// HandleListDockerContainers returns an MCP tool handler that lists the containers of a Docker environment.
+func (s *PortainerMCPServer) HandleListDockerContainers() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
+		}
+		if err := validatePositiveID("environmentId", environmentId); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		all, err := parser.GetBoolean("all", false)
+		if err != nil {
+			return toolErrorFromErr("invalid all parameter", err), nil
+		}
+
+		filters, err := parser.GetArrayOfObjects("filters", false)
+		if err != nil {
+			return toolErrorFromErr("invalid filters parameter", err), nil
+		}
+		filtersMap, err := parseKeyValuesMap(filters)
+		if err != nil {
+			return toolErrorFromErr("invalid filters", err), nil
+		}
+
+		containers, err := s.client(ctx).ListDockerContainers(ctx, environmentId, models.DockerContainerListOptions{
+			All:     all,
+			Filters: filtersMap,
+		})
+		if err != nil {
+			return toolErrorFromErr("failed to list docker containers", err), nil
+		}
+
+		return jsonResult(containers, "failed to marshal docker containers")
+	}
+}
+
+// HandleInspectDockerContainer returns an MCP tool handler that retrieves the details of a Docker container.
+func (s *PortainerMCPServer) HandleInspectDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		container, err := s.client(ctx).InspectDockerContainer(ctx, environmentId, containerId)
+		if err != nil {
+			return toolErrorFromErr("failed to inspect docker container", err), nil
+		}
+
+		return jsonResult(container, "failed to marshal docker container")
+	}
+}
+
+// HandleGetDockerContainerLogs returns an MCP tool handler that retrieves the logs of a Docker container.
+// Each line of the result is prefixed with the stream it was written to.
+func (s *PortainerMCPServer) HandleGetDockerContainerLogs() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		tail := defaultContainerLogTail
+		if _, ok := request.GetArguments()["tail"]; ok {
+			tail, err = parser.GetInt("tail", false)
+			if err != nil {
+				return toolErrorFromErr("invalid tail parameter", err), nil
+			}
+			if tail < 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("tail must be zero or a positive number of lines, got %d", tail)), nil
+			}
+		}
+
+		now := time.Now()
+		since, err := parseLogTimeParam(parser, "since", now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		until, err := parseLogTimeParam(parser, "until", now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if since > 0 && until > 0 && since > until {
+			return mcp.NewToolResultError("since must not be later than until"), nil
+		}
+
+		timestamps, err := parser.GetBoolean("timestamps", false)
+		if err != nil {
+			return toolErrorFromErr("invalid timestamps parameter", err), nil
+		}
+
+		logs, err := s.client(ctx).GetDockerContainerLogs(ctx, environmentId, containerId, models.DockerContainerLogOptions{
+			Tail:       tail,
+			Since:      since,
+			Until:      until,
+			Timestamps: timestamps,
+		})
+		if err != nil {
+			return toolErrorFromErr("failed to get docker container logs", err), nil
+		}
+
+		return mcp.NewToolResultText(formatContainerLogs(containerId, logs)), nil
+	}
+}
+
+// HandleStartDockerContainer returns an MCP tool handler that starts a Docker container.
+func (s *PortainerMCPServer) HandleStartDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := s.client(ctx).StartDockerContainer(ctx, environmentId, containerId); err != nil {
+			return toolErrorFromErr("failed to start docker container", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s started successfully", containerId)), nil
+	}
+}
+
+// HandleStopDockerContainer returns an MCP tool handler that stops a Docker container.
+func (s *PortainerMCPServer) HandleStopDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		timeout, err := parseContainerTimeout(request, parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := s.client(ctx).StopDockerContainer(ctx, environmentId, containerId, timeout); err != nil {
+			return toolErrorFromErr("failed to stop docker container", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s stopped successfully", containerId)), nil
+	}
+}
+
+// HandleRestartDockerContainer returns an MCP tool handler that restarts a Docker container.
+func (s *PortainerMCPServer) HandleRestartDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		timeout, err := parseContainerTimeout(request, parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := s.client(ctx).RestartDockerContainer(ctx, environmentId, containerId, timeout); err != nil {
+			return toolErrorFromErr("failed to restart docker container", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s restarted successfully", containerId)), nil
+	}
+}
+
+// HandleKillDockerContainer returns an MCP tool handler that sends a signal to a Docker container.
+func (s *PortainerMCPServer) HandleKillDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		signal, err := parser.GetString("signal", false)
+		if err != nil {
+			return toolErrorFromErr("invalid signal parameter", err), nil
+		}
+
+		if err := s.client(ctx).KillDockerContainer(ctx, environmentId, containerId, strings.TrimSpace(signal)); err != nil {
+			return toolErrorFromErr("failed to kill docker container", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s killed successfully", containerId)), nil
+	}
+}
+
+// HandleRemoveDockerContainer returns an MCP tool handler that removes a Docker container.
+func (s *PortainerMCPServer) HandleRemoveDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		force, err := parser.GetBoolean("force", false)
+		if err != nil {
+			return toolErrorFromErr("invalid force parameter", err), nil
+		}
+
+		removeVolumes, err := parser.GetBoolean("removeVolumes", false)
+		if err != nil {
+			return toolErrorFromErr("invalid removeVolumes parameter", err), nil
+		}
+
+		if err := s.client(ctx).RemoveDockerContainer(ctx, environmentId, containerId, force, removeVolumes); err != nil {
+			return toolErrorFromErr("failed to remove docker container", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s removed successfully", containerId)), nil
+	}
+}
+
+// HandleRenameDockerContainer returns an MCP tool handler that renames a Docker container.
+func (s *PortainerMCPServer) HandleRenameDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		name, err := parser.GetString("name", true)
+		if err != nil {
+			return toolErrorFromErr("invalid name parameter", err), nil
+		}
+		if err := validateName(name); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := s.client(ctx).RenameDockerContainer(ctx, environmentId, containerId, name); err != nil {
+			return toolErrorFromErr("failed to rename docker container", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Container %s renamed to %s successfully", containerId, name)), nil
+	}
+}
+
+// parseContainerTarget parses and validates the environmentId and containerId
+// parameters shared by all single-container tools.
+func parseContainerTarget(parser *toolgen.ParameterParser) (int, string, error) {
+	environmentId, err := parser.GetInt("environmentId", true)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+	if err := validatePositiveID("environmentId", environmentId); err != nil {
+		return 0, "", err
+	}
+
+	containerId, err := parser.GetString("containerId", true)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid containerId parameter: %w", err)
+	}
+	containerId = strings.TrimSpace(containerId)
+	if containerId == "" {
+		return 0, "", fmt.Errorf("containerId cannot be empty or whitespace-only")
+	}
+
+	return environmentId, containerId, nil
+}
+
+// parseContainerTimeout parses the optional stop timeout in seconds. It returns
+// nil when the parameter is absent so that the container's own default applies.
+func parseContainerTimeout(request mcp.CallToolRequest, parser *toolgen.ParameterParser) (*int, error) {
+	if _, ok := request.GetArguments()["timeout"]; !ok {
+		return nil, nil
+	}
+
+	timeout, err := parser.GetInt("timeout", false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout parameter: %w", err)
+	}
+	if timeout < 0 {
+		return nil, fmt.Errorf("timeout must be zero or a positive number of seconds, got %d", timeout)
+	}
+
+	return &timeout, nil
+}
+
+// defaultContainerLogTail is the number of log lines returned when the tail parameter is omitted.
+const defaultContainerLogTail = 100
+
+// parseLogTimeParam parses an optional log time bound and returns it as a UNIX
+// timestamp in seconds, or 0 when the parameter is absent. The value may be a
+// UNIX timestamp, an RFC3339 date, or a duration that is subtracted from now.
+func parseLogTimeParam(parser *toolgen.ParameterParser, name string, now time.Time) (int64, error) {
+	value, err := parser.GetString(name, false)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter: %w", name, err)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	if ts, err := strconv.ParseInt(value, 10, 64); err == nil && ts > 0 {
+		return ts, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Unix(), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(-d).Unix(), nil
+	}
+
+	return 0, fmt.Errorf("invalid %s parameter: %q is not a UNIX timestamp, an RFC3339 date, or a positive duration", name, value)
+}
+
+// formatContainerLogs renders container logs as text with one "[stream] line" entry per line.
+func formatContainerLogs(containerId string, logs models.DockerContainerLogs) string {
+	if len(logs.Lines) == 0 {
+		return fmt.Sprintf("No log output for container %s", containerId)
+	}
+
+	var b strings.Builder
+	if logs.Truncated {
+		b.WriteString("... older log output truncated, only the newest lines are shown; use tail, since, or until to narrow the range\n")
+	}
+	for _, line := range logs.Lines {
+		fmt.Fprintf(&b, "[%s] %s\n", line.Stream, line.Text)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,535 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHandleListDockerContainers verifies the HandleListDockerContainers MCP tool handler.
+func TestHandleListDockerContainers(t *testing.T) {
+	containers := []models.DockerContainer{{ID: "abc", Name: "web", State: "running"}}
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		setupMock   func(m *MockPortainerClient)
+		expectError string
+	}{
+		{
+			name:   "running containers only",
+			params: map[string]any{"environmentId": float64(1)},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ListDockerContainers", 1, models.DockerContainerListOptions{Filters: map[string][]string{}}).Return(containers, nil)
+			},
+		},
+		{
+			name: "all containers with repeated filters",
+			params: map[string]any{
+				"environmentId": float64(1),
+				"all":           true,
+				"filters": []any{
+					map[string]any{"key": "label", "value": "app=web"},
+					map[string]any{"key": "label", "value": "tier=front"},
+					map[string]any{"key": "status", "value": "exited"},
+				},
+			},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ListDockerContainers", 1, models.DockerContainerListOptions{
+					All: true,
+					Filters: map[string][]string{
+						"label":  {"app=web", "tier=front"},
+						"status": {"exited"},
+					},
+				}).Return(containers, nil)
+			},
+		},
+		{
+			name:        "missing environmentId",
+			params:      map[string]any{},
+			expectError: "environmentId",
+		},
+		{
+			name:        "invalid environmentId",
+			params:      map[string]any{"environmentId": float64(0)},
+			expectError: "environmentId must be a positive integer",
+		},
+		{
+			name:        "invalid filter entry",
+			params:      map[string]any{"environmentId": float64(1), "filters": []any{map[string]any{"key": "label"}}},
+			expectError: "invalid filters",
+		},
+		{
+			name:   "api error",
+			params: map[string]any{"environmentId": float64(1)},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ListDockerContainers", 1, models.DockerContainerListOptions{Filters: map[string][]string{}}).Return(nil, fmt.Errorf("docker down"))
+			},
+			expectError: "failed to list docker containers: docker down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+
+			s := &PortainerMCPServer{cli: mockClient}
+			result, err := s.HandleListDockerContainers()(context.Background(), CreateMCPRequest(tt.params))
+			require.NoError(t, err)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.expectError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tt.expectError)
+			} else {
+				assert.False(t, result.IsError)
+				var got []models.DockerContainer
+				require.NoError(t, json.Unmarshal([]byte(text), &got))
+				assert.Equal(t, containers, got)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandleDockerContainerOperations verifies the single-container MCP tool handlers,
+// covering the shared environmentId/containerId validation and each client call.
+func TestHandleDockerContainerOperations(t *testing.T) {
+	timeout := 5
+	zero := 0
+	target := map[string]any{"environmentId": float64(3), "containerId": " web "}
+	withTarget := func(extra map[string]any) map[string]any {
+		params := map[string]any{}
+		for k, v := range target {
+			params[k] = v
+		}
+		for k, v := range extra {
+			params[k] = v
+		}
+		return params
+	}
+
+	tests := []struct {
+		name        string
+		handler     func(s *PortainerMCPServer) server.ToolHandlerFunc
+		params      map[string]any
+		setupMock   func(m *MockPortainerClient)
+		expectText  string
+		expectError string
+	}{
+		{
+			name:    "inspect",
+			handler: (*PortainerMCPServer).HandleInspectDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("InspectDockerContainer", 3, "web").Return(models.DockerContainerDetails{ID: "abc", Name: "web"}, nil)
+			},
+			expectText: `"name":"web"`,
+		},
+		{
+			name:    "inspect api error",
+			handler: (*PortainerMCPServer).HandleInspectDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("InspectDockerContainer", 3, "web").Return(models.DockerContainerDetails{}, fmt.Errorf("no such container"))
+			},
+			expectError: "failed to inspect docker container: no such container",
+		},
+		{
+			name:        "missing containerId",
+			handler:     (*PortainerMCPServer).HandleInspectDockerContainer,
+			params:      map[string]any{"environmentId": float64(3)},
+			expectError: "invalid containerId parameter",
+		},
+		{
+			name:        "blank containerId",
+			handler:     (*PortainerMCPServer).HandleStartDockerContainer,
+			params:      map[string]any{"environmentId": float64(3), "containerId": "  "},
+			expectError: "containerId cannot be empty",
+		},
+		{
+			name:        "missing environmentId",
+			handler:     (*PortainerMCPServer).HandleStartDockerContainer,
+			params:      map[string]any{"containerId": "web"},
+			expectError: "invalid environmentId parameter",
+		},
+		{
+			name:        "invalid environmentId",
+			handler:     (*PortainerMCPServer).HandleStartDockerContainer,
+			params:      map[string]any{"environmentId": float64(-1), "containerId": "web"},
+			expectError: "environmentId must be a positive integer",
+		},
+		{
+			name:    "start",
+			handler: (*PortainerMCPServer).HandleStartDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("StartDockerContainer", 3, "web").Return(nil)
+			},
+			expectText: "Container web started successfully",
+		},
+		{
+			name:    "start api error",
+			handler: (*PortainerMCPServer).HandleStartDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("StartDockerContainer", 3, "web").Return(fmt.Errorf("boom"))
+			},
+			expectError: "failed to start docker container: boom",
+		},
+		{
+			name:    "stop with default timeout",
+			handler: (*PortainerMCPServer).HandleStopDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("StopDockerContainer", 3, "web", (*int)(nil)).Return(nil)
+			},
+			expectText: "Container web stopped successfully",
+		},
+		{
+			name:    "stop with zero timeout",
+			handler: (*PortainerMCPServer).HandleStopDockerContainer,
+			params:  withTarget(map[string]any{"timeout": float64(0)}),
+			setupMock: func(m *MockPortainerClient) {
+				m.On("StopDockerContainer", 3, "web", &zero).Return(nil)
+			},
+			expectText: "Container web stopped successfully",
+		},
+		{
+			name:        "stop with negative timeout",
+			handler:     (*PortainerMCPServer).HandleStopDockerContainer,
+			params:      withTarget(map[string]any{"timeout": float64(-1)}),
+			expectError: "timeout must be zero or a positive number",
+		},
+		{
+			name:        "stop with invalid timeout type",
+			handler:     (*PortainerMCPServer).HandleStopDockerContainer,
+			params:      withTarget(map[string]any{"timeout": "soon"}),
+			expectError: "invalid timeout parameter",
+		},
+		{
+			name:    "restart with timeout",
+			handler: (*PortainerMCPServer).HandleRestartDockerContainer,
+			params:  withTarget(map[string]any{"timeout": float64(5)}),
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RestartDockerContainer", 3, "web", &timeout).Return(nil)
+			},
+			expectText: "Container web restarted successfully",
+		},
+		{
+			name:    "restart api error",
+			handler: (*PortainerMCPServer).HandleRestartDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RestartDockerContainer", 3, "web", (*int)(nil)).Return(fmt.Errorf("boom"))
+			},
+			expectError: "failed to restart docker container: boom",
+		},
+		{
+			name:    "kill with default signal",
+			handler: (*PortainerMCPServer).HandleKillDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("KillDockerContainer", 3, "web", "").Return(nil)
+			},
+			expectText: "Container web killed successfully",
+		},
+		{
+			name:    "kill with signal",
+			handler: (*PortainerMCPServer).HandleKillDockerContainer,
+			params:  withTarget(map[string]any{"signal": "SIGHUP"}),
+			setupMock: func(m *MockPortainerClient) {
+				m.On("KillDockerContainer", 3, "web", "SIGHUP").Return(nil)
+			},
+			expectText: "Container web killed successfully",
+		},
+		{
+			name:    "kill api error",
+			handler: (*PortainerMCPServer).HandleKillDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("KillDockerContainer", 3, "web", "").Return(fmt.Errorf("not running"))
+			},
+			expectError: "failed to kill docker container: not running",
+		},
+		{
+			name:    "remove with defaults",
+			handler: (*PortainerMCPServer).HandleRemoveDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RemoveDockerContainer", 3, "web", false, false).Return(nil)
+			},
+			expectText: "Container web removed successfully",
+		},
+		{
+			name:    "remove forced with volumes",
+			handler: (*PortainerMCPServer).HandleRemoveDockerContainer,
+			params:  withTarget(map[string]any{"force": true, "removeVolumes": true}),
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RemoveDockerContainer", 3, "web", true, true).Return(nil)
+			},
+			expectText: "Container web removed successfully",
+		},
+		{
+			name:        "remove with invalid force",
+			handler:     (*PortainerMCPServer).HandleRemoveDockerContainer,
+			params:      withTarget(map[string]any{"force": "yes"}),
+			expectError: "invalid force parameter",
+		},
+		{
+			name:    "remove api error",
+			handler: (*PortainerMCPServer).HandleRemoveDockerContainer,
+			params:  target,
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RemoveDockerContainer", 3, "web", false, false).Return(fmt.Errorf("container is running"))
+			},
+			expectError: "failed to remove docker container: container is running",
+		},
+		{
+			name:    "rename",
+			handler: (*PortainerMCPServer).HandleRenameDockerContainer,
+			params:  withTarget(map[string]any{"name": "api"}),
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RenameDockerContainer", 3, "web", "api").Return(nil)
+			},
+			expectText: "Container web renamed to api successfully",
+		},
+		{
+			name:        "rename without name",
+			handler:     (*PortainerMCPServer).HandleRenameDockerContainer,
+			params:      target,
+			expectError: "invalid name parameter",
+		},
+		{
+			name:        "rename with blank name",
+			handler:     (*PortainerMCPServer).HandleRenameDockerContainer,
+			params:      withTarget(map[string]any{"name": " "}),
+			expectError: "name cannot be empty",
+		},
+		{
+			name:    "rename api error",
+			handler: (*PortainerMCPServer).HandleRenameDockerContainer,
+			params:  withTarget(map[string]any{"name": "api"}),
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RenameDockerContainer", 3, "web", "api").Return(fmt.Errorf("name in use"))
+			},
+			expectError: "failed to rename docker container: name in use",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+
+			s := &PortainerMCPServer{cli: mockClient}
+			result, err := tt.handler(s)(context.Background(), CreateMCPRequest(tt.params))
+			require.NoError(t, err)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.expectError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tt.expectError)
+			} else {
+				assert.False(t, result.IsError)
+				assert.Contains(t, text, tt.expectText)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandleGetDockerContainerLogs verifies the HandleGetDockerContainerLogs MCP tool handler,
+// including parameter defaults, time parsing, and the labelled text output.
+func TestHandleGetDockerContainerLogs(t *testing.T) {
+	logs := models.DockerContainerLogs{Lines: []models.DockerLogLine{
+		{Stream: "stdout", Text: "listening on :80"},
+		{Stream: "stderr", Text: "warning: low memory"},
+	}}
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		setupMock   func(m *MockPortainerClient)
+		expectText  string
+		expectError string
+	}{
+		{
+			name:   "default tail",
+			params: map[string]any{"environmentId": float64(1), "containerId": "web"},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetDockerContainerLogs", 1, "web", models.DockerContainerLogOptions{Tail: 100}).Return(logs, nil)
+			},
+			expectText: "[stdout] listening on :80\n[stderr] warning: low memory\n",
+		},
+		{
+			name: "all lines with time range and timestamps",
+			params: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"tail":          float64(0),
+				"since":         "1735689600",
+				"until":         "2025-01-02T00:00:00Z",
+				"timestamps":    true,
+			},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetDockerContainerLogs", 1, "web", models.DockerContainerLogOptions{
+					Since:      1735689600,
+					Until:      1735776000,
+					Timestamps: true,
+				}).Return(logs, nil)
+			},
+			expectText: "[stderr] warning: low memory",
+		},
+		{
+			name:   "no output",
+			params: map[string]any{"environmentId": float64(1), "containerId": "web", "tail": float64(10)},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetDockerContainerLogs", 1, "web", models.DockerContainerLogOptions{Tail: 10}).Return(models.DockerContainerLogs{}, nil)
+			},
+			expectText: "No log output for container web",
+		},
+		{
+			name:   "truncated output",
+			params: map[string]any{"environmentId": float64(1), "containerId": "web"},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetDockerContainerLogs", 1, "web", models.DockerContainerLogOptions{Tail: 100}).
+					Return(models.DockerContainerLogs{Lines: logs.Lines, Truncated: true}, nil)
+			},
+			expectText: "older log output truncated, only the newest lines are shown",
+		},
+		{
+			name:        "negative tail",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web", "tail": float64(-1)},
+			expectError: "tail must be zero or a positive number of lines",
+		},
+		{
+			name:        "invalid since",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web", "since": "yesterday"},
+			expectError: "invalid since parameter",
+		},
+		{
+			name:        "since after until",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web", "since": "200", "until": "100"},
+			expectError: "since must not be later than until",
+		},
+		{
+			name:        "missing containerId",
+			params:      map[string]any{"environmentId": float64(1)},
+			expectError: "containerId",
+		},
+		{
+			name:   "api error",
+			params: map[string]any{"environmentId": float64(1), "containerId": "web"},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetDockerContainerLogs", 1, "web", models.DockerContainerLogOptions{Tail: 100}).
+					Return(models.DockerContainerLogs{}, fmt.Errorf("no such container"))
+			},
+			expectError: "failed to get docker container logs: no such container",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+
+			s := &PortainerMCPServer{cli: mockClient}
+			result, err := s.HandleGetDockerContainerLogs()(context.Background(), CreateMCPRequest(tt.params))
+			require.NoError(t, err)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.expectError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tt.expectError)
+			} else {
+				assert.False(t, result.IsError)
+				assert.Contains(t, text, tt.expectText)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestParseLogTimeParam verifies the accepted formats of the since/until parameters.
+func TestParseLogTimeParam(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		value       any
+		expected    int64
+		expectError bool
+	}{
+		{name: "absent", expected: 0},
+		{name: "empty", value: "  ", expected: 0},
+		{name: "unix timestamp", value: "1735819200", expected: 1735819200},
+		{name: "rfc3339", value: "2025-01-02T11:00:00Z", expected: now.Add(-time.Hour).Unix()},
+		{name: "relative duration", value: "30m", expected: now.Add(-30 * time.Minute).Unix()},
+		{name: "negative duration", value: "-5m", expectError: true},
+		{name: "garbage", value: "last week", expectError: true},
+		{name: "wrong type", value: true, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{}
+			if tt.value != nil {
+				args["since"] = tt.value
+			}
+
+			got, err := parseLogTimeParam(toolgen.NewParameterParser(CreateMCPRequest(args)), "since", now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+// TestDockerContainerMetaToolReadOnly verifies that read-only mode keeps the
+// container list and inspect actions while hiding the lifecycle actions.
+func TestDockerContainerMetaToolReadOnly(t *testing.T) {
+	var docker metaToolDef
+	for _, def := range metaToolDefinitions() {
+		if def.name == "manage_docker" {
+			docker = def
+		}
+	}
+	require.NotEmpty(t, docker.actions)
+
+	readOnly := map[string]bool{}
+	for _, a := range docker.actions {
+		readOnly[a.name] = a.readOnly
+	}
+
+	assert.True(t, readOnly["list_docker_containers"])
+	assert.True(t, readOnly["inspect_docker_container"])
+	assert.True(t, readOnly["get_docker_container_logs"])
+	for _, action := range []string{
+		"start_docker_container", "stop_docker_container", "restart_docker_container",
+		"kill_docker_container", "remove_docker_container", "rename_docker_container",
+	} {
+		isReadOnly, ok := readOnly[action]
+		assert.True(t, ok, "action %s should be registered", action)
+		assert.False(t, isReadOnly, "action %s should be hidden in read-only mode", action)
+	}
+}
@@ -24,9 +24,9 @@ func (s *PortainerMCPServer) AddCustomTemplateFeatures() {
 // HandleListCustomTemplates returns an MCP tool handler that lists custom templates.
 func (s *PortainerMCPServer) HandleListCustomTemplates() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		templates, err := s.cli.GetCustomTemplates()
+		templates, err := s.client(ctx).GetCustomTemplates(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list custom templates", err), nil
+			return toolErrorFromErr("failed to list custom templates", err), nil
 		}
 
 		return jsonResult(templates, "failed to marshal custom templates")
@@ -40,15 +40,15 @@ func (s *PortainerMCPServer) HandleGetCustomTemplate() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		template, err := s.cli.GetCustomTemplate(id)
+		template, err := s.client(ctx).GetCustomTemplate(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get custom template", err), nil
+			return toolErrorFromErr("failed to get custom template", err), nil
 		}
 
 		return jsonResult(template, "failed to marshal custom template")
@@ -62,15 +62,15 @@ func (s *PortainerMCPServer) HandleGetCustomTemplateFile() server.ToolHandlerFun
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		content, err := s.cli.GetCustomTemplateFile(id)
+		content, err := s.client(ctx).GetCustomTemplateFile(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get custom template file", err), nil
+			return toolErrorFromErr("failed to get custom template file", err), nil
 		}
 
 		return mcp.NewToolResultText(content), nil
@@ -84,22 +84,22 @@ func (s *PortainerMCPServer) HandleCreateCustomTemplate() server.ToolHandlerFunc
 
 		title, err := parser.GetString("title", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid title parameter", err), nil
+			return toolErrorFromErr("invalid title parameter", err), nil
 		}
 
 		description, err := parser.GetString("description", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid description parameter", err), nil
+			return toolErrorFromErr("invalid description parameter", err), nil
 		}
 
 		fileContent, err := parser.GetString("fileContent", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid fileContent parameter", err), nil
+			return toolErrorFromErr("invalid fileContent parameter", err), nil
 		}
 
 		templateType, err := parser.GetInt("type", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
+			return toolErrorFromErr("invalid type parameter", err), nil
 		}
 
 		if !isValidTemplateType(templateType) {
@@ -108,15 +108,15 @@ func (s *PortainerMCPServer) HandleCreateCustomTemplate() server.ToolHandlerFunc
 
 		platform, err := parser.GetInt("platform", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid platform parameter", err), nil
+			return toolErrorFromErr("invalid platform parameter", err), nil
 		}
 
 		note, _ := parser.GetString("note", false)
 		logo, _ := parser.GetString("logo", false)
 
-		id, err := s.cli.CreateCustomTemplate(title, description, note, logo, fileContent, platform, templateType)
+		id, err := s.client(ctx).CreateCustomTemplate(ctx, title, description, note, logo, fileContent, platform, templateType)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create custom template", err), nil
+			return toolErrorFromErr("failed to create custom template", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Custom template created successfully with ID: %d", id)), nil
@@ -130,15 +130,15 @@ func (s *PortainerMCPServer) HandleDeleteCustomTemplate() server.ToolHandlerFunc
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteCustomTemplate(id)
+		err = s.client(ctx).DeleteCustomTemplate(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete custom template", err), nil
+			return toolErrorFromErr("failed to delete custom template", err), nil
 		}
 
 		return mcp.NewToolResultText("Custom template deleted successfully"), nil
@@ -0,0 +1,255 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dryRunParam is the name of the per-call argument that requests a dry run.
+const dryRunParam = "dryRun"
+
+// dryRunParamDescription documents the dryRun argument added to write tools.
+const dryRunParamDescription = "Validate the parameters and return the changes this call would make, without applying them (default: false)"
+
+// dryRunResult is the structured plan returned instead of the tool result in dry-run mode.
+type dryRunResult struct {
+	DryRun     bool               `json:"dryRun"`
+	Tool       string             `json:"tool,omitempty"`
+	Action     string             `json:"action,omitempty"`
+	Operations []plannedOperation `json:"operations"`
+	Warnings   []string           `json:"warnings,omitempty"`
+}
+
+// plannedOperation is a write that a tool call would have sent to Portainer.
+// Current holds the state of the target fetched from Portainer and Changes
+// the difference the write would make, when they can be determined.
+type plannedOperation struct {
+	Operation string         `json:"operation"`
+	Params    map[string]any `json:"params,omitempty"`
+	Current   any            `json:"current,omitempty"`
+	Changes   any            `json:"changes,omitempty"`
+}
+
+// dryRunPlan collects the operations planned during a dry-run tool call.
+type dryRunPlan struct {
+	mu         sync.Mutex
+	operations []plannedOperation
+	warnings   []string
+}
+
+// add records a planned operation.
+func (p *dryRunPlan) add(op plannedOperation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.operations = append(p.operations, op)
+}
+
+// warn records a warning about the plan, such as state that could not be fetched.
+func (p *dryRunPlan) warn(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.warnings = append(p.warnings, message)
+}
+
+// snapshot returns the planned operations and warnings recorded so far.
+func (p *dryRunPlan) snapshot() ([]plannedOperation, []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.operations), slices.Clone(p.warnings)
+}
+
+// dryRunContextKey is the context key under which a dryRunPlan is stored.
+type dryRunContextKey struct{}
+
+// withDryRunPlan returns a copy of ctx in which Portainer writes are recorded in plan.
+func withDryRunPlan(ctx context.Context, plan *dryRunPlan) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, plan)
+}
+
+// dryRunPlanFromContext returns the plan stored in ctx, if the call is a dry run.
+func dryRunPlanFromContext(ctx context.Context) (*dryRunPlan, bool) {
+	plan, ok := ctx.Value(dryRunContextKey{}).(*dryRunPlan)
+	return plan, ok
+}
+
+// withDryRun wraps a write tool handler so that, when the server runs in
+// dry-run mode or the call sets dryRun, the handler runs against a client that
+// records writes instead of sending them. The handler still validates its
+// parameters and reads from Portainer, and its result is replaced by the plan.
+// A call that plans no write, such as a GET through a proxy tool or a call that
+// fails validation, returns the handler result unchanged.
+func (s *PortainerMCPServer) withDryRun(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dryRun := s.dryRun
+		if !dryRun {
+			requested, err := toolgen.NewParameterParser(request).GetBoolean(dryRunParam, false)
+			if err != nil {
+				return toolErrorFromErr("invalid dryRun parameter", err), nil
+			}
+			dryRun = requested
+		}
+		if !dryRun {
+			return next(ctx, request)
+		}
+
+		plan, result, err := planToolCall(ctx, next, request)
+		if plan == nil {
+			return result, err
+		}
+		return jsonResult(plan, "failed to marshal dry-run plan")
+	}
+}
+
+// planToolCall runs a handler against a client that records writes instead
+// of sending them, and returns the resulting plan. If the handler planned no
+// write, the plan is nil and the handler result is returned instead.
+func planToolCall(ctx context.Context, next server.ToolHandlerFunc, request mcp.CallToolRequest) (*dryRunResult, *mcp.CallToolResult, error) {
+	plan := &dryRunPlan{}
+	result, err := next(withDryRunPlan(ctx, plan), request)
+
+	operations, warnings := plan.snapshot()
+	if len(operations) == 0 {
+		return nil, result, err
+	}
+	if message := toolCallError(result, err); message != "" {
+		warnings = append(warnings, "the handler failed after planning its changes, so later steps may be missing: "+message)
+	}
+
+	action, _ := request.GetArguments()["action"].(string)
+	return &dryRunResult{
+		DryRun:     true,
+		Tool:       request.Params.Name,
+		Action:     action,
+		Operations: operations,
+		Warnings:   warnings,
+	}, nil, nil
+}
+
+// isDryRun reports whether a tool call runs in dry-run mode. An invalid dryRun
+// argument is reported as not a dry run; withDryRun rejects it.
+func (s *PortainerMCPServer) isDryRun(request mcp.CallToolRequest) bool {
+	if s.dryRun {
+		return true
+	}
+	dryRun, _ := request.GetArguments()[dryRunParam].(bool)
+	return dryRun
+}
+
+// isReadOnlyTool reports whether a tool is annotated as read-only.
+func isReadOnlyTool(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
+
+// withDryRunParameter returns a copy of a write tool whose input schema accepts the dryRun argument.
+func withDryRunParameter(tool mcp.Tool) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	properties[dryRunParam] = map[string]any{
+		"type":        "boolean",
+		"description": dryRunParamDescription,
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// accessChanges is the difference between two user or team access maps.
+type accessChanges struct {
+	Add    map[int]string       `json:"add,omitempty"`
+	Update map[int]accessChange `json:"update,omitempty"`
+	Remove map[int]string       `json:"remove,omitempty"`
+}
+
+// accessChange is a role change for a user or team that keeps its access.
+type accessChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// diffAccesses returns the accesses to add, update, and remove to turn current into desired.
+func diffAccesses(current, desired map[int]string) accessChanges {
+	changes := accessChanges{}
+	for id, role := range desired {
+		existing, ok := current[id]
+		switch {
+		case !ok:
+			if changes.Add == nil {
+				changes.Add = map[int]string{}
+			}
+			changes.Add[id] = role
+		case existing != role:
+			if changes.Update == nil {
+				changes.Update = map[int]accessChange{}
+			}
+			changes.Update[id] = accessChange{From: existing, To: role}
+		}
+	}
+	for id, role := range current {
+		if _, ok := desired[id]; !ok {
+			if changes.Remove == nil {
+				changes.Remove = map[int]string{}
+			}
+			changes.Remove[id] = role
+		}
+	}
+	return changes
+}
+
+// idChanges is the difference between two sets of IDs.
+type idChanges struct {
+	Add    []int `json:"add"`
+	Remove []int `json:"remove"`
+}
+
+// diffIDs returns the sorted IDs to add and to remove to turn current into desired.
+func diffIDs(current, desired []int) idChanges {
+	changes := idChanges{Add: []int{}, Remove: []int{}}
+	for _, id := range desired {
+		if !slices.Contains(current, id) && !slices.Contains(changes.Add, id) {
+			changes.Add = append(changes.Add, id)
+		}
+	}
+	for _, id := range current {
+		if !slices.Contains(desired, id) && !slices.Contains(changes.Remove, id) {
+			changes.Remove = append(changes.Remove, id)
+		}
+	}
+	slices.Sort(changes.Add)
+	slices.Sort(changes.Remove)
+	return changes
+}
+
+// membershipChanges lists, by user ID, the team memberships that would be
+// deleted and created to make the team members match the requested users.
+type membershipChanges struct {
+	Delete []int `json:"delete"`
+	Create []int `json:"create"`
+}
+
+// valueChange is the change of a single field.
+type valueChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// redactState returns the JSON form of a resource with its sensitive fields
+// redacted, so that the current state shown in a plan never exposes secrets.
+func redactState(state any) any {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+	return redactValue(decoded)
+}
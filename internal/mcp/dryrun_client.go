@@ -0,0 +1,640 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+)
+
+// dryRunClient is the PortainerClient used during a dry run. Reads are passed
+// to the wrapped client, while every write is recorded in the plan and
+// reported as successful without reaching Portainer. Where the target of a
+// write can be read, its current state and the resulting changes are recorded
+// with it.
+//
+// Every write method of [PortainerClient] must be overridden here; methods
+// that are only promoted from the embedded client are sent to Portainer.
+type dryRunClient struct {
+	PortainerClient
+	plan *dryRunPlan
+}
+
+// newDryRunClient returns a client that records the writes made through cli in plan.
+func newDryRunClient(cli PortainerClient, plan *dryRunPlan) *dryRunClient {
+	return &dryRunClient{PortainerClient: cli, plan: plan}
+}
+
+// record adds a planned operation. Parameters and current state are redacted.
+func (d *dryRunClient) record(operation string, params map[string]any, current any, changes any) {
+	op := plannedOperation{Operation: operation, Params: redactArguments(params), Changes: changes}
+	if current != nil {
+		op.Current = redactState(current)
+	}
+	d.plan.add(op)
+}
+
+// state returns the current state of the target of a write, or nil with a
+// warning in the plan if it could not be read.
+func (d *dryRunClient) state(resource string, value any, err error) any {
+	if err != nil {
+		d.plan.warn(fmt.Sprintf("could not read the current state of %s: %v", resource, err))
+		return nil
+	}
+	return value
+}
+
+// findByID returns the item of a list with the given ID.
+func findByID[T any](items []T, err error, id int, idOf func(T) int) (T, error) {
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+	for _, item := range items {
+		if idOf(item) == id {
+			return item, nil
+		}
+	}
+	return zero, errors.New("not found")
+}
+
+// isReadMethod reports whether an HTTP method sent through a proxy tool only reads.
+func isReadMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// plannedResponse is the response returned for a proxied write in a dry run.
+func plannedResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
+
+// Tag methods
+
+func (d *dryRunClient) CreateEnvironmentTag(ctx context.Context, name string) (int, error) {
+	d.record("CreateEnvironmentTag", map[string]any{"name": name}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) DeleteEnvironmentTag(ctx context.Context, id int) error {
+	tags, err := d.PortainerClient.GetEnvironmentTags(ctx)
+	tag, err := findByID(tags, err, id, func(t models.EnvironmentTag) int { return t.ID })
+	d.record("DeleteEnvironmentTag", map[string]any{"id": id}, d.state(fmt.Sprintf("environment tag %d", id), tag, err), nil)
+	return nil
+}
+
+// Environment methods
+
+func (d *dryRunClient) DeleteEnvironment(ctx context.Context, id int) error {
+	env, err := d.PortainerClient.GetEnvironment(ctx, id)
+	d.record("DeleteEnvironment", map[string]any{"id": id}, d.state(fmt.Sprintf("environment %d", id), env, err), nil)
+	return nil
+}
+
+func (d *dryRunClient) SnapshotEnvironment(ctx context.Context, id int) error {
+	d.record("SnapshotEnvironment", map[string]any{"id": id}, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) SnapshotAllEnvironments(ctx context.Context) error {
+	d.record("SnapshotAllEnvironments", nil, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error {
+	env, err := d.PortainerClient.GetEnvironment(ctx, id)
+	var changes any
+	if err == nil {
+		changes = diffIDs(env.TagIds, tagIds)
+	}
+	d.record("UpdateEnvironmentTags", map[string]any{"id": id, "tagIds": tagIds}, d.state(fmt.Sprintf("environment %d", id), env.TagIds, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
+	env, err := d.PortainerClient.GetEnvironment(ctx, id)
+	var changes any
+	if err == nil {
+		changes = diffAccesses(env.UserAccesses, userAccesses)
+	}
+	d.record("UpdateEnvironmentUserAccesses", map[string]any{"id": id, "userAccesses": userAccesses}, d.state(fmt.Sprintf("environment %d", id), env.UserAccesses, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
+	env, err := d.PortainerClient.GetEnvironment(ctx, id)
+	var changes any
+	if err == nil {
+		changes = diffAccesses(env.TeamAccesses, teamAccesses)
+	}
+	d.record("UpdateEnvironmentTeamAccesses", map[string]any{"id": id, "teamAccesses": teamAccesses}, d.state(fmt.Sprintf("environment %d", id), env.TeamAccesses, err), changes)
+	return nil
+}
+
+// Environment group methods
+
+func (d *dryRunClient) environmentGroup(ctx context.Context, id int) (models.Group, error) {
+	groups, err := d.PortainerClient.GetEnvironmentGroups(ctx)
+	return findByID(groups, err, id, func(g models.Group) int { return g.ID })
+}
+
+func (d *dryRunClient) CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	d.record("CreateEnvironmentGroup", map[string]any{"name": name, "environmentIds": environmentIds}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error {
+	group, err := d.environmentGroup(ctx, id)
+	var changes any
+	if err == nil {
+		changes = map[string]valueChange{"name": {From: group.Name, To: name}}
+	}
+	d.record("UpdateEnvironmentGroupName", map[string]any{"id": id, "name": name}, d.state(fmt.Sprintf("environment group %d", id), group, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error {
+	group, err := d.environmentGroup(ctx, id)
+	var changes any
+	if err == nil {
+		changes = diffIDs(group.EnvironmentIds, environmentIds)
+	}
+	d.record("UpdateEnvironmentGroupEnvironments", map[string]any{"id": id, "environmentIds": environmentIds}, d.state(fmt.Sprintf("environment group %d", id), group, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error {
+	group, err := d.environmentGroup(ctx, id)
+	var changes any
+	if err == nil {
+		changes = diffIDs(group.TagIds, tagIds)
+	}
+	d.record("UpdateEnvironmentGroupTags", map[string]any{"id": id, "tagIds": tagIds}, d.state(fmt.Sprintf("environment group %d", id), group, err), changes)
+	return nil
+}
+
+// Access group methods
+
+func (d *dryRunClient) accessGroup(ctx context.Context, id int) (models.AccessGroup, error) {
+	groups, err := d.PortainerClient.GetAccessGroups(ctx)
+	return findByID(groups, err, id, func(g models.AccessGroup) int { return g.ID })
+}
+
+func (d *dryRunClient) CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	d.record("CreateAccessGroup", map[string]any{"name": name, "environmentIds": environmentIds}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) UpdateAccessGroupName(ctx context.Context, id int, name string) error {
+	group, err := d.accessGroup(ctx, id)
+	var changes any
+	if err == nil {
+		changes = map[string]valueChange{"name": {From: group.Name, To: name}}
+	}
+	d.record("UpdateAccessGroupName", map[string]any{"id": id, "name": name}, d.state(fmt.Sprintf("access group %d", id), group, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
+	group, err := d.accessGroup(ctx, id)
+	var changes any
+	if err == nil {
+		changes = diffAccesses(group.UserAccesses, userAccesses)
+	}
+	d.record("UpdateAccessGroupUserAccesses", map[string]any{"id": id, "userAccesses": userAccesses}, d.state(fmt.Sprintf("access group %d", id), group.UserAccesses, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
+	group, err := d.accessGroup(ctx, id)
+	var changes any
+	if err == nil {
+		changes = diffAccesses(group.TeamAccesses, teamAccesses)
+	}
+	d.record("UpdateAccessGroupTeamAccesses", map[string]any{"id": id, "teamAccesses": teamAccesses}, d.state(fmt.Sprintf("access group %d", id), group.TeamAccesses, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error {
+	group, err := d.accessGroup(ctx, id)
+	d.record("AddEnvironmentToAccessGroup", map[string]any{"id": id, "environmentId": environmentId}, d.state(fmt.Sprintf("access group %d", id), group, err), nil)
+	return nil
+}
+
+func (d *dryRunClient) RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error {
+	group, err := d.accessGroup(ctx, id)
+	d.record("RemoveEnvironmentFromAccessGroup", map[string]any{"id": id, "environmentId": environmentId}, d.state(fmt.Sprintf("access group %d", id), group, err), nil)
+	return nil
+}
+
+// Stack methods
+
+func (d *dryRunClient) CreateStack(ctx context.Context, name string, file string, environmentGroupIds []int) (int, error) {
+	d.record("CreateStack", map[string]any{"name": name, "file": file, "environmentGroupIds": environmentGroupIds}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error {
+	stacks, err := d.PortainerClient.GetStacks(ctx)
+	stack, err := findByID(stacks, err, id, func(s models.Stack) int { return s.ID })
+	var changes any
+	if err == nil {
+		changes = map[string]idChanges{"environmentGroupIds": diffIDs(stack.EnvironmentGroupIds, environmentGroupIds)}
+	}
+	d.record("UpdateStack", map[string]any{"id": id, "file": file, "environmentGroupIds": environmentGroupIds}, d.state(fmt.Sprintf("stack %d", id), stack, err), changes)
+	return nil
+}
+
+// regularStackState returns the current state of a regular stack.
+func (d *dryRunClient) regularStackState(ctx context.Context, id int) any {
+	stack, err := d.PortainerClient.InspectStack(ctx, id)
+	return d.state(fmt.Sprintf("stack %d", id), stack, err)
+}
+
+func (d *dryRunClient) DeleteStack(ctx context.Context, id int, endpointID int, removeVolumes bool) error {
+	d.record("DeleteStack", map[string]any{"id": id, "environmentId": endpointID, "removeVolumes": removeVolumes}, d.regularStackState(ctx, id), nil)
+	return nil
+}
+
+func (d *dryRunClient) UpdateStackGit(ctx context.Context, id int, endpointID int, referenceName string, prune bool) (models.RegularStack, error) {
+	d.record("UpdateStackGit", map[string]any{"id": id, "environmentId": endpointID, "referenceName": referenceName, "prune": prune}, d.regularStackState(ctx, id), nil)
+	return models.RegularStack{}, nil
+}
+
+func (d *dryRunClient) RedeployStackGit(ctx context.Context, id int, endpointID int, pullImage bool, prune bool) (models.RegularStack, error) {
+	d.record("RedeployStackGit", map[string]any{"id": id, "environmentId": endpointID, "pullImage": pullImage, "prune": prune}, d.regularStackState(ctx, id), nil)
+	return models.RegularStack{}, nil
+}
+
+func (d *dryRunClient) StartStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error) {
+	d.record("StartStack", map[string]any{"id": id, "environmentId": endpointID}, d.regularStackState(ctx, id), nil)
+	return models.RegularStack{}, nil
+}
+
+func (d *dryRunClient) StopStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error) {
+	d.record("StopStack", map[string]any{"id": id, "environmentId": endpointID}, d.regularStackState(ctx, id), nil)
+	return models.RegularStack{}, nil
+}
+
+func (d *dryRunClient) MigrateStack(ctx context.Context, id int, endpointID int, targetEndpointID int, name string) (models.RegularStack, error) {
+	d.record("MigrateStack", map[string]any{"id": id, "environmentId": endpointID, "targetEnvironmentId": targetEndpointID, "name": name}, d.regularStackState(ctx, id), nil)
+	return models.RegularStack{}, nil
+}
+
+// Team methods
+
+func (d *dryRunClient) CreateTeam(ctx context.Context, name string) (int, error) {
+	d.record("CreateTeam", map[string]any{"name": name}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) DeleteTeam(ctx context.Context, id int) error {
+	team, err := d.PortainerClient.GetTeam(ctx, id)
+	d.record("DeleteTeam", map[string]any{"id": id}, d.state(fmt.Sprintf("team %d", id), team, err), nil)
+	return nil
+}
+
+func (d *dryRunClient) UpdateTeamName(ctx context.Context, id int, name string) error {
+	team, err := d.PortainerClient.GetTeam(ctx, id)
+	var changes any
+	if err == nil {
+		changes = map[string]valueChange{"name": {From: team.Name, To: name}}
+	}
+	d.record("UpdateTeamName", map[string]any{"id": id, "name": name}, d.state(fmt.Sprintf("team %d", id), team, err), changes)
+	return nil
+}
+
+func (d *dryRunClient) UpdateTeamMembers(ctx context.Context, id int, userIds []int) error {
+	team, err := d.PortainerClient.GetTeam(ctx, id)
+	var changes any
+	if err == nil {
+		diff := diffIDs(team.MemberIDs, userIds)
+		changes = membershipChanges{Delete: diff.Remove, Create: diff.Add}
+	}
+	d.record("UpdateTeamMembers", map[string]any{"id": id, "userIds": userIds}, d.state(fmt.Sprintf("team %d", id), team, err), changes)
+	return nil
+}
+
+// User methods
+
+func (d *dryRunClient) CreateUser(ctx context.Context, username, password, role string) (int, error) {
+	d.record("CreateUser", map[string]any{"username": username, "password": password, "role": role}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) DeleteUser(ctx context.Context, id int) error {
+	user, err := d.PortainerClient.GetUser(ctx, id)
+	d.record("DeleteUser", map[string]any{"id": id}, d.state(fmt.Sprintf("user %d", id), user, err), nil)
+	return nil
+}
+
+func (d *dryRunClient) UpdateUserRole(ctx context.Context, id int, role string) error {
+	user, err := d.PortainerClient.GetUser(ctx, id)
+	var changes any
+	if err == nil {
+		changes = map[string]valueChange{"role": {From: user.Role, To: role}}
+	}
+	d.record("UpdateUserRole", map[string]any{"id": id, "role": role}, d.state(fmt.Sprintf("user %d", id), user, err), changes)
+	return nil
+}
+
+// Settings methods
+
+func (d *dryRunClient) UpdateSettings(ctx context.Context, settingsJSON map[string]interface{}) error {
+	settings, err := d.PortainerClient.GetSettings(ctx)
+	d.record("UpdateSettings", map[string]any{"settings": settingsJSON}, d.state("the settings", settings, err), nil)
+	return nil
+}
+
+func (d *dryRunClient) UpdateSSLSettings(ctx context.Context, cert, key string, httpEnabled *bool) error {
+	params := map[string]any{}
+	if cert != "" {
+		params["cert"] = cert
+	}
+	if key != "" {
+		params["privateKey"] = key
+	}
+	if httpEnabled != nil {
+		params["httpEnabled"] = *httpEnabled
+	}
+	settings, err := d.PortainerClient.GetSSLSettings(ctx)
+	d.record("UpdateSSLSettings", params, d.state("the SSL settings", settings, err), nil)
+	return nil
+}
+
+// Docker methods
+
+func (d *dryRunClient) ProxyDockerRequest(ctx context.Context, opts models.DockerProxyRequestOptions) (*http.Response, error) {
+	if isReadMethod(opts.Method) {
+		return d.PortainerClient.ProxyDockerRequest(ctx, opts)
+	}
+	d.record("ProxyDockerRequest", map[string]any{
+		"environmentId": opts.EnvironmentID,
+		"method":        opts.Method,
+		"path":          opts.Path,
+		"queryParams":   opts.QueryParams,
+		"headers":       opts.Headers,
+	}, nil, nil)
+	return plannedResponse(), nil
+}
+
+// containerState returns the current state of a Docker container.
+func (d *dryRunClient) containerState(ctx context.Context, environmentId int, containerId string) any {
+	container, err := d.PortainerClient.InspectDockerContainer(ctx, environmentId, containerId)
+	return d.state(fmt.Sprintf("container %s", containerId), container, err)
+}
+
+func (d *dryRunClient) StartDockerContainer(ctx context.Context, environmentId int, containerId string) error {
+	d.record("StartDockerContainer", map[string]any{"environmentId": environmentId, "containerId": containerId}, d.containerState(ctx, environmentId, containerId), nil)
+	return nil
+}
+
+func (d *dryRunClient) StopDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error {
+	params := map[string]any{"environmentId": environmentId, "containerId": containerId}
+	if timeout != nil {
+		params["timeout"] = *timeout
+	}
+	d.record("StopDockerContainer", params, d.containerState(ctx, environmentId, containerId), nil)
+	return nil
+}
+
+func (d *dryRunClient) RestartDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error {
+	params := map[string]any{"environmentId": environmentId, "containerId": containerId}
+	if timeout != nil {
+		params["timeout"] = *timeout
+	}
+	d.record("RestartDockerContainer", params, d.containerState(ctx, environmentId, containerId), nil)
+	return nil
+}
+
+func (d *dryRunClient) KillDockerContainer(ctx context.Context, environmentId int, containerId string, signal string) error {
+	d.record("KillDockerContainer", map[string]any{"environmentId": environmentId, "containerId": containerId, "signal": signal}, d.containerState(ctx, environmentId, containerId), nil)
+	return nil
+}
+
+func (d *dryRunClient) RemoveDockerContainer(ctx context.Context, environmentId int, containerId string, force bool, removeVolumes bool) error {
+	d.record("RemoveDockerContainer", map[string]any{"environmentId": environmentId, "containerId": containerId, "force": force, "removeVolumes": removeVolumes}, d.containerState(ctx, environmentId, containerId), nil)
+	return nil
+}
+
+func (d *dryRunClient) RenameDockerContainer(ctx context.Context, environmentId int, containerId string, name string) error {
+	d.record("RenameDockerContainer", map[string]any{"environmentId": environmentId, "containerId": containerId, "name": name}, d.containerState(ctx, environmentId, containerId), nil)
+	return nil
+}
+
+func (d *dryRunClient) ExecDockerContainer(ctx context.Context, environmentId int, containerId string, opts models.ExecOptions) (models.ExecResult, error) {
+	d.record("ExecDockerContainer", map[string]any{
+		"environmentId": environmentId,
+		"containerId":   containerId,
+		"command":       opts.Command,
+		"workingDir":    opts.WorkingDir,
+		"user":          opts.User,
+	}, nil, nil)
+	return models.ExecResult{}, nil
+}
+
+// Kubernetes methods
+
+func (d *dryRunClient) ProxyKubernetesRequest(ctx context.Context, opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
+	if isReadMethod(opts.Method) {
+		return d.PortainerClient.ProxyKubernetesRequest(ctx, opts)
+	}
+	d.record("ProxyKubernetesRequest", map[string]any{
+		"environmentId": opts.EnvironmentID,
+		"method":        opts.Method,
+		"path":          opts.Path,
+		"queryParams":   opts.QueryParams,
+		"headers":       opts.Headers,
+	}, nil, nil)
+	return plannedResponse(), nil
+}
+
+func (d *dryRunClient) ExecKubernetesPod(ctx context.Context, environmentId int, namespace, pod, container string, opts models.ExecOptions) (models.ExecResult, error) {
+	d.record("ExecKubernetesPod", map[string]any{
+		"environmentId": environmentId,
+		"namespace":     namespace,
+		"pod":           pod,
+		"container":     container,
+		"command":       opts.Command,
+		"workingDir":    opts.WorkingDir,
+	}, nil, nil)
+	return models.ExecResult{}, nil
+}
+
+// Webhook methods
+
+func (d *dryRunClient) CreateWebhook(ctx context.Context, resourceId string, endpointId int, webhookType int) (int, error) {
+	d.record("CreateWebhook", map[string]any{"resourceId": resourceId, "environmentId": endpointId, "webhookType": webhookType}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) DeleteWebhook(ctx context.Context, id int) error {
+	webhooks, err := d.PortainerClient.GetWebhooks(ctx)
+	webhook, err := findByID(webhooks, err, id, func(w models.Webhook) int { return w.ID })
+	d.record("DeleteWebhook", map[string]any{"id": id}, d.state(fmt.Sprintf("webhook %d", id), webhook, err), nil)
+	return nil
+}
+
+// Custom template methods
+
+func (d *dryRunClient) CreateCustomTemplate(ctx context.Context, title, description, note, logo, fileContent string, platform, templateType int) (int, error) {
+	d.record("CreateCustomTemplate", map[string]any{
+		"title":       title,
+		"description": description,
+		"note":        note,
+		"logo":        logo,
+		"fileContent": fileContent,
+		"platform":    platform,
+		"type":        templateType,
+	}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) DeleteCustomTemplate(ctx context.Context, id int) error {
+	template, err := d.PortainerClient.GetCustomTemplate(ctx, id)
+	d.record("DeleteCustomTemplate", map[string]any{"id": id}, d.state(fmt.Sprintf("custom template %d", id), template, err), nil)
+	return nil
+}
+
+// Registry methods
+
+func (d *dryRunClient) CreateRegistry(ctx context.Context, name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error) {
+	d.record("CreateRegistry", map[string]any{
+		"name":           name,
+		"type":           registryType,
+		"url":            url,
+		"authentication": authentication,
+		"username":       username,
+		"password":       password,
+		"baseURL":        baseURL,
+	}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) UpdateRegistry(ctx context.Context, id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error {
+	params := map[string]any{"id": id}
+	for key, value := range map[string]*string{"name": name, "url": url, "username": username, "password": password, "baseURL": baseURL} {
+		if value != nil {
+			params[key] = *value
+		}
+	}
+	if authentication != nil {
+		params["authentication"] = *authentication
+	}
+	registry, err := d.PortainerClient.GetRegistry(ctx, id)
+	d.record("UpdateRegistry", params, d.state(fmt.Sprintf("registry %d", id), registry, err), nil)
+	return nil
+}
+
+func (d *dryRunClient) DeleteRegistry(ctx context.Context, id int) error {
+	registry, err := d.PortainerClient.GetRegistry(ctx, id)
+	d.record("DeleteRegistry", map[string]any{"id": id}, d.state(fmt.Sprintf("registry %d", id), registry, err), nil)
+	return nil
+}
+
+// Backup methods
+
+func (d *dryRunClient) CreateBackup(ctx context.Context, password string) error {
+	d.record("CreateBackup", map[string]any{"password": password}, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) BackupToS3(ctx context.Context, settings models.S3BackupSettings) error {
+	d.record("BackupToS3", map[string]any{
+		"accessKeyID":      settings.AccessKeyID,
+		"bucketName":       settings.BucketName,
+		"cronRule":         settings.CronRule,
+		"password":         settings.Password,
+		"region":           settings.Region,
+		"s3CompatibleHost": settings.S3CompatibleHost,
+		"secretAccessKey":  settings.SecretAccessKey,
+	}, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) RestoreFromS3(ctx context.Context, accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error {
+	d.record("RestoreFromS3", map[string]any{
+		"accessKeyID":      accessKeyID,
+		"bucketName":       bucketName,
+		"filename":         filename,
+		"password":         password,
+		"region":           region,
+		"s3CompatibleHost": s3CompatibleHost,
+		"secretAccessKey":  secretAccessKey,
+	}, nil, nil)
+	return nil
+}
+
+// Edge job methods
+
+func (d *dryRunClient) CreateEdgeJob(ctx context.Context, name, cronExpression, fileContent string, endpoints []int, edgeGroups []int, recurring bool) (int, error) {
+	d.record("CreateEdgeJob", map[string]any{
+		"name":           name,
+		"cronExpression": cronExpression,
+		"fileContent":    fileContent,
+		"endpoints":      endpoints,
+		"edgeGroups":     edgeGroups,
+		"recurring":      recurring,
+	}, nil, nil)
+	return 0, nil
+}
+
+func (d *dryRunClient) DeleteEdgeJob(ctx context.Context, id int) error {
+	job, err := d.PortainerClient.GetEdgeJob(ctx, id)
+	d.record("DeleteEdgeJob", map[string]any{"id": id}, d.state(fmt.Sprintf("edge job %d", id), job, err), nil)
+	return nil
+}
+
+// Auth methods
+
+func (d *dryRunClient) AuthenticateUser(ctx context.Context, username, password string) (models.AuthResponse, error) {
+	d.record("AuthenticateUser", map[string]any{"username": username, "password": password}, nil, nil)
+	return models.AuthResponse{}, nil
+}
+
+func (d *dryRunClient) Logout(ctx context.Context) error {
+	d.record("Logout", nil, nil, nil)
+	return nil
+}
+
+// Helm methods
+
+func (d *dryRunClient) CreateHelmRepository(ctx context.Context, userId int, url string) (models.HelmRepository, error) {
+	d.record("CreateHelmRepository", map[string]any{"userId": userId, "url": url}, nil, nil)
+	return models.HelmRepository{}, nil
+}
+
+func (d *dryRunClient) DeleteHelmRepository(ctx context.Context, userId int, repositoryId int) error {
+	d.record("DeleteHelmRepository", map[string]any{"userId": userId, "repositoryId": repositoryId}, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) InstallHelmChart(ctx context.Context, environmentId int, chart, name, namespace, repo, values, version string) (models.HelmReleaseDetails, error) {
+	d.record("InstallHelmChart", map[string]any{
+		"environmentId": environmentId,
+		"chart":         chart,
+		"name":          name,
+		"namespace":     namespace,
+		"repo":          repo,
+		"values":        values,
+		"version":       version,
+	}, nil, nil)
+	return models.HelmReleaseDetails{}, nil
+}
+
+func (d *dryRunClient) DeleteHelmRelease(ctx context.Context, environmentId int, release, namespace string) error {
+	d.record("DeleteHelmRelease", map[string]any{"environmentId": environmentId, "release": release, "namespace": namespace}, nil, nil)
+	return nil
+}
@@ -0,0 +1,346 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// isReadClientMethod reports whether a PortainerClient method only reads, by its name.
+func isReadClientMethod(name string) bool {
+	for _, prefix := range []string{"Get", "List", "Inspect", "Search"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestDryRunClientRecordsEveryWrite verifies that every write method of
+// PortainerClient is intercepted by dryRunClient. A write method that is not
+// overridden reaches the mock, which has no expectation for it and panics.
+func TestDryRunClientRecordsEveryWrite(t *testing.T) {
+	clientType := reflect.TypeOf((*PortainerClient)(nil)).Elem()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+
+	// Reads made to fetch the current state fail, which must only produce warnings.
+	mockClient := new(MockPortainerClient)
+	for i := 0; i < clientType.NumMethod(); i++ {
+		method := clientType.Method(i)
+		if !isReadClientMethod(method.Name) {
+			continue
+		}
+		args := make([]any, method.Type.NumIn()-1)
+		for j := range args {
+			args[j] = mock.Anything
+		}
+		returns := make([]any, method.Type.NumOut())
+		for j := range returns {
+			if method.Type.Out(j) == errorType {
+				returns[j] = errors.New("unavailable")
+			} else {
+				returns[j] = reflect.Zero(method.Type.Out(j)).Interface()
+			}
+		}
+		mockClient.On(method.Name, args...).Return(returns...)
+	}
+
+	for i := 0; i < clientType.NumMethod(); i++ {
+		method := clientType.Method(i)
+		if isReadClientMethod(method.Name) {
+			continue
+		}
+
+		t.Run(method.Name, func(t *testing.T) {
+			plan := &dryRunPlan{}
+			cli := reflect.ValueOf(newDryRunClient(mockClient, plan))
+
+			args := []reflect.Value{reflect.ValueOf(context.Background())}
+			for j := 1; j < method.Type.NumIn(); j++ {
+				args = append(args, reflect.Zero(method.Type.In(j)))
+			}
+
+			var out []reflect.Value
+			require.NotPanics(t, func() { out = cli.MethodByName(method.Name).Call(args) })
+			assert.True(t, out[len(out)-1].IsNil(), "a planned write must report success")
+
+			operations, _ := plan.snapshot()
+			require.Len(t, operations, 1)
+			assert.Equal(t, method.Name, operations[0].Operation)
+		})
+	}
+}
+
+// TestDryRunClientProxyReads verifies that reads through the proxies are sent to Portainer.
+func TestDryRunClientProxyReads(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("[]"))}
+	mockClient := new(MockPortainerClient)
+	mockClient.On("ProxyDockerRequest", models.DockerProxyRequestOptions{EnvironmentID: 1, Method: "get", Path: "/containers/json"}).Return(resp, nil)
+	mockClient.On("ProxyKubernetesRequest", models.KubernetesProxyRequestOptions{EnvironmentID: 1, Method: http.MethodHead, Path: "/api/v1/pods"}).Return(resp, nil)
+	plan := &dryRunPlan{}
+	cli := newDryRunClient(mockClient, plan)
+
+	got, err := cli.ProxyDockerRequest(context.Background(), models.DockerProxyRequestOptions{EnvironmentID: 1, Method: "get", Path: "/containers/json"})
+	require.NoError(t, err)
+	assert.Same(t, resp, got)
+
+	got, err = cli.ProxyKubernetesRequest(context.Background(), models.KubernetesProxyRequestOptions{EnvironmentID: 1, Method: http.MethodHead, Path: "/api/v1/pods"})
+	require.NoError(t, err)
+	assert.Same(t, resp, got)
+
+	operations, _ := plan.snapshot()
+	assert.Empty(t, operations)
+	mockClient.AssertExpectations(t)
+}
+
+// callDryRun calls a handler wrapped by withDryRun and decodes the plan it returns.
+func callDryRun(t *testing.T, s *PortainerMCPServer, handler server.ToolHandlerFunc, args map[string]any) dryRunResult {
+	t.Helper()
+
+	result, err := s.withDryRun(handler)(context.Background(), CreateMCPRequest(args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error result: %v", result.Content)
+
+	var plan dryRunResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &plan))
+	return plan
+}
+
+// TestDryRunUpdateTeamMembers verifies the membership diff planned for a team.
+func TestDryRunUpdateTeamMembers(t *testing.T) {
+	s := newTestMetaServer(false)
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetTeam", 4).Return(models.Team{ID: 4, Name: "ops", MemberIDs: []int{1, 2, 3}}, nil)
+
+	plan := callDryRun(t, s, s.HandleUpdateTeamMembers(), map[string]any{
+		"id":      float64(4),
+		"userIds": []any{float64(3), float64(5), float64(1)},
+		"dryRun":  true,
+	})
+
+	assert.True(t, plan.DryRun)
+	require.Len(t, plan.Operations, 1)
+	op := plan.Operations[0]
+	assert.Equal(t, "UpdateTeamMembers", op.Operation)
+	assert.Equal(t, map[string]any{"delete": []any{float64(2)}, "create": []any{float64(5)}}, op.Changes)
+	assert.Equal(t, map[string]any{"id": float64(4), "name": "ops", "members": []any{float64(1), float64(2), float64(3)}}, op.Current)
+	mockClient.AssertNotCalled(t, "UpdateTeamMembers", mock.Anything, mock.Anything)
+}
+
+// TestDryRunUpdateEnvironmentTeamAccesses verifies the access diff planned for an environment.
+func TestDryRunUpdateEnvironmentTeamAccesses(t *testing.T) {
+	s := newTestMetaServer(false)
+	s.dryRun = true
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetEnvironment", 2).Return(models.Environment{
+		ID:           2,
+		TeamAccesses: map[int]string{1: "environment_administrator", 2: "standard_user"},
+	}, nil)
+
+	plan := callDryRun(t, s, s.HandleUpdateEnvironmentTeamAccesses(), map[string]any{
+		"id": float64(2),
+		"teamAccesses": []any{
+			map[string]any{"id": float64(1), "access": "readonly_user"},
+			map[string]any{"id": float64(3), "access": "standard_user"},
+		},
+	})
+
+	require.Len(t, plan.Operations, 1)
+	assert.Equal(t, map[string]any{
+		"add":    map[string]any{"3": "standard_user"},
+		"update": map[string]any{"1": map[string]any{"from": "environment_administrator", "to": "readonly_user"}},
+		"remove": map[string]any{"2": "standard_user"},
+	}, plan.Operations[0].Changes)
+	mockClient.AssertNotCalled(t, "UpdateEnvironmentTeamAccesses", mock.Anything, mock.Anything)
+}
+
+// TestDryRunDeleteStack verifies that the stack to delete is described, and
+// that a failure to read it is reported as a warning.
+func TestDryRunDeleteStack(t *testing.T) {
+	args := map[string]any{"id": float64(7), "environmentId": float64(2), "removeVolumes": true, "dryRun": true}
+
+	t.Run("current stack", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("InspectStack", 7).Return(models.RegularStack{ID: 7, Name: "web", EndpointID: 2}, nil)
+
+		plan := callDryRun(t, s, s.HandleDeleteStack(), args)
+
+		require.Len(t, plan.Operations, 1)
+		op := plan.Operations[0]
+		assert.Equal(t, "DeleteStack", op.Operation)
+		assert.Equal(t, map[string]any{"id": float64(7), "environmentId": float64(2), "removeVolumes": true}, op.Params)
+		assert.Equal(t, "web", op.Current.(map[string]any)["name"])
+		assert.Empty(t, plan.Warnings)
+		mockClient.AssertNotCalled(t, "DeleteStack", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("stack cannot be read", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.cli.(*MockPortainerClient).On("InspectStack", 7).Return(nil, errors.New("stack not found"))
+
+		plan := callDryRun(t, s, s.HandleDeleteStack(), args)
+
+		require.Len(t, plan.Operations, 1)
+		assert.Nil(t, plan.Operations[0].Current)
+		assert.Equal(t, []string{"could not read the current state of stack 7: stack not found"}, plan.Warnings)
+	})
+}
+
+// TestDryRunRedactsSecrets verifies that planned parameters and state do not expose secrets.
+func TestDryRunRedactsSecrets(t *testing.T) {
+	s := newTestMetaServer(false)
+
+	plan := callDryRun(t, s, s.HandleCreateUser(), map[string]any{
+		"username": "bob",
+		"password": "hunter2",
+		"role":     "user",
+		"dryRun":   true,
+	})
+
+	require.Len(t, plan.Operations, 1)
+	assert.Equal(t, auditRedacted, plan.Operations[0].Params["password"])
+	assert.Equal(t, "bob", plan.Operations[0].Params["username"])
+}
+
+// TestWithDryRunPassThrough verifies the cases in which the handler result is returned unchanged.
+func TestWithDryRunPassThrough(t *testing.T) {
+	t.Run("dry run not requested", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("UpdateTeamMembers", 4, []int{1}).Return(nil)
+
+		result, err := s.withDryRun(s.HandleUpdateTeamMembers())(context.Background(), CreateMCPRequest(map[string]any{
+			"id":      float64(4),
+			"userIds": []any{float64(1)},
+			"dryRun":  false,
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		s := newTestMetaServer(false)
+
+		result, err := s.withDryRun(s.HandleDeleteStack())(context.Background(), CreateMCPRequest(map[string]any{
+			"id":     float64(-1),
+			"dryRun": true,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("invalid dryRun", func(t *testing.T) {
+		s := newTestMetaServer(false)
+
+		result, err := s.withDryRun(s.HandleDeleteStack())(context.Background(), CreateMCPRequest(map[string]any{
+			"id":     float64(1),
+			"dryRun": "yes",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid dryRun parameter")
+	})
+}
+
+// TestDryRunParameterRegistration verifies that write tools, and only write tools, accept dryRun.
+func TestDryRunParameterRegistration(t *testing.T) {
+	readOnly, write := true, false
+	s := newTestMetaServer(false)
+	s.tools = map[string]mcp.Tool{
+		"readTool": {
+			Name:        "readTool",
+			InputSchema: mcp.ToolInputSchema{Properties: map[string]any{"id": map[string]any{"type": "number"}}},
+			Annotations: mcp.ToolAnnotation{ReadOnlyHint: &readOnly},
+		},
+		"writeTool": {
+			Name:        "writeTool",
+			InputSchema: mcp.ToolInputSchema{Properties: map[string]any{"id": map[string]any{"type": "number"}}},
+			Annotations: mcp.ToolAnnotation{ReadOnlyHint: &write},
+		},
+	}
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	s.addToolIfExists("readTool", handler)
+	s.addToolIfExists("writeTool", handler)
+	s.RegisterMetaTools()
+
+	properties := listToolProperties(t, s.srv)
+	assert.NotContains(t, properties["readTool"], dryRunParam)
+	assert.Contains(t, properties["writeTool"], dryRunParam)
+	assert.NotContains(t, s.tools["writeTool"].InputSchema.Properties, dryRunParam, "the loaded tool definition must not be modified")
+	assert.Contains(t, properties["manage_stacks"], dryRunParam)
+}
+
+// TestDryRunParameterReadOnlyMetaTool verifies that meta-tools without write actions do not accept dryRun.
+func TestDryRunParameterReadOnlyMetaTool(t *testing.T) {
+	s := newTestMetaServer(true)
+	s.RegisterMetaTools()
+
+	properties := listToolProperties(t, s.srv)
+	require.Contains(t, properties, "manage_stacks")
+	assert.NotContains(t, properties["manage_stacks"], dryRunParam)
+}
+
+// listToolProperties returns the input schema properties of every registered tool.
+func listToolProperties(t *testing.T, srv *server.MCPServer) map[string]map[string]any {
+	t.Helper()
+
+	resp := srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				InputSchema struct {
+					Properties map[string]any `json:"properties"`
+				} `json:"inputSchema"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	properties := make(map[string]map[string]any, len(decoded.Result.Tools))
+	for _, tool := range decoded.Result.Tools {
+		properties[tool.Name] = tool.InputSchema.Properties
+	}
+	return properties
+}
+
+// TestDiffAccesses verifies the access map diff.
+func TestDiffAccesses(t *testing.T) {
+	assert.Equal(t, accessChanges{}, diffAccesses(map[int]string{1: "a"}, map[int]string{1: "a"}))
+	assert.Equal(t, accessChanges{
+		Add:    map[int]string{3: "c"},
+		Update: map[int]accessChange{1: {From: "a", To: "b"}},
+		Remove: map[int]string{2: "b"},
+	}, diffAccesses(map[int]string{1: "a", 2: "b"}, map[int]string{1: "b", 3: "c"}))
+	assert.Equal(t, accessChanges{Remove: map[int]string{1: "a"}}, diffAccesses(map[int]string{1: "a"}, nil))
+}
+
+// TestDiffIDs verifies the ID set diff.
+func TestDiffIDs(t *testing.T) {
+	assert.Equal(t, idChanges{Add: []int{}, Remove: []int{}}, diffIDs([]int{1, 2}, []int{2, 1}))
+	assert.Equal(t, idChanges{Add: []int{4, 5}, Remove: []int{1}}, diffIDs([]int{1, 2}, []int{5, 2, 4, 5}))
+	assert.Equal(t, idChanges{Add: []int{1}, Remove: []int{}}, diffIDs(nil, []int{1}))
+}
@@ -25,9 +25,9 @@ func (s *PortainerMCPServer) AddEdgeJobFeatures() {
 // HandleListEdgeJobs returns an MCP tool handler that lists edge jobs.
 func (s *PortainerMCPServer) HandleListEdgeJobs() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		jobs, err := s.cli.GetEdgeJobs()
+		jobs, err := s.client(ctx).GetEdgeJobs(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list edge jobs", err), nil
+			return toolErrorFromErr("failed to list edge jobs", err), nil
 		}
 
 		return jsonResult(jobs, "failed to marshal edge jobs")
@@ -41,15 +41,15 @@ func (s *PortainerMCPServer) HandleGetEdgeJob() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		job, err := s.cli.GetEdgeJob(id)
+		job, err := s.client(ctx).GetEdgeJob(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get edge job", err), nil
+			return toolErrorFromErr("failed to get edge job", err), nil
 		}
 
 		return jsonResult(job, "failed to marshal edge job")
@@ -63,15 +63,15 @@ func (s *PortainerMCPServer) HandleGetEdgeJobFile() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		content, err := s.cli.GetEdgeJobFile(id)
+		content, err := s.client(ctx).GetEdgeJobFile(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get edge job file", err), nil
+			return toolErrorFromErr("failed to get edge job file", err), nil
 		}
 
 		return mcp.NewToolResultText(content), nil
@@ -85,21 +85,21 @@ func (s *PortainerMCPServer) HandleCreateEdgeJob() server.ToolHandlerFunc {
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 
 		cronExpression, err := parser.GetString("cronExpression", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid cronExpression parameter", err), nil
+			return toolErrorFromErr("invalid cronExpression parameter", err), nil
 		}
 
 		if !isValidCronExpression(cronExpression) {
-			return mcp.NewToolResultErrorFromErr("invalid cronExpression parameter", fmt.Errorf("cron expression must have 5 fields (minute hour day month weekday)")), nil
+			return toolErrorFromErr("invalid cronExpression parameter", fmt.Errorf("cron expression must have 5 fields (minute hour day month weekday)")), nil
 		}
 
 		fileContent, err := parser.GetString("fileContent", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid fileContent parameter", err), nil
+			return toolErrorFromErr("invalid fileContent parameter", err), nil
 		}
 
 		recurring, _ := parser.GetBoolean("recurring", false)
@@ -107,9 +107,9 @@ func (s *PortainerMCPServer) HandleCreateEdgeJob() server.ToolHandlerFunc {
 		endpoints, _ := parser.GetArrayOfIntegers("endpoints", false)
 		edgeGroups, _ := parser.GetArrayOfIntegers("edgeGroups", false)
 
-		id, err := s.cli.CreateEdgeJob(name, cronExpression, fileContent, endpoints, edgeGroups, recurring)
+		id, err := s.client(ctx).CreateEdgeJob(ctx, name, cronExpression, fileContent, endpoints, edgeGroups, recurring)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create edge job", err), nil
+			return toolErrorFromErr("failed to create edge job", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Edge job created successfully with ID: %d", id)), nil
@@ -123,15 +123,15 @@ func (s *PortainerMCPServer) HandleDeleteEdgeJob() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteEdgeJob(id)
+		err = s.client(ctx).DeleteEdgeJob(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete edge job", err), nil
+			return toolErrorFromErr("failed to delete edge job", err), nil
 		}
 
 		return mcp.NewToolResultText("Edge job deleted successfully"), nil
@@ -153,9 +153,9 @@ func (s *PortainerMCPServer) AddEdgeUpdateScheduleFeatures() {
 // HandleListEdgeUpdateSchedules returns an MCP tool handler that lists edge update schedules.
 func (s *PortainerMCPServer) HandleListEdgeUpdateSchedules() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		schedules, err := s.cli.GetEdgeUpdateSchedules()
+		schedules, err := s.client(ctx).GetEdgeUpdateSchedules(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list edge update schedules", err), nil
+			return toolErrorFromErr("failed to list edge update schedules", err), nil
 		}
 
 		return jsonResult(schedules, "failed to marshal edge update schedules")
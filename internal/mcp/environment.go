@@ -2,9 +2,12 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
 )
 
@@ -26,9 +29,14 @@ func (s *PortainerMCPServer) AddEnvironmentFeatures() {
 // HandleGetEnvironments returns an MCP tool handler that retrieves environments.
 func (s *PortainerMCPServer) HandleGetEnvironments() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		environments, err := s.cli.GetEnvironments()
+		environments, err := s.client(ctx).GetEnvironments(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get environments", err), nil
+			return toolErrorFromErr("failed to get environments", err), nil
+		}
+
+		environments, err = s.filterEnvironments(ctx, environments)
+		if err != nil {
+			return toolErrorFromErr("failed to check environment scope", err), nil
 		}
 
 		return jsonResult(environments, "failed to marshal environments")
@@ -42,15 +50,15 @@ func (s *PortainerMCPServer) HandleGetEnvironment() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		environment, err := s.cli.GetEnvironment(id)
+		environment, err := s.client(ctx).GetEnvironment(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get environment", err), nil
+			return toolErrorFromErr("failed to get environment", err), nil
 		}
 
 		return jsonResult(environment, "failed to marshal environment")
@@ -64,15 +72,15 @@ func (s *PortainerMCPServer) HandleDeleteEnvironment() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteEnvironment(id)
+		err = s.client(ctx).DeleteEnvironment(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete environment", err), nil
+			return toolErrorFromErr("failed to delete environment", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment deleted successfully"), nil
@@ -86,15 +94,15 @@ func (s *PortainerMCPServer) HandleSnapshotEnvironment() server.ToolHandlerFunc
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.SnapshotEnvironment(id)
+		err = s.client(ctx).SnapshotEnvironment(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to snapshot environment", err), nil
+			return toolErrorFromErr("failed to snapshot environment", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment snapshot created successfully"), nil
@@ -102,15 +110,53 @@ func (s *PortainerMCPServer) HandleSnapshotEnvironment() server.ToolHandlerFunc
 }
 
 // HandleSnapshotAllEnvironments returns an MCP tool handler that triggers a snapshot of all environments.
+// The environments are snapshotted one by one, so that a failed environment
+// does not stop the others, and the progress is reported after each of them
+// when the client asks for progress notifications.
 func (s *PortainerMCPServer) HandleSnapshotAllEnvironments() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		err := s.cli.SnapshotAllEnvironments()
+		progress := s.newProgressReporter(ctx, request)
+		environments, err := s.client(ctx).GetEnvironments(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to snapshot all environments", err), nil
+			return toolErrorFromErr("failed to get environments", err), nil
+		}
+
+		// Like Portainer's snapshot of all environments, skip the edge and
+		// Azure environments, which cannot be snapshotted on request.
+		snapshotted := make([]models.Environment, 0, len(environments))
+		for _, environment := range environments {
+			if supportsDirectSnapshot(environment) {
+				snapshotted = append(snapshotted, environment)
+			}
+		}
+
+		total := float64(len(snapshotted))
+		var failures []string
+		for i, environment := range snapshotted {
+			progress.report(float64(i), total, fmt.Sprintf("Snapshotting environment %s (%d)", environment.Name, environment.ID))
+			if err := s.client(ctx).SnapshotEnvironment(ctx, environment.ID); err != nil {
+				failures = append(failures, fmt.Sprintf("%s (%d): %v", environment.Name, environment.ID, err))
+			}
+		}
+		progress.report(total, total, fmt.Sprintf("Snapshotted %d environments", len(snapshotted)-len(failures)))
+
+		if len(failures) > 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to snapshot %d of %d environments: %s", len(failures), len(snapshotted), strings.Join(failures, "; "))), nil
 		}
 
-		return mcp.NewToolResultText("All environment snapshots created successfully"), nil
+		return mcp.NewToolResultText(fmt.Sprintf("All environment snapshots created successfully (%d environments, %d edge or Azure environments skipped)", len(snapshotted), len(environments)-len(snapshotted))), nil
+	}
+}
+
+// supportsDirectSnapshot reports whether Portainer can snapshot an
+// environment on request. Edge environments are snapshotted by their agent
+// when it checks in, and Azure environments have no snapshots.
+func supportsDirectSnapshot(environment models.Environment) bool {
+	switch environment.Type {
+	case models.EnvironmentTypeDockerEdgeAgent, models.EnvironmentTypeKubernetesEdgeAgent, models.EnvironmentTypeAzureACI:
+		return false
 	}
+	return true
 }
 
 // HandleUpdateEnvironmentTags returns an MCP tool handler that updates environment tags.
@@ -120,7 +166,7 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentTags() server.ToolHandlerFun
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -128,12 +174,12 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentTags() server.ToolHandlerFun
 
 		tagIds, err := parser.GetArrayOfIntegers("tagIds", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid tagIds parameter", err), nil
+			return toolErrorFromErr("invalid tagIds parameter", err), nil
 		}
 
-		err = s.cli.UpdateEnvironmentTags(id, tagIds)
+		err = s.client(ctx).UpdateEnvironmentTags(ctx, id, tagIds)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update environment tags", err), nil
+			return toolErrorFromErr("failed to update environment tags", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment tags updated successfully"), nil
@@ -147,7 +193,7 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentUserAccesses() server.ToolHa
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -155,17 +201,17 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentUserAccesses() server.ToolHa
 
 		userAccesses, err := parser.GetArrayOfObjects("userAccesses", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userAccesses parameter", err), nil
+			return toolErrorFromErr("invalid userAccesses parameter", err), nil
 		}
 
 		userAccessesMap, err := parseAccessMap(userAccesses)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid user accesses", err), nil
+			return toolErrorFromErr("invalid user accesses", err), nil
 		}
 
-		err = s.cli.UpdateEnvironmentUserAccesses(id, userAccessesMap)
+		err = s.client(ctx).UpdateEnvironmentUserAccesses(ctx, id, userAccessesMap)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update environment user accesses", err), nil
+			return toolErrorFromErr("failed to update environment user accesses", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment user accesses updated successfully"), nil
@@ -179,7 +225,7 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentTeamAccesses() server.ToolHa
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -187,17 +233,17 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentTeamAccesses() server.ToolHa
 
 		teamAccesses, err := parser.GetArrayOfObjects("teamAccesses", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid teamAccesses parameter", err), nil
+			return toolErrorFromErr("invalid teamAccesses parameter", err), nil
 		}
 
 		teamAccessesMap, err := parseAccessMap(teamAccesses)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid team accesses", err), nil
+			return toolErrorFromErr("invalid team accesses", err), nil
 		}
 
-		err = s.cli.UpdateEnvironmentTeamAccesses(id, teamAccessesMap)
+		err = s.client(ctx).UpdateEnvironmentTeamAccesses(ctx, id, teamAccessesMap)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update environment team accesses", err), nil
+			return toolErrorFromErr("failed to update environment team accesses", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment team accesses updated successfully"), nil
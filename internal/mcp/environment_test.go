@@ -359,7 +359,8 @@ func TestHandleSnapshotAllEnvironments(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := &MockPortainerClient{}
-			mockClient.On("SnapshotAllEnvironments").Return(tt.mockError)
+			mockClient.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local"}}, nil)
+			mockClient.On("SnapshotEnvironment", 1).Return(tt.mockError)
 
 			server := &PortainerMCPServer{
 				cli: mockClient,
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultExecTimeout is the time a command may run when the timeout parameter is omitted.
+	defaultExecTimeout = 30 * time.Second
+
+	// maxExecTimeout is the longest timeout accepted for a command. It matches
+	// the timeout of the HTTP client used to reach Portainer, which bounds how
+	// long a proxied request can last.
+	maxExecTimeout = 30 * time.Second
+)
+
+// HandleExecDockerContainer returns an MCP tool handler that runs a one-shot command inside a
+// Docker container and returns its stdout, stderr, and exit code.
+func (s *PortainerMCPServer) HandleExecDockerContainer() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, containerId, err := parseContainerTarget(parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		opts, timeout, err := parseExecParams(request, parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := s.client(ctx).ExecDockerContainer(ctx, environmentId, containerId, opts)
+		if err != nil {
+			return execError(ctx, "failed to exec in docker container", timeout, err), nil
+		}
+
+		return jsonResult(result, "failed to marshal exec result")
+	}
+}
+
+// HandleExecKubernetesPod returns an MCP tool handler that runs a one-shot command inside a
+// Kubernetes pod container and returns its stdout, stderr, and exit code.
+func (s *PortainerMCPServer) HandleExecKubernetesPod() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
+		}
+		if err := validatePositiveID("environmentId", environmentId); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		namespace, err := parser.GetString("namespace", true)
+		if err != nil {
+			return toolErrorFromErr("invalid namespace parameter", err), nil
+		}
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" {
+			return mcp.NewToolResultError("namespace cannot be empty or whitespace-only"), nil
+		}
+
+		pod, err := parser.GetString("pod", true)
+		if err != nil {
+			return toolErrorFromErr("invalid pod parameter", err), nil
+		}
+		pod = strings.TrimSpace(pod)
+		if pod == "" {
+			return mcp.NewToolResultError("pod cannot be empty or whitespace-only"), nil
+		}
+
+		container, err := parser.GetString("container", false)
+		if err != nil {
+			return toolErrorFromErr("invalid container parameter", err), nil
+		}
+
+		opts, timeout, err := parseExecParams(request, parser)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if opts.User != "" {
+			return mcp.NewToolResultError("user is not supported for Kubernetes pods; the command runs as the container user"), nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := s.client(ctx).ExecKubernetesPod(ctx, environmentId, namespace, pod, strings.TrimSpace(container), opts)
+		if err != nil {
+			return execError(ctx, "failed to exec in kubernetes pod", timeout, err), nil
+		}
+
+		return jsonResult(result, "failed to marshal exec result")
+	}
+}
+
+// parseExecParams parses the command, workingDir, user, and timeout parameters shared by the exec tools.
+func parseExecParams(request mcp.CallToolRequest, parser *toolgen.ParameterParser) (models.ExecOptions, time.Duration, error) {
+	command, err := parser.GetArrayOfStrings("command", true)
+	if err != nil {
+		return models.ExecOptions{}, 0, fmt.Errorf("invalid command parameter: %w", err)
+	}
+	if len(command) == 0 || strings.TrimSpace(command[0]) == "" {
+		return models.ExecOptions{}, 0, errors.New("command must contain at least the program to run")
+	}
+
+	workingDir, err := parser.GetString("workingDir", false)
+	if err != nil {
+		return models.ExecOptions{}, 0, fmt.Errorf("invalid workingDir parameter: %w", err)
+	}
+
+	user, err := parser.GetString("user", false)
+	if err != nil {
+		return models.ExecOptions{}, 0, fmt.Errorf("invalid user parameter: %w", err)
+	}
+
+	timeout := defaultExecTimeout
+	if _, ok := request.GetArguments()["timeout"]; ok {
+		seconds, err := parser.GetInt("timeout", false)
+		if err != nil {
+			return models.ExecOptions{}, 0, fmt.Errorf("invalid timeout parameter: %w", err)
+		}
+		timeout = time.Duration(seconds) * time.Second
+		if timeout <= 0 || timeout > maxExecTimeout {
+			return models.ExecOptions{}, 0, fmt.Errorf("timeout must be between 1 and %d seconds, got %d", int(maxExecTimeout.Seconds()), seconds)
+		}
+	}
+
+	return models.ExecOptions{
+		Command:    command,
+		WorkingDir: strings.TrimSpace(workingDir),
+		User:       strings.TrimSpace(user),
+	}, timeout, nil
+}
+
+// execError builds the tool error for a failed exec, explaining a timeout
+// separately because the command may still be running in the container.
+func execError(ctx context.Context, message string, timeout time.Duration, err error) *mcp.CallToolResult {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return mcp.NewToolResultError(fmt.Sprintf("%s: command did not finish within %s and may still be running in the container", message, timeout))
+	}
+	return toolErrorFromErr(message, err)
+}
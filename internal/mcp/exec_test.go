@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHandleExecDockerContainer verifies the HandleExecDockerContainer MCP tool handler.
+func TestHandleExecDockerContainer(t *testing.T) {
+	command := []any{"cat", "/etc/resolv.conf"}
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		setupMock   func(m *MockPortainerClient)
+		expectText  string
+		expectError string
+	}{
+		{
+			name:   "command with exit code",
+			params: map[string]any{"environmentId": float64(1), "containerId": "web", "command": command},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ExecDockerContainer", 1, "web", models.ExecOptions{Command: []string{"cat", "/etc/resolv.conf"}}).
+					Return(models.ExecResult{Stdout: "nameserver 127.0.0.11\n", ExitCode: 0}, nil)
+			},
+			expectText: `{"stdout":"nameserver 127.0.0.11\n","stderr":"","exit_code":0}`,
+		},
+		{
+			name: "working dir and user",
+			params: map[string]any{
+				"environmentId": float64(1),
+				"containerId":   "web",
+				"command":       []any{"ls"},
+				"workingDir":    "/app",
+				"user":          "1000:1000",
+				"timeout":       float64(5),
+			},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ExecDockerContainer", 1, "web", models.ExecOptions{Command: []string{"ls"}, WorkingDir: "/app", User: "1000:1000"}).
+					Return(models.ExecResult{Stderr: "ls: cannot open directory '.'\n", ExitCode: 2}, nil)
+			},
+			expectText: `"exit_code":2`,
+		},
+		{
+			name:        "missing command",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web"},
+			expectError: "invalid command parameter",
+		},
+		{
+			name:        "empty command",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web", "command": []any{}},
+			expectError: "command must contain at least the program to run",
+		},
+		{
+			name:        "non-string argument",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web", "command": []any{"sleep", float64(1)}},
+			expectError: "invalid command parameter",
+		},
+		{
+			name:        "timeout too long",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web", "command": command, "timeout": float64(31)},
+			expectError: "timeout must be between 1 and 30 seconds",
+		},
+		{
+			name:        "timeout zero",
+			params:      map[string]any{"environmentId": float64(1), "containerId": "web", "command": command, "timeout": float64(0)},
+			expectError: "timeout must be between 1 and 30 seconds",
+		},
+		{
+			name:        "missing containerId",
+			params:      map[string]any{"environmentId": float64(1), "command": command},
+			expectError: "containerId",
+		},
+		{
+			name:   "client error",
+			params: map[string]any{"environmentId": float64(1), "containerId": "web", "command": command},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ExecDockerContainer", 1, "web", models.ExecOptions{Command: []string{"cat", "/etc/resolv.conf"}}).
+					Return(models.ExecResult{}, errors.New("container web is not running"))
+			},
+			expectError: "failed to exec in docker container: container web is not running",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+
+			s := &PortainerMCPServer{cli: mockClient}
+			result, err := s.HandleExecDockerContainer()(context.Background(), CreateMCPRequest(tt.params))
+			require.NoError(t, err)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.expectError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tt.expectError)
+			} else {
+				assert.False(t, result.IsError)
+				assert.Contains(t, text, tt.expectText)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandleExecKubernetesPod verifies the HandleExecKubernetesPod MCP tool handler.
+func TestHandleExecKubernetesPod(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		setupMock   func(m *MockPortainerClient)
+		expectText  string
+		expectError string
+	}{
+		{
+			name: "command in a named container",
+			params: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "default",
+				"pod":           "web-1",
+				"container":     "app",
+				"command":       []any{"env"},
+				"workingDir":    "/srv",
+			},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ExecKubernetesPod", 1, "default", "web-1", "app", models.ExecOptions{Command: []string{"env"}, WorkingDir: "/srv"}).
+					Return(models.ExecResult{Stdout: "HOME=/root\n"}, nil)
+			},
+			expectText: `"stdout":"HOME=/root\n"`,
+		},
+		{
+			name:        "user is rejected",
+			params:      map[string]any{"environmentId": float64(1), "namespace": "default", "pod": "web-1", "command": []any{"id"}, "user": "root"},
+			expectError: "user is not supported for Kubernetes pods",
+		},
+		{
+			name:        "missing pod",
+			params:      map[string]any{"environmentId": float64(1), "namespace": "default", "command": []any{"id"}},
+			expectError: "invalid pod parameter",
+		},
+		{
+			name:        "blank namespace",
+			params:      map[string]any{"environmentId": float64(1), "namespace": " ", "pod": "web-1", "command": []any{"id"}},
+			expectError: "namespace cannot be empty",
+		},
+		{
+			name:   "client error",
+			params: map[string]any{"environmentId": float64(1), "namespace": "default", "pod": "web-1", "command": []any{"nope"}},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("ExecKubernetesPod", 1, "default", "web-1", "", models.ExecOptions{Command: []string{"nope"}}).
+					Return(models.ExecResult{}, errors.New("command failed: executable file not found"))
+			},
+			expectError: "failed to exec in kubernetes pod: command failed: executable file not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+
+			s := &PortainerMCPServer{cli: mockClient}
+			result, err := s.HandleExecKubernetesPod()(context.Background(), CreateMCPRequest(tt.params))
+			require.NoError(t, err)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.expectError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tt.expectError)
+			} else {
+				assert.False(t, result.IsError)
+				assert.Contains(t, text, tt.expectText)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestExecErrorTimeout verifies that an exec which exceeded its deadline is reported as a timeout.
+func TestExecErrorTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	result := execError(ctx, "failed to exec in docker container", 5*time.Second, context.DeadlineExceeded)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "command did not finish within 5s")
+
+	result = execError(context.Background(), "failed to exec in docker container", 5*time.Second, errors.New("boom"))
+	assert.Equal(t, "failed to exec in docker container: boom", result.Content[0].(mcp.TextContent).Text)
+}
+
+// TestExecToolsAreWriteGated verifies that the exec actions are hidden in read-only mode.
+func TestExecToolsAreWriteGated(t *testing.T) {
+	for _, def := range metaToolDefinitions() {
+		for _, action := range def.actions {
+			if action.name == "exec_docker_container" || action.name == "exec_kubernetes_pod" {
+				assert.False(t, action.readOnly, "action %s must not be available in read-only mode", action.name)
+			}
+		}
+	}
+}
@@ -34,8 +34,11 @@ ToolUpdateAccessGroupName, ToolUpdateAccessGroupUserAccesses, ToolUpdateAccessGr
 ToolUpdateEnvironmentTags, ToolUpdateEnvironmentUserAccesses, ToolUpdateEnvironmentTeamAccesses,
 ToolUpdateEnvironmentGroupName, ToolUpdateEnvironmentGroupEnvironments, ToolUpdateEnvironmentGroupTags,
 ToolDockerProxy, ToolGetDockerDashboard,
+ToolListDockerContainers, ToolInspectDockerContainer, ToolStartDockerContainer, ToolStopDockerContainer,
+ToolRestartDockerContainer, ToolKillDockerContainer, ToolRemoveDockerContainer, ToolRenameDockerContainer,
+ToolGetDockerContainerLogs, ToolExecDockerContainer,
 ToolKubernetesProxy, ToolKubernetesProxyStripped,
-ToolGetKubernetesDashboard, ToolListKubernetesNamespaces, ToolGetKubernetesConfig,
+ToolGetKubernetesDashboard, ToolListKubernetesNamespaces, ToolGetKubernetesConfig, ToolGetKubernetesPodLogs, ToolExecKubernetesPod,
 ToolGetSystemStatus,
 ToolListCustomTemplates, ToolGetCustomTemplate, ToolGetCustomTemplateFile,
 ToolCreateCustomTemplate, ToolDeleteCustomTemplate,
@@ -148,6 +151,18 @@ assert.NotPanics(t, func() { s.AddDockerProxyFeatures() })
 })
 }
 
+// TestAddDockerContainerFeatures verifies tool registration for Docker containers.
+func TestAddDockerContainerFeatures(t *testing.T) {
+t.Run("read-write", func(t *testing.T) {
+s := newTestServer(false)
+assert.NotPanics(t, func() { s.AddDockerContainerFeatures() })
+})
+t.Run("read-only", func(t *testing.T) {
+s := newTestServer(true)
+assert.NotPanics(t, func() { s.AddDockerContainerFeatures() })
+})
+}
+
 // TestAddEdgeJobFeatures verifies tool registration for edge jobs.
 func TestAddEdgeJobFeatures(t *testing.T) {
 t.Run("read-write", func(t *testing.T) {
@@ -24,9 +24,9 @@ func (s *PortainerMCPServer) AddEnvironmentGroupFeatures() {
 // HandleGetEnvironmentGroups returns an MCP tool handler that retrieves environment groups.
 func (s *PortainerMCPServer) HandleGetEnvironmentGroups() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		edgeGroups, err := s.cli.GetEnvironmentGroups()
+		edgeGroups, err := s.client(ctx).GetEnvironmentGroups(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get environment groups", err), nil
+			return toolErrorFromErr("failed to get environment groups", err), nil
 		}
 
 		return jsonResult(edgeGroups, "failed to marshal environment groups")
@@ -40,7 +40,7 @@ func (s *PortainerMCPServer) HandleCreateEnvironmentGroup() server.ToolHandlerFu
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -48,12 +48,12 @@ func (s *PortainerMCPServer) HandleCreateEnvironmentGroup() server.ToolHandlerFu
 
 		environmentIds, err := parser.GetArrayOfIntegers("environmentIds", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentIds parameter", err), nil
+			return toolErrorFromErr("invalid environmentIds parameter", err), nil
 		}
 
-		id, err := s.cli.CreateEnvironmentGroup(name, environmentIds)
+		id, err := s.client(ctx).CreateEnvironmentGroup(ctx, name, environmentIds)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create environment group", err), nil
+			return toolErrorFromErr("failed to create environment group", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Environment group created successfully with ID: %d", id)), nil
@@ -67,20 +67,20 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupName() server.ToolHandl
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateEnvironmentGroupName(id, name)
+		err = s.client(ctx).UpdateEnvironmentGroupName(ctx, id, name)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update environment group name", err), nil
+			return toolErrorFromErr("failed to update environment group name", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment group name updated successfully"), nil
@@ -94,17 +94,17 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupEnvironments() server.T
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		environmentIds, err := parser.GetArrayOfIntegers("environmentIds", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentIds parameter", err), nil
+			return toolErrorFromErr("invalid environmentIds parameter", err), nil
 		}
 
-		err = s.cli.UpdateEnvironmentGroupEnvironments(id, environmentIds)
+		err = s.client(ctx).UpdateEnvironmentGroupEnvironments(ctx, id, environmentIds)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update environment group environments", err), nil
+			return toolErrorFromErr("failed to update environment group environments", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment group environments updated successfully"), nil
@@ -118,17 +118,17 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupTags() server.ToolHandl
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		tagIds, err := parser.GetArrayOfIntegers("tagIds", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid tagIds parameter", err), nil
+			return toolErrorFromErr("invalid tagIds parameter", err), nil
 		}
 
-		err = s.cli.UpdateEnvironmentGroupTags(id, tagIds)
+		err = s.client(ctx).UpdateEnvironmentGroupTags(ctx, id, tagIds)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update environment group tags", err), nil
+			return toolErrorFromErr("failed to update environment group tags", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment group tags updated successfully"), nil
@@ -2,9 +2,11 @@ package mcp
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
 )
 
@@ -30,15 +32,15 @@ func (s *PortainerMCPServer) HandleListHelmRepositories() server.ToolHandlerFunc
 
 		userId, err := parser.GetInt("userId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userId parameter", err), nil
+			return toolErrorFromErr("invalid userId parameter", err), nil
 		}
 		if err := validatePositiveID("userId", userId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		repos, err := s.cli.GetHelmRepositories(userId)
+		repos, err := s.client(ctx).GetHelmRepositories(ctx, userId)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list helm repositories", err), nil
+			return toolErrorFromErr("failed to list helm repositories", err), nil
 		}
 
 		return jsonResult(repos, "failed to marshal helm repositories")
@@ -52,7 +54,7 @@ func (s *PortainerMCPServer) HandleAddHelmRepository() server.ToolHandlerFunc {
 
 		userId, err := parser.GetInt("userId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userId parameter", err), nil
+			return toolErrorFromErr("invalid userId parameter", err), nil
 		}
 		if err := validatePositiveID("userId", userId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -60,16 +62,16 @@ func (s *PortainerMCPServer) HandleAddHelmRepository() server.ToolHandlerFunc {
 
 		url, err := parser.GetString("url", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid url parameter", err), nil
+			return toolErrorFromErr("invalid url parameter", err), nil
 		}
 
 		if err := validateURL(url); err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid repository URL", err), nil
+			return toolErrorFromErr("invalid repository URL", err), nil
 		}
 
-		repo, err := s.cli.CreateHelmRepository(userId, url)
+		repo, err := s.client(ctx).CreateHelmRepository(ctx, userId, url)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to add helm repository", err), nil
+			return toolErrorFromErr("failed to add helm repository", err), nil
 		}
 
 		return jsonResult(repo, "failed to marshal helm repository")
@@ -83,7 +85,7 @@ func (s *PortainerMCPServer) HandleRemoveHelmRepository() server.ToolHandlerFunc
 
 		userId, err := parser.GetInt("userId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userId parameter", err), nil
+			return toolErrorFromErr("invalid userId parameter", err), nil
 		}
 		if err := validatePositiveID("userId", userId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -91,15 +93,15 @@ func (s *PortainerMCPServer) HandleRemoveHelmRepository() server.ToolHandlerFunc
 
 		repositoryId, err := parser.GetInt("repositoryId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid repositoryId parameter", err), nil
+			return toolErrorFromErr("invalid repositoryId parameter", err), nil
 		}
 		if err := validatePositiveID("repositoryId", repositoryId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteHelmRepository(userId, repositoryId)
+		err = s.client(ctx).DeleteHelmRepository(ctx, userId, repositoryId)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to remove helm repository", err), nil
+			return toolErrorFromErr("failed to remove helm repository", err), nil
 		}
 
 		return mcp.NewToolResultText("Helm repository removed successfully"), nil
@@ -113,21 +115,21 @@ func (s *PortainerMCPServer) HandleSearchHelmCharts() server.ToolHandlerFunc {
 
 		repo, err := parser.GetString("repo", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid repo parameter", err), nil
+			return toolErrorFromErr("invalid repo parameter", err), nil
 		}
 
 		if err := validateURL(repo); err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid repository URL", err), nil
+			return toolErrorFromErr("invalid repository URL", err), nil
 		}
 
 		chart, err := parser.GetString("chart", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid chart parameter", err), nil
+			return toolErrorFromErr("invalid chart parameter", err), nil
 		}
 
-		result, err := s.cli.SearchHelmCharts(repo, chart)
+		result, err := s.client(ctx).SearchHelmCharts(ctx, repo, chart)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to search helm charts", err), nil
+			return toolErrorFromErr("failed to search helm charts", err), nil
 		}
 
 		return mcp.NewToolResultText(result), nil
@@ -141,7 +143,7 @@ func (s *PortainerMCPServer) HandleInstallHelmChart() server.ToolHandlerFunc {
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -149,41 +151,47 @@ func (s *PortainerMCPServer) HandleInstallHelmChart() server.ToolHandlerFunc {
 
 		chart, err := parser.GetString("chart", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid chart parameter", err), nil
+			return toolErrorFromErr("invalid chart parameter", err), nil
 		}
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 
 		repo, err := parser.GetString("repo", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid repo parameter", err), nil
+			return toolErrorFromErr("invalid repo parameter", err), nil
 		}
 
 		if err := validateURL(repo); err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid repository URL", err), nil
+			return toolErrorFromErr("invalid repository URL", err), nil
 		}
 
 		namespace, err := parser.GetString("namespace", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+			return toolErrorFromErr("invalid namespace parameter", err), nil
 		}
 
 		values, err := parser.GetString("values", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid values parameter", err), nil
+			return toolErrorFromErr("invalid values parameter", err), nil
 		}
 
 		version, err := parser.GetString("version", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid version parameter", err), nil
+			return toolErrorFromErr("invalid version parameter", err), nil
 		}
 
-		release, err := s.cli.InstallHelmChart(environmentId, chart, name, namespace, repo, values, version)
+		var release models.HelmReleaseDetails
+		progress := s.newProgressReporter(ctx, request)
+		err = progress.run(fmt.Sprintf("Installing Helm chart %s as release %s", chart, name), func() error {
+			var err error
+			release, err = s.client(ctx).InstallHelmChart(ctx, environmentId, chart, name, namespace, repo, values, version)
+			return err
+		})
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to install helm chart", err), nil
+			return toolErrorFromErr("failed to install helm chart", err), nil
 		}
 
 		return jsonResult(release, "failed to marshal helm release")
@@ -197,7 +205,7 @@ func (s *PortainerMCPServer) HandleListHelmReleases() server.ToolHandlerFunc {
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -205,22 +213,22 @@ func (s *PortainerMCPServer) HandleListHelmReleases() server.ToolHandlerFunc {
 
 		namespace, err := parser.GetString("namespace", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+			return toolErrorFromErr("invalid namespace parameter", err), nil
 		}
 
 		filter, err := parser.GetString("filter", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid filter parameter", err), nil
+			return toolErrorFromErr("invalid filter parameter", err), nil
 		}
 
 		selector, err := parser.GetString("selector", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid selector parameter", err), nil
+			return toolErrorFromErr("invalid selector parameter", err), nil
 		}
 
-		releases, err := s.cli.GetHelmReleases(environmentId, namespace, filter, selector)
+		releases, err := s.client(ctx).GetHelmReleases(ctx, environmentId, namespace, filter, selector)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list helm releases", err), nil
+			return toolErrorFromErr("failed to list helm releases", err), nil
 		}
 
 		return jsonResult(releases, "failed to marshal helm releases")
@@ -234,7 +242,7 @@ func (s *PortainerMCPServer) HandleDeleteHelmRelease() server.ToolHandlerFunc {
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -242,17 +250,17 @@ func (s *PortainerMCPServer) HandleDeleteHelmRelease() server.ToolHandlerFunc {
 
 		release, err := parser.GetString("release", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid release parameter", err), nil
+			return toolErrorFromErr("invalid release parameter", err), nil
 		}
 
 		namespace, err := parser.GetString("namespace", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+			return toolErrorFromErr("invalid namespace parameter", err), nil
 		}
 
-		err = s.cli.DeleteHelmRelease(environmentId, release, namespace)
+		err = s.client(ctx).DeleteHelmRelease(ctx, environmentId, release, namespace)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete helm release", err), nil
+			return toolErrorFromErr("failed to delete helm release", err), nil
 		}
 
 		return mcp.NewToolResultText("Helm release deleted successfully"), nil
@@ -266,7 +274,7 @@ func (s *PortainerMCPServer) HandleGetHelmReleaseHistory() server.ToolHandlerFun
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -274,17 +282,17 @@ func (s *PortainerMCPServer) HandleGetHelmReleaseHistory() server.ToolHandlerFun
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 
 		namespace, err := parser.GetString("namespace", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+			return toolErrorFromErr("invalid namespace parameter", err), nil
 		}
 
-		history, err := s.cli.GetHelmReleaseHistory(environmentId, name, namespace)
+		history, err := s.client(ctx).GetHelmReleaseHistory(ctx, environmentId, name, namespace)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get helm release history", err), nil
+			return toolErrorFromErr("failed to get helm release history", err), nil
 		}
 
 		return jsonResult(history, "failed to marshal helm release history")
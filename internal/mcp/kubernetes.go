@@ -29,7 +29,7 @@ func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerF
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -37,28 +37,31 @@ func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerF
 
 		kubernetesAPIPath, err := parser.GetString("kubernetesAPIPath", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid kubernetesAPIPath parameter", err), nil
+			return toolErrorFromErr("invalid kubernetesAPIPath parameter", err), nil
 		}
 		if !strings.HasPrefix(kubernetesAPIPath, "/") {
 			return mcp.NewToolResultError("kubernetesAPIPath must start with a leading slash"), nil
 		}
+		if err := s.policy.checkKubernetesProxy("GET", kubernetesAPIPath); err != nil {
+			return toolErrorFromErr("Kubernetes API request denied by policy", err), nil
+		}
 
 		queryParams, err := parser.GetArrayOfObjects("queryParams", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid queryParams parameter", err), nil
+			return toolErrorFromErr("invalid queryParams parameter", err), nil
 		}
 		queryParamsMap, err := parseKeyValueMap(queryParams)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid query params", err), nil
+			return toolErrorFromErr("invalid query params", err), nil
 		}
 
 		headers, err := parser.GetArrayOfObjects("headers", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid headers parameter", err), nil
+			return toolErrorFromErr("invalid headers parameter", err), nil
 		}
 		headersMap, err := parseKeyValueMap(headers)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid headers", err), nil
+			return toolErrorFromErr("invalid headers", err), nil
 		}
 
 		opts := models.KubernetesProxyRequestOptions{
@@ -69,14 +72,14 @@ func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerF
 			Headers:       headersMap,
 		}
 
-		response, err := s.cli.ProxyKubernetesRequest(opts)
+		response, err := s.client(ctx).ProxyKubernetesRequest(ctx, opts)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
+			return toolErrorFromErr("failed to send Kubernetes API request", err), nil
 		}
 
 		responseBody, err := k8sutil.ProcessRawKubernetesAPIResponse(response)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to process Kubernetes API response", err), nil
+			return toolErrorFromErr("failed to process Kubernetes API response", err), nil
 		}
 
 		return mcp.NewToolResultText(string(responseBody)), nil
@@ -86,17 +89,17 @@ func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerF
 // HandleKubernetesProxy proxies arbitrary Kubernetes API requests to a Portainer environment.
 //
 // SECURITY NOTE: This handler allows the caller to invoke any Kubernetes API endpoint
-// on the target environment. There is no allowlist restricting which API paths are
-// permitted. Access control relies entirely on the Portainer API token permissions and
-// the read-only mode flag. Operators should be aware that this grants broad Kubernetes
-// API access to whoever holds the MCP server's Portainer token.
+// on the target environment. Unless the policy file restricts the permitted methods and
+// paths under proxy.kubernetes, access control relies entirely on the Portainer API token
+// permissions and the read-only mode flag. Operators should be aware that this grants
+// broad Kubernetes API access to whoever holds the MCP server's Portainer token.
 func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		parser := toolgen.NewParameterParser(request)
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -104,7 +107,7 @@ func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 
 		method, err := parser.GetString("method", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid method parameter", err), nil
+			return toolErrorFromErr("invalid method parameter", err), nil
 		}
 		if !isValidHTTPMethod(method) {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid method: %s", method)), nil
@@ -112,33 +115,36 @@ func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 
 		kubernetesAPIPath, err := parser.GetString("kubernetesAPIPath", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid kubernetesAPIPath parameter", err), nil
+			return toolErrorFromErr("invalid kubernetesAPIPath parameter", err), nil
 		}
 		if !strings.HasPrefix(kubernetesAPIPath, "/") {
 			return mcp.NewToolResultError("kubernetesAPIPath must start with a leading slash"), nil
 		}
+		if err := s.policy.checkKubernetesProxy(method, kubernetesAPIPath); err != nil {
+			return toolErrorFromErr("Kubernetes API request denied by policy", err), nil
+		}
 
 		queryParams, err := parser.GetArrayOfObjects("queryParams", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid queryParams parameter", err), nil
+			return toolErrorFromErr("invalid queryParams parameter", err), nil
 		}
 		queryParamsMap, err := parseKeyValueMap(queryParams)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid query params", err), nil
+			return toolErrorFromErr("invalid query params", err), nil
 		}
 
 		headers, err := parser.GetArrayOfObjects("headers", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid headers parameter", err), nil
+			return toolErrorFromErr("invalid headers parameter", err), nil
 		}
 		headersMap, err := parseKeyValueMap(headers)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid headers", err), nil
+			return toolErrorFromErr("invalid headers", err), nil
 		}
 
 		body, err := parser.GetString("body", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid body parameter", err), nil
+			return toolErrorFromErr("invalid body parameter", err), nil
 		}
 
 		opts := models.KubernetesProxyRequestOptions{
@@ -153,15 +159,15 @@ func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 			opts.Body = strings.NewReader(body)
 		}
 
-		response, err := s.cli.ProxyKubernetesRequest(opts)
+		response, err := s.client(ctx).ProxyKubernetesRequest(ctx, opts)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
+			return toolErrorFromErr("failed to send Kubernetes API request", err), nil
 		}
 		defer response.Body.Close()
 
 		responseBody, err := io.ReadAll(io.LimitReader(response.Body, maxProxyResponseSize))
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to read Kubernetes API response", err), nil
+			return toolErrorFromErr("failed to read Kubernetes API response", err), nil
 		}
 
 		return mcp.NewToolResultText(string(responseBody)), nil
@@ -173,6 +179,11 @@ func (s *PortainerMCPServer) AddKubernetesNativeFeatures() {
 	s.addToolIfExists(ToolGetKubernetesDashboard, s.HandleGetKubernetesDashboard())
 	s.addToolIfExists(ToolListKubernetesNamespaces, s.HandleListKubernetesNamespaces())
 	s.addToolIfExists(ToolGetKubernetesConfig, s.HandleGetKubernetesConfig())
+	s.addToolIfExists(ToolGetKubernetesPodLogs, s.HandleGetKubernetesPodLogs())
+
+	if !s.readOnly {
+		s.addToolIfExists(ToolExecKubernetesPod, s.HandleExecKubernetesPod())
+	}
 }
 
 // HandleGetKubernetesDashboard returns an MCP tool handler that retrieves kubernetes dashboard.
@@ -182,15 +193,15 @@ func (s *PortainerMCPServer) HandleGetKubernetesDashboard() server.ToolHandlerFu
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		dashboard, err := s.cli.GetKubernetesDashboard(environmentId)
+		dashboard, err := s.client(ctx).GetKubernetesDashboard(ctx, environmentId)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get kubernetes dashboard", err), nil
+			return toolErrorFromErr("failed to get kubernetes dashboard", err), nil
 		}
 
 		return jsonResult(dashboard, "failed to marshal kubernetes dashboard")
@@ -204,15 +215,15 @@ func (s *PortainerMCPServer) HandleListKubernetesNamespaces() server.ToolHandler
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		namespaces, err := s.cli.GetKubernetesNamespaces(environmentId)
+		namespaces, err := s.client(ctx).GetKubernetesNamespaces(ctx, environmentId)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get kubernetes namespaces", err), nil
+			return toolErrorFromErr("failed to get kubernetes namespaces", err), nil
 		}
 
 		return jsonResult(namespaces, "failed to marshal kubernetes namespaces")
@@ -226,15 +237,15 @@ func (s *PortainerMCPServer) HandleGetKubernetesConfig() server.ToolHandlerFunc
 
 		environmentId, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", environmentId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		config, err := s.cli.GetKubernetesConfig(environmentId)
+		config, err := s.client(ctx).GetKubernetesConfig(ctx, environmentId)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get kubernetes config", err), nil
+			return toolErrorFromErr("failed to get kubernetes config", err), nil
 		}
 
 		switch v := config.(type) {
@@ -245,3 +256,128 @@ func (s *PortainerMCPServer) HandleGetKubernetesConfig() server.ToolHandlerFunc
 		}
 	}
 }
+
+// defaultPodLogTailLines is the number of log lines returned per pod when the tailLines parameter is omitted.
+const defaultPodLogTailLines = 100
+
+// HandleGetKubernetesPodLogs returns an MCP tool handler that retrieves the logs of a Kubernetes pod,
+// or of every pod matching a label selector, as plain text.
+func (s *PortainerMCPServer) HandleGetKubernetesPodLogs() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		environmentId, err := parser.GetInt("environmentId", true)
+		if err != nil {
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
+		}
+		if err := validatePositiveID("environmentId", environmentId); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		namespace, err := parser.GetString("namespace", true)
+		if err != nil {
+			return toolErrorFromErr("invalid namespace parameter", err), nil
+		}
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" {
+			return mcp.NewToolResultError("namespace cannot be empty or whitespace-only"), nil
+		}
+
+		pod, err := parser.GetString("pod", false)
+		if err != nil {
+			return toolErrorFromErr("invalid pod parameter", err), nil
+		}
+		labelSelector, err := parser.GetString("labelSelector", false)
+		if err != nil {
+			return toolErrorFromErr("invalid labelSelector parameter", err), nil
+		}
+		pod, labelSelector = strings.TrimSpace(pod), strings.TrimSpace(labelSelector)
+		if (pod == "") == (labelSelector == "") {
+			return mcp.NewToolResultError("exactly one of pod or labelSelector must be provided"), nil
+		}
+
+		container, err := parser.GetString("container", false)
+		if err != nil {
+			return toolErrorFromErr("invalid container parameter", err), nil
+		}
+
+		previous, err := parser.GetBoolean("previous", false)
+		if err != nil {
+			return toolErrorFromErr("invalid previous parameter", err), nil
+		}
+
+		tailLines := defaultPodLogTailLines
+		if _, ok := request.GetArguments()["tailLines"]; ok {
+			tailLines, err = parser.GetInt("tailLines", false)
+			if err != nil {
+				return toolErrorFromErr("invalid tailLines parameter", err), nil
+			}
+			if tailLines < 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("tailLines must be zero or a positive number of lines, got %d", tailLines)), nil
+			}
+		}
+
+		sinceSeconds, err := parser.GetInt("sinceSeconds", false)
+		if err != nil {
+			return toolErrorFromErr("invalid sinceSeconds parameter", err), nil
+		}
+		if sinceSeconds < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("sinceSeconds must be a positive number of seconds, got %d", sinceSeconds)), nil
+		}
+
+		logs, err := s.client(ctx).GetKubernetesPodLogs(ctx, environmentId, namespace, models.KubernetesPodLogOptions{
+			Pod:           pod,
+			LabelSelector: labelSelector,
+			Container:     strings.TrimSpace(container),
+			Previous:      previous,
+			TailLines:     tailLines,
+			SinceSeconds:  sinceSeconds,
+		})
+		if err != nil {
+			return toolErrorFromErr("failed to get kubernetes pod logs", err), nil
+		}
+
+		if labelSelector == "" {
+			return mcp.NewToolResultText(formatPodLogs(logs[0])), nil
+		}
+		if len(logs) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No pods in namespace %s match label selector %q", namespace, labelSelector)), nil
+		}
+
+		var b strings.Builder
+		for i, podLogs := range logs {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			name := podLogs.Pod
+			if podLogs.Container != "" {
+				name += "/" + podLogs.Container
+			}
+			fmt.Fprintf(&b, "==> %s <==\n", name)
+			if podLogs.Error != "" {
+				fmt.Fprintf(&b, "error: %s\n", podLogs.Error)
+				continue
+			}
+			b.WriteString(formatPodLogs(podLogs))
+		}
+
+		return mcp.NewToolResultText(b.String()), nil
+	}
+}
+
+// formatPodLogs renders the logs of one pod, noting empty or truncated output.
+func formatPodLogs(logs models.KubernetesPodLogs) string {
+	if logs.Logs == "" {
+		return fmt.Sprintf("No log output for pod %s\n", logs.Pod)
+	}
+
+	text := logs.Logs
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if logs.Truncated {
+		text += "... log output truncated; use tailLines or sinceSeconds to narrow the range\n"
+	}
+
+	return text
+}
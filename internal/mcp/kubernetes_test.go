@@ -857,3 +857,126 @@ _, err := handler(context.Background(), request)
 assert.NoError(t, err)
 assert.True(t, tc.closed, "response body should be closed after handler returns")
 }
+
+// TestHandleGetKubernetesPodLogs verifies the HandleGetKubernetesPodLogs MCP tool handler,
+// covering single-pod and label-selector output as well as parameter validation.
+func TestHandleGetKubernetesPodLogs(t *testing.T) {
+	tests := []struct {
+		name             string
+		inputParams      map[string]any
+		setupMock        func(m *MockPortainerClient)
+		expectedResult   string
+		expectedErrorMsg string
+	}{
+		{
+			name:        "single pod with default tail",
+			inputParams: map[string]any{"environmentId": float64(1), "namespace": "default", "pod": "web-1"},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetKubernetesPodLogs", 1, "default", models.KubernetesPodLogOptions{Pod: "web-1", TailLines: 100}).
+					Return([]models.KubernetesPodLogs{{Pod: "web-1", Logs: "line 1\nline 2"}}, nil)
+			},
+			expectedResult: "line 1\nline 2\n",
+		},
+		{
+			name: "previous container with all lines",
+			inputParams: map[string]any{
+				"environmentId": float64(1),
+				"namespace":     "default",
+				"pod":           "web-1",
+				"container":     "app",
+				"previous":      true,
+				"tailLines":     float64(0),
+				"sinceSeconds":  float64(600),
+			},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetKubernetesPodLogs", 1, "default", models.KubernetesPodLogOptions{
+					Pod: "web-1", Container: "app", Previous: true, SinceSeconds: 600,
+				}).Return([]models.KubernetesPodLogs{{Pod: "web-1", Container: "app", Logs: "panic: boom\n", Truncated: true}}, nil)
+			},
+			expectedResult: "panic: boom\n... log output truncated; use tailLines or sinceSeconds to narrow the range\n",
+		},
+		{
+			name:        "label selector fan-out",
+			inputParams: map[string]any{"environmentId": float64(1), "namespace": "default", "labelSelector": "app=web"},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetKubernetesPodLogs", 1, "default", models.KubernetesPodLogOptions{LabelSelector: "app=web", TailLines: 100}).
+					Return([]models.KubernetesPodLogs{
+						{Pod: "web-1", Logs: "ready\n"},
+						{Pod: "web-2", Error: "kubernetes API returned status 400: container is waiting to start"},
+						{Pod: "web-3"},
+					}, nil)
+			},
+			expectedResult: "==> web-1 <==\nready\n\n" +
+				"==> web-2 <==\nerror: kubernetes API returned status 400: container is waiting to start\n\n" +
+				"==> web-3 <==\nNo log output for pod web-3\n",
+		},
+		{
+			name:        "label selector without matches",
+			inputParams: map[string]any{"environmentId": float64(1), "namespace": "default", "labelSelector": "app=none"},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetKubernetesPodLogs", 1, "default", models.KubernetesPodLogOptions{LabelSelector: "app=none", TailLines: 100}).
+					Return([]models.KubernetesPodLogs{}, nil)
+			},
+			expectedResult: `No pods in namespace default match label selector "app=none"`,
+		},
+		{
+			name:             "pod and label selector",
+			inputParams:      map[string]any{"environmentId": float64(1), "namespace": "default", "pod": "web-1", "labelSelector": "app=web"},
+			expectedErrorMsg: "exactly one of pod or labelSelector must be provided",
+		},
+		{
+			name:             "neither pod nor label selector",
+			inputParams:      map[string]any{"environmentId": float64(1), "namespace": "default"},
+			expectedErrorMsg: "exactly one of pod or labelSelector must be provided",
+		},
+		{
+			name:             "missing namespace",
+			inputParams:      map[string]any{"environmentId": float64(1), "pod": "web-1"},
+			expectedErrorMsg: "namespace",
+		},
+		{
+			name:             "negative tailLines",
+			inputParams:      map[string]any{"environmentId": float64(1), "namespace": "default", "pod": "web-1", "tailLines": float64(-5)},
+			expectedErrorMsg: "tailLines must be zero or a positive number of lines",
+		},
+		{
+			name:             "negative sinceSeconds",
+			inputParams:      map[string]any{"environmentId": float64(1), "namespace": "default", "pod": "web-1", "sinceSeconds": float64(-1)},
+			expectedErrorMsg: "sinceSeconds must be a positive number of seconds",
+		},
+		{
+			name:        "client error",
+			inputParams: map[string]any{"environmentId": float64(1), "namespace": "default", "pod": "web-1"},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetKubernetesPodLogs", 1, "default", models.KubernetesPodLogOptions{Pod: "web-1", TailLines: 100}).
+					Return(nil, errors.New("pods \"web-1\" not found"))
+			},
+			expectedErrorMsg: "failed to get kubernetes pod logs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+
+			server := &PortainerMCPServer{cli: mockClient}
+			result, err := server.HandleGetKubernetesPodLogs()(context.Background(), CreateMCPRequest(tt.inputParams))
+
+			assert.NoError(t, err)
+			textContent, ok := result.Content[0].(mcp.TextContent)
+			assert.True(t, ok)
+			if tt.expectedErrorMsg != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tt.expectedErrorMsg)
+			} else {
+				assert.False(t, result.IsError)
+				assert.Equal(t, tt.expectedResult, textContent.Text)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -11,9 +12,9 @@ import (
 
 // RegisterMetaTools builds and registers all meta-tools on the MCP server.
 // In read-only mode, write actions are excluded from the action enum and
-// their handlers are not registered. If a meta-tool has no available
-// actions after filtering (e.g. all are write-only and read-only is on),
-// it is silently skipped.
+// their handlers are not registered, and so are the actions denied by the
+// policy file. If a meta-tool has no available actions after filtering
+// (e.g. all are write-only and read-only is on), it is silently skipped.
 func (s *PortainerMCPServer) RegisterMetaTools() {
 	defs := metaToolDefinitions()
 	for _, def := range defs {
@@ -22,12 +23,12 @@ func (s *PortainerMCPServer) RegisterMetaTools() {
 }
 
 // registerOneMetaTool builds a single meta-tool from its definition,
-// filtering actions by read-only mode, and registers it.
+// filtering actions by read-only mode and policy, and registers it.
 func (s *PortainerMCPServer) registerOneMetaTool(def metaToolDef) {
-	// Filter actions based on read-only mode
+	// Filter actions based on read-only mode and policy
 	available := make([]metaAction, 0, len(def.actions))
 	for _, a := range def.actions {
-		if s.readOnly && !a.readOnly {
+		if !s.actionAllowed(def.name, a) {
 			continue
 		}
 		available = append(available, a)
@@ -40,9 +41,59 @@ func (s *PortainerMCPServer) registerOneMetaTool(def metaToolDef) {
 	// Build action enum values and handler dispatch map
 	actionNames := make([]string, len(available))
 	handlers := make(map[string]server.ToolHandlerFunc, len(available))
+	actionTools := make(map[string]string, len(available))
+	needsConfirmation := false
+	needsIncludeSecrets := false
+	hasList := false
+	var genericFilterActions []string
+	ownFilters := map[string]map[string]any{}
+	needsRefresh := false
+	var nameArgs []string
 	for i, a := range available {
 		actionNames[i] = a.name
 		handlers[a.name] = a.handler(s)
+		actionTools[a.name] = a.tool
+		if isListTool(a.tool) {
+			ownFilter := hasOwnFilter(s.tools[a.tool])
+			handlers[a.name] = s.withPagination(ownFilter, handlers[a.name])
+			hasList = true
+			if ownFilter {
+				ownFilters[a.name] = ownFilterSchema(s.tools[a.tool])
+			} else {
+				genericFilterActions = append(genericFilterActions, a.name)
+			}
+		}
+		if !a.readOnly {
+			handlers[a.name] = s.withDryRun(handlers[a.name])
+		} else if s.cacheStats != nil {
+			handlers[a.name] = withCacheRefreshArgument(handlers[a.name])
+			needsRefresh = true
+		}
+		if s.requiresConfirmation(a.tool) {
+			handlers[a.name] = s.withConfirmation(handlers[a.name])
+			needsConfirmation = true
+		}
+		if s.policy.scoped() {
+			handlers[a.name] = s.withEnvironmentScope(a.tool, handlers[a.name])
+		}
+		if s.redactor != nil {
+			target := policyTarget{metaTool: def.name, action: a.name, tool: a.tool}
+			if s.policy.allowsOptOut(target) {
+				needsIncludeSecrets = true
+			}
+			handlers[a.name] = s.withRedaction(target, handlers[a.name])
+		}
+		if params := nameParams(a.tool, s.tools[a.tool]); len(params) > 0 {
+			handlers[a.name] = s.withNameResolution(params, handlers[a.name])
+			for _, p := range params {
+				if !slices.Contains(nameArgs, p.name) {
+					nameArgs = append(nameArgs, p.name)
+				}
+			}
+		}
+		if s.limiter != nil {
+			handlers[a.name] = s.withRateLimit(policyTarget{metaTool: def.name, action: a.name, tool: a.tool}, handlers[a.name])
+		}
 	}
 
 	// Compute annotation: if ALL remaining actions are read-only, mark the
@@ -61,7 +112,7 @@ func (s *PortainerMCPServer) registerOneMetaTool(def metaToolDef) {
 	}
 
 	// Build the MCP tool programmatically
-	tool := mcp.NewTool(def.name,
+	toolOptions := []mcp.ToolOption{
 		mcp.WithDescription(def.description),
 		mcp.WithToolAnnotation(annotation),
 		mcp.WithString("action",
@@ -69,10 +120,27 @@ func (s *PortainerMCPServer) registerOneMetaTool(def metaToolDef) {
 			mcp.Description(fmt.Sprintf("The operation to perform. Available actions: %s", strings.Join(actionNames, ", "))),
 			mcp.Enum(actionNames...),
 		),
-	)
+	}
+	if !allReadOnly {
+		toolOptions = append(toolOptions, mcp.WithBoolean(dryRunParam, mcp.Description(dryRunParamDescription+". Ignored by read-only actions")))
+	}
+	if needsConfirmation {
+		toolOptions = append(toolOptions, mcp.WithString(confirmationTokenParam, mcp.Description(confirmationTokenParamDescription+". Ignored by non-destructive actions")))
+	}
+	if needsRefresh {
+		toolOptions = append(toolOptions, mcp.WithBoolean(refreshParam, mcp.Description(refreshParamDescription+". Only used by read-only actions")))
+	}
+	if hasList {
+		toolOptions = append(toolOptions, paginationToolOptions(genericFilterActions, ownFilters)...)
+	}
+	toolOptions = append(toolOptions, nameToolOptions(nameArgs)...)
+	if needsIncludeSecrets {
+		toolOptions = append(toolOptions, mcp.WithBoolean(includeSecretsParam, mcp.Description(includeSecretsParamDescription+". Only accepted by the actions the server policy allows")))
+	}
+	tool := mcp.NewTool(def.name, toolOptions...)
 
 	// Register the meta-tool with a routing handler
-	s.srv.AddTool(tool, makeMetaHandler(def.name, handlers))
+	s.srv.AddTool(tool, s.withTracing(def.name, actionTools, s.withMetrics(def.name, handlers, s.withAudit(def.name, makeMetaHandler(def.name, handlers)))))
 }
 
 // makeMetaHandler creates a ToolHandlerFunc that routes to the correct
@@ -60,7 +60,7 @@ func listRegisteredTools(t *testing.T, srv *server.MCPServer) []string {
 }
 
 // TestMetaToolDefinitionsCount verifies that metaToolDefinitions returns
-// exactly 15 groups with 98 total actions.
+// exactly 15 groups with 110 total actions.
 func TestMetaToolDefinitionsCount(t *testing.T) {
 	defs := metaToolDefinitions()
 	assert.Equal(t, 15, len(defs), "expected 15 meta-tool groups")
@@ -69,7 +69,7 @@ func TestMetaToolDefinitionsCount(t *testing.T) {
 	for _, def := range defs {
 		totalActions += len(def.actions)
 	}
-	assert.Equal(t, 98, totalActions, "expected 98 total actions across all meta-tools")
+	assert.Equal(t, 110, totalActions, "expected 110 total actions across all meta-tools")
 }
 
 // TestMetaToolUniqueActionNames verifies that all action names within each
@@ -0,0 +1,293 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// metricsNamespace prefixes the name of every metric.
+	metricsNamespace = "portainer_mcp"
+	// metricsEndpoint is the path the metrics are served on.
+	metricsEndpoint = "/metrics"
+)
+
+// Error classes of failed tool calls, used as the class label of the tool error counter.
+const (
+	errorClassNotFound    = "not_found"
+	errorClassForbidden   = "forbidden"
+	errorClassConflict    = "conflict"
+	errorClassUnavailable = "unavailable"
+	errorClassRateLimited = "rate_limited"
+	errorClassPolicy      = "policy"
+	errorClassInternal    = "internal"
+	errorClassOther       = "other"
+)
+
+// upstreamAPIPortainer is the api label of the upstream requests to the
+// Portainer API itself. Proxy requests are labelled docker or kubernetes.
+const upstreamAPIPortainer = "portainer"
+
+// proxyPathPattern matches the paths of Docker and Kubernetes proxy requests;
+// its first group is the api label.
+var proxyPathPattern = regexp.MustCompile(`^/api/endpoints/\d+/(docker|kubernetes)(/|$)`)
+
+// serverMetrics holds the Prometheus metrics of the server. Each server has
+// its own registry, so that several servers can run in one process.
+type serverMetrics struct {
+	registry *prometheus.Registry
+
+	toolCalls          *prometheus.CounterVec
+	toolDuration       *prometheus.HistogramVec
+	toolErrors         *prometheus.CounterVec
+	upstreamDuration   *prometheus.HistogramVec
+	proxyResponseBytes *prometheus.HistogramVec
+}
+
+// newServerMetrics creates and registers the metrics of the server, the Go
+// runtime and process metrics, and the client cache statistics when stats is not nil.
+func newServerMetrics(stats *cacheStats) *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_calls_total",
+			Help:      "Number of tool calls, by tool and meta-tool action.",
+		}, []string{"tool", "action"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Duration of tool calls, by tool and meta-tool action.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"tool", "action"}),
+		toolErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_errors_total",
+			Help:      "Number of failed tool calls, by tool, meta-tool action, and error class.",
+		}, []string{"tool", "action", "class"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Duration of the requests sent to Portainer, counting each retry separately, by API, method, and status code.",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"api", "method", "code"}),
+		proxyResponseBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "proxy_response_size_bytes",
+			Help:      "Size of the Docker and Kubernetes proxy response bodies read by the server.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 9),
+		}, []string{"api"}),
+	}
+
+	m.registry.MustRegister(
+		m.toolCalls,
+		m.toolDuration,
+		m.toolErrors,
+		m.upstreamDuration,
+		m.proxyResponseBytes,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if stats != nil {
+		m.registry.MustRegister(newCacheStatsCollector(stats))
+	}
+	return m
+}
+
+// withMetrics wraps a tool handler so that its calls, duration, and errors are
+// recorded. For meta-tools, the action label is the action argument when it is
+// one of actions, and "unknown" otherwise, so that callers cannot create new
+// label values.
+func (s *PortainerMCPServer) withMetrics(toolName string, actions map[string]server.ToolHandlerFunc, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if s.metrics == nil {
+		return next
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		action := ""
+		if actions != nil {
+			action = "unknown"
+			if name, ok := request.GetArguments()["action"].(string); ok {
+				if _, known := actions[name]; known {
+					action = name
+				}
+			}
+		}
+
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		s.metrics.toolCalls.WithLabelValues(toolName, action).Inc()
+		s.metrics.toolDuration.WithLabelValues(toolName, action).Observe(time.Since(start).Seconds())
+		if class := toolErrorClass(result, err); class != "" {
+			s.metrics.toolErrors.WithLabelValues(toolName, action, class).Inc()
+		}
+		return result, err
+	}
+}
+
+// toolErrorClass returns the error class of a tool call, or "" when it succeeded.
+func toolErrorClass(result *mcp.CallToolResult, err error) string {
+	if err != nil {
+		return errorClassInternal
+	}
+	if result == nil || !result.IsError {
+		return ""
+	}
+
+	text := resultText(result)
+	for _, h := range apiErrorHints {
+		if strings.HasSuffix(text, h.hint) {
+			return h.class
+		}
+	}
+	switch {
+	case strings.Contains(text, `"rateLimited":true`):
+		return errorClassRateLimited
+	case strings.Contains(text, "denied by policy"), strings.Contains(text, "by the server policy"), strings.Contains(text, "is out of scope"):
+		return errorClassPolicy
+	default:
+		return errorClassOther
+	}
+}
+
+// wrapTransport returns a transport that records the duration and status of
+// every request sent to Portainer, and the size of the proxy response bodies.
+// It is passed to the Portainer client with client.WithTransportWrapper.
+func (m *serverMetrics) wrapTransport(next http.RoundTripper) http.RoundTripper {
+	return &metricsTransport{next: next, metrics: m}
+}
+
+// metricsTransport is the http.RoundTripper returned by [serverMetrics.wrapTransport].
+type metricsTransport struct {
+	next    http.RoundTripper
+	metrics *serverMetrics
+}
+
+// RoundTrip sends the request and records its metrics.
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	api := upstreamAPIPortainer
+	if match := proxyPathPattern.FindStringSubmatch(req.URL.Path); match != nil {
+		api = match[1]
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	t.metrics.upstreamDuration.WithLabelValues(api, req.Method, code).Observe(time.Since(start).Seconds())
+
+	// A switched protocol response body is the connection itself and must
+	// keep implementing io.ReadWriteCloser, so it is not counted.
+	if err == nil && api != upstreamAPIPortainer && resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body = &countingBody{ReadCloser: resp.Body, observe: t.metrics.proxyResponseBytes.WithLabelValues(api)}
+	}
+	return resp, err
+}
+
+// countingBody counts the bytes read from a response body and records them
+// when the body is closed.
+type countingBody struct {
+	io.ReadCloser
+	observe prometheus.Observer
+	n       int64
+	once    sync.Once
+}
+
+// Read reads from the body and counts the bytes read.
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// Close closes the body and records the number of bytes read.
+func (b *countingBody) Close() error {
+	b.once.Do(func() { b.observe.Observe(float64(b.n)) })
+	return b.ReadCloser.Close()
+}
+
+// cacheStatsCollector exports the hits and misses of the client cache.
+type cacheStatsCollector struct {
+	stats  *cacheStats
+	hits   *prometheus.Desc
+	misses *prometheus.Desc
+}
+
+// newCacheStatsCollector returns a collector that reads stats at every scrape.
+func newCacheStatsCollector(stats *cacheStats) *cacheStatsCollector {
+	return &cacheStatsCollector{
+		stats:  stats,
+		hits:   prometheus.NewDesc(metricsNamespace+"_cache_hits_total", "Number of Portainer reads served from the client cache, by client method.", []string{"method"}, nil),
+		misses: prometheus.NewDesc(metricsNamespace+"_cache_misses_total", "Number of Portainer reads the client cache sent to Portainer, by client method.", []string{"method"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *cacheStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+}
+
+// Collect implements prometheus.Collector.
+func (c *cacheStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, method := range c.stats.snapshot() {
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(method.Hits), method.Method)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(method.Misses), method.Method)
+	}
+}
+
+// handler returns the HTTP handler serving the metrics on [metricsEndpoint].
+func (m *serverMetrics) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(metricsEndpoint, promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// serveMetrics listens on addr and serves the metrics on [metricsEndpoint]
+// until the returned function is called. The listener is opened before it
+// returns, so that an invalid or busy address is reported at startup.
+func (s *PortainerMCPServer) serveMetrics(addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for metrics on %s: %w", addr, err)
+	}
+
+	httpSrv := &http.Server{Handler: s.metrics.handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := httpSrv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("Metrics listener failed")
+		}
+	}()
+	log.Info().Str("addr", listener.Addr().String()).Str("endpoint", metricsEndpoint).Msg("Serving Prometheus metrics")
+
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("Failed to shut down metrics listener")
+		}
+		<-done
+	}, nil
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestWithMetrics verifies that meta-tool calls are counted by action and error class.
+func TestWithMetrics(t *testing.T) {
+	s := newTestMetaServer(false)
+	s.metrics = newServerMetrics(nil)
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetUsers").Return([]models.User{}, nil).Once()
+	mockClient.On("GetUsers").Return(nil, &client.APIError{Kind: client.ErrUnavailable, Err: errors.New("connection reset by peer")})
+	s.RegisterMetaTools()
+
+	call := func(arguments string) {
+		resp := s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"manage_users","arguments":`+arguments+`}}`))
+		_, err := json.Marshal(resp)
+		require.NoError(t, err)
+	}
+	call(`{"action":"list_users"}`)
+	call(`{"action":"list_users"}`)
+	call(`{"action":"drop_all_users"}`)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(s.metrics.toolCalls.WithLabelValues("manage_users", "list_users")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(s.metrics.toolCalls.WithLabelValues("manage_users", "unknown")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(s.metrics.toolErrors.WithLabelValues("manage_users", "list_users", errorClassUnavailable)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(s.metrics.toolErrors.WithLabelValues("manage_users", "unknown", errorClassOther)))
+	assert.Equal(t, 2, testutil.CollectAndCount(s.metrics.toolDuration))
+}
+
+// TestToolErrorClass verifies the classification of failed tool calls.
+func TestToolErrorClass(t *testing.T) {
+	rateLimitedResult, err := rateLimited(0, "1 calls per minute for dockerProxy")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		result   *mcp.CallToolResult
+		err      error
+		expected string
+	}{
+		{
+			name:     "success",
+			result:   mcp.NewToolResultText("ok"),
+			expected: "",
+		},
+		{
+			name:     "handler error",
+			err:      errors.New("boom"),
+			expected: errorClassInternal,
+		},
+		{
+			name:     "not found",
+			result:   toolErrorFromErr("failed to get stack", &client.APIError{StatusCode: 404, Kind: client.ErrNotFound, Err: errors.New("stackInspectNotFound")}),
+			expected: errorClassNotFound,
+		},
+		{
+			name:     "forbidden",
+			result:   toolErrorFromErr("failed to get stack", &client.APIError{StatusCode: 403, Kind: client.ErrForbidden, Err: errors.New("forbidden")}),
+			expected: errorClassForbidden,
+		},
+		{
+			name:     "rate limited",
+			result:   rateLimitedResult,
+			expected: errorClassRateLimited,
+		},
+		{
+			name:     "proxy policy",
+			result:   toolErrorFromErr("Docker API request denied by policy", errors.New(`DELETE /containers/abc is denied by policy rule "DELETE /**"`)),
+			expected: errorClassPolicy,
+		},
+		{
+			name:     "environment scope",
+			result:   mcp.NewToolResultError("environment 7 is out of scope: this server is restricted to a subset of environments"),
+			expected: errorClassPolicy,
+		},
+		{
+			name:     "other",
+			result:   mcp.NewToolResultError("id must be a positive integer"),
+			expected: errorClassOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, toolErrorClass(tt.result, tt.err))
+		})
+	}
+}
+
+// TestMetricsTransport verifies that upstream requests are recorded by API,
+// method, and status, and that proxy response sizes are recorded once read.
+func TestMetricsTransport(t *testing.T) {
+	m := newServerMetrics(nil)
+	transport := m.wrapTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/tags" {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(strings.Repeat("x", 1000)))}, nil
+	}))
+
+	for _, path := range []string{"/api/endpoints/1/docker/containers/json", "/api/endpoints/2/kubernetes/api/v1/pods", "/api/endpoints", "/api/tags"} {
+		req := httptest.NewRequest(http.MethodGet, "http://portainer.local"+path, nil)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			continue
+		}
+		_, err = io.Copy(io.Discard, resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.NoError(t, resp.Body.Close())
+	}
+
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+	series := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := []string{family.GetName()}
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+			if histogram := metric.GetHistogram(); histogram != nil {
+				series[strings.Join(labels, ",")] = histogram.GetSampleSum()
+				series[strings.Join(labels, ",")+",count"] = float64(histogram.GetSampleCount())
+			}
+		}
+	}
+
+	assert.Equal(t, 1.0, series["portainer_mcp_upstream_request_duration_seconds,api=docker,code=200,method=GET,count"])
+	assert.Equal(t, 1.0, series["portainer_mcp_upstream_request_duration_seconds,api=kubernetes,code=200,method=GET,count"])
+	assert.Equal(t, 1.0, series["portainer_mcp_upstream_request_duration_seconds,api=portainer,code=200,method=GET,count"])
+	assert.Equal(t, 1.0, series["portainer_mcp_upstream_request_duration_seconds,api=portainer,code=error,method=GET,count"])
+	assert.Equal(t, 1000.0, series["portainer_mcp_proxy_response_size_bytes,api=docker"])
+	assert.Equal(t, 1.0, series["portainer_mcp_proxy_response_size_bytes,api=docker,count"], "a body closed twice is recorded once")
+	assert.NotContains(t, series, "portainer_mcp_proxy_response_size_bytes,api=portainer")
+}
+
+// TestMetricsHandler verifies that the metrics endpoint serves the tool and cache metrics.
+func TestMetricsHandler(t *testing.T) {
+	stats := newCacheStats()
+	stats.hits["GetEnvironments"].Add(3)
+	stats.misses["GetEnvironments"].Add(1)
+	m := newServerMetrics(stats)
+	m.toolCalls.WithLabelValues("manage_users", "list_users").Inc()
+
+	rec := httptest.NewRecorder()
+	m.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metricsEndpoint, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `portainer_mcp_tool_calls_total{action="list_users",tool="manage_users"} 1`)
+	assert.Contains(t, body, `portainer_mcp_cache_hits_total{method="GetEnvironments"} 3`)
+	assert.Contains(t, body, `portainer_mcp_cache_misses_total{method="GetEnvironments"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+
+	s := &PortainerMCPServer{metrics: m}
+	_, err := s.serveMetrics("invalid-address")
+	assert.ErrorContains(t, err, "failed to listen for metrics on invalid-address")
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -16,7 +17,7 @@ import (
 //    - Uses m.Called() to record the method call and get mock behavior
 //    - Includes nil check on first return value to avoid type assertion panics
 //    - Example:
-//      func (m *Mock) Method() (T, error) {
+//      func (m *Mock) Method(ctx context.Context) (T, error) {
 //          args := m.Called()
 //          if args.Get(0) == nil {
 //              return nil, args.Error(1)
@@ -28,15 +29,18 @@ import (
 //    - Uses m.Called() with any parameters
 //    - Returns only the error value
 //    - Example:
-//      func (m *Mock) Method(param string) error {
+//      func (m *Mock) Method(ctx context.Context, param string) error {
 //          args := m.Called(param)
 //          return args.Error(0)
 //      }
 //
+// The context argument is accepted but not recorded, so expectations are set
+// on the remaining arguments only.
+//
 // Usage in Tests:
 //   mock := new(MockPortainerClient)
 //   mock.On("MethodName").Return(expectedValue, nil)
-//   result, err := mock.MethodName()
+//   result, err := mock.MethodName(ctx)
 //   mock.AssertExpectations(t)
 
 // MockPortainerClient is a mock implementation of the PortainerClient interface
@@ -46,7 +50,7 @@ type MockPortainerClient struct {
 
 // Tag methods
 
-func (m *MockPortainerClient) GetEnvironmentTags() ([]models.EnvironmentTag, error) {
+func (m *MockPortainerClient) GetEnvironmentTags(ctx context.Context) ([]models.EnvironmentTag, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -54,19 +58,19 @@ func (m *MockPortainerClient) GetEnvironmentTags() ([]models.EnvironmentTag, err
 	return args.Get(0).([]models.EnvironmentTag), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateEnvironmentTag(name string) (int, error) {
+func (m *MockPortainerClient) CreateEnvironmentTag(ctx context.Context, name string) (int, error) {
 	args := m.Called(name)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteEnvironmentTag(id int) error {
+func (m *MockPortainerClient) DeleteEnvironmentTag(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Environment methods
 
-func (m *MockPortainerClient) GetEnvironments() ([]models.Environment, error) {
+func (m *MockPortainerClient) GetEnvironments(ctx context.Context) ([]models.Environment, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -74,44 +78,44 @@ func (m *MockPortainerClient) GetEnvironments() ([]models.Environment, error) {
 	return args.Get(0).([]models.Environment), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetEnvironment(id int) (models.Environment, error) {
+func (m *MockPortainerClient) GetEnvironment(ctx context.Context, id int) (models.Environment, error) {
 	args := m.Called(id)
 	return args.Get(0).(models.Environment), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteEnvironment(id int) error {
+func (m *MockPortainerClient) DeleteEnvironment(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) SnapshotEnvironment(id int) error {
+func (m *MockPortainerClient) SnapshotEnvironment(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) SnapshotAllEnvironments() error {
+func (m *MockPortainerClient) SnapshotAllEnvironments(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentTags(id int, tagIds []int) error {
+func (m *MockPortainerClient) UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error {
 	args := m.Called(id, tagIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentUserAccesses(id int, userAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
 	args := m.Called(id, userAccesses)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentTeamAccesses(id int, teamAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
 	args := m.Called(id, teamAccesses)
 	return args.Error(0)
 }
 
 // Environment Group methods
 
-func (m *MockPortainerClient) GetEnvironmentGroups() ([]models.Group, error) {
+func (m *MockPortainerClient) GetEnvironmentGroups(ctx context.Context) ([]models.Group, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -119,29 +123,29 @@ func (m *MockPortainerClient) GetEnvironmentGroups() ([]models.Group, error) {
 	return args.Get(0).([]models.Group), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateEnvironmentGroup(name string, environmentIds []int) (int, error) {
+func (m *MockPortainerClient) CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
 	args := m.Called(name, environmentIds)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentGroupName(id int, name string) error {
+func (m *MockPortainerClient) UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error {
 	args := m.Called(id, name)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentGroupEnvironments(id int, environmentIds []int) error {
+func (m *MockPortainerClient) UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error {
 	args := m.Called(id, environmentIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentGroupTags(id int, tagIds []int) error {
+func (m *MockPortainerClient) UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error {
 	args := m.Called(id, tagIds)
 	return args.Error(0)
 }
 
 // Access Group methods
 
-func (m *MockPortainerClient) GetAccessGroups() ([]models.AccessGroup, error) {
+func (m *MockPortainerClient) GetAccessGroups(ctx context.Context) ([]models.AccessGroup, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -149,39 +153,39 @@ func (m *MockPortainerClient) GetAccessGroups() ([]models.AccessGroup, error) {
 	return args.Get(0).([]models.AccessGroup), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateAccessGroup(name string, environmentIds []int) (int, error) {
+func (m *MockPortainerClient) CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
 	args := m.Called(name, environmentIds)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateAccessGroupName(id int, name string) error {
+func (m *MockPortainerClient) UpdateAccessGroupName(ctx context.Context, id int, name string) error {
 	args := m.Called(id, name)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateAccessGroupUserAccesses(id int, userAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
 	args := m.Called(id, userAccesses)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateAccessGroupTeamAccesses(id int, teamAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
 	args := m.Called(id, teamAccesses)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) AddEnvironmentToAccessGroup(id int, environmentId int) error {
+func (m *MockPortainerClient) AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error {
 	args := m.Called(id, environmentId)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) RemoveEnvironmentFromAccessGroup(id int, environmentId int) error {
+func (m *MockPortainerClient) RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error {
 	args := m.Called(id, environmentId)
 	return args.Error(0)
 }
 
 // Stack methods
 
-func (m *MockPortainerClient) GetStacks() ([]models.Stack, error) {
+func (m *MockPortainerClient) GetStacks(ctx context.Context) ([]models.Stack, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -189,7 +193,7 @@ func (m *MockPortainerClient) GetStacks() ([]models.Stack, error) {
 	return args.Get(0).([]models.Stack), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetRegularStacks() ([]models.RegularStack, error) {
+func (m *MockPortainerClient) GetRegularStacks(ctx context.Context) ([]models.RegularStack, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -197,22 +201,22 @@ func (m *MockPortainerClient) GetRegularStacks() ([]models.RegularStack, error)
 	return args.Get(0).([]models.RegularStack), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetStackFile(id int) (string, error) {
+func (m *MockPortainerClient) GetStackFile(ctx context.Context, id int) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateStack(name string, file string, environmentGroupIds []int) (int, error) {
+func (m *MockPortainerClient) CreateStack(ctx context.Context, name string, file string, environmentGroupIds []int) (int, error) {
 	args := m.Called(name, file, environmentGroupIds)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateStack(id int, file string, environmentGroupIds []int) error {
+func (m *MockPortainerClient) UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error {
 	args := m.Called(id, file, environmentGroupIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) InspectStack(id int) (models.RegularStack, error) {
+func (m *MockPortainerClient) InspectStack(ctx context.Context, id int) (models.RegularStack, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.RegularStack{}, args.Error(1)
@@ -220,17 +224,17 @@ func (m *MockPortainerClient) InspectStack(id int) (models.RegularStack, error)
 	return args.Get(0).(models.RegularStack), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteStack(id int, endpointID int, removeVolumes bool) error {
+func (m *MockPortainerClient) DeleteStack(ctx context.Context, id int, endpointID int, removeVolumes bool) error {
 	args := m.Called(id, endpointID, removeVolumes)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) InspectStackFile(id int) (string, error) {
+func (m *MockPortainerClient) InspectStackFile(ctx context.Context, id int) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateStackGit(id int, endpointID int, referenceName string, prune bool) (models.RegularStack, error) {
+func (m *MockPortainerClient) UpdateStackGit(ctx context.Context, id int, endpointID int, referenceName string, prune bool) (models.RegularStack, error) {
 	args := m.Called(id, endpointID, referenceName, prune)
 	if args.Get(0) == nil {
 		return models.RegularStack{}, args.Error(1)
@@ -238,7 +242,7 @@ func (m *MockPortainerClient) UpdateStackGit(id int, endpointID int, referenceNa
 	return args.Get(0).(models.RegularStack), args.Error(1)
 }
 
-func (m *MockPortainerClient) RedeployStackGit(id int, endpointID int, pullImage bool, prune bool) (models.RegularStack, error) {
+func (m *MockPortainerClient) RedeployStackGit(ctx context.Context, id int, endpointID int, pullImage bool, prune bool) (models.RegularStack, error) {
 	args := m.Called(id, endpointID, pullImage, prune)
 	if args.Get(0) == nil {
 		return models.RegularStack{}, args.Error(1)
@@ -246,7 +250,7 @@ func (m *MockPortainerClient) RedeployStackGit(id int, endpointID int, pullImage
 	return args.Get(0).(models.RegularStack), args.Error(1)
 }
 
-func (m *MockPortainerClient) StartStack(id int, endpointID int) (models.RegularStack, error) {
+func (m *MockPortainerClient) StartStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error) {
 	args := m.Called(id, endpointID)
 	if args.Get(0) == nil {
 		return models.RegularStack{}, args.Error(1)
@@ -254,7 +258,7 @@ func (m *MockPortainerClient) StartStack(id int, endpointID int) (models.Regular
 	return args.Get(0).(models.RegularStack), args.Error(1)
 }
 
-func (m *MockPortainerClient) StopStack(id int, endpointID int) (models.RegularStack, error) {
+func (m *MockPortainerClient) StopStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error) {
 	args := m.Called(id, endpointID)
 	if args.Get(0) == nil {
 		return models.RegularStack{}, args.Error(1)
@@ -262,7 +266,7 @@ func (m *MockPortainerClient) StopStack(id int, endpointID int) (models.RegularS
 	return args.Get(0).(models.RegularStack), args.Error(1)
 }
 
-func (m *MockPortainerClient) MigrateStack(id int, endpointID int, targetEndpointID int, name string) (models.RegularStack, error) {
+func (m *MockPortainerClient) MigrateStack(ctx context.Context, id int, endpointID int, targetEndpointID int, name string) (models.RegularStack, error) {
 	args := m.Called(id, endpointID, targetEndpointID, name)
 	if args.Get(0) == nil {
 		return models.RegularStack{}, args.Error(1)
@@ -272,12 +276,12 @@ func (m *MockPortainerClient) MigrateStack(id int, endpointID int, targetEndpoin
 
 // Team methods
 
-func (m *MockPortainerClient) CreateTeam(name string) (int, error) {
+func (m *MockPortainerClient) CreateTeam(ctx context.Context, name string) (int, error) {
 	args := m.Called(name)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetTeam(id int) (models.Team, error) {
+func (m *MockPortainerClient) GetTeam(ctx context.Context, id int) (models.Team, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.Team{}, args.Error(1)
@@ -285,12 +289,12 @@ func (m *MockPortainerClient) GetTeam(id int) (models.Team, error) {
 	return args.Get(0).(models.Team), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteTeam(id int) error {
+func (m *MockPortainerClient) DeleteTeam(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetTeams() ([]models.Team, error) {
+func (m *MockPortainerClient) GetTeams(ctx context.Context) ([]models.Team, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -298,19 +302,19 @@ func (m *MockPortainerClient) GetTeams() ([]models.Team, error) {
 	return args.Get(0).([]models.Team), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateTeamName(id int, name string) error {
+func (m *MockPortainerClient) UpdateTeamName(ctx context.Context, id int, name string) error {
 	args := m.Called(id, name)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateTeamMembers(id int, userIds []int) error {
+func (m *MockPortainerClient) UpdateTeamMembers(ctx context.Context, id int, userIds []int) error {
 	args := m.Called(id, userIds)
 	return args.Error(0)
 }
 
 // User methods
 
-func (m *MockPortainerClient) GetUsers() ([]models.User, error) {
+func (m *MockPortainerClient) GetUsers(ctx context.Context) ([]models.User, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -318,17 +322,17 @@ func (m *MockPortainerClient) GetUsers() ([]models.User, error) {
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateUserRole(id int, role string) error {
+func (m *MockPortainerClient) UpdateUserRole(ctx context.Context, id int, role string) error {
 	args := m.Called(id, role)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) CreateUser(username, password, role string) (int, error) {
+func (m *MockPortainerClient) CreateUser(ctx context.Context, username, password, role string) (int, error) {
 	args := m.Called(username, password, role)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetUser(id int) (models.User, error) {
+func (m *MockPortainerClient) GetUser(ctx context.Context, id int) (models.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.User{}, args.Error(1)
@@ -336,14 +340,14 @@ func (m *MockPortainerClient) GetUser(id int) (models.User, error) {
 	return args.Get(0).(models.User), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteUser(id int) error {
+func (m *MockPortainerClient) DeleteUser(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // System methods
 
-func (m *MockPortainerClient) GetSystemStatus() (models.SystemStatus, error) {
+func (m *MockPortainerClient) GetSystemStatus(ctx context.Context) (models.SystemStatus, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.SystemStatus{}, args.Error(1)
@@ -353,7 +357,7 @@ func (m *MockPortainerClient) GetSystemStatus() (models.SystemStatus, error) {
 
 // Settings methods
 
-func (m *MockPortainerClient) GetSettings() (models.PortainerSettings, error) {
+func (m *MockPortainerClient) GetSettings(ctx context.Context) (models.PortainerSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.PortainerSettings{}, args.Error(1)
@@ -361,12 +365,12 @@ func (m *MockPortainerClient) GetSettings() (models.PortainerSettings, error) {
 	return args.Get(0).(models.PortainerSettings), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateSettings(settings map[string]interface{}) error {
+func (m *MockPortainerClient) UpdateSettings(ctx context.Context, settings map[string]interface{}) error {
 	args := m.Called(settings)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetPublicSettings() (models.PublicSettings, error) {
+func (m *MockPortainerClient) GetPublicSettings(ctx context.Context) (models.PublicSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.PublicSettings{}, args.Error(1)
@@ -374,7 +378,7 @@ func (m *MockPortainerClient) GetPublicSettings() (models.PublicSettings, error)
 	return args.Get(0).(models.PublicSettings), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetSSLSettings() (models.SSLSettings, error) {
+func (m *MockPortainerClient) GetSSLSettings(ctx context.Context) (models.SSLSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.SSLSettings{}, args.Error(1)
@@ -382,12 +386,12 @@ func (m *MockPortainerClient) GetSSLSettings() (models.SSLSettings, error) {
 	return args.Get(0).(models.SSLSettings), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateSSLSettings(cert, key string, httpEnabled *bool) error {
+func (m *MockPortainerClient) UpdateSSLSettings(ctx context.Context, cert, key string, httpEnabled *bool) error {
 	args := m.Called(cert, key, httpEnabled)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetAppTemplates() ([]models.AppTemplate, error) {
+func (m *MockPortainerClient) GetAppTemplates(ctx context.Context) ([]models.AppTemplate, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -395,12 +399,12 @@ func (m *MockPortainerClient) GetAppTemplates() ([]models.AppTemplate, error) {
 	return args.Get(0).([]models.AppTemplate), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetAppTemplateFile(id int) (string, error) {
+func (m *MockPortainerClient) GetAppTemplateFile(ctx context.Context, id int) (string, error) {
 	args := m.Called(id)
 	return args.Get(0).(string), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetVersion() (string, error) {
+func (m *MockPortainerClient) GetVersion(ctx context.Context) (string, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return "", args.Error(1)
@@ -409,7 +413,7 @@ func (m *MockPortainerClient) GetVersion() (string, error) {
 }
 
 // Docker Proxy methods
-func (m *MockPortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestOptions) (*http.Response, error) {
+func (m *MockPortainerClient) ProxyDockerRequest(ctx context.Context, opts models.DockerProxyRequestOptions) (*http.Response, error) {
 	args := m.Called(opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -417,13 +421,70 @@ func (m *MockPortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestO
 	return args.Get(0).(*http.Response), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetDockerDashboard(environmentId int) (models.DockerDashboard, error) {
+func (m *MockPortainerClient) GetDockerDashboard(ctx context.Context, environmentId int) (models.DockerDashboard, error) {
 	args := m.Called(environmentId)
 	return args.Get(0).(models.DockerDashboard), args.Error(1)
 }
 
+// Docker Container methods
+func (m *MockPortainerClient) ListDockerContainers(ctx context.Context, environmentId int, opts models.DockerContainerListOptions) ([]models.DockerContainer, error) {
+	args := m.Called(environmentId, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DockerContainer), args.Error(1)
+}
+
+func (m *MockPortainerClient) InspectDockerContainer(ctx context.Context, environmentId int, containerId string) (models.DockerContainerDetails, error) {
+	args := m.Called(environmentId, containerId)
+	return args.Get(0).(models.DockerContainerDetails), args.Error(1)
+}
+
+func (m *MockPortainerClient) StartDockerContainer(ctx context.Context, environmentId int, containerId string) error {
+	args := m.Called(environmentId, containerId)
+	return args.Error(0)
+}
+
+func (m *MockPortainerClient) StopDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error {
+	args := m.Called(environmentId, containerId, timeout)
+	return args.Error(0)
+}
+
+func (m *MockPortainerClient) RestartDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error {
+	args := m.Called(environmentId, containerId, timeout)
+	return args.Error(0)
+}
+
+func (m *MockPortainerClient) KillDockerContainer(ctx context.Context, environmentId int, containerId string, signal string) error {
+	args := m.Called(environmentId, containerId, signal)
+	return args.Error(0)
+}
+
+func (m *MockPortainerClient) RemoveDockerContainer(ctx context.Context, environmentId int, containerId string, force bool, removeVolumes bool) error {
+	args := m.Called(environmentId, containerId, force, removeVolumes)
+	return args.Error(0)
+}
+
+func (m *MockPortainerClient) GetDockerContainerLogs(ctx context.Context, environmentId int, containerId string, opts models.DockerContainerLogOptions) (models.DockerContainerLogs, error) {
+	args := m.Called(environmentId, containerId, opts)
+	if args.Get(0) == nil {
+		return models.DockerContainerLogs{}, args.Error(1)
+	}
+	return args.Get(0).(models.DockerContainerLogs), args.Error(1)
+}
+
+func (m *MockPortainerClient) RenameDockerContainer(ctx context.Context, environmentId int, containerId string, name string) error {
+	args := m.Called(environmentId, containerId, name)
+	return args.Error(0)
+}
+
+func (m *MockPortainerClient) ExecDockerContainer(ctx context.Context, environmentId int, containerId string, opts models.ExecOptions) (models.ExecResult, error) {
+	args := m.Called(environmentId, containerId, opts)
+	return args.Get(0).(models.ExecResult), args.Error(1)
+}
+
 // Kubernetes Proxy methods
-func (m *MockPortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
+func (m *MockPortainerClient) ProxyKubernetesRequest(ctx context.Context, opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
 	args := m.Called(opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -431,12 +492,12 @@ func (m *MockPortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxy
 	return args.Get(0).(*http.Response), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetKubernetesDashboard(environmentId int) (models.KubernetesDashboard, error) {
+func (m *MockPortainerClient) GetKubernetesDashboard(ctx context.Context, environmentId int) (models.KubernetesDashboard, error) {
 	args := m.Called(environmentId)
 	return args.Get(0).(models.KubernetesDashboard), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetKubernetesNamespaces(environmentId int) ([]models.KubernetesNamespace, error) {
+func (m *MockPortainerClient) GetKubernetesNamespaces(ctx context.Context, environmentId int) ([]models.KubernetesNamespace, error) {
 	args := m.Called(environmentId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -444,14 +505,27 @@ func (m *MockPortainerClient) GetKubernetesNamespaces(environmentId int) ([]mode
 	return args.Get(0).([]models.KubernetesNamespace), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetKubernetesConfig(environmentId int) (interface{}, error) {
+func (m *MockPortainerClient) GetKubernetesConfig(ctx context.Context, environmentId int) (interface{}, error) {
 	args := m.Called(environmentId)
 	return args.Get(0), args.Error(1)
 }
 
+func (m *MockPortainerClient) GetKubernetesPodLogs(ctx context.Context, environmentId int, namespace string, opts models.KubernetesPodLogOptions) ([]models.KubernetesPodLogs, error) {
+	args := m.Called(environmentId, namespace, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.KubernetesPodLogs), args.Error(1)
+}
+
+func (m *MockPortainerClient) ExecKubernetesPod(ctx context.Context, environmentId int, namespace, pod, container string, opts models.ExecOptions) (models.ExecResult, error) {
+	args := m.Called(environmentId, namespace, pod, container, opts)
+	return args.Get(0).(models.ExecResult), args.Error(1)
+}
+
 // Custom Template methods
 
-func (m *MockPortainerClient) GetCustomTemplates() ([]models.CustomTemplate, error) {
+func (m *MockPortainerClient) GetCustomTemplates(ctx context.Context) ([]models.CustomTemplate, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -459,29 +533,29 @@ func (m *MockPortainerClient) GetCustomTemplates() ([]models.CustomTemplate, err
 	return args.Get(0).([]models.CustomTemplate), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetCustomTemplate(id int) (models.CustomTemplate, error) {
+func (m *MockPortainerClient) GetCustomTemplate(ctx context.Context, id int) (models.CustomTemplate, error) {
 	args := m.Called(id)
 	return args.Get(0).(models.CustomTemplate), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetCustomTemplateFile(id int) (string, error) {
+func (m *MockPortainerClient) GetCustomTemplateFile(ctx context.Context, id int) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateCustomTemplate(title, description, note, logo, fileContent string, platform, templateType int) (int, error) {
+func (m *MockPortainerClient) CreateCustomTemplate(ctx context.Context, title, description, note, logo, fileContent string, platform, templateType int) (int, error) {
 	args := m.Called(title, description, note, logo, fileContent, platform, templateType)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteCustomTemplate(id int) error {
+func (m *MockPortainerClient) DeleteCustomTemplate(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Webhook methods
 
-func (m *MockPortainerClient) GetWebhooks() ([]models.Webhook, error) {
+func (m *MockPortainerClient) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -489,19 +563,19 @@ func (m *MockPortainerClient) GetWebhooks() ([]models.Webhook, error) {
 	return args.Get(0).([]models.Webhook), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateWebhook(resourceId string, endpointId int, webhookType int) (int, error) {
+func (m *MockPortainerClient) CreateWebhook(ctx context.Context, resourceId string, endpointId int, webhookType int) (int, error) {
 	args := m.Called(resourceId, endpointId, webhookType)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteWebhook(id int) error {
+func (m *MockPortainerClient) DeleteWebhook(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Registry methods
 
-func (m *MockPortainerClient) GetRegistries() ([]models.Registry, error) {
+func (m *MockPortainerClient) GetRegistries(ctx context.Context) ([]models.Registry, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -509,29 +583,29 @@ func (m *MockPortainerClient) GetRegistries() ([]models.Registry, error) {
 	return args.Get(0).([]models.Registry), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetRegistry(id int) (models.Registry, error) {
+func (m *MockPortainerClient) GetRegistry(ctx context.Context, id int) (models.Registry, error) {
 	args := m.Called(id)
 	return args.Get(0).(models.Registry), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateRegistry(name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error) {
+func (m *MockPortainerClient) CreateRegistry(ctx context.Context, name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error) {
 	args := m.Called(name, registryType, url, authentication, username, password, baseURL)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateRegistry(id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error {
+func (m *MockPortainerClient) UpdateRegistry(ctx context.Context, id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error {
 	args := m.Called(id, name, url, authentication, username, password, baseURL)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteRegistry(id int) error {
+func (m *MockPortainerClient) DeleteRegistry(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Backup methods
 
-func (m *MockPortainerClient) GetBackupStatus() (models.BackupStatus, error) {
+func (m *MockPortainerClient) GetBackupStatus(ctx context.Context) (models.BackupStatus, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.BackupStatus{}, args.Error(1)
@@ -539,7 +613,7 @@ func (m *MockPortainerClient) GetBackupStatus() (models.BackupStatus, error) {
 	return args.Get(0).(models.BackupStatus), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetBackupS3Settings() (models.S3BackupSettings, error) {
+func (m *MockPortainerClient) GetBackupS3Settings(ctx context.Context) (models.S3BackupSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.S3BackupSettings{}, args.Error(1)
@@ -547,24 +621,24 @@ func (m *MockPortainerClient) GetBackupS3Settings() (models.S3BackupSettings, er
 	return args.Get(0).(models.S3BackupSettings), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateBackup(password string) error {
+func (m *MockPortainerClient) CreateBackup(ctx context.Context, password string) error {
 	args := m.Called(password)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) BackupToS3(settings models.S3BackupSettings) error {
+func (m *MockPortainerClient) BackupToS3(ctx context.Context, settings models.S3BackupSettings) error {
 	args := m.Called(settings)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) RestoreFromS3(accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error {
+func (m *MockPortainerClient) RestoreFromS3(ctx context.Context, accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error {
 	args := m.Called(accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey)
 	return args.Error(0)
 }
 
 // Role methods
 
-func (m *MockPortainerClient) GetRoles() ([]models.Role, error) {
+func (m *MockPortainerClient) GetRoles(ctx context.Context) ([]models.Role, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -574,7 +648,7 @@ func (m *MockPortainerClient) GetRoles() ([]models.Role, error) {
 
 // MOTD methods
 
-func (m *MockPortainerClient) GetMOTD() (models.MOTD, error) {
+func (m *MockPortainerClient) GetMOTD(ctx context.Context) (models.MOTD, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.MOTD{}, args.Error(1)
@@ -584,7 +658,7 @@ func (m *MockPortainerClient) GetMOTD() (models.MOTD, error) {
 
 // Edge Job methods
 
-func (m *MockPortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
+func (m *MockPortainerClient) GetEdgeJobs(ctx context.Context) ([]models.EdgeJob, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -592,7 +666,7 @@ func (m *MockPortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
 	return args.Get(0).([]models.EdgeJob), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
+func (m *MockPortainerClient) GetEdgeJob(ctx context.Context, id int) (models.EdgeJob, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.EdgeJob{}, args.Error(1)
@@ -600,24 +674,24 @@ func (m *MockPortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
 	return args.Get(0).(models.EdgeJob), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetEdgeJobFile(id int) (string, error) {
+func (m *MockPortainerClient) GetEdgeJobFile(ctx context.Context, id int) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateEdgeJob(name, cronExpression, fileContent string, endpoints []int, edgeGroups []int, recurring bool) (int, error) {
+func (m *MockPortainerClient) CreateEdgeJob(ctx context.Context, name, cronExpression, fileContent string, endpoints []int, edgeGroups []int, recurring bool) (int, error) {
 	args := m.Called(name, cronExpression, fileContent, endpoints, edgeGroups, recurring)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteEdgeJob(id int) error {
+func (m *MockPortainerClient) DeleteEdgeJob(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Edge Update Schedule methods
 
-func (m *MockPortainerClient) GetEdgeUpdateSchedules() ([]models.EdgeUpdateSchedule, error) {
+func (m *MockPortainerClient) GetEdgeUpdateSchedules(ctx context.Context) ([]models.EdgeUpdateSchedule, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -627,44 +701,44 @@ func (m *MockPortainerClient) GetEdgeUpdateSchedules() ([]models.EdgeUpdateSched
 
 // Auth methods
 
-func (m *MockPortainerClient) AuthenticateUser(username, password string) (models.AuthResponse, error) {
+func (m *MockPortainerClient) AuthenticateUser(ctx context.Context, username, password string) (models.AuthResponse, error) {
 	args := m.Called(username, password)
 	return args.Get(0).(models.AuthResponse), args.Error(1)
 }
 
-func (m *MockPortainerClient) Logout() error {
+func (m *MockPortainerClient) Logout(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
 // Helm methods
 
-func (m *MockPortainerClient) GetHelmRepositories(userId int) (models.HelmRepositoryList, error) {
+func (m *MockPortainerClient) GetHelmRepositories(ctx context.Context, userId int) (models.HelmRepositoryList, error) {
 	args := m.Called(userId)
 	return args.Get(0).(models.HelmRepositoryList), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateHelmRepository(userId int, url string) (models.HelmRepository, error) {
+func (m *MockPortainerClient) CreateHelmRepository(ctx context.Context, userId int, url string) (models.HelmRepository, error) {
 	args := m.Called(userId, url)
 	return args.Get(0).(models.HelmRepository), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteHelmRepository(userId int, repositoryId int) error {
+func (m *MockPortainerClient) DeleteHelmRepository(ctx context.Context, userId int, repositoryId int) error {
 	args := m.Called(userId, repositoryId)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) SearchHelmCharts(repo string, chart string) (string, error) {
+func (m *MockPortainerClient) SearchHelmCharts(ctx context.Context, repo string, chart string) (string, error) {
 	args := m.Called(repo, chart)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) InstallHelmChart(environmentId int, chart, name, namespace, repo, values, version string) (models.HelmReleaseDetails, error) {
+func (m *MockPortainerClient) InstallHelmChart(ctx context.Context, environmentId int, chart, name, namespace, repo, values, version string) (models.HelmReleaseDetails, error) {
 	args := m.Called(environmentId, chart, name, namespace, repo, values, version)
 	return args.Get(0).(models.HelmReleaseDetails), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetHelmReleases(environmentId int, namespace, filter, selector string) ([]models.HelmRelease, error) {
+func (m *MockPortainerClient) GetHelmReleases(ctx context.Context, environmentId int, namespace, filter, selector string) ([]models.HelmRelease, error) {
 	args := m.Called(environmentId, namespace, filter, selector)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -672,12 +746,12 @@ func (m *MockPortainerClient) GetHelmReleases(environmentId int, namespace, filt
 	return args.Get(0).([]models.HelmRelease), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteHelmRelease(environmentId int, release, namespace string) error {
+func (m *MockPortainerClient) DeleteHelmRelease(ctx context.Context, environmentId int, release, namespace string) error {
 	args := m.Called(environmentId, release, namespace)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetHelmReleaseHistory(environmentId int, name, namespace string) ([]models.HelmReleaseDetails, error) {
+func (m *MockPortainerClient) GetHelmReleaseHistory(ctx context.Context, environmentId int, name, namespace string) ([]models.HelmReleaseDetails, error) {
 	args := m.Called(environmentId, name, namespace)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
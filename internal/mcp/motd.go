@@ -15,9 +15,9 @@ func (s *PortainerMCPServer) AddMotdFeatures() {
 // HandleGetMOTD returns an MCP tool handler that retrieves m o t d.
 func (s *PortainerMCPServer) HandleGetMOTD() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		motd, err := s.cli.GetMOTD()
+		motd, err := s.client(ctx).GetMOTD(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get MOTD", err), nil
+			return toolErrorFromErr("failed to get MOTD", err), nil
 		}
 
 		return jsonResult(motd, "failed to marshal MOTD")
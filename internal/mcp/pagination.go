@@ -0,0 +1,466 @@
+package mcp
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// limitParam, cursorParam, filterParam, and sortByParam are the arguments
+	// that page, filter, and sort the results of list tools.
+	limitParam  = "limit"
+	cursorParam = "cursor"
+	filterParam = "filter"
+	sortByParam = "sortBy"
+
+	// defaultPageLimit is the number of items returned when no limit is given.
+	defaultPageLimit = 100
+
+	// maxPageLimit is the largest accepted limit.
+	maxPageLimit = 500
+
+	// cursorParamDescription, sortByParamDescription, and filterParamDescription document the pagination arguments.
+	cursorParamDescription = "The nextCursor of the previous call, to return the next page with the same filter and sortBy"
+	sortByParamDescription = "Field to sort the items by, such as 'name' or 'id'. Prefix with '-' for descending order"
+	filterParamDescription = "Only return the items that match all of the given fields"
+)
+
+var (
+	// limitParamDescription documents the limit argument.
+	limitParamDescription = fmt.Sprintf("Maximum number of items to return (default: %d, maximum: %d)", defaultPageLimit, maxPageLimit)
+
+	// nameKeys are the keys whose value is matched by the name filter, in order of preference.
+	nameKeys = []string{"name", "Name", "title", "username"}
+
+	// filterProperties are the fields of the filter argument.
+	filterProperties = map[string]any{
+		"name":   map[string]any{"type": "string", "description": "Case-insensitive substring of the name"},
+		"status": map[string]any{"type": "string", "description": "Status, such as 'active' for environments"},
+		"type":   map[string]any{"type": "string", "description": "Type, such as 'docker-agent' for environments"},
+		"tag":    map[string]any{"type": "string", "description": "Name or ID of an environment tag"},
+	}
+
+	// unpagedListTools are the list tools whose result is not a list of items:
+	// listHelmRepositories returns the global repository and the user's
+	// repositories in one object.
+	unpagedListTools = []string{ToolListHelmRepositories}
+)
+
+// listPage is the result of a list tool: one page of the items that match the
+// filter, the number of those items, and the cursor of the next page, if any.
+type listPage struct {
+	Items      []json.RawMessage `json:"items"`
+	Total      int               `json:"total"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// listFilter selects the items of a list. Empty fields match every item.
+type listFilter struct {
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+// listQuery holds the pagination arguments of a call.
+type listQuery struct {
+	limit  int
+	offset int
+	filter listFilter
+	sortBy string
+	// toolFilter is the filter argument of a tool that defines its own.
+	toolFilter string
+}
+
+// isListTool reports whether a granular tool returns a list of items.
+func isListTool(toolName string) bool {
+	return strings.HasPrefix(toolName, "list") && !slices.Contains(unpagedListTools, toolName)
+}
+
+// withPaginationParameters returns a copy of a list tool whose input schema
+// accepts the pagination arguments. Arguments the tool already defines, such
+// as the name filter of listHelmReleases, are kept.
+func withPaginationParameters(tool mcp.Tool) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	for name, property := range map[string]map[string]any{
+		limitParam:  {"type": "number", "description": limitParamDescription},
+		cursorParam: {"type": "string", "description": cursorParamDescription},
+		sortByParam: {"type": "string", "description": sortByParamDescription},
+		filterParam: {"type": "object", "description": filterParamDescription, "properties": filterProperties},
+	} {
+		if _, exists := properties[name]; !exists {
+			properties[name] = property
+		}
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// paginationToolOptions returns the options that declare the pagination
+// arguments of the list actions of a meta-tool. The filter argument is decided
+// per action: genericFilterActions take the generic filter, and ownFilters
+// maps the actions that define their own filter, such as list_helm_releases,
+// to its schema. When both kinds are present, the argument accepts either
+// form and its description tells which action takes which.
+func paginationToolOptions(genericFilterActions []string, ownFilters map[string]map[string]any) []mcp.ToolOption {
+	const suffix = ". Only used by list actions"
+	options := []mcp.ToolOption{
+		mcp.WithNumber(limitParam, mcp.Description(limitParamDescription+suffix)),
+		mcp.WithString(cursorParam, mcp.Description(cursorParamDescription+suffix)),
+		mcp.WithString(sortByParam, mcp.Description(sortByParamDescription+suffix)),
+	}
+	if len(ownFilters) == 0 {
+		return append(options, mcp.WithObject(filterParam, mcp.Description(filterParamDescription+suffix), mcp.Properties(filterProperties)))
+	}
+
+	var schemas []map[string]any
+	var descriptions []string
+	if len(genericFilterActions) > 0 {
+		schemas = append(schemas, map[string]any{"type": "object", "properties": filterProperties})
+		descriptions = append(descriptions, fmt.Sprintf("For %s: %s, given as an object with name, status, type, or tag fields", strings.Join(genericFilterActions, ", "), filterParamDescription))
+	}
+	for _, action := range slices.Sorted(maps.Keys(ownFilters)) {
+		schema := maps.Clone(ownFilters[action])
+		description, ok := schema["description"].(string)
+		if !ok || description == "" {
+			description = "a filter argument of its own"
+		}
+		delete(schema, "description")
+		schemas = append(schemas, schema)
+		descriptions = append(descriptions, fmt.Sprintf("For %s: %s", action, description))
+	}
+
+	property := map[string]any{"anyOf": schemas}
+	if len(schemas) == 1 {
+		property = schemas[0]
+	}
+	property["description"] = strings.Join(descriptions, ". ")
+	return append(options, func(tool *mcp.Tool) {
+		tool.InputSchema.Properties[filterParam] = property
+	})
+}
+
+// hasOwnFilter reports whether a tool defines a filter argument of its own,
+// which is then left to its handler.
+func hasOwnFilter(tool mcp.Tool) bool {
+	_, ok := tool.InputSchema.Properties[filterParam]
+	return ok
+}
+
+// ownFilterSchema returns the schema of the filter argument a tool defines.
+func ownFilterSchema(tool mcp.Tool) map[string]any {
+	schema, _ := tool.InputSchema.Properties[filterParam].(map[string]any)
+	return schema
+}
+
+// withPagination wraps the handler of a list tool so that its items are
+// filtered, sorted, and returned one page at a time in a listPage. Results
+// that are not a JSON array are returned unchanged. When ownFilter is set, the
+// filter argument belongs to the tool and is not applied here.
+func (s *PortainerMCPServer) withPagination(ownFilter bool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query, err := parseListQuery(request, ownFilter)
+		if err != nil {
+			return toolErrorFromErr("invalid pagination parameters", err), nil
+		}
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError || len(result.Content) != 1 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal([]byte(text.Text), &items); err != nil {
+			return result, nil
+		}
+
+		page, err := s.paginate(ctx, items, query)
+		if err != nil {
+			return toolErrorFromErr("invalid pagination parameters", err), nil
+		}
+		return jsonResult(page, "failed to marshal list page")
+	}
+}
+
+// parseListQuery reads the pagination arguments of a call.
+func parseListQuery(request mcp.CallToolRequest, ownFilter bool) (listQuery, error) {
+	parser := toolgen.NewParameterParser(request)
+	query := listQuery{limit: defaultPageLimit}
+
+	limit, err := parser.GetInt(limitParam, false)
+	if err != nil {
+		return listQuery{}, err
+	}
+	if limit != 0 {
+		if limit < 0 || limit > maxPageLimit {
+			return listQuery{}, fmt.Errorf("limit must be between 1 and %d, got %d", maxPageLimit, limit)
+		}
+		query.limit = limit
+	}
+
+	query.sortBy, err = parser.GetString(sortByParam, false)
+	if err != nil {
+		return listQuery{}, err
+	}
+
+	filter, ok := request.GetArguments()[filterParam]
+	if ok && ownFilter {
+		query.toolFilter = fmt.Sprint(filter)
+	} else if ok {
+		fields, ok := filter.(map[string]any)
+		if !ok {
+			return listQuery{}, fmt.Errorf("filter must be an object with name, status, type, or tag fields")
+		}
+		for key, value := range fields {
+			text, ok := value.(string)
+			if !ok {
+				if number, isNumber := value.(float64); isNumber {
+					text = strconv.FormatFloat(number, 'f', -1, 64)
+				} else {
+					return listQuery{}, fmt.Errorf("filter field %q must be a string", key)
+				}
+			}
+			switch key {
+			case "name":
+				query.filter.Name = text
+			case "status":
+				query.filter.Status = text
+			case "type":
+				query.filter.Type = text
+			case "tag":
+				query.filter.Tag = text
+			default:
+				return listQuery{}, fmt.Errorf("unknown filter field %q: supported fields are name, status, type, and tag", key)
+			}
+		}
+	}
+
+	cursor, err := parser.GetString(cursorParam, false)
+	if err != nil {
+		return listQuery{}, err
+	}
+	if cursor != "" {
+		if query.offset, err = query.decodeCursor(cursor); err != nil {
+			return listQuery{}, err
+		}
+	}
+	return query, nil
+}
+
+// digest identifies the filter and sort order of a query, so that a cursor is
+// only used with the query that returned it.
+func (q listQuery) digest() string {
+	data, _ := json.Marshal(struct {
+		Filter     listFilter `json:"filter"`
+		ToolFilter string     `json:"toolFilter"`
+		SortBy     string     `json:"sortBy"`
+	}{q.filter, q.toolFilter, q.sortBy})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:4])
+}
+
+// encodeCursor returns the cursor of the page that starts at offset.
+func (q listQuery) encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%s", offset, q.digest()))
+}
+
+// decodeCursor returns the offset of a cursor returned by the same query.
+func (q listQuery) decodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	offsetText, digest, ok := strings.Cut(string(data), ":")
+	offset, err := strconv.Atoi(offsetText)
+	if !ok || err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	if digest != q.digest() {
+		return 0, fmt.Errorf("cursor was returned for another filter or sortBy; call again without cursor")
+	}
+	return offset, nil
+}
+
+// paginate filters and sorts the items and returns the page the query asks for.
+func (s *PortainerMCPServer) paginate(ctx context.Context, items []json.RawMessage, query listQuery) (listPage, error) {
+	fields := make([]map[string]any, len(items))
+	for i, item := range items {
+		decoder := json.NewDecoder(bytes.NewReader(item))
+		decoder.UseNumber()
+		// Items that are not objects have no fields to filter or sort on.
+		_ = decoder.Decode(&fields[i])
+	}
+
+	matcher, err := s.newListMatcher(ctx, query.filter, fields)
+	if err != nil {
+		return listPage{}, err
+	}
+	indexes := make([]int, 0, len(items))
+	for i := range items {
+		if matcher.matches(fields[i]) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if query.sortBy != "" {
+		key, descending := strings.CutPrefix(query.sortBy, "-")
+		if len(indexes) > 0 && !slices.ContainsFunc(indexes, func(i int) bool { _, ok := fields[i][key]; return ok }) {
+			return listPage{}, fmt.Errorf("unknown sortBy field %q", key)
+		}
+		slices.SortStableFunc(indexes, func(a, b int) int {
+			c := compareFields(fields[a][key], fields[b][key])
+			if descending {
+				return -c
+			}
+			return c
+		})
+	}
+
+	page := listPage{Items: []json.RawMessage{}, Total: len(indexes)}
+	end := min(query.offset+query.limit, len(indexes))
+	for _, i := range indexes[min(query.offset, len(indexes)):end] {
+		page.Items = append(page.Items, items[i])
+	}
+	if end < len(indexes) {
+		page.NextCursor = query.encodeCursor(end)
+	}
+	return page, nil
+}
+
+// compareFields orders two field values: numbers numerically, other values
+// by their case-insensitive text, and missing values last.
+func compareFields(a, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		default:
+			return -1
+		}
+	}
+	if numberA, ok := a.(json.Number); ok {
+		if numberB, ok := b.(json.Number); ok {
+			floatA, errA := numberA.Float64()
+			floatB, errB := numberB.Float64()
+			if errA == nil && errB == nil {
+				return cmp.Compare(floatA, floatB)
+			}
+		}
+	}
+	return cmp.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+}
+
+// listMatcher decides whether an item matches a filter.
+type listMatcher struct {
+	filter listFilter
+	// tagIDs are the IDs of the environment tags the tag filter refers to.
+	tagIDs []string
+}
+
+// newListMatcher returns a matcher for a filter. A tag filter given by name is
+// resolved to tag IDs when the items carry tag_ids, such as environments.
+func (s *PortainerMCPServer) newListMatcher(ctx context.Context, filter listFilter, fields []map[string]any) (*listMatcher, error) {
+	m := &listMatcher{filter: filter}
+	if filter.Tag == "" {
+		return m, nil
+	}
+	if _, err := strconv.Atoi(filter.Tag); err == nil {
+		m.tagIDs = []string{filter.Tag}
+		return m, nil
+	}
+	if !slices.ContainsFunc(fields, func(f map[string]any) bool { _, ok := f["tag_ids"]; return ok }) {
+		return m, nil
+	}
+
+	tags, err := s.client(ctx).GetEnvironmentTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment tags: %w", err)
+	}
+	for _, tag := range tags {
+		if strings.EqualFold(tag.Name, filter.Tag) {
+			m.tagIDs = append(m.tagIDs, strconv.Itoa(tag.ID))
+		}
+	}
+	return m, nil
+}
+
+// matches reports whether an item matches every field of the filter. An item
+// without the field a filter refers to does not match.
+func (m *listMatcher) matches(fields map[string]any) bool {
+	if m.filter.Name != "" {
+		name, ok := firstField(fields, nameKeys)
+		if !ok || !strings.Contains(strings.ToLower(name), strings.ToLower(m.filter.Name)) {
+			return false
+		}
+	}
+	if m.filter.Status != "" {
+		status, ok := firstField(fields, []string{"status", "Status", "State"})
+		if !ok || !strings.EqualFold(status, m.filter.Status) {
+			return false
+		}
+	}
+	if m.filter.Type != "" {
+		kind, ok := firstField(fields, []string{"type", "Type"})
+		if !ok || !strings.EqualFold(kind, m.filter.Type) {
+			return false
+		}
+	}
+	if m.filter.Tag != "" && !m.matchesTag(fields) {
+		return false
+	}
+	return true
+}
+
+// matchesTag reports whether an item has the tag of the filter, among its tag
+// IDs or its tag names.
+func (m *listMatcher) matchesTag(fields map[string]any) bool {
+	if ids, ok := fields["tag_ids"].([]any); ok {
+		for _, id := range ids {
+			if slices.Contains(m.tagIDs, fmt.Sprint(id)) {
+				return true
+			}
+		}
+	}
+	if names, ok := fields["tags"].([]any); ok {
+		for _, name := range names {
+			if strings.EqualFold(fmt.Sprint(name), m.filter.Tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// firstField returns the text of the first of the keys that is present and not empty.
+func firstField(fields map[string]any, keys []string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok && value != nil {
+			if text := fmt.Sprint(value); text != "" {
+				return text, true
+			}
+		}
+	}
+	return "", false
+}
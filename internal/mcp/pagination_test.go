@@ -0,0 +1,340 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEnvironments returns environments to page through.
+func testEnvironments() []models.Environment {
+	return []models.Environment{
+		{ID: 1, Name: "local", Status: "active", Type: "docker-local", TagIds: []int{}},
+		{ID: 2, Name: "edge-berlin", Status: "inactive", Type: "docker-edge-agent", TagIds: []int{10}},
+		{ID: 3, Name: "edge-paris", Status: "active", Type: "docker-edge-agent", TagIds: []int{10, 11}},
+		{ID: 4, Name: "Staging", Status: "active", Type: "kubernetes-agent", TagIds: []int{11}},
+	}
+}
+
+// callPaginated calls a paginated handler that returns the given items and decodes the page.
+func callPaginated(t *testing.T, s *PortainerMCPServer, items any, args map[string]any) (listPage, *mcp.CallToolResult) {
+	t.Helper()
+
+	handler := s.withPagination(false, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return jsonResult(items, "failed to marshal items")
+	})
+	result, err := handler(context.Background(), CreateMCPRequest(args))
+	require.NoError(t, err)
+	var page listPage
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &page))
+	}
+	return page, result
+}
+
+// pageIDs returns the IDs of the items of a page.
+func pageIDs(t *testing.T, page listPage) []int {
+	t.Helper()
+
+	ids := make([]int, 0, len(page.Items))
+	for _, item := range page.Items {
+		var environment models.Environment
+		require.NoError(t, json.Unmarshal(item, &environment))
+		ids = append(ids, environment.ID)
+	}
+	return ids
+}
+
+// TestWithPagination verifies filtering, sorting, and paging of list results.
+func TestWithPagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]any
+		mockSetup     func(*MockPortainerClient)
+		expectedIDs   []int
+		expectedTotal int
+		errorContains string
+	}{
+		{
+			name:          "defaults",
+			args:          map[string]any{},
+			expectedIDs:   []int{1, 2, 3, 4},
+			expectedTotal: 4,
+		},
+		{
+			name:          "name substring",
+			args:          map[string]any{"filter": map[string]any{"name": "EDGE"}},
+			expectedIDs:   []int{2, 3},
+			expectedTotal: 2,
+		},
+		{
+			name:          "status and type",
+			args:          map[string]any{"filter": map[string]any{"status": "active", "type": "docker-edge-agent"}},
+			expectedIDs:   []int{3},
+			expectedTotal: 1,
+		},
+		{
+			name: "tag name",
+			args: map[string]any{"filter": map[string]any{"tag": "production"}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentTags").Return([]models.EnvironmentTag{{ID: 10, Name: "edge"}, {ID: 11, Name: "production"}}, nil)
+			},
+			expectedIDs:   []int{3, 4},
+			expectedTotal: 2,
+		},
+		{
+			name:          "tag ID",
+			args:          map[string]any{"filter": map[string]any{"tag": float64(10)}},
+			expectedIDs:   []int{2, 3},
+			expectedTotal: 2,
+		},
+		{
+			name:          "sort by name",
+			args:          map[string]any{"sortBy": "name"},
+			expectedIDs:   []int{2, 3, 1, 4},
+			expectedTotal: 4,
+		},
+		{
+			name:          "sort by id descending",
+			args:          map[string]any{"sortBy": "-id", "limit": float64(2)},
+			expectedIDs:   []int{4, 3},
+			expectedTotal: 4,
+		},
+		{
+			name:          "unknown sort field",
+			args:          map[string]any{"sortBy": "region"},
+			errorContains: `unknown sortBy field "region"`,
+		},
+		{
+			name:          "unknown filter field",
+			args:          map[string]any{"filter": map[string]any{"region": "eu"}},
+			errorContains: `unknown filter field "region"`,
+		},
+		{
+			name:          "filter is not an object",
+			args:          map[string]any{"filter": "edge"},
+			errorContains: "filter must be an object",
+		},
+		{
+			name:          "limit too large",
+			args:          map[string]any{"limit": float64(1000)},
+			errorContains: "limit must be between 1 and 500",
+		},
+		{
+			name:          "invalid cursor",
+			args:          map[string]any{"cursor": "not a cursor"},
+			errorContains: "invalid cursor",
+		},
+		{
+			name: "tag lookup error",
+			args: map[string]any{"filter": map[string]any{"tag": "production"}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentTags").Return(nil, errors.New("connection refused"))
+			},
+			errorContains: "failed to get environment tags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestMetaServer(false)
+			if tt.mockSetup != nil {
+				tt.mockSetup(s.cli.(*MockPortainerClient))
+			}
+
+			page, result := callPaginated(t, s, testEnvironments(), tt.args)
+
+			if tt.errorContains != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.errorContains)
+				return
+			}
+			assert.False(t, result.IsError, "unexpected error result: %v", result.Content)
+			assert.Equal(t, tt.expectedIDs, pageIDs(t, page))
+			assert.Equal(t, tt.expectedTotal, page.Total)
+		})
+	}
+}
+
+// TestPaginationCursor verifies that cursors walk through every page and are
+// bound to the filter and sort order that returned them.
+func TestPaginationCursor(t *testing.T) {
+	s := newTestMetaServer(false)
+	environments := make([]models.Environment, 0, 250)
+	for i := 1; i <= 250; i++ {
+		environments = append(environments, models.Environment{ID: i, Name: fmt.Sprintf("edge-%03d", i)})
+	}
+
+	var ids []int
+	args := map[string]any{"sortBy": "-name"}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page, result := callPaginated(t, s, environments, args)
+		require.False(t, result.IsError, "unexpected error result: %v", result.Content)
+		assert.Equal(t, 250, page.Total)
+		ids = append(ids, pageIDs(t, page)...)
+		if page.NextCursor == "" {
+			break
+		}
+		args = map[string]any{"sortBy": "-name", "cursor": page.NextCursor}
+	}
+	require.Len(t, ids, 250)
+	assert.Equal(t, 250, ids[0])
+	assert.Equal(t, 1, ids[249])
+
+	page, _ := callPaginated(t, s, environments, map[string]any{"limit": float64(10)})
+	require.NotEmpty(t, page.NextCursor)
+	_, result := callPaginated(t, s, environments, map[string]any{"cursor": page.NextCursor, "sortBy": "name"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "cursor was returned for another filter or sortBy")
+}
+
+// TestPaginationPassThrough verifies that results that are not lists are returned unchanged.
+func TestPaginationPassThrough(t *testing.T) {
+	s := newTestMetaServer(false)
+	for _, result := range []*mcp.CallToolResult{
+		mcp.NewToolResultText(`{"id":1}`),
+		mcp.NewToolResultText("plain text"),
+		mcp.NewToolResultError("failed to get environments"),
+	} {
+		handler := s.withPagination(false, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return result, nil
+		})
+		got, err := handler(context.Background(), CreateMCPRequest(map[string]any{}))
+		require.NoError(t, err)
+		assert.Same(t, result, got)
+	}
+
+	page, result := callPaginated(t, s, []models.Environment(nil), map[string]any{})
+	assert.False(t, result.IsError)
+	assert.Equal(t, 0, page.Total)
+	assert.Empty(t, page.Items)
+}
+
+// TestPaginationOwnFilter verifies that the filter argument of a tool that
+// defines its own is left to its handler.
+func TestPaginationOwnFilter(t *testing.T) {
+	s := newTestMetaServer(false)
+	var received any
+	handler := s.withPagination(true, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		received = request.GetArguments()["filter"]
+		return jsonResult([]models.HelmRelease{{Name: "my-nginx"}}, "failed to marshal releases")
+	})
+
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{"filter": "ngin.*"}))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError, "unexpected error result: %v", result.Content)
+	assert.Equal(t, "ngin.*", received)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"total":1`)
+}
+
+// TestPaginationRegistration verifies that list tools and meta-tools with list
+// actions declare the pagination arguments.
+func TestPaginationRegistration(t *testing.T) {
+	t.Run("meta-tools", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.tools = map[string]mcp.Tool{
+			ToolListHelmReleases: {Name: ToolListHelmReleases, InputSchema: mcp.ToolInputSchema{Properties: map[string]any{"filter": map[string]any{"type": "string"}}}},
+		}
+		s.cli.(*MockPortainerClient).On("GetEnvironments").Return(testEnvironments(), nil)
+		s.RegisterMetaTools()
+
+		properties := listToolProperties(t, s.srv)
+		for _, param := range []string{limitParam, cursorParam, sortByParam, filterParam} {
+			assert.Contains(t, properties["manage_environments"], param)
+		}
+		assert.Contains(t, properties["manage_helm"], limitParam)
+
+		resp := s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"manage_environments","arguments":{"action":"list_environments","limit":1}}}`))
+		data, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `\"total\":4`)
+		assert.Contains(t, string(data), `\"nextCursor\"`)
+	})
+
+	t.Run("filter decided per action", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.tools = map[string]mcp.Tool{
+			ToolListHelmReleases: {Name: ToolListHelmReleases, InputSchema: mcp.ToolInputSchema{Properties: map[string]any{
+				"filter": map[string]any{"type": "string", "description": "Filter releases by name pattern"},
+			}}},
+			ToolListEnvironments: {Name: ToolListEnvironments},
+		}
+		s.RegisterMetaTools()
+		properties := listToolProperties(t, s.srv)
+
+		// Both list actions of manage_environments take the generic filter.
+		assert.Equal(t, "object", properties["manage_environments"][filterParam].(map[string]any)["type"])
+
+		filter := properties["manage_helm"][filterParam].(map[string]any)
+		assert.Equal(t, "string", filter["type"])
+		assert.Equal(t, "For list_helm_releases: Filter releases by name pattern", filter["description"])
+	})
+
+	t.Run("both kinds of filter", func(t *testing.T) {
+		options := paginationToolOptions([]string{"list_a"}, map[string]map[string]any{
+			"list_b": {"type": "string", "description": "Name pattern"},
+		})
+		tool := mcp.NewTool("manage_things", options...)
+
+		filter := tool.InputSchema.Properties[filterParam].(map[string]any)
+		require.Len(t, filter["anyOf"], 2)
+		assert.Equal(t, "object", filter["anyOf"].([]map[string]any)[0]["type"])
+		assert.Equal(t, map[string]any{"type": "string"}, filter["anyOf"].([]map[string]any)[1])
+		assert.Contains(t, filter["description"], "For list_a: "+filterParamDescription)
+		assert.Contains(t, filter["description"], "For list_b: Name pattern")
+	})
+
+	t.Run("manage_helm", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.tools = map[string]mcp.Tool{
+			ToolListHelmReleases: {Name: ToolListHelmReleases, InputSchema: mcp.ToolInputSchema{Properties: map[string]any{"filter": map[string]any{"type": "string"}}}},
+		}
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetHelmReleases", 3, "", "ngin", "").Return([]models.HelmRelease{{Name: "my-nginx"}, {Name: "nginx-ingress"}}, nil)
+		mockClient.On("GetHelmRepositories", 1).Return(models.HelmRepositoryList{GlobalRepository: "https://charts.bitnami.com/bitnami"}, nil)
+		s.RegisterMetaTools()
+
+		call := func(arguments string) string {
+			resp := s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"manage_helm","arguments":`+arguments+`}}`))
+			data, err := json.Marshal(resp)
+			require.NoError(t, err)
+			return string(data)
+		}
+
+		// The filter of list_helm_releases is passed to the action, and the page is still applied.
+		releases := call(`{"action":"list_helm_releases","environmentId":3,"filter":"ngin","limit":1}`)
+		assert.Contains(t, releases, `\"total\":2`)
+		assert.Contains(t, releases, `\"nextCursor\"`)
+
+		// list_helm_repositories does not return a list of items and is not paginated.
+		repositories := call(`{"action":"list_helm_repositories","userId":1}`)
+		assert.Contains(t, repositories, `\"globalRepository\"`)
+		assert.NotContains(t, repositories, `\"total\"`)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("granular tools", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.tools = map[string]mcp.Tool{
+			ToolListUsers: {Name: ToolListUsers},
+			ToolGetUser:   {Name: ToolGetUser},
+		}
+		s.addToolIfExists(ToolListUsers, okHandler)
+		s.addToolIfExists(ToolGetUser, okHandler)
+
+		properties := listToolProperties(t, s.srv)
+		assert.Contains(t, properties[ToolListUsers], limitParam)
+		assert.Contains(t, properties[ToolListUsers], filterParam)
+		assert.NotContains(t, properties[ToolGetUser], limitParam)
+		assert.False(t, isListTool(ToolListHelmRepositories), "listHelmRepositories does not return a list of items")
+	})
+}
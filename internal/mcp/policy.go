@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// policy is the access policy loaded from the file given with -policy. It
+// restricts which tools and actions are registered, on top of read-only mode,
+// which requests the Docker and Kubernetes proxy tools may send, which
+// environments the tools may target, how secrets are redacted from results,
+// and how often tools may be called.
+type policy struct {
+	Tools        toolPolicy       `yaml:"tools"`
+	Proxy        proxyPolicy      `yaml:"proxy"`
+	Environments environmentScope `yaml:"environments"`
+	Redaction    redactionPolicy  `yaml:"redaction"`
+	RateLimits   []rateLimit      `yaml:"rateLimits"`
+
+	// targets maps each granular tool name to the meta-tool action it belongs to.
+	targets map[string]policyTarget
+}
+
+// toolPolicy lists the tools and actions to allow and deny. Each rule is a
+// meta-tool name (manage_stacks), a meta-tool action (manage_stacks.delete_stack),
+// or a granular tool name (deleteStack), and may use path.Match wildcards
+// (manage_stacks.*, *.delete_*). When Allow is not empty, only the tools and
+// actions it matches are registered. Deny always takes precedence over Allow.
+type toolPolicy struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// policyTarget identifies a tool or action by every name a rule can match.
+type policyTarget struct {
+	metaTool string
+	action   string
+	tool     string
+}
+
+// names returns the names a policy rule is matched against.
+func (t policyTarget) names() []string {
+	if t.metaTool == "" {
+		return []string{t.tool}
+	}
+	return []string{t.metaTool, t.metaTool + "." + t.action, t.tool}
+}
+
+// loadPolicy reads a policy file and checks that each of its rules matches at
+// least one tool or action, so that a misspelt rule is not silently ignored.
+func loadPolicy(filePath string) (*policy, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	p := &policy{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	p.targets = make(map[string]policyTarget)
+	for _, def := range metaToolDefinitions() {
+		for _, a := range def.actions {
+			p.targets[a.tool] = policyTarget{metaTool: def.name, action: a.name, tool: a.tool}
+		}
+	}
+
+	rules := append(append([]string{}, p.Tools.Allow...), p.Tools.Deny...)
+	if err := p.validateToolRules(append(rules, p.Redaction.OptOut...)); err != nil {
+		return nil, err
+	}
+
+	if err := p.Proxy.Docker.compile(); err != nil {
+		return nil, fmt.Errorf("invalid docker proxy policy: %w", err)
+	}
+	if err := p.Proxy.Kubernetes.compile(); err != nil {
+		return nil, fmt.Errorf("invalid kubernetes proxy policy: %w", err)
+	}
+	if err := p.Environments.validate(); err != nil {
+		return nil, fmt.Errorf("invalid environment scope: %w", err)
+	}
+	if _, err := newRedactor(p); err != nil {
+		return nil, err
+	}
+	for i, limit := range p.RateLimits {
+		if err := limit.validate(p); err != nil {
+			return nil, fmt.Errorf("invalid rate limit %d: %w", i+1, err)
+		}
+	}
+
+	return p, nil
+}
+
+// validateToolRules checks that each tool rule is a valid pattern that matches at least one tool or action.
+func (p *policy) validateToolRules(rules []string) error {
+	for _, rule := range rules {
+		if _, err := path.Match(rule, ""); err != nil {
+			return fmt.Errorf("invalid policy rule %q: %w", rule, err)
+		}
+		matched := false
+		for _, target := range p.targets {
+			if matchesAnyRule([]string{rule}, target) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("policy rule %q does not match any tool or action", rule)
+		}
+	}
+	return nil
+}
+
+// allows reports whether the policy permits a tool or action. A nil policy permits everything.
+func (p *policy) allows(target policyTarget) bool {
+	if p == nil {
+		return true
+	}
+	if matchesAnyRule(p.Tools.Deny, target) {
+		return false
+	}
+	return len(p.Tools.Allow) == 0 || matchesAnyRule(p.Tools.Allow, target)
+}
+
+// allowsAction reports whether the policy permits an action of a meta-tool.
+func (p *policy) allowsAction(metaTool string, a metaAction) bool {
+	return p.allows(policyTarget{metaTool: metaTool, action: a.name, tool: a.tool})
+}
+
+// allowsTool reports whether the policy permits a granular tool.
+func (p *policy) allowsTool(toolName string) bool {
+	return p.allows(p.target(toolName))
+}
+
+// target returns the names policy rules are matched against for a granular
+// tool. A tool that belongs to no meta-tool is matched by its name only.
+func (p *policy) target(toolName string) policyTarget {
+	if p != nil {
+		if target, ok := p.targets[toolName]; ok {
+			return target
+		}
+	}
+	return policyTarget{tool: toolName}
+}
+
+// matchesAnyRule reports whether any of the rules matches one of the target's names.
+func matchesAnyRule(rules []string, target policyTarget) bool {
+	for _, rule := range rules {
+		for _, name := range target.names() {
+			if ok, _ := path.Match(rule, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// metaToolPolicy is the effective policy of one meta-tool: the actions that
+// are registered and those removed by read-only mode or the policy file.
+type metaToolPolicy struct {
+	metaTool string
+	allowed  []string
+	denied   []string
+}
+
+// effectivePolicy returns, for each meta-tool, the actions the server
+// registers given its read-only mode and policy. Granular tools follow the
+// same decision as the action they correspond to.
+func (s *PortainerMCPServer) effectivePolicy() []metaToolPolicy {
+	defs := metaToolDefinitions()
+	summary := make([]metaToolPolicy, 0, len(defs))
+	for _, def := range defs {
+		entry := metaToolPolicy{metaTool: def.name}
+		for _, a := range def.actions {
+			if s.actionAllowed(def.name, a) {
+				entry.allowed = append(entry.allowed, a.name)
+			} else {
+				entry.denied = append(entry.denied, a.name)
+			}
+		}
+		summary = append(summary, entry)
+	}
+	return summary
+}
+
+// actionAllowed reports whether a meta-tool action, or its granular tool, is
+// registered under the server's read-only mode and policy. Snapshotting all
+// environments is not available when the server is restricted to some of them.
+func (s *PortainerMCPServer) actionAllowed(metaTool string, a metaAction) bool {
+	if s.readOnly && !a.readOnly {
+		return false
+	}
+	if a.tool == ToolSnapshotAllEnvironments && s.policy.scoped() {
+		return false
+	}
+	return s.policy.allowsAction(metaTool, a)
+}
+
+// logEffectivePolicy logs the actions that each meta-tool exposes, so that the
+// outcome of the read-only mode and the policy file can be checked at startup.
+func (s *PortainerMCPServer) logEffectivePolicy(policyPath string) {
+	allowed, denied := 0, 0
+	for _, entry := range s.effectivePolicy() {
+		allowed += len(entry.allowed)
+		denied += len(entry.denied)
+		if len(entry.denied) == 0 {
+			continue
+		}
+		log.Info().
+			Str("meta-tool", entry.metaTool).
+			Strs("allowed", entry.allowed).
+			Strs("denied", entry.denied).
+			Msg("effective tool policy")
+	}
+	log.Info().
+		Str("policy", policyPath).
+		Bool("read-only", s.readOnly).
+		Int("allowed-actions", allowed).
+		Int("denied-actions", denied).
+		Msg("effective tool policy summary")
+
+	if s.policy == nil {
+		return
+	}
+	if s.policy.scoped() {
+		log.Info().
+			Ints("ids", s.policy.Environments.IDs).
+			Strs("tags", s.policy.Environments.Tags).
+			Strs("groups", s.policy.Environments.Groups).
+			Strs("access-groups", s.policy.Environments.AccessGroups).
+			Msg("effective environment scope")
+	}
+	for _, proxy := range []struct {
+		name  string
+		rules proxyRules
+	}{{"docker", s.policy.Proxy.Docker}, {"kubernetes", s.policy.Proxy.Kubernetes}} {
+		if proxy.rules.empty() {
+			continue
+		}
+		log.Info().
+			Str("proxy", proxy.name).
+			Strs("allow", proxy.rules.Allow).
+			Strs("deny", proxy.rules.Deny).
+			Msg("effective proxy policy")
+	}
+	for _, limit := range s.policy.RateLimits {
+		log.Info().
+			Strs("tools", limit.Tools).
+			Float64("per-minute", limit.PerMinute).
+			Int("burst", limit.Burst).
+			Msg("effective rate limit")
+	}
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePolicyFile writes a policy file to a temporary directory and returns its path.
+func writePolicyFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// TestLoadPolicy verifies policy file parsing and rule validation.
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expected      toolPolicy
+		errorContains string
+	}{
+		{
+			name:     "allow and deny rules",
+			content:  "tools:\n  allow:\n    - manage_stacks.*\n  deny:\n    - deleteStack\n",
+			expected: toolPolicy{Allow: []string{"manage_stacks.*"}, Deny: []string{"deleteStack"}},
+		},
+		{
+			name:     "empty file",
+			content:  "",
+			expected: toolPolicy{},
+		},
+		{
+			name:          "unknown field",
+			content:       "tools:\n  denied:\n    - deleteStack\n",
+			errorContains: "failed to parse policy file",
+		},
+		{
+			name:          "rule matching nothing",
+			content:       "tools:\n  deny:\n    - manage_stacks.remove_stack\n",
+			errorContains: `policy rule "manage_stacks.remove_stack" does not match any tool or action`,
+		},
+		{
+			name:          "invalid pattern",
+			content:       "tools:\n  deny:\n    - manage_stacks.[\n",
+			errorContains: "invalid policy rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := loadPolicy(writePolicyFile(t, tt.content))
+
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, p.Tools)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read policy file")
+	})
+}
+
+// TestPolicyAllows verifies how allow and deny rules match meta-tools, actions, and granular tools.
+func TestPolicyAllows(t *testing.T) {
+	deleteStack := metaAction{name: "delete_stack", tool: ToolDeleteStack}
+	startStack := metaAction{name: "start_stack", tool: ToolStartStack}
+	listUsers := metaAction{name: "list_users", tool: ToolListUsers}
+
+	tests := []struct {
+		name     string
+		policy   toolPolicy
+		metaTool string
+		action   metaAction
+		expected bool
+	}{
+		{name: "no rules", metaTool: "manage_stacks", action: deleteStack, expected: true},
+		{name: "denied action", policy: toolPolicy{Deny: []string{"manage_stacks.delete_stack"}}, metaTool: "manage_stacks", action: deleteStack, expected: false},
+		{name: "other action", policy: toolPolicy{Deny: []string{"manage_stacks.delete_stack"}}, metaTool: "manage_stacks", action: startStack, expected: true},
+		{name: "denied granular tool", policy: toolPolicy{Deny: []string{ToolDeleteStack}}, metaTool: "manage_stacks", action: deleteStack, expected: false},
+		{name: "denied meta-tool", policy: toolPolicy{Deny: []string{"manage_stacks"}}, metaTool: "manage_stacks", action: startStack, expected: false},
+		{name: "denied by wildcard", policy: toolPolicy{Deny: []string{"*.delete_*"}}, metaTool: "manage_stacks", action: deleteStack, expected: false},
+		{name: "allowed action", policy: toolPolicy{Allow: []string{"manage_stacks.start_stack"}}, metaTool: "manage_stacks", action: startStack, expected: true},
+		{name: "not allowed", policy: toolPolicy{Allow: []string{"manage_stacks.start_stack"}}, metaTool: "manage_users", action: listUsers, expected: false},
+		{name: "deny takes precedence", policy: toolPolicy{Allow: []string{"manage_stacks"}, Deny: []string{"manage_stacks.delete_stack"}}, metaTool: "manage_stacks", action: deleteStack, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &policy{Tools: tt.policy}
+			assert.Equal(t, tt.expected, p.allowsAction(tt.metaTool, tt.action))
+		})
+	}
+
+	t.Run("nil policy", func(t *testing.T) {
+		var p *policy
+		assert.True(t, p.allowsAction("manage_stacks", deleteStack))
+		assert.True(t, p.allowsTool(ToolDeleteStack))
+	})
+}
+
+// TestPolicyRegistration verifies that denied actions are left out of the
+// action enum and denied granular tools are not registered.
+func TestPolicyRegistration(t *testing.T) {
+	p, err := loadPolicy(writePolicyFile(t, "tools:\n  deny:\n    - manage_stacks.delete_stack\n    - manage_webhooks\n"))
+	require.NoError(t, err)
+
+	t.Run("meta-tools", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.policy = p
+		s.RegisterMetaTools()
+
+		properties := listToolProperties(t, s.srv)
+		assert.NotContains(t, properties, "manage_webhooks")
+		enum := properties["manage_stacks"]["action"].(map[string]any)["enum"]
+		assert.Contains(t, enum, "start_stack")
+		assert.Contains(t, enum, "stop_stack")
+		assert.NotContains(t, enum, "delete_stack")
+	})
+
+	t.Run("granular tools", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		s.policy = p
+		s.tools = map[string]mcp.Tool{
+			ToolDeleteStack:  {Name: ToolDeleteStack},
+			ToolStartStack:   {Name: ToolStartStack},
+			ToolListWebhooks: {Name: ToolListWebhooks},
+		}
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		}
+		for name := range s.tools {
+			s.addToolIfExists(name, handler)
+		}
+
+		assert.ElementsMatch(t, []string{ToolStartStack}, listRegisteredTools(t, s.srv))
+	})
+}
+
+// TestEffectivePolicy verifies the summary of the actions registered under read-only mode and policy.
+func TestEffectivePolicy(t *testing.T) {
+	s := newTestMetaServer(true)
+	s.policy = &policy{Tools: toolPolicy{Deny: []string{"manage_stacks.list_stacks"}}}
+
+	var stacks metaToolPolicy
+	for _, entry := range s.effectivePolicy() {
+		if entry.metaTool == "manage_stacks" {
+			stacks = entry
+		}
+	}
+
+	assert.NotContains(t, stacks.allowed, "list_stacks")
+	assert.Contains(t, stacks.denied, "list_stacks")
+	assert.Contains(t, stacks.denied, "delete_stack", "write actions are denied in read-only mode")
+	assert.Contains(t, stacks.allowed, "get_stack")
+}
+
+// TestWithPolicyFile verifies that the server loads its policy file and rejects an invalid one.
+func TestWithPolicyFile(t *testing.T) {
+	newServer := func(path string) (*PortainerMCPServer, error) {
+		return NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+			WithClient(&MockPortainerClient{}), WithDisableVersionCheck(true), WithPolicyFile(path))
+	}
+
+	s, err := newServer(writePolicyFile(t, "tools:\n  deny:\n    - deleteStack\n"))
+	require.NoError(t, err)
+	require.NotNil(t, s.policy)
+	assert.False(t, s.policy.allowsTool(ToolDeleteStack))
+
+	_, err = newServer(writePolicyFile(t, "tools:\n  deny:\n    - noSuchTool\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load policy")
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// methodNotificationProgress is the method of MCP progress notifications.
+	methodNotificationProgress = "notifications/progress"
+	// progressInterval is how often a long-running Portainer request reports
+	// that it is still running.
+	progressInterval = 5 * time.Second
+)
+
+// progressReporter sends MCP progress notifications for a tool call whose
+// request carries a progress token. Without a token, it sends nothing.
+type progressReporter struct {
+	ctx      context.Context
+	srv      *server.MCPServer
+	token    mcp.ProgressToken
+	interval time.Duration
+}
+
+// newProgressReporter returns the progress reporter of a tool call.
+func (s *PortainerMCPServer) newProgressReporter(ctx context.Context, request mcp.CallToolRequest) *progressReporter {
+	var token mcp.ProgressToken
+	if request.Params.Meta != nil {
+		token = request.Params.Meta.ProgressToken
+	}
+	return &progressReporter{ctx: ctx, srv: s.srv, token: token, interval: progressInterval}
+}
+
+// enabled reports whether the client asked for progress notifications.
+func (p *progressReporter) enabled() bool {
+	return p.token != nil
+}
+
+// report sends the progress of the call, out of total when total is positive.
+// progress must increase from one notification to the next.
+func (p *progressReporter) report(progress, total float64, message string) {
+	if !p.enabled() {
+		return
+	}
+	params := map[string]any{
+		"progressToken": p.token,
+		"progress":      progress,
+		"message":       message,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	// A client that disconnected or does not read its notifications must
+	// not fail the call.
+	if err := p.srv.SendNotificationToClient(p.ctx, methodNotificationProgress, params); err != nil {
+		log.Debug().Err(err).Msg("Failed to send progress notification")
+	}
+}
+
+// run calls a single long-running Portainer request. As the request gives no
+// progress of its own, the reporter notifies when it starts, every interval
+// while it runs, so that clients know the call is alive, and when it ends.
+func (p *progressReporter) run(message string, fn func() error) error {
+	if !p.enabled() {
+		return fn()
+	}
+
+	start := time.Now()
+	p.report(0, 0, message)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for step := 1; ; step++ {
+		select {
+		case err := <-done:
+			status := "done"
+			if err != nil {
+				status = "failed"
+			}
+			p.report(float64(step), float64(step), fmt.Sprintf("%s: %s after %s", message, status, time.Since(start).Round(time.Second)))
+			return err
+		case <-ticker.C:
+			p.report(float64(step), 0, fmt.Sprintf("%s: %s elapsed", message, time.Since(start).Round(time.Second)))
+		}
+	}
+}
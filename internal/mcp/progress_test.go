@@ -0,0 +1,301 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notificationSession is a client session that keeps the notifications sent to it.
+type notificationSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newNotificationSession() *notificationSession {
+	return &notificationSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+}
+
+func (s *notificationSession) SessionID() string { return "progress-session" }
+func (s *notificationSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *notificationSession) Initialize()       {}
+func (s *notificationSession) Initialized() bool { return true }
+
+// progressParams returns the parameters of the progress notifications sent so far.
+func (s *notificationSession) progressParams() []map[string]any {
+	var params []map[string]any
+	for {
+		select {
+		case n := <-s.notifications:
+			if n.Method == methodNotificationProgress {
+				params = append(params, n.Params.AdditionalFields)
+			}
+		default:
+			return params
+		}
+	}
+}
+
+// newProgressRequest returns a tool call request with a progress token.
+func newProgressRequest(token string, arguments map[string]any) mcp.CallToolRequest {
+	request := CreateMCPRequest(arguments)
+	request.Params.Meta = &mcp.Meta{ProgressToken: token}
+	return request
+}
+
+// TestProgressReporterRun verifies the notifications of a single long-running request.
+func TestProgressReporterRun(t *testing.T) {
+	s := newTestMetaServer(false)
+	session := newNotificationSession()
+	ctx := s.srv.WithContext(context.Background(), session)
+
+	progress := s.newProgressReporter(ctx, newProgressRequest("backup-1", nil))
+	progress.interval = 10 * time.Millisecond
+	err := progress.run("Creating backup", func() error {
+		time.Sleep(35 * time.Millisecond)
+		return nil
+	})
+	require.NoError(t, err)
+
+	params := session.progressParams()
+	require.GreaterOrEqual(t, len(params), 3)
+	assert.Equal(t, map[string]any{"progressToken": "backup-1", "progress": 0.0, "message": "Creating backup"}, params[0])
+	assert.Contains(t, params[1]["message"], "Creating backup: ")
+	assert.Contains(t, params[1]["message"], "elapsed")
+	assert.NotContains(t, params[1], "total")
+	for i := 1; i < len(params); i++ {
+		assert.Greater(t, params[i]["progress"], params[i-1]["progress"])
+	}
+	last := params[len(params)-1]
+	assert.Equal(t, last["progress"], last["total"])
+	assert.Contains(t, last["message"], "Creating backup: done after")
+
+	err = progress.run("Creating backup", func() error { return errors.New("disk full") })
+	assert.EqualError(t, err, "disk full")
+	params = session.progressParams()
+	require.Len(t, params, 2)
+	assert.Contains(t, params[1]["message"], "Creating backup: failed after")
+}
+
+// TestProgressReporterWithoutToken verifies that no notification is sent
+// when the request carries no progress token.
+func TestProgressReporterWithoutToken(t *testing.T) {
+	s := newTestMetaServer(false)
+	session := newNotificationSession()
+	ctx := s.srv.WithContext(context.Background(), session)
+
+	progress := s.newProgressReporter(ctx, CreateMCPRequest(nil))
+	called := false
+	err := progress.run("Creating backup", func() error {
+		called = true
+		return nil
+	})
+	progress.report(1, 2, "Snapshotting")
+
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.False(t, progress.enabled())
+	assert.Empty(t, session.progressParams())
+}
+
+// TestHandleSnapshotAllEnvironmentsProgress verifies that environments are
+// snapshotted one by one with progress when the request carries a progress
+// token, and that edge environments are skipped.
+func TestHandleSnapshotAllEnvironmentsProgress(t *testing.T) {
+	environments := []models.Environment{
+		{ID: 1, Name: "local", Type: models.EnvironmentTypeDockerLocal},
+		{ID: 2, Name: "edge-01", Type: models.EnvironmentTypeDockerEdgeAgent},
+		{ID: 3, Name: "k8s", Type: models.EnvironmentTypeKubernetesAgent},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetEnvironments").Return(environments, nil)
+		mockClient.On("SnapshotEnvironment", 1).Return(nil)
+		mockClient.On("SnapshotEnvironment", 3).Return(nil)
+		session := newNotificationSession()
+		ctx := s.srv.WithContext(context.Background(), session)
+
+		result, err := s.HandleSnapshotAllEnvironments()(ctx, newProgressRequest("snap", nil))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "All environment snapshots created successfully (2 environments, 1 edge or Azure environments skipped)", resultText(result))
+		assert.Equal(t, []map[string]any{
+			{"progressToken": "snap", "progress": 0.0, "total": 2.0, "message": "Snapshotting environment local (1)"},
+			{"progressToken": "snap", "progress": 1.0, "total": 2.0, "message": "Snapshotting environment k8s (3)"},
+			{"progressToken": "snap", "progress": 2.0, "total": 2.0, "message": "Snapshotted 2 environments"},
+		}, session.progressParams())
+		mockClient.AssertNotCalled(t, "SnapshotAllEnvironments")
+		mockClient.AssertNotCalled(t, "SnapshotEnvironment", 2)
+	})
+
+	t.Run("failed environment", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetEnvironments").Return(environments, nil)
+		mockClient.On("SnapshotEnvironment", 1).Return(errors.New("environment is unreachable"))
+		mockClient.On("SnapshotEnvironment", 3).Return(nil)
+		ctx := s.srv.WithContext(context.Background(), newNotificationSession())
+
+		result, err := s.HandleSnapshotAllEnvironments()(ctx, newProgressRequest("snap", nil))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "failed to snapshot 1 of 2 environments: local (1): environment is unreachable", resultText(result))
+		mockClient.AssertCalled(t, "SnapshotEnvironment", 3)
+	})
+
+	t.Run("failed to list environments", func(t *testing.T) {
+		s := newTestMetaServer(false)
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetEnvironments").Return(nil, errors.New("connection refused"))
+		ctx := s.srv.WithContext(context.Background(), newNotificationSession())
+
+		result, err := s.HandleSnapshotAllEnvironments()(ctx, newProgressRequest("snap", nil))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "failed to get environments")
+	})
+}
+
+// TestHandleSnapshotAllEnvironmentsSameResult verifies that a failing
+// environment gives the same result with and without a progress token, and
+// that only the request with a token is reported.
+func TestHandleSnapshotAllEnvironmentsSameResult(t *testing.T) {
+	tests := []struct {
+		name             string
+		request          mcp.CallToolRequest
+		expectedProgress int
+	}{
+		{
+			name:    "without progress token",
+			request: CreateMCPRequest(nil),
+		},
+		{
+			name:             "with progress token",
+			request:          newProgressRequest("snap", nil),
+			expectedProgress: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestMetaServer(false)
+			mockClient := s.cli.(*MockPortainerClient)
+			mockClient.On("GetEnvironments").Return([]models.Environment{
+				{ID: 1, Name: "local", Type: models.EnvironmentTypeDockerLocal},
+				{ID: 2, Name: "edge-01", Type: models.EnvironmentTypeDockerEdgeAgent},
+				{ID: 3, Name: "k8s", Type: models.EnvironmentTypeKubernetesAgent},
+			}, nil)
+			mockClient.On("SnapshotEnvironment", 1).Return(errors.New("environment is unreachable"))
+			mockClient.On("SnapshotEnvironment", 3).Return(nil)
+			session := newNotificationSession()
+			ctx := s.srv.WithContext(context.Background(), session)
+
+			result, err := s.HandleSnapshotAllEnvironments()(ctx, tt.request)
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Equal(t, "failed to snapshot 1 of 2 environments: local (1): environment is unreachable", resultText(result))
+			assert.Len(t, session.progressParams(), tt.expectedProgress)
+			mockClient.AssertCalled(t, "SnapshotEnvironment", 3)
+			mockClient.AssertNotCalled(t, "SnapshotEnvironment", 2)
+			mockClient.AssertNotCalled(t, "SnapshotAllEnvironments")
+		})
+	}
+}
+
+// TestLongRunningHandlersProgress verifies that the handlers of long-running
+// operations report their start and end when the request carries a progress token.
+func TestLongRunningHandlersProgress(t *testing.T) {
+	tests := []struct {
+		name            string
+		handler         func(s *PortainerMCPServer) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		arguments       map[string]any
+		setupMock       func(m *MockPortainerClient)
+		expectedMessage string
+	}{
+		{
+			name: "create backup",
+			handler: func(s *PortainerMCPServer) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return s.HandleCreateBackup()
+			},
+			arguments:       map[string]any{},
+			setupMock:       func(m *MockPortainerClient) { m.On("CreateBackup", "").Return(nil) },
+			expectedMessage: "Creating backup",
+		},
+		{
+			name: "backup to S3",
+			handler: func(s *PortainerMCPServer) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return s.HandleBackupToS3()
+			},
+			arguments: map[string]any{
+				"accessKeyID":     "AKIA",
+				"secretAccessKey": "secret",
+				"bucketName":      "backups",
+			},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("BackupToS3", models.S3BackupSettings{AccessKeyID: "AKIA", SecretAccessKey: "secret", BucketName: "backups"}).Return(nil)
+			},
+			expectedMessage: "Backing up to S3 bucket backups",
+		},
+		{
+			name: "redeploy stack with image pull",
+			handler: func(s *PortainerMCPServer) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return s.HandleRedeployStackGit()
+			},
+			arguments: map[string]any{"id": float64(4), "environmentId": float64(1), "pullImage": true},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("RedeployStackGit", 4, 1, true, false).Return(models.RegularStack{ID: 4}, nil)
+			},
+			expectedMessage: "Redeploying stack 4 and pulling its images",
+		},
+		{
+			name: "install helm chart",
+			handler: func(s *PortainerMCPServer) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return s.HandleInstallHelmChart()
+			},
+			arguments: map[string]any{
+				"environmentId": float64(3),
+				"chart":         "redis",
+				"name":          "cache",
+				"repo":          "https://charts.bitnami.com/bitnami",
+			},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("InstallHelmChart", 3, "redis", "cache", "", "https://charts.bitnami.com/bitnami", "", "").Return(models.HelmReleaseDetails{Name: "cache"}, nil)
+			},
+			expectedMessage: "Installing Helm chart redis as release cache",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestMetaServer(false)
+			mockClient := s.cli.(*MockPortainerClient)
+			tt.setupMock(mockClient)
+			session := newNotificationSession()
+			ctx := s.srv.WithContext(context.Background(), session)
+
+			result, err := tt.handler(s)(ctx, newProgressRequest("op", tt.arguments))
+
+			require.NoError(t, err)
+			assert.False(t, result.IsError, resultText(result))
+			params := session.progressParams()
+			require.Len(t, params, 2)
+			assert.Equal(t, tt.expectedMessage, params[0]["message"])
+			assert.Contains(t, params[1]["message"], tt.expectedMessage+": done after")
+			assert.Equal(t, 1.0, params[1]["total"])
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// findMetaAction returns the meta-tool action named by a "meta_tool.action"
+// reference of the prompts file.
+func findMetaAction(ref string) (string, metaAction, bool) {
+	metaTool, actionName, ok := strings.Cut(ref, ".")
+	if !ok {
+		return "", metaAction{}, false
+	}
+	for _, def := range metaToolDefinitions() {
+		if def.name != metaTool {
+			continue
+		}
+		for _, a := range def.actions {
+			if a.name == actionName {
+				return def.name, a, true
+			}
+		}
+	}
+	return "", metaAction{}, false
+}
+
+// toolReference returns how a prompt tells the model to call an action: the
+// meta-tool and its action, or the granular tool in granular mode. An action
+// that is unavailable, because of read-only mode or the policy, is an error;
+// templates guard such references with the available function.
+func (s *PortainerMCPServer) toolReference(ref string) (string, error) {
+	metaTool, a, ok := findMetaAction(ref)
+	if !ok {
+		return "", fmt.Errorf("unknown action %q", ref)
+	}
+	if !s.actionAllowed(metaTool, a) {
+		return "", fmt.Errorf("action %q is not available on this server", ref)
+	}
+	if s.granularTools {
+		return fmt.Sprintf("the %s tool", a.tool), nil
+	}
+	return fmt.Sprintf("the %s tool (action %s)", metaTool, a.name), nil
+}
+
+// actionAvailable reports whether an action can be called on this server, so
+// that a template can mention the actions that are not listed by the prompt.
+func (s *PortainerMCPServer) actionAvailable(ref string) (bool, error) {
+	metaTool, a, ok := findMetaAction(ref)
+	if !ok {
+		return false, fmt.Errorf("unknown action %q", ref)
+	}
+	return s.actionAllowed(metaTool, a), nil
+}
+
+// parsePromptTemplate parses the template of a prompt definition, checking
+// that the actions it lists exist.
+func (s *PortainerMCPServer) parsePromptTemplate(def toolgen.PromptDefinition) (*template.Template, error) {
+	for _, ref := range def.Actions {
+		if _, _, ok := findMetaAction(ref); !ok {
+			return nil, fmt.Errorf("unknown action %q", ref)
+		}
+	}
+	tmpl, err := template.New(def.Name).
+		Option("missingkey=zero").
+		Funcs(template.FuncMap{"tool": s.toolReference, "available": s.actionAvailable}).
+		Parse(def.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RegisterPrompts registers the prompts of the prompts file, which expand into
+// guided instructions for common workflows. A prompt is skipped when one of
+// the actions it lists is unavailable because of read-only mode or the
+// policy, and invalid prompts are skipped with a warning.
+func (s *PortainerMCPServer) RegisterPrompts() {
+	for _, def := range s.prompts {
+		tmpl, err := s.parsePromptTemplate(def)
+		if err != nil {
+			log.Warn().Str("prompt", def.Name).Err(err).Msg("Skipping invalid prompt")
+			continue
+		}
+		if ref, missing := s.missingPromptAction(def); missing {
+			log.Debug().Str("prompt", def.Name).Str("action", ref).Msg("Prompt action unavailable, prompt will not be registered")
+			continue
+		}
+
+		options := []mcp.PromptOption{mcp.WithPromptDescription(def.Description)}
+		for _, arg := range def.Arguments {
+			argOptions := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.Description)}
+			if arg.Required {
+				argOptions = append(argOptions, mcp.RequiredArgument())
+			}
+			options = append(options, mcp.WithArgument(arg.Name, argOptions...))
+		}
+		s.srv.AddPrompt(mcp.NewPrompt(def.Name, options...), promptHandler(def, tmpl))
+	}
+}
+
+// missingPromptAction returns the first action of a prompt that is not
+// available, if any.
+func (s *PortainerMCPServer) missingPromptAction(def toolgen.PromptDefinition) (string, bool) {
+	for _, ref := range def.Actions {
+		metaTool, a, _ := findMetaAction(ref)
+		if !s.actionAllowed(metaTool, a) {
+			return ref, true
+		}
+	}
+	return "", false
+}
+
+// promptHandler returns the handler expanding a prompt with its arguments.
+func promptHandler(def toolgen.PromptDefinition, tmpl *template.Template) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		arguments := request.Params.Arguments
+		if arguments == nil {
+			arguments = map[string]string{}
+		}
+		for _, arg := range def.Arguments {
+			if arg.Required && strings.TrimSpace(arguments[arg.Name]) == "" {
+				return nil, fmt.Errorf("missing required argument %q", arg.Name)
+			}
+		}
+
+		var text strings.Builder
+		if err := tmpl.Execute(&text, arguments); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", def.Name, err)
+		}
+
+		return mcp.NewGetPromptResult(def.Description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text.String())),
+		}), nil
+	}
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/internal/tooldef"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPromptServer returns a test server with prompt capabilities and the
+// prompts of the embedded prompts file.
+func newTestPromptServer(t *testing.T, readOnly bool) *PortainerMCPServer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prompts.yaml")
+	require.NoError(t, os.WriteFile(path, tooldef.PromptsFile, 0644))
+	prompts, err := toolgen.LoadPromptsFromYAML(path, MinimumPromptsVersion)
+	require.NoError(t, err)
+
+	s := newTestMetaServer(readOnly)
+	s.srv = server.NewMCPServer("test-prompt-server", "0.0.1", server.WithPromptCapabilities(false))
+	s.prompts = prompts
+	return s
+}
+
+// listPromptNames returns the names of the registered prompts.
+func listPromptNames(t *testing.T, s *PortainerMCPServer) []string {
+	t.Helper()
+	data, err := json.Marshal(s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`)))
+	require.NoError(t, err)
+	var resp struct {
+		Result struct {
+			Prompts []struct {
+				Name string `json:"name"`
+			} `json:"prompts"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(data, &resp))
+
+	var names []string
+	for _, p := range resp.Result.Prompts {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// getPrompt sends a prompts/get request and returns the text of its message,
+// or the message of the JSON-RPC error.
+func getPrompt(t *testing.T, s *PortainerMCPServer, name string, arguments map[string]string) (string, string) {
+	t.Helper()
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "prompts/get",
+		"params":  map[string]any{"name": name, "arguments": arguments},
+	})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(s.srv.HandleMessage(context.Background(), raw))
+	require.NoError(t, err)
+	var resp struct {
+		Result struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"messages"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(data, &resp))
+	if resp.Error != nil {
+		return "", resp.Error.Message
+	}
+	require.Len(t, resp.Result.Messages, 1)
+	assert.Equal(t, "user", resp.Result.Messages[0].Role)
+	return resp.Result.Messages[0].Content.Text, ""
+}
+
+// TestRegisterPrompts verifies that the default prompts are registered, and
+// that a prompt is hidden when one of its actions is denied.
+func TestRegisterPrompts(t *testing.T) {
+	defaultPrompts := []string{"triage_unhealthy_container", "review_user_access", "prepare_stack_upgrade"}
+
+	s := newTestPromptServer(t, false)
+	s.RegisterPrompts()
+	assert.ElementsMatch(t, defaultPrompts, listPromptNames(t, s))
+
+	// The default prompts only list read actions, and mention write actions
+	// only when they are available.
+	s = newTestPromptServer(t, true)
+	s.RegisterPrompts()
+	assert.ElementsMatch(t, defaultPrompts, listPromptNames(t, s))
+
+	s = newTestPromptServer(t, false)
+	var err error
+	s.policy, err = loadPolicy(writePolicyFile(t, "tools:\n  deny: [manage_docker.get_docker_container_logs, manage_users]\n"))
+	require.NoError(t, err)
+	s.RegisterPrompts()
+	assert.Equal(t, []string{"prepare_stack_upgrade"}, listPromptNames(t, s))
+}
+
+// TestGetPromptUnavailableActions verifies that a prompt only names the
+// actions that are available on the server.
+func TestGetPromptUnavailableActions(t *testing.T) {
+	s := newTestPromptServer(t, true)
+	s.RegisterPrompts()
+
+	text, errMsg := getPrompt(t, s, "prepare_stack_upgrade", map[string]string{"stack": "shop"})
+	require.Empty(t, errMsg)
+	assert.NotContains(t, text, "update_stack")
+	assert.NotContains(t, text, "redeploy_stack_git")
+	assert.Contains(t, text, "This server cannot change stacks")
+
+	s = newTestPromptServer(t, true)
+	s.prompts = []toolgen.PromptDefinition{
+		{Name: "unguarded", Description: "d", Actions: []string{"manage_stacks.get_stack"}, Template: `Call {{tool "manage_stacks.delete_stack"}}.`},
+	}
+	s.RegisterPrompts()
+
+	_, errMsg = getPrompt(t, s, "unguarded", nil)
+	assert.Contains(t, errMsg, `action "manage_stacks.delete_stack" is not available on this server`)
+}
+
+// TestPromptStackKinds verifies that the default prompts do not mix regular
+// and edge stacks: a stack ID found among regular stacks must not be passed
+// to an edge stack action, whose IDs are another sequence.
+func TestPromptStackKinds(t *testing.T) {
+	s := newTestPromptServer(t, false)
+	toolRef := regexp.MustCompile(`\{\{tool "([^"]+)"\}\}`)
+
+	for _, def := range s.prompts {
+		t.Run(def.Name, func(t *testing.T) {
+			var regular, edge []string
+			for _, match := range toolRef.FindAllStringSubmatch(def.Template, -1) {
+				_, a, ok := findMetaAction(match[1])
+				require.True(t, ok, "unknown action %s", match[1])
+				switch {
+				case a.tool == ToolListRegularStacks || slices.Contains(stackTools, a.tool):
+					regular = append(regular, match[1])
+				case a.tool == ToolListStacks || slices.Contains(edgeStackTools, a.tool):
+					edge = append(edge, match[1])
+				}
+			}
+			assert.False(t, len(regular) > 0 && len(edge) > 0, "prompt mixes regular stack actions %v with edge stack actions %v", regular, edge)
+		})
+	}
+
+	assert.NotContains(t, s.prompts[slices.IndexFunc(s.prompts, func(def toolgen.PromptDefinition) bool { return def.Name == "prepare_stack_upgrade" })].Template, "manage_stacks.update_stack\"")
+}
+
+// TestRegisterPromptsInvalid verifies that prompts referencing unknown actions
+// or with invalid templates are skipped.
+func TestRegisterPromptsInvalid(t *testing.T) {
+	s := newTestPromptServer(t, false)
+	s.prompts = []toolgen.PromptDefinition{
+		{Name: "unknown_action", Description: "d", Actions: []string{"manage_docker.format_disk"}, Template: "Text."},
+		{Name: "not_a_reference", Description: "d", Actions: []string{"manage_docker"}, Template: "Text."},
+		{Name: "invalid_template", Description: "d", Template: "{{if .user}}"},
+		{Name: "valid", Description: "d", Actions: []string{"manage_users.get_user"}, Template: "Text."},
+	}
+	s.RegisterPrompts()
+
+	assert.Equal(t, []string{"valid"}, listPromptNames(t, s))
+}
+
+// TestGetPrompt verifies the expansion of prompts with their arguments.
+func TestGetPrompt(t *testing.T) {
+	s := newTestPromptServer(t, false)
+	s.RegisterPrompts()
+
+	text, errMsg := getPrompt(t, s, "triage_unhealthy_container", map[string]string{"environment": "production", "container": "web-1"})
+	require.Empty(t, errMsg)
+	assert.Contains(t, text, `Triage the unhealthy container "web-1" in the Portainer environment "production".`)
+	assert.Contains(t, text, "the manage_docker tool (action inspect_docker_container)")
+
+	text, errMsg = getPrompt(t, s, "prepare_stack_upgrade", map[string]string{"stack": "shop"})
+	require.Empty(t, errMsg)
+	assert.Contains(t, text, `Prepare the upgrade of the stack "shop". Only prepare it`)
+	assert.NotContains(t, text, "<no value>")
+
+	text, errMsg = getPrompt(t, s, "prepare_stack_upgrade", map[string]string{"stack": "shop", "environment": "2", "target": "v2.1.0"})
+	require.Empty(t, errMsg)
+	assert.Contains(t, text, `Prepare the upgrade of the stack "shop" in the environment "2" to v2.1.0.`)
+	assert.Contains(t, text, "the manage_stacks tool (action update_stack_git) then the manage_stacks tool (action redeploy_stack_git)")
+
+	_, errMsg = getPrompt(t, s, "review_user_access", map[string]string{})
+	assert.Contains(t, errMsg, `missing required argument "user"`)
+
+	_, errMsg = getPrompt(t, s, "format_disk", nil)
+	assert.Contains(t, errMsg, "not found")
+
+	s = newTestPromptServer(t, false)
+	s.granularTools = true
+	s.RegisterPrompts()
+
+	text, errMsg = getPrompt(t, s, "review_user_access", map[string]string{"user": "alice"})
+	require.Empty(t, errMsg)
+	assert.Contains(t, text, `Review the access of the Portainer user "alice".`)
+	assert.Contains(t, text, "the "+ToolGetUser+" tool")
+	assert.NotContains(t, text, "manage_users")
+}
+
+// TestNewPortainerMCPServerPrompts verifies that the prompts file is loaded
+// by the constructor, and that an unreadable file is an error.
+func TestNewPortainerMCPServerPrompts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompts.yaml")
+	require.NoError(t, os.WriteFile(path, tooldef.PromptsFile, 0644))
+
+	s, err := NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true), WithGranularTools(true), WithPromptsFile(path))
+	require.NoError(t, err)
+	assert.Len(t, s.prompts, 3)
+	assert.True(t, s.granularTools)
+
+	_, err = NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true), WithPromptsFile("testdata/nonexistent.yaml"))
+	assert.ErrorContains(t, err, "failed to load prompts")
+}
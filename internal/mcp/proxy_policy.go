@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
+)
+
+// dockerAPIVersionPrefix matches the optional API version segment of a Docker
+// Engine API path, such as /v1.41, which proxy rules do not include.
+var dockerAPIVersionPrefix = regexp.MustCompile(`^/v[0-9]+(\.[0-9]+)?(/|$)`)
+
+// proxyPolicy holds the request rules of the Docker and Kubernetes proxy tools.
+type proxyPolicy struct {
+	Docker     proxyRules `yaml:"docker"`
+	Kubernetes proxyRules `yaml:"kubernetes"`
+}
+
+// proxyRules lists the requests a proxy tool may and may not send. Each rule
+// is an HTTP method, or * for any method, followed by a path pattern, such as
+// "GET /containers/**" or "POST /containers/*/exec". In a path pattern * matches
+// a single path segment and ** any number of segments; other path.Match
+// wildcards apply within a segment. When Allow is not empty, only the requests
+// it matches are sent. Deny always takes precedence over Allow.
+type proxyRules struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+
+	allow []proxyRule
+	deny  []proxyRule
+}
+
+// proxyRule is a parsed proxy request rule.
+type proxyRule struct {
+	raw      string
+	method   string
+	segments []string
+}
+
+// compile parses the allow and deny rules.
+func (r *proxyRules) compile() error {
+	var err error
+	if r.allow, err = parseProxyRules(r.Allow); err != nil {
+		return err
+	}
+	r.deny, err = parseProxyRules(r.Deny)
+	return err
+}
+
+// empty reports whether no rule is configured.
+func (r *proxyRules) empty() bool {
+	return len(r.Allow) == 0 && len(r.Deny) == 0
+}
+
+// parseProxyRules parses "METHOD /path/pattern" rules.
+func parseProxyRules(rules []string) ([]proxyRule, error) {
+	parsed := make([]proxyRule, 0, len(rules))
+	for _, raw := range rules {
+		fields := strings.Fields(raw)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid proxy rule %q: must be a method and a path pattern, such as \"GET /containers/**\"", raw)
+		}
+		method := strings.ToUpper(fields[0])
+		if method != "*" && !isValidHTTPMethod(method) {
+			return nil, fmt.Errorf("invalid proxy rule %q: unsupported method %s", raw, fields[0])
+		}
+		if !strings.HasPrefix(fields[1], "/") {
+			return nil, fmt.Errorf("invalid proxy rule %q: path pattern must start with a leading slash", raw)
+		}
+		segments := splitPath(fields[1])
+		for _, segment := range segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("invalid proxy rule %q: %w", raw, err)
+			}
+		}
+		parsed = append(parsed, proxyRule{raw: raw, method: method, segments: segments})
+	}
+	return parsed, nil
+}
+
+// check returns an error if the rules do not permit a request.
+func (r *proxyRules) check(method, apiPath string) error {
+	segments := splitPath(apiPath)
+	method = strings.ToUpper(method)
+	for _, rule := range r.deny {
+		if rule.matches(method, segments) {
+			return fmt.Errorf("%s %s is denied by policy rule %q", method, apiPath, rule.raw)
+		}
+	}
+	if len(r.allow) == 0 {
+		return nil
+	}
+	for _, rule := range r.allow {
+		if rule.matches(method, segments) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s is not allowed by any policy rule", method, apiPath)
+}
+
+// matches reports whether the rule matches a request.
+func (r proxyRule) matches(method string, segments []string) bool {
+	return (r.method == "*" || r.method == method) && matchSegments(r.segments, segments)
+}
+
+// matchSegments matches path segments against pattern segments, where ** matches any number of segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// splitPath returns the non-empty segments of a path.
+func splitPath(p string) []string {
+	return strings.FieldsFunc(p, func(r rune) bool { return r == '/' })
+}
+
+// normalizeProxyPath returns the path a proxy request is matched on: without
+// query string, percent-decoded, and with dot segments and repeated slashes
+// resolved, so that a request cannot avoid a rule by spelling its path differently.
+func normalizeProxyPath(apiPath string) (string, error) {
+	if i := strings.IndexByte(apiPath, '?'); i >= 0 {
+		apiPath = apiPath[:i]
+	}
+	decoded, err := url.PathUnescape(apiPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: %w", apiPath, err)
+	}
+	return path.Clean("/" + decoded), nil
+}
+
+// checkDockerProxy returns an error if the policy does not permit a Docker
+// API request. The API version prefix of the path, if any, is ignored.
+func (p *policy) checkDockerProxy(method, apiPath string) error {
+	if p == nil || p.Proxy.Docker.empty() {
+		return nil
+	}
+	normalized, err := normalizeProxyPath(apiPath)
+	if err != nil {
+		return err
+	}
+	if loc := dockerAPIVersionPrefix.FindStringIndex(normalized); loc != nil {
+		normalized = "/" + normalized[loc[1]:]
+	}
+	return p.Proxy.Docker.check(method, normalized)
+}
+
+// checkKubernetesProxy returns an error if the policy does not permit a Kubernetes API request.
+func (p *policy) checkKubernetesProxy(method, apiPath string) error {
+	if p == nil || p.Proxy.Kubernetes.empty() {
+		return nil
+	}
+	normalized, err := normalizeProxyPath(apiPath)
+	if err != nil {
+		return err
+	}
+	return p.Proxy.Kubernetes.check(method, normalized)
+}
+
+// hasProxyRules reports whether the policy has Docker or Kubernetes proxy rules.
+func (p *policy) hasProxyRules() bool {
+	return p != nil && (!p.Proxy.Docker.empty() || !p.Proxy.Kubernetes.empty())
+}
+
+// checkProxyRequest is the proxy request check of the Portainer client, so
+// that the rules also apply to the requests of the typed Docker and
+// Kubernetes tools, such as execDockerContainer and getKubernetesPodLogs.
+func (p *policy) checkProxyRequest(api, method, apiPath string) error {
+	var err error
+	switch api {
+	case client.ProxyAPIDocker:
+		err = p.checkDockerProxy(method, apiPath)
+	case client.ProxyAPIKubernetes:
+		err = p.checkKubernetesProxy(method, apiPath)
+	}
+	if err != nil {
+		return fmt.Errorf("%s API request denied by policy: %w", api, err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProxyRulesCheck verifies how method and path rules match proxy requests.
+func TestProxyRulesCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         proxyRules
+		method        string
+		path          string
+		errorContains string
+	}{
+		{
+			name:   "no rules",
+			method: "POST",
+			path:   "/swarm/leave",
+		},
+		{
+			name:   "allowed by double wildcard",
+			rules:  proxyRules{Allow: []string{"GET /containers/**"}},
+			method: "GET",
+			path:   "/containers/abc/json",
+		},
+		{
+			name:   "double wildcard matches no segment",
+			rules:  proxyRules{Allow: []string{"GET /containers/**"}},
+			method: "GET",
+			path:   "/containers",
+		},
+		{
+			name:          "method not allowed",
+			rules:         proxyRules{Allow: []string{"GET /containers/**"}},
+			method:        "DELETE",
+			path:          "/containers/abc",
+			errorContains: "DELETE /containers/abc is not allowed by any policy rule",
+		},
+		{
+			name:          "denied by single wildcard",
+			rules:         proxyRules{Allow: []string{"* /containers/**"}, Deny: []string{"POST /containers/*/exec"}},
+			method:        "POST",
+			path:          "/containers/abc/exec",
+			errorContains: `POST /containers/abc/exec is denied by policy rule "POST /containers/*/exec"`,
+		},
+		{
+			name:   "single wildcard matches one segment",
+			rules:  proxyRules{Deny: []string{"POST /containers/*/exec"}},
+			method: "POST",
+			path:   "/containers/abc/start",
+		},
+		{
+			name:          "any method",
+			rules:         proxyRules{Deny: []string{"* /swarm/**"}},
+			method:        "POST",
+			path:          "/swarm/leave",
+			errorContains: "denied by policy rule",
+		},
+		{
+			name:          "wildcard within a segment",
+			rules:         proxyRules{Deny: []string{"DELETE /api/v1/namespaces/kube-*/**"}},
+			method:        "DELETE",
+			path:          "/api/v1/namespaces/kube-system/pods/coredns",
+			errorContains: "denied by policy rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.rules.compile())
+
+			err := tt.rules.check(tt.method, tt.path)
+
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+// TestParseProxyRules verifies that malformed proxy rules are rejected.
+func TestParseProxyRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		rule          string
+		errorContains string
+	}{
+		{name: "missing method", rule: "/containers/**", errorContains: "must be a method and a path pattern"},
+		{name: "unsupported method", rule: "FETCH /containers", errorContains: "unsupported method FETCH"},
+		{name: "relative path", rule: "GET containers/**", errorContains: "must start with a leading slash"},
+		{name: "invalid pattern", rule: "GET /containers/[", errorContains: "syntax error in pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseProxyRules([]string{tt.rule})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+
+	rules, err := parseProxyRules([]string{"get /info"})
+	require.NoError(t, err)
+	assert.Equal(t, "GET", rules[0].method, "methods are case-insensitive")
+}
+
+// TestCheckProxyPathNormalization verifies that a request cannot avoid a rule
+// by spelling its path differently.
+func TestCheckProxyPathNormalization(t *testing.T) {
+	p := &policy{Proxy: proxyPolicy{
+		Docker:     proxyRules{Deny: []string{"POST /swarm/leave"}},
+		Kubernetes: proxyRules{Deny: []string{"DELETE /api/v1/namespaces/*"}},
+	}}
+	require.NoError(t, p.Proxy.Docker.compile())
+	require.NoError(t, p.Proxy.Kubernetes.compile())
+
+	for _, dockerPath := range []string{
+		"/swarm/leave",
+		"/v1.41/swarm/leave",
+		"/v1/swarm/leave",
+		"//swarm//leave/",
+		"/containers/../swarm/leave",
+		"/swarm/%6Ceave",
+		"/swarm/leave?force=true",
+	} {
+		assert.Error(t, p.checkDockerProxy("POST", dockerPath), dockerPath)
+	}
+	assert.NoError(t, p.checkDockerProxy("POST", "/swarm/init"))
+	assert.Error(t, p.checkDockerProxy("POST", "/swarm/%zz"), "an undecodable path is rejected")
+
+	assert.Error(t, p.checkKubernetesProxy("DELETE", "/api/v1/./namespaces/default"))
+	assert.NoError(t, p.checkKubernetesProxy("GET", "/api/v1/namespaces/default"))
+
+	var nilPolicy *policy
+	assert.NoError(t, nilPolicy.checkDockerProxy("POST", "/swarm/leave"))
+	assert.NoError(t, nilPolicy.checkKubernetesProxy("DELETE", "/api/v1/namespaces/default"))
+}
+
+// TestLoadPolicyProxyRules verifies that proxy rules are parsed when the policy file is loaded.
+func TestLoadPolicyProxyRules(t *testing.T) {
+	p, err := loadPolicy(writePolicyFile(t, `proxy:
+  docker:
+    allow:
+      - GET /containers/**
+    deny:
+      - POST /containers/*/exec
+  kubernetes:
+    deny:
+      - DELETE /**
+`))
+	require.NoError(t, err)
+	assert.NoError(t, p.checkDockerProxy("GET", "/containers/json"))
+	assert.Error(t, p.checkDockerProxy("GET", "/images/json"))
+	assert.Error(t, p.checkKubernetesProxy("DELETE", "/api/v1/namespaces/default/pods/web"))
+
+	_, err = loadPolicy(writePolicyFile(t, "proxy:\n  kubernetes:\n    deny:\n      - DELETE\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid kubernetes proxy policy")
+}
+
+// TestProxyHandlersEnforcePolicy verifies that denied requests are rejected
+// before they reach the Portainer client.
+func TestProxyHandlersEnforcePolicy(t *testing.T) {
+	p := &policy{Proxy: proxyPolicy{
+		Docker:     proxyRules{Deny: []string{"POST /containers/*/exec"}},
+		Kubernetes: proxyRules{Allow: []string{"GET /api/v1/namespaces/default/**"}},
+	}}
+	require.NoError(t, p.Proxy.Docker.compile())
+	require.NoError(t, p.Proxy.Kubernetes.compile())
+
+	tests := []struct {
+		name          string
+		handler       func(s *PortainerMCPServer) server.ToolHandlerFunc
+		args          map[string]any
+		errorContains string
+	}{
+		{
+			name:    "docker proxy",
+			handler: func(s *PortainerMCPServer) server.ToolHandlerFunc { return s.HandleDockerProxy() },
+			args: map[string]any{
+				"environmentId": float64(1),
+				"method":        "POST",
+				"dockerAPIPath": "/containers/web/exec",
+			},
+			errorContains: "Docker API request denied by policy",
+		},
+		{
+			name:    "kubernetes proxy",
+			handler: func(s *PortainerMCPServer) server.ToolHandlerFunc { return s.HandleKubernetesProxy() },
+			args: map[string]any{
+				"environmentId":     float64(1),
+				"method":            "DELETE",
+				"kubernetesAPIPath": "/api/v1/namespaces/default/pods/web",
+			},
+			errorContains: "Kubernetes API request denied by policy",
+		},
+		{
+			name:    "kubernetes stripped proxy",
+			handler: func(s *PortainerMCPServer) server.ToolHandlerFunc { return s.HandleKubernetesProxyStripped() },
+			args: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/namespaces/kube-system/secrets",
+			},
+			errorContains: "GET /api/v1/namespaces/kube-system/secrets is not allowed by any policy rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestMetaServer(false)
+			s.policy = p
+			mockClient := s.cli.(*MockPortainerClient)
+
+			result, err := tt.handler(s)(context.Background(), CreateMCPRequest(tt.args))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.errorContains)
+			mockClient.AssertNotCalled(t, "ProxyDockerRequest", mock.Anything)
+			mockClient.AssertNotCalled(t, "ProxyKubernetesRequest", mock.Anything)
+		})
+	}
+}
+
+// TestPolicyCheckProxyRequest verifies the proxy request check that the
+// Portainer client applies to the requests of the typed Docker and Kubernetes tools.
+func TestPolicyCheckProxyRequest(t *testing.T) {
+	p := &policy{Proxy: proxyPolicy{
+		Docker:     proxyRules{Deny: []string{"POST /containers/*/exec"}},
+		Kubernetes: proxyRules{Deny: []string{"GET /api/v1/namespaces/*/pods/*/exec"}},
+	}}
+	require.NoError(t, p.Proxy.Docker.compile())
+	require.NoError(t, p.Proxy.Kubernetes.compile())
+	assert.True(t, p.hasProxyRules())
+
+	err := p.checkProxyRequest(client.ProxyAPIDocker, "POST", "/containers/web/exec")
+	assert.ErrorContains(t, err, "docker API request denied by policy: POST /containers/web/exec is denied")
+	err = p.checkProxyRequest(client.ProxyAPIKubernetes, "GET", "/api/v1/namespaces/default/pods/web/exec?command=id&stdout=true")
+	assert.ErrorContains(t, err, "kubernetes API request denied by policy")
+	assert.NoError(t, p.checkProxyRequest(client.ProxyAPIDocker, "GET", "/containers/web/json"))
+	assert.NoError(t, p.checkProxyRequest(client.ProxyAPIKubernetes, "GET", "/api/v1/namespaces/default/pods/web/log"))
+
+	assert.False(t, (*policy)(nil).hasProxyRules())
+	assert.False(t, (&policy{}).hasProxyRules())
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// rateLimit limits how often the tools and actions it matches can be called.
+// Tools lists rules with the syntax of tool rules. PerMinute is the sustained
+// rate, and Burst the number of calls that can be made at once after a quiet
+// period; it defaults to PerMinute, and at least 1. The calls of every tool a
+// rule matches share one budget.
+type rateLimit struct {
+	Tools     []string `yaml:"tools"`
+	PerMinute float64  `yaml:"perMinute"`
+	Burst     int      `yaml:"burst"`
+}
+
+// validate checks that the rate limit matches at least one tool and has a positive rate.
+func (r rateLimit) validate(p *policy) error {
+	if len(r.Tools) == 0 {
+		return fmt.Errorf("tools must not be empty")
+	}
+	if err := p.validateToolRules(r.Tools); err != nil {
+		return err
+	}
+	if r.PerMinute <= 0 {
+		return fmt.Errorf("perMinute must be positive, got %g", r.PerMinute)
+	}
+	if r.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got %d", r.Burst)
+	}
+	return nil
+}
+
+// String describes the rate limit.
+func (r rateLimit) String() string {
+	return fmt.Sprintf("%g calls per minute for %s", r.PerMinute, strings.Join(r.Tools, ", "))
+}
+
+// rateLimitedResult is returned instead of running a tool call that exceeds a limit.
+type rateLimitedResult struct {
+	RateLimited       bool   `json:"rateLimited"`
+	RetryAfterSeconds int    `json:"retryAfterSeconds"`
+	Limit             string `json:"limit"`
+	Message           string `json:"message"`
+}
+
+// rateLimiter bounds the tool calls sent toward Portainer: the number of calls
+// running at once across all tools, and the rate of the calls matched by each
+// rate limit of the policy.
+type rateLimiter struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	// inFlight holds a slot for each running call; nil when unlimited.
+	inFlight    chan struct{}
+	maxInFlight int
+	buckets     []*tokenBucket
+}
+
+// tokenBucket is the budget of one rate limit. It holds up to burst tokens,
+// refilled at the rate of the limit, and each call takes one.
+type tokenBucket struct {
+	limit  rateLimit
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter for the given maximum of calls in flight,
+// where zero means unlimited, and rate limits. It returns nil when neither
+// limits anything.
+func newRateLimiter(maxInFlight int, limits []rateLimit) *rateLimiter {
+	if maxInFlight <= 0 && len(limits) == 0 {
+		return nil
+	}
+
+	l := &rateLimiter{now: time.Now, maxInFlight: maxInFlight}
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+	for _, limit := range limits {
+		burst := float64(limit.Burst)
+		if limit.Burst == 0 {
+			burst = math.Max(1, math.Floor(limit.PerMinute))
+		}
+		l.buckets = append(l.buckets, &tokenBucket{
+			limit:  limit,
+			rate:   limit.PerMinute / 60,
+			burst:  burst,
+			tokens: burst,
+		})
+	}
+	return l
+}
+
+// bucketsFor returns the buckets of the rate limits that match a tool or action.
+func (l *rateLimiter) bucketsFor(target policyTarget) []*tokenBucket {
+	var buckets []*tokenBucket
+	for _, bucket := range l.buckets {
+		if matchesAnyRule(bucket.limit.Tools, target) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
+// acquireSlot reserves a slot for a running call. It returns false when the
+// maximum of calls in flight is reached.
+func (l *rateLimiter) acquireSlot() bool {
+	if l.inFlight == nil {
+		return true
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseSlot frees the slot reserved by acquireSlot.
+func (l *rateLimiter) releaseSlot() {
+	if l.inFlight != nil {
+		<-l.inFlight
+	}
+}
+
+// take takes a token from each bucket. When a bucket is empty, no token is
+// taken and it returns the time until every bucket has one and the limit that
+// was exceeded.
+func (l *rateLimiter) take(buckets []*tokenBucket) (time.Duration, *rateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	var exceeded *rateLimit
+	for _, bucket := range buckets {
+		bucket.refill(now)
+		if bucket.tokens >= 1 {
+			continue
+		}
+		if delay := time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second)); delay > wait {
+			wait = delay
+			exceeded = &bucket.limit
+		}
+	}
+	if exceeded != nil {
+		return wait, exceeded
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return 0, nil
+}
+
+// refill adds the tokens accrued since the last call, up to the burst.
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// withRateLimit wraps the handler of a tool or action so that calls beyond the
+// maximum in flight or a rate limit of the policy are not run. They get a
+// rateLimitedResult that tells the caller when to retry.
+func (s *PortainerMCPServer) withRateLimit(target policyTarget, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	buckets := s.limiter.bucketsFor(target)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !s.limiter.acquireSlot() {
+			return rateLimited(time.Second, fmt.Sprintf("at most %d calls in flight", s.limiter.maxInFlight))
+		}
+		defer s.limiter.releaseSlot()
+
+		if wait, limit := s.limiter.take(buckets); limit != nil {
+			return rateLimited(wait, limit.String())
+		}
+		return next(ctx, request)
+	}
+}
+
+// rateLimited returns the error result of a call rejected by a limit.
+func rateLimited(wait time.Duration, limit string) (*mcp.CallToolResult, error) {
+	retryAfter := max(1, int(math.Ceil(wait.Seconds())))
+	result, err := jsonResult(rateLimitedResult{
+		RateLimited:       true,
+		RetryAfterSeconds: retryAfter,
+		Limit:             limit,
+		Message:           fmt.Sprintf("rate limited, retry after %d seconds", retryAfter),
+	}, "rate limited")
+	if result != nil {
+		result.IsError = true
+	}
+	return result, err
+}
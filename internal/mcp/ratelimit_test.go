@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRateLimitedServer returns a test server with the given limits and a
+// clock that only moves when the returned function is called.
+func newTestRateLimitedServer(maxInFlight int, limits []rateLimit) (*PortainerMCPServer, func(time.Duration)) {
+	s := newTestMetaServer(false)
+	s.limiter = newRateLimiter(maxInFlight, limits)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.limiter.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+// decodeRateLimited decodes the result of a call rejected by a limit.
+func decodeRateLimited(t *testing.T, result *mcp.CallToolResult) rateLimitedResult {
+	t.Helper()
+
+	require.True(t, result.IsError)
+	var limited rateLimitedResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &limited))
+	require.True(t, limited.RateLimited)
+	return limited
+}
+
+// TestWithRateLimit verifies that calls beyond a rate limit are rejected with
+// the time to wait, and run again once the bucket has refilled.
+func TestWithRateLimit(t *testing.T) {
+	s, advance := newTestRateLimitedServer(0, []rateLimit{{Tools: []string{ToolSnapshotEnvironment}, PerMinute: 6, Burst: 2}})
+	calls := 0
+	handler := s.withRateLimit(policyTarget{metaTool: "manage_environments", action: "snapshot_environment", tool: ToolSnapshotEnvironment},
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls++
+			return okHandler(ctx, request)
+		})
+	call := func() *mcp.CallToolResult {
+		result, err := handler(context.Background(), CreateMCPRequest(map[string]any{"id": float64(1)}))
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.False(t, call().IsError)
+	assert.False(t, call().IsError)
+	limited := decodeRateLimited(t, call())
+	assert.Equal(t, 10, limited.RetryAfterSeconds)
+	assert.Equal(t, "rate limited, retry after 10 seconds", limited.Message)
+	assert.Equal(t, "6 calls per minute for snapshotEnvironment", limited.Limit)
+	assert.Equal(t, 2, calls)
+
+	advance(4 * time.Second)
+	assert.Equal(t, 6, decodeRateLimited(t, call()).RetryAfterSeconds)
+
+	advance(6 * time.Second)
+	assert.False(t, call().IsError)
+	assert.Equal(t, 3, calls)
+}
+
+// TestRateLimitSharedBudget verifies that the tools a rate limit matches share
+// one budget and that other tools are not limited.
+func TestRateLimitSharedBudget(t *testing.T) {
+	s, _ := newTestRateLimitedServer(0, []rateLimit{{Tools: []string{"manage_docker", ToolDockerProxy}, PerMinute: 1}})
+	proxy := s.withRateLimit(policyTarget{metaTool: "manage_docker", action: "docker_proxy", tool: ToolDockerProxy}, okHandler)
+	granularProxy := s.withRateLimit(policyTarget{tool: ToolDockerProxy}, okHandler)
+	other := s.withRateLimit(policyTarget{metaTool: "manage_stacks", action: "list_stacks", tool: ToolListStacks}, okHandler)
+
+	result, err := proxy(context.Background(), CreateMCPRequest(map[string]any{}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	result, err = granularProxy(context.Background(), CreateMCPRequest(map[string]any{}))
+	require.NoError(t, err)
+	assert.Equal(t, 60, decodeRateLimited(t, result).RetryAfterSeconds)
+
+	for range 5 {
+		result, err = other(context.Background(), CreateMCPRequest(map[string]any{}))
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+	}
+}
+
+// TestMaxInFlight verifies that calls beyond the maximum in flight are
+// rejected while the others run, and accepted once a slot is free.
+func TestMaxInFlight(t *testing.T) {
+	s, _ := newTestRateLimitedServer(1, nil)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := s.withRateLimit(policyTarget{tool: ToolDockerProxy}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return okHandler(ctx, request)
+	})
+	fast := s.withRateLimit(policyTarget{tool: ToolListStacks}, okHandler)
+
+	done := make(chan *mcp.CallToolResult)
+	go func() {
+		result, _ := slow(context.Background(), CreateMCPRequest(map[string]any{}))
+		done <- result
+	}()
+	<-started
+
+	result, err := fast(context.Background(), CreateMCPRequest(map[string]any{}))
+	require.NoError(t, err)
+	limited := decodeRateLimited(t, result)
+	assert.Equal(t, 1, limited.RetryAfterSeconds)
+	assert.Equal(t, "at most 1 calls in flight", limited.Limit)
+
+	close(release)
+	assert.False(t, (<-done).IsError)
+
+	result, err = fast(context.Background(), CreateMCPRequest(map[string]any{}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+}
+
+// TestRateLimitRegistration verifies that limits apply to meta-tool actions.
+func TestRateLimitRegistration(t *testing.T) {
+	s, _ := newTestRateLimitedServer(0, []rateLimit{{Tools: []string{ToolListUsers}, PerMinute: 1}})
+	s.cli.(*MockPortainerClient).On("GetUsers").Return(nil, nil)
+	s.RegisterMetaTools()
+
+	call := func() string {
+		resp := s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"manage_users","arguments":{"action":"list_users"}}}`))
+		data, err := json.Marshal(resp)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	assert.NotContains(t, call(), "rateLimited")
+	assert.Contains(t, call(), "rate limited, retry after 60 seconds")
+}
+
+// TestLoadPolicyRateLimits verifies that rate limits are validated when the policy file is loaded.
+func TestLoadPolicyRateLimits(t *testing.T) {
+	p, err := loadPolicy(writePolicyFile(t, "rateLimits:\n  - tools: [snapshotEnvironment, dockerProxy]\n    perMinute: 10\n    burst: 5\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []rateLimit{{Tools: []string{"snapshotEnvironment", "dockerProxy"}, PerMinute: 10, Burst: 5}}, p.RateLimits)
+
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{
+			name:          "no tools",
+			content:       "rateLimits:\n  - perMinute: 10\n",
+			errorContains: "invalid rate limit 1: tools must not be empty",
+		},
+		{
+			name:          "unknown tool",
+			content:       "rateLimits:\n  - tools: [snapshotEverything]\n    perMinute: 10\n",
+			errorContains: `policy rule "snapshotEverything" does not match any tool or action`,
+		},
+		{
+			name:          "no rate",
+			content:       "rateLimits:\n  - tools: [dockerProxy]\n",
+			errorContains: "perMinute must be positive",
+		},
+		{
+			name:          "negative burst",
+			content:       "rateLimits:\n  - tools: [dockerProxy]\n    perMinute: 10\n    burst: -1\n",
+			errorContains: "burst must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadPolicy(writePolicyFile(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+
+	assert.Nil(t, newRateLimiter(0, nil))
+}
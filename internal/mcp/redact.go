@@ -0,0 +1,326 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// includeSecretsParam is the argument that turns off redaction for one call,
+	// on the tools for which the policy allows it.
+	includeSecretsParam = "includeSecrets"
+
+	// includeSecretsParamDescription documents the includeSecrets argument.
+	includeSecretsParamDescription = "Return secret values such as passwords, tokens, and Kubernetes Secret data instead of redacting them (default: false)"
+
+	// lastAppliedConfigAnnotation holds a copy of the object as last applied by kubectl, secrets included.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	// sensitiveOutputKeys are the exact keys, in lower case with '_' and '-'
+	// removed, whose values are redacted in tool results in addition to the
+	// argument keys of the audit log, such as registry credentials in Docker
+	// configuration files.
+	sensitiveOutputKeys = []string{"auth", "xregistryauth", "registryauth"}
+
+	// kubeconfigCredentialFields are the fields of a kubeconfig user that hold
+	// its credentials, whose names are not all sensitive keys.
+	kubeconfigCredentialFields = []string{"client-key-data", "client-certificate-data", "token", "password"}
+
+	// referenceKeySuffixes mark keys that only name or locate a secret, such as
+	// secretName or tokenId, and are therefore not redacted.
+	referenceKeySuffixes = []string{"name", "names", "ref", "id", "ids", "path", "file", "type"}
+
+	// envAssignment matches a line holding a KEY=value environment variable or
+	// --key=value flag, after an optional [stdout] or [stderr] stream label and
+	// an optional RFC 3339 timestamp, as in container and pod logs.
+	envAssignment = regexp.MustCompile(`^((?:\[[a-z]+\] )?(?:\d{4}-\d{2}-\d{2}T[0-9:.]+(?:Z|[+-]\d{2}:\d{2}) )?\s*-{0,2}([A-Za-z_][A-Za-z0-9_.-]*)=)(.+)$`)
+
+	// urlCredentials matches the password of credentials embedded in a URL.
+	urlCredentials = regexp.MustCompile(`\b([A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]+:)[^/\s@]+@`)
+)
+
+// redactionPolicy configures redaction of tool results in the policy file.
+// Keys are additional key names, matched case-insensitively with path.Match
+// wildcards, whose values are redacted. Patterns are regular expressions
+// whose matches are redacted in every string. OptOut lists, with the syntax
+// of tool rules, the tools and actions that accept the includeSecrets argument.
+type redactionPolicy struct {
+	Keys     []string `yaml:"keys"`
+	Patterns []string `yaml:"patterns"`
+	OptOut   []string `yaml:"optOut"`
+}
+
+// redactor removes secrets from tool results.
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+}
+
+// newRedactor returns a redactor with the built-in rules and those of the
+// policy. A nil policy uses the built-in rules only.
+func newRedactor(p *policy) (*redactor, error) {
+	r := &redactor{}
+	if p == nil {
+		return r, nil
+	}
+	for _, key := range p.Redaction.Keys {
+		if _, err := path.Match(key, ""); err != nil {
+			return nil, fmt.Errorf("invalid redaction key %q: %w", key, err)
+		}
+		r.keys = append(r.keys, strings.ToLower(key))
+	}
+	for _, pattern := range p.Redaction.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// allowsOptOut reports whether the policy lets calls of a tool or action
+// include secrets with the includeSecrets argument.
+func (p *policy) allowsOptOut(target policyTarget) bool {
+	return p != nil && matchesAnyRule(p.Redaction.OptOut, target)
+}
+
+// withRedaction wraps a tool handler so that secrets are redacted from its
+// result. When the policy allows it for the tool, a call can set
+// includeSecrets to receive the result unredacted.
+func (s *PortainerMCPServer) withRedaction(target policyTarget, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	optOut := s.policy.allowsOptOut(target)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		includeSecrets, _ := request.GetArguments()[includeSecretsParam].(bool)
+		if includeSecrets && !optOut {
+			return mcp.NewToolResultError("includeSecrets is not allowed for this tool by the server policy"), nil
+		}
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil || includeSecrets {
+			return result, err
+		}
+		return s.redactor.redactResult(result), nil
+	}
+}
+
+// redactResult redacts the text content of a tool result in place.
+func (r *redactor) redactResult(result *mcp.CallToolResult) *mcp.CallToolResult {
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			text.Text = r.redactText(text.Text)
+			result.Content[i] = text
+		}
+	}
+	return result
+}
+
+// redactText redacts a JSON document, or plain text line by line. A JSON
+// document is only re-encoded when something was redacted.
+func (r *redactor) redactText(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		var document any
+		if err := decoder.Decode(&document); err == nil && !decoder.More() {
+			redacted, changed := r.redactJSON(document)
+			if !changed {
+				return text
+			}
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(redacted); err == nil {
+				return strings.TrimSuffix(buf.String(), "\n")
+			}
+		}
+	}
+
+	return r.redactString(text)
+}
+
+// redactJSON returns a redacted copy of a decoded JSON value and whether anything was redacted.
+func (r *redactor) redactJSON(value any) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		changed := false
+		switch kind, _ := v["kind"].(string); kind {
+		case "Secret":
+			changed = redactKubernetesSecret(v)
+		case "SecretList":
+			// The items of a list leave out their kind.
+			items, _ := v["items"].([]any)
+			for _, item := range items {
+				if secret, ok := item.(map[string]any); ok {
+					changed = redactKubernetesSecret(secret) || changed
+				}
+			}
+		}
+		// A kubeconfig is recognized by its users rather than by its kind, which is optional.
+		changed = redactKubeconfigUsers(v) || changed
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if text, ok := item.(string); ok && text != "" && text != auditRedacted && r.isSensitiveOutputKey(key) {
+				out[key] = auditRedacted
+				changed = true
+				continue
+			}
+			redacted, itemChanged := r.redactJSON(item)
+			out[key] = redacted
+			changed = changed || itemChanged
+		}
+		// Kubernetes environment variables and headers are {name|key, value} pairs.
+		for _, nameKey := range []string{"name", "key"} {
+			name, ok := v[nameKey].(string)
+			if !ok || !r.isSensitiveOutputKey(name) {
+				continue
+			}
+			if text, ok := v["value"].(string); ok && text != "" && text != auditRedacted {
+				out["value"] = auditRedacted
+				changed = true
+			}
+		}
+		return out, changed
+	case []any:
+		changed := false
+		out := make([]any, len(v))
+		for i, item := range v {
+			redacted, itemChanged := r.redactJSON(item)
+			out[i] = redacted
+			changed = changed || itemChanged
+		}
+		return out, changed
+	case string:
+		redacted := r.redactString(v)
+		return redacted, redacted != v
+	default:
+		return v, false
+	}
+}
+
+// redactKubernetesSecret replaces the values of a Kubernetes Secret, and the
+// copy kept in its last-applied-configuration annotation, in place.
+func redactKubernetesSecret(secret map[string]any) bool {
+	changed := false
+	for _, field := range []string{"data", "stringData"} {
+		data, ok := secret[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for key := range data {
+			data[key] = auditRedacted
+			changed = true
+		}
+	}
+	if metadata, ok := secret["metadata"].(map[string]any); ok {
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			if _, ok := annotations[lastAppliedConfigAnnotation]; ok {
+				annotations[lastAppliedConfigAnnotation] = auditRedacted
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactKubeconfigUsers replaces the credentials of the users of a kubeconfig
+// in place.
+func redactKubeconfigUsers(config map[string]any) bool {
+	changed := false
+	users, _ := config["users"].([]any)
+	for _, item := range users {
+		entry, _ := item.(map[string]any)
+		user, ok := entry["user"].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range kubeconfigCredentialFields {
+			if text, ok := user[field].(string); ok && text != "" && text != auditRedacted {
+				user[field] = auditRedacted
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactString redacts, line by line, the value of a KEY=value assignment
+// with a sensitive key, credentials embedded in URLs, and the matches of the
+// policy patterns.
+func (r *redactor) redactString(value string) string {
+	if !strings.Contains(value, "\n") {
+		return r.redactLine(value)
+	}
+	// Command output and logs hold one assignment per line.
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = r.redactLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redactLine applies the rules of redactString to a single line.
+func (r *redactor) redactLine(value string) string {
+	if match := envAssignment.FindStringSubmatch(value); match != nil && r.isSensitiveOutputKey(match[2]) && match[3] != auditRedacted {
+		value = match[1] + auditRedacted
+	}
+	value = urlCredentials.ReplaceAllString(value, "${1}"+auditRedacted+"@")
+	for _, pattern := range r.patterns {
+		value = pattern.ReplaceAllString(value, auditRedacted)
+	}
+	return value
+}
+
+// isSensitiveOutputKey reports whether the value of a key in a tool result is a
+// secret: its name contains a sensitive fragment and does not merely name or
+// locate a secret, it is a known credential key, or it matches a policy key.
+func (r *redactor) isSensitiveOutputKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if ok, _ := path.Match(pattern, lower); ok {
+			return true
+		}
+	}
+
+	normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(lower)
+	for _, known := range sensitiveOutputKeys {
+		if normalized == known {
+			return true
+		}
+	}
+	if !isSensitiveKey(key) {
+		return false
+	}
+	for _, suffix := range referenceKeySuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// withIncludeSecretsParameter returns a copy of a tool whose input schema accepts the includeSecrets argument.
+func withIncludeSecretsParameter(tool mcp.Tool) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	properties[includeSecretsParam] = map[string]any{
+		"type":        "boolean",
+		"description": includeSecretsParamDescription,
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
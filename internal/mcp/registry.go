@@ -25,9 +25,9 @@ func (s *PortainerMCPServer) AddRegistryFeatures() {
 // HandleListRegistries returns an MCP tool handler that lists registries.
 func (s *PortainerMCPServer) HandleListRegistries() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		registries, err := s.cli.GetRegistries()
+		registries, err := s.client(ctx).GetRegistries(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list registries", err), nil
+			return toolErrorFromErr("failed to list registries", err), nil
 		}
 
 		return jsonResult(registries, "failed to marshal registries")
@@ -41,15 +41,15 @@ func (s *PortainerMCPServer) HandleGetRegistry() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		registry, err := s.cli.GetRegistry(id)
+		registry, err := s.client(ctx).GetRegistry(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get registry", err), nil
+			return toolErrorFromErr("failed to get registry", err), nil
 		}
 
 		return jsonResult(registry, "failed to marshal registry")
@@ -63,12 +63,12 @@ func (s *PortainerMCPServer) HandleCreateRegistry() server.ToolHandlerFunc {
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 
 		registryType, err := parser.GetInt("type", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
+			return toolErrorFromErr("invalid type parameter", err), nil
 		}
 
 		if !isValidRegistryType(registryType) {
@@ -77,28 +77,28 @@ func (s *PortainerMCPServer) HandleCreateRegistry() server.ToolHandlerFunc {
 
 		url, err := parser.GetString("url", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid url parameter", err), nil
+			return toolErrorFromErr("invalid url parameter", err), nil
 		}
 
 		// Registry URLs like "docker.io" may not have a scheme; only validate if scheme is present
 		if strings.Contains(url, "://") {
 			if err := validateURL(url); err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid registry URL", err), nil
+				return toolErrorFromErr("invalid registry URL", err), nil
 			}
 		}
 
 		authentication, err := parser.GetBoolean("authentication", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid authentication parameter", err), nil
+			return toolErrorFromErr("invalid authentication parameter", err), nil
 		}
 
 		username, _ := parser.GetString("username", false)
 		password, _ := parser.GetString("password", false)
 		baseURL, _ := parser.GetString("baseURL", false)
 
-		id, err := s.cli.CreateRegistry(name, registryType, url, authentication, username, password, baseURL)
+		id, err := s.client(ctx).CreateRegistry(ctx, name, registryType, url, authentication, username, password, baseURL)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create registry", err), nil
+			return toolErrorFromErr("failed to create registry", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Registry created successfully with ID: %d", id)), nil
@@ -112,7 +112,7 @@ func (s *PortainerMCPServer) HandleUpdateRegistry() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -124,7 +124,7 @@ func (s *PortainerMCPServer) HandleUpdateRegistry() server.ToolHandlerFunc {
 		if _, ok := args["name"]; ok {
 			v, err := parser.GetString("name", false)
 			if err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+				return toolErrorFromErr("invalid name parameter", err), nil
 			}
 			name = &v
 		}
@@ -133,11 +133,11 @@ func (s *PortainerMCPServer) HandleUpdateRegistry() server.ToolHandlerFunc {
 		if _, ok := args["url"]; ok {
 			v, err := parser.GetString("url", false)
 			if err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid url parameter", err), nil
+				return toolErrorFromErr("invalid url parameter", err), nil
 			}
 			if strings.Contains(v, "://") {
 				if err := validateURL(v); err != nil {
-					return mcp.NewToolResultErrorFromErr("invalid registry URL", err), nil
+					return toolErrorFromErr("invalid registry URL", err), nil
 				}
 			}
 			url = &v
@@ -147,7 +147,7 @@ func (s *PortainerMCPServer) HandleUpdateRegistry() server.ToolHandlerFunc {
 		if _, ok := args["authentication"]; ok {
 			v, err := parser.GetBoolean("authentication", false)
 			if err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid authentication parameter", err), nil
+				return toolErrorFromErr("invalid authentication parameter", err), nil
 			}
 			authentication = &v
 		}
@@ -156,7 +156,7 @@ func (s *PortainerMCPServer) HandleUpdateRegistry() server.ToolHandlerFunc {
 		if _, ok := args["username"]; ok {
 			v, err := parser.GetString("username", false)
 			if err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid username parameter", err), nil
+				return toolErrorFromErr("invalid username parameter", err), nil
 			}
 			username = &v
 		}
@@ -165,7 +165,7 @@ func (s *PortainerMCPServer) HandleUpdateRegistry() server.ToolHandlerFunc {
 		if _, ok := args["password"]; ok {
 			v, err := parser.GetString("password", false)
 			if err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+				return toolErrorFromErr("invalid password parameter", err), nil
 			}
 			password = &v
 		}
@@ -174,14 +174,14 @@ func (s *PortainerMCPServer) HandleUpdateRegistry() server.ToolHandlerFunc {
 		if _, ok := args["baseURL"]; ok {
 			v, err := parser.GetString("baseURL", false)
 			if err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid baseURL parameter", err), nil
+				return toolErrorFromErr("invalid baseURL parameter", err), nil
 			}
 			baseURL = &v
 		}
 
-		err = s.cli.UpdateRegistry(id, name, url, authentication, username, password, baseURL)
+		err = s.client(ctx).UpdateRegistry(ctx, id, name, url, authentication, username, password, baseURL)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update registry", err), nil
+			return toolErrorFromErr("failed to update registry", err), nil
 		}
 
 		return mcp.NewToolResultText("Registry updated successfully"), nil
@@ -195,15 +195,15 @@ func (s *PortainerMCPServer) HandleDeleteRegistry() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteRegistry(id)
+		err = s.client(ctx).DeleteRegistry(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete registry", err), nil
+			return toolErrorFromErr("failed to delete registry", err), nil
 		}
 
 		return mcp.NewToolResultText("Registry deleted successfully"), nil
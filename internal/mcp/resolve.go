@@ -0,0 +1,325 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Kinds of Portainer objects that tool arguments can name.
+const (
+	kindEnvironment = "environment"
+	kindStack       = "stack"
+	kindEdgeStack   = "edge stack"
+	kindUser        = "user"
+	kindTeam        = "team"
+	kindRegistry    = "registry"
+)
+
+var (
+	// nameParamDescriptions document the arguments that take the name or the
+	// ID of a Portainer object.
+	nameParamDescriptions = map[string]string{
+		"environment":       "Name or ID of the environment",
+		"targetEnvironment": "Name or ID of the destination environment",
+		"stack":             "Name or ID of the stack",
+		"user":              "Username or ID of the user",
+		"team":              "Name or ID of the team",
+		"registry":          "Name or ID of the registry",
+	}
+
+	// userTools are the tools whose id argument is a user ID.
+	userTools = []string{ToolGetUser, ToolDeleteUser, ToolUpdateUserRole}
+
+	// teamTools are the tools whose id argument is a team ID.
+	teamTools = []string{ToolGetTeam, ToolDeleteTeam, ToolUpdateTeamName, ToolUpdateTeamMembers}
+
+	// registryTools are the tools whose id argument is a registry ID.
+	registryTools = []string{ToolGetRegistry, ToolUpdateRegistry, ToolDeleteRegistry}
+)
+
+// nameParam is a tool argument that takes the name or the ID of a Portainer
+// object, such as environment, and the ID argument it stands for, such as
+// environmentId.
+type nameParam struct {
+	name     string
+	idParam  string
+	kind     string
+	required bool
+}
+
+// nameParams returns the arguments of a tool that take a name or an ID. The
+// environment arguments come first, so that they are resolved before the
+// stacks they narrow down. An argument is left out when the tool already
+// defines one with the same name.
+func nameParams(toolName string, tool mcp.Tool) []nameParam {
+	var params []nameParam
+	add := func(name, idParam, kind string) {
+		if _, ok := tool.InputSchema.Properties[idParam]; !ok {
+			return
+		}
+		if _, exists := tool.InputSchema.Properties[name]; exists {
+			return
+		}
+		params = append(params, nameParam{
+			name:     name,
+			idParam:  idParam,
+			kind:     kind,
+			required: slices.Contains(tool.InputSchema.Required, idParam),
+		})
+	}
+
+	add("environment", "environmentId", kindEnvironment)
+	add("environment", "endpointId", kindEnvironment)
+	add("targetEnvironment", "targetEnvironmentId", kindEnvironment)
+	add("user", "userId", kindUser)
+
+	switch {
+	case slices.Contains(environmentTools, toolName):
+		add("environment", "id", kindEnvironment)
+	case slices.Contains(stackTools, toolName):
+		add("stack", "id", kindStack)
+	case slices.Contains(edgeStackTools, toolName):
+		add("stack", "id", kindEdgeStack)
+	case slices.Contains(userTools, toolName):
+		add("user", "id", kindUser)
+	case slices.Contains(teamTools, toolName):
+		add("team", "id", kindTeam)
+	case slices.Contains(registryTools, toolName):
+		add("registry", "id", kindRegistry)
+	}
+	return params
+}
+
+// withNameParameters returns a copy of a tool whose input schema accepts the
+// name arguments. The ID arguments they stand for are no longer required, as
+// either one may be given.
+func withNameParameters(tool mcp.Tool, params []nameParam) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	required := slices.Clone(tool.InputSchema.Required)
+	for _, p := range params {
+		properties[p.name] = map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("%s, instead of %s. A name matches exactly, or else case-insensitively", nameParamDescriptions[p.name], p.idParam),
+		}
+		required = slices.DeleteFunc(required, func(name string) bool { return name == p.idParam })
+	}
+	tool.InputSchema.Properties = properties
+	tool.InputSchema.Required = required
+	return tool
+}
+
+// nameToolOptions returns the options that declare the name arguments of the
+// actions of a meta-tool.
+func nameToolOptions(names []string) []mcp.ToolOption {
+	options := make([]mcp.ToolOption, 0, len(names))
+	for _, name := range names {
+		options = append(options, mcp.WithString(name, mcp.Description(nameParamDescriptions[name]+", instead of the numeric ID argument. Only used by the actions that take one")))
+	}
+	return options
+}
+
+// withNameResolution wraps the handler of a tool so that the name arguments
+// of a call are replaced by the ID arguments the handler reads. Names are
+// looked up with the identity of the caller, among the environments in scope.
+func (s *PortainerMCPServer) withNameResolution(params []nameParam, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments := request.GetArguments()
+		resolved := false
+		for _, p := range params {
+			value, ok := arguments[p.name]
+			if !ok || value == nil {
+				if _, ok := arguments[p.idParam]; !ok && p.required {
+					return mcp.NewToolResultError(fmt.Sprintf("%s or %s is required", p.idParam, p.name)), nil
+				}
+				continue
+			}
+			if _, ok := arguments[p.idParam]; ok {
+				return mcp.NewToolResultError(fmt.Sprintf("%s and %s cannot be used together", p.idParam, p.name)), nil
+			}
+
+			id, err := s.resolveName(ctx, p.kind, value, arguments)
+			if err != nil {
+				return toolErrorFromErr(fmt.Sprintf("failed to resolve %s", p.name), err), nil
+			}
+			if !resolved {
+				arguments = maps.Clone(arguments)
+				resolved = true
+			}
+			delete(arguments, p.name)
+			arguments[p.idParam] = float64(id)
+		}
+
+		if resolved {
+			request.Params.Arguments = arguments
+		}
+		return next(ctx, request)
+	}
+}
+
+// nameCandidate is a Portainer object that a name can refer to.
+type nameCandidate struct {
+	id            int
+	name          string
+	environmentID int
+}
+
+// String describes a candidate in the error of an ambiguous name.
+func (c nameCandidate) String() string {
+	if c.environmentID != 0 {
+		return fmt.Sprintf("%s (ID %d, environment %d)", c.name, c.id, c.environmentID)
+	}
+	return fmt.Sprintf("%s (ID %d)", c.name, c.id)
+}
+
+// resolveName returns the ID of the object of a kind that a value refers to.
+// A number or a string of digits is an ID. Any other string is a name, which
+// matches exactly or, when no name does, case-insensitively. The other
+// arguments of the call narrow down stacks to the environment they give.
+func (s *PortainerMCPServer) resolveName(ctx context.Context, kind string, value any, arguments map[string]any) (int, error) {
+	var name string
+	switch v := value.(type) {
+	case float64:
+		if v > math.MaxInt || math.Trunc(v) != v {
+			return 0, fmt.Errorf("%s ID must be a valid integer, got %v", kind, v)
+		}
+		if err := validatePositiveID(kind+" ID", int(v)); err != nil {
+			return 0, err
+		}
+		return int(v), nil
+	case string:
+		name = strings.TrimSpace(v)
+	default:
+		return 0, fmt.Errorf("%s must be a name or an ID, got %v", kind, value)
+	}
+	if name == "" {
+		return 0, fmt.Errorf("%s name cannot be empty", kind)
+	}
+	if id, err := strconv.Atoi(name); err == nil {
+		if err := validatePositiveID(kind+" ID", id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	candidates, err := s.nameCandidates(ctx, kind)
+	if err != nil {
+		return 0, err
+	}
+	if kind == kindStack {
+		if environmentID, ok := arguments["environmentId"].(float64); ok {
+			candidates = slices.DeleteFunc(candidates, func(c nameCandidate) bool { return c.environmentID != int(environmentID) })
+		}
+	}
+
+	matches := matchingCandidates(candidates, name, func(a, b string) bool { return a == b })
+	if len(matches) == 0 {
+		matches = matchingCandidates(candidates, name, strings.EqualFold)
+	}
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no %s is named %q", kind, name)
+	case 1:
+		return matches[0].id, nil
+	default:
+		described := make([]string, len(matches))
+		for i, c := range matches {
+			described[i] = c.String()
+		}
+		return 0, fmt.Errorf("%s name %q is ambiguous, it matches: %s. Use the ID of the one you mean", kind, name, strings.Join(described, ", "))
+	}
+}
+
+// matchingCandidates returns the candidates whose name matches.
+func matchingCandidates(candidates []nameCandidate, name string, equal func(a, b string) bool) []nameCandidate {
+	var matches []nameCandidate
+	for _, c := range candidates {
+		if equal(c.name, name) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// nameCandidates lists the objects of a kind that a name can refer to.
+// Environments out of scope and their stacks are left out.
+func (s *PortainerMCPServer) nameCandidates(ctx context.Context, kind string) ([]nameCandidate, error) {
+	cli := s.client(ctx)
+	var candidates []nameCandidate
+	switch kind {
+	case kindEnvironment:
+		environments, err := s.scopedEnvironments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, environment := range environments {
+			candidates = append(candidates, nameCandidate{id: environment.ID, name: environment.Name})
+		}
+	case kindStack:
+		stacks, err := cli.GetRegularStacks(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stacks: %w", err)
+		}
+		stacks, err = s.filterRegularStacks(ctx, stacks)
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range stacks {
+			candidates = append(candidates, nameCandidate{id: stack.ID, name: stack.Name, environmentID: stack.EndpointID})
+		}
+	case kindEdgeStack:
+		stacks, err := cli.GetStacks(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get edge stacks: %w", err)
+		}
+		stacks, err = s.filterEdgeStacks(ctx, stacks)
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range stacks {
+			candidates = append(candidates, nameCandidate{id: stack.ID, name: stack.Name})
+		}
+	case kindUser:
+		users, err := cli.GetUsers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get users: %w", err)
+		}
+		for _, user := range users {
+			candidates = append(candidates, nameCandidate{id: user.ID, name: user.Username})
+		}
+	case kindTeam:
+		teams, err := cli.GetTeams(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get teams: %w", err)
+		}
+		for _, team := range teams {
+			candidates = append(candidates, nameCandidate{id: team.ID, name: team.Name})
+		}
+	case kindRegistry:
+		registries, err := cli.GetRegistries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get registries: %w", err)
+		}
+		for _, registry := range registries {
+			candidates = append(candidates, nameCandidate{id: registry.ID, name: registry.Name})
+		}
+	}
+	return candidates, nil
+}
+
+// scopedEnvironments returns the environments in scope.
+func (s *PortainerMCPServer) scopedEnvironments(ctx context.Context) ([]models.Environment, error) {
+	environments, err := s.client(ctx).GetEnvironments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environments: %w", err)
+	}
+	return s.filterEnvironments(ctx, environments)
+}
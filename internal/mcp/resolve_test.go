@@ -0,0 +1,304 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNameParams verifies which arguments of the tools of tools.yaml take a name or an ID.
+func TestNameParams(t *testing.T) {
+	tools, err := toolgen.LoadToolsFromYAML("../../tools.yaml", MinimumToolsVersion)
+	require.NoError(t, err)
+
+	tests := []struct {
+		tool     string
+		expected []nameParam
+	}{
+		{tool: ToolGetEnvironment, expected: []nameParam{{name: "environment", idParam: "id", kind: kindEnvironment, required: true}}},
+		{tool: ToolListEnvironments},
+		{tool: ToolAddEnvironmentToAccessGroup, expected: []nameParam{{name: "environment", idParam: "environmentId", kind: kindEnvironment, required: true}}},
+		{tool: ToolMigrateStack, expected: []nameParam{
+			{name: "environment", idParam: "environmentId", kind: kindEnvironment, required: true},
+			{name: "targetEnvironment", idParam: "targetEnvironmentId", kind: kindEnvironment, required: true},
+			{name: "stack", idParam: "id", kind: kindStack, required: true},
+		}},
+		{tool: ToolGetStackFile, expected: []nameParam{{name: "stack", idParam: "id", kind: kindEdgeStack, required: true}}},
+		{tool: ToolCreateWebhook, expected: []nameParam{{name: "environment", idParam: "endpointId", kind: kindEnvironment, required: true}}},
+		{tool: ToolListHelmRepositories, expected: []nameParam{{name: "user", idParam: "userId", kind: kindUser, required: true}}},
+		{tool: ToolUpdateUserRole, expected: []nameParam{{name: "user", idParam: "id", kind: kindUser, required: true}}},
+		{tool: ToolUpdateTeamMembers, expected: []nameParam{{name: "team", idParam: "id", kind: kindTeam, required: true}}},
+		{tool: ToolDeleteRegistry, expected: []nameParam{{name: "registry", idParam: "id", kind: kindRegistry, required: true}}},
+		// The user argument of execDockerContainer is the user running the command.
+		{tool: ToolExecDockerContainer, expected: []nameParam{{name: "environment", idParam: "environmentId", kind: kindEnvironment, required: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			tool, ok := tools[tt.tool]
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, nameParams(tt.tool, tool))
+		})
+	}
+}
+
+// TestWithNameParameters verifies that the name arguments are added to the
+// input schema, and that the ID arguments they stand for are no longer required.
+func TestWithNameParameters(t *testing.T) {
+	tool := mcp.NewTool(ToolDeleteStack,
+		mcp.WithNumber("id", mcp.Required()),
+		mcp.WithNumber("environmentId", mcp.Required()),
+		mcp.WithBoolean("removeVolumes", mcp.Required()),
+	)
+
+	updated := withNameParameters(tool, nameParams(ToolDeleteStack, tool))
+
+	assert.Contains(t, updated.InputSchema.Properties, "stack")
+	assert.Contains(t, updated.InputSchema.Properties, "environment")
+	assert.Contains(t, updated.InputSchema.Properties["environment"].(map[string]any)["description"], "instead of environmentId")
+	assert.Equal(t, []string{"removeVolumes"}, updated.InputSchema.Required)
+	assert.NotContains(t, tool.InputSchema.Properties, "stack", "the tool definition must not be modified")
+	assert.Equal(t, []string{"id", "environmentId", "removeVolumes"}, tool.InputSchema.Required)
+}
+
+// TestResolveName verifies how IDs and names are resolved, and the errors of
+// unknown and ambiguous names.
+func TestResolveName(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          string
+		value         any
+		arguments     map[string]any
+		setupMock     func(m *MockPortainerClient)
+		expectedID    int
+		expectedError string
+	}{
+		{name: "numeric ID", kind: kindEnvironment, value: float64(3), expectedID: 3},
+		{name: "ID as a string", kind: kindTeam, value: " 7 ", expectedID: 7},
+		{name: "invalid ID", kind: kindEnvironment, value: float64(-1), expectedError: "environment ID must be a positive integer, got -1"},
+		{name: "fractional ID", kind: kindUser, value: 1.5, expectedError: "user ID must be a valid integer"},
+		{name: "empty name", kind: kindTeam, value: "  ", expectedError: "team name cannot be empty"},
+		{name: "invalid type", kind: kindTeam, value: true, expectedError: "team must be a name or an ID"},
+		{
+			name:  "exact environment name",
+			kind:  kindEnvironment,
+			value: "production",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local"}, {ID: 2, Name: "production"}}, nil)
+			},
+			expectedID: 2,
+		},
+		{
+			name:  "case-insensitive user name",
+			kind:  kindUser,
+			value: "Alice",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetUsers").Return([]models.User{{ID: 1, Username: "admin"}, {ID: 4, Username: "alice"}}, nil)
+			},
+			expectedID: 4,
+		},
+		{
+			name:  "exact match preferred",
+			kind:  kindTeam,
+			value: "Ops",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetTeams").Return([]models.Team{{ID: 1, Name: "ops"}, {ID: 2, Name: "Ops"}}, nil)
+			},
+			expectedID: 2,
+		},
+		{
+			name:  "ambiguous case-insensitive match",
+			kind:  kindTeam,
+			value: "OPS",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetTeams").Return([]models.Team{{ID: 1, Name: "ops"}, {ID: 2, Name: "Ops"}}, nil)
+			},
+			expectedError: `team name "OPS" is ambiguous, it matches: ops (ID 1), Ops (ID 2). Use the ID of the one you mean`,
+		},
+		{
+			name:  "unknown registry",
+			kind:  kindRegistry,
+			value: "quay",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetRegistries").Return([]models.Registry{{ID: 1, Name: "dockerhub"}}, nil)
+			},
+			expectedError: `no registry is named "quay"`,
+		},
+		{
+			name:  "edge stack",
+			kind:  kindEdgeStack,
+			value: "monitoring",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetStacks").Return([]models.Stack{{ID: 9, Name: "monitoring"}}, nil)
+			},
+			expectedID: 9,
+		},
+		{
+			name:  "stack on several environments",
+			kind:  kindStack,
+			value: "shop",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetRegularStacks").Return([]models.RegularStack{{ID: 5, Name: "shop", EndpointID: 1}, {ID: 8, Name: "shop", EndpointID: 2}}, nil)
+			},
+			expectedError: `stack name "shop" is ambiguous, it matches: shop (ID 5, environment 1), shop (ID 8, environment 2)`,
+		},
+		{
+			name:      "stack narrowed by environment",
+			kind:      kindStack,
+			value:     "shop",
+			arguments: map[string]any{"environmentId": float64(2)},
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetRegularStacks").Return([]models.RegularStack{{ID: 5, Name: "shop", EndpointID: 1}, {ID: 8, Name: "shop", EndpointID: 2}}, nil)
+			},
+			expectedID: 8,
+		},
+		{
+			name:  "failed to list",
+			kind:  kindUser,
+			value: "alice",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetUsers").Return(nil, errors.New("connection refused"))
+			},
+			expectedError: "failed to get users: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+			s := &PortainerMCPServer{cli: mockClient}
+
+			id, err := s.resolveName(context.Background(), tt.kind, tt.value, tt.arguments)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, id)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestResolveNameScope verifies that environments out of scope and their
+// stacks cannot be named.
+func TestResolveNameScope(t *testing.T) {
+	mockClient := &MockPortainerClient{}
+	mockClient.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local"}, {ID: 2, Name: "production"}}, nil)
+	mockClient.On("GetRegularStacks").Return([]models.RegularStack{{ID: 5, Name: "shop", EndpointID: 1}, {ID: 8, Name: "shop", EndpointID: 2}}, nil)
+	s := &PortainerMCPServer{cli: mockClient, policy: &policy{Environments: environmentScope{IDs: []int{1}}}}
+
+	_, err := s.resolveName(context.Background(), kindEnvironment, "production", nil)
+	assert.EqualError(t, err, `no environment is named "production"`)
+
+	id, err := s.resolveName(context.Background(), kindStack, "shop", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, id)
+}
+
+// TestWithNameResolution verifies that name arguments are replaced by the ID
+// arguments before the handler runs.
+func TestWithNameResolution(t *testing.T) {
+	params := []nameParam{
+		{name: "environment", idParam: "environmentId", kind: kindEnvironment, required: true},
+		{name: "stack", idParam: "id", kind: kindStack, required: true},
+	}
+
+	tests := []struct {
+		name              string
+		arguments         map[string]any
+		expectedArguments map[string]any
+		expectedError     string
+	}{
+		{
+			name:              "names",
+			arguments:         map[string]any{"environment": "Production", "stack": "shop", "pullImage": true},
+			expectedArguments: map[string]any{"environmentId": float64(2), "id": float64(8), "pullImage": true},
+		},
+		{
+			name:              "IDs",
+			arguments:         map[string]any{"environmentId": float64(1), "id": float64(5)},
+			expectedArguments: map[string]any{"environmentId": float64(1), "id": float64(5)},
+		},
+		{
+			name:          "both name and ID",
+			arguments:     map[string]any{"environment": "local", "environmentId": float64(1), "id": float64(5)},
+			expectedError: "environmentId and environment cannot be used together",
+		},
+		{
+			name:          "missing required argument",
+			arguments:     map[string]any{"environment": "local"},
+			expectedError: "id or stack is required",
+		},
+		{
+			name:          "unknown name",
+			arguments:     map[string]any{"environment": "staging", "id": float64(5)},
+			expectedError: `failed to resolve environment: no environment is named "staging"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockPortainerClient{}
+			mockClient.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local"}, {ID: 2, Name: "production"}}, nil)
+			mockClient.On("GetRegularStacks").Return([]models.RegularStack{{ID: 5, Name: "shop", EndpointID: 1}, {ID: 8, Name: "shop", EndpointID: 2}}, nil)
+			s := &PortainerMCPServer{cli: mockClient}
+
+			var received map[string]any
+			handler := s.withNameResolution(params, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				received = request.GetArguments()
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			result, err := handler(context.Background(), CreateMCPRequest(tt.arguments))
+
+			require.NoError(t, err)
+			if tt.expectedError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, resultText(result), tt.expectedError)
+				assert.Nil(t, received)
+				return
+			}
+			assert.False(t, result.IsError)
+			assert.Equal(t, tt.expectedArguments, received)
+		})
+	}
+}
+
+// TestNameArgumentsRegistration verifies that granular tools and meta-tools
+// declare the name arguments of their actions, and that a meta-tool action
+// resolves them.
+func TestNameArgumentsRegistration(t *testing.T) {
+	tools, err := toolgen.LoadToolsFromYAML("../../tools.yaml", MinimumToolsVersion)
+	require.NoError(t, err)
+
+	s := newTestMetaServer(false)
+	s.tools = tools
+	s.addToolIfExists(ToolGetEnvironment, s.HandleGetEnvironment())
+	s.RegisterMetaTools()
+
+	properties := listToolProperties(t, s.srv)
+	assert.Contains(t, properties[ToolGetEnvironment], "environment")
+	for _, name := range []string{"environment", "targetEnvironment", "stack"} {
+		assert.Contains(t, properties["manage_stacks"], name)
+	}
+	assert.Contains(t, properties["manage_users"], "user")
+	assert.Contains(t, properties["manage_teams"], "team")
+	assert.Contains(t, properties["manage_registries"], "registry")
+	assert.NotContains(t, properties["manage_settings"], "environment")
+
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetTeams").Return([]models.Team{{ID: 3, Name: "developers"}}, nil)
+	mockClient.On("GetTeam", 3).Return(models.Team{ID: 3, Name: "developers"}, nil)
+	callMetaTool(t, s, "manage_teams", `{"action":"get_team","team":"Developers"}`)
+	mockClient.AssertCalled(t, "GetTeam", 3)
+}
@@ -0,0 +1,328 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// resourceScheme is the scheme of the URIs of the Portainer resources.
+	resourceScheme = "portainer://"
+
+	mimeTypeJSON = "application/json"
+	mimeTypeYAML = "application/yaml"
+	mimeTypeText = "text/plain"
+)
+
+// resourceDef describes an MCP resource, or a resource template when its URI
+// contains the {id} variable. The resource is only registered when the policy
+// allows tool, the tool that returns the same data. read returns the content
+// of the resource: a string is returned as is with mimeType, anything else
+// as JSON.
+type resourceDef struct {
+	uri         string
+	name        string
+	description string
+	mimeType    string
+	tool        string
+	read        func(s *PortainerMCPServer, ctx context.Context, id int) (any, error)
+}
+
+// resourceDefinitions returns the Portainer objects exposed as resources.
+func resourceDefinitions() []resourceDef {
+	return []resourceDef{
+		{
+			uri:         resourceScheme + "environments",
+			name:        "Environments",
+			description: "All Portainer environments (endpoints) with their type, status, tags, and accesses",
+			mimeType:    mimeTypeJSON,
+			tool:        ToolListEnvironments,
+			read: func(s *PortainerMCPServer, ctx context.Context, _ int) (any, error) {
+				environments, err := s.client(ctx).GetEnvironments(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get environments: %w", err)
+				}
+				return s.filterEnvironments(ctx, environments)
+			},
+		},
+		{
+			uri:         resourceScheme + "environments/{id}",
+			name:        "Environment",
+			description: "A Portainer environment (endpoint) by ID",
+			mimeType:    mimeTypeJSON,
+			tool:        ToolGetEnvironment,
+			read: func(s *PortainerMCPServer, ctx context.Context, id int) (any, error) {
+				if err := s.checkEnvironmentScope(ctx, id); err != nil {
+					return nil, err
+				}
+				environment, err := s.client(ctx).GetEnvironment(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get environment: %w", err)
+				}
+				return environment, nil
+			},
+		},
+		{
+			uri:         resourceScheme + "environments/{id}/kubeconfig",
+			name:        "Environment kubeconfig",
+			description: "The kubeconfig of a Kubernetes environment by ID, with its credentials redacted",
+			mimeType:    mimeTypeJSON,
+			tool:        ToolGetKubernetesConfig,
+			read: func(s *PortainerMCPServer, ctx context.Context, id int) (any, error) {
+				if err := s.checkEnvironmentScope(ctx, id); err != nil {
+					return nil, err
+				}
+				config, err := s.client(ctx).GetKubernetesConfig(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
+				}
+				return config, nil
+			},
+		},
+		{
+			uri:         resourceScheme + "stacks",
+			name:        "Stacks",
+			description: "All regular (non-edge) Docker Compose and Swarm stacks",
+			mimeType:    mimeTypeJSON,
+			tool:        ToolListRegularStacks,
+			read: func(s *PortainerMCPServer, ctx context.Context, _ int) (any, error) {
+				stacks, err := s.client(ctx).GetRegularStacks(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get stacks: %w", err)
+				}
+				return s.filterRegularStacks(ctx, stacks)
+			},
+		},
+		{
+			uri:         resourceScheme + "stacks/{id}",
+			name:        "Stack",
+			description: "A regular (non-edge) stack by ID",
+			mimeType:    mimeTypeJSON,
+			tool:        ToolGetStack,
+			read: func(s *PortainerMCPServer, ctx context.Context, id int) (any, error) {
+				stack, err := s.client(ctx).InspectStack(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("failed to inspect stack: %w", err)
+				}
+				if err := s.checkEnvironmentScope(ctx, stack.EndpointID); err != nil {
+					return nil, err
+				}
+				return stack, nil
+			},
+		},
+		{
+			uri:         resourceScheme + "stacks/{id}/file",
+			name:        "Stack file",
+			description: "The compose file of a regular (non-edge) stack by ID",
+			mimeType:    mimeTypeYAML,
+			tool:        ToolInspectStackFile,
+			read: func(s *PortainerMCPServer, ctx context.Context, id int) (any, error) {
+				if err := s.checkStackScope(ctx, id); err != nil {
+					return nil, err
+				}
+				content, err := s.client(ctx).InspectStackFile(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("failed to inspect stack file: %w", err)
+				}
+				return content, nil
+			},
+		},
+		{
+			uri:         resourceScheme + "edge-stacks/{id}/file",
+			name:        "Edge stack file",
+			description: "The compose file of an edge stack by ID",
+			mimeType:    mimeTypeYAML,
+			tool:        ToolGetStackFile,
+			read: func(s *PortainerMCPServer, ctx context.Context, id int) (any, error) {
+				if err := s.checkEdgeStackScope(ctx, id); err != nil {
+					return nil, err
+				}
+				content, err := s.client(ctx).GetStackFile(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get stack file: %w", err)
+				}
+				return content, nil
+			},
+		},
+		{
+			uri:         resourceScheme + "custom-templates/{id}/file",
+			name:        "Custom template file",
+			description: "The compose file or Kubernetes manifest of a custom template by ID",
+			mimeType:    mimeTypeYAML,
+			tool:        ToolGetCustomTemplateFile,
+			read: func(s *PortainerMCPServer, ctx context.Context, id int) (any, error) {
+				content, err := s.client(ctx).GetCustomTemplateFile(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get custom template file: %w", err)
+				}
+				return content, nil
+			},
+		},
+		{
+			uri:         resourceScheme + "edge-jobs/{id}/file",
+			name:        "Edge job script",
+			description: "The script run by an edge job, by ID",
+			mimeType:    mimeTypeText,
+			tool:        ToolGetEdgeJobFile,
+			read: func(s *PortainerMCPServer, ctx context.Context, id int) (any, error) {
+				content, err := s.client(ctx).GetEdgeJobFile(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get edge job file: %w", err)
+				}
+				return content, nil
+			},
+		},
+	}
+}
+
+// RegisterResources registers the Portainer objects as MCP resources and
+// resource templates, so that clients can attach them as context without a
+// tool call. A resource is skipped when the policy denies the tool that
+// returns the same data. Resources are read-only, so they are registered in
+// read-only mode too.
+func (s *PortainerMCPServer) RegisterResources() {
+	for _, def := range resourceDefinitions() {
+		if !s.policy.allowsTool(def.tool) {
+			log.Debug().Str("resource", def.uri).Str("tool", def.tool).Msg("Resource denied by policy, will not be registered")
+			continue
+		}
+
+		if strings.Contains(def.uri, "{id}") {
+			template := mcp.NewResourceTemplate(def.uri, def.name,
+				mcp.WithTemplateDescription(def.description),
+				mcp.WithTemplateMIMEType(def.mimeType),
+			)
+			s.srv.AddResourceTemplate(template, s.resourceHandler(def))
+		} else {
+			resource := mcp.NewResource(def.uri, def.name,
+				mcp.WithResourceDescription(def.description),
+				mcp.WithMIMEType(def.mimeType),
+			)
+			s.srv.AddResource(resource, s.resourceHandler(def))
+		}
+	}
+}
+
+// resourceHandler returns the handler reading a resource. The {id} of a
+// template URI must be a positive integer, and secrets are redacted from the
+// content as they are from tool results.
+func (s *PortainerMCPServer) resourceHandler(def resourceDef) func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		var id int
+		if strings.Contains(def.uri, "{id}") {
+			var err error
+			id, err = resourceID(request)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		value, err := def.read(s, ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		text, mimeType := "", def.mimeType
+		if content, ok := value.(string); ok {
+			text = content
+		} else {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal resource: %w", err)
+			}
+			text, mimeType = string(data), mimeTypeJSON
+		}
+		if s.redactor != nil {
+			text = s.redactor.redactText(text)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: request.Params.URI, MIMEType: mimeType, Text: text},
+		}, nil
+	}
+}
+
+// resourceID returns the {id} variable of a resource template URI.
+func resourceID(request mcp.ReadResourceRequest) (int, error) {
+	var value string
+	switch v := request.Params.Arguments["id"].(type) {
+	case string:
+		value = v
+	case []string:
+		if len(v) == 1 {
+			value = v[0]
+		}
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resource URI %s: the id must be an integer", request.Params.URI)
+	}
+	if err := validatePositiveID("id", id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// checkEnvironmentScope returns an error when the server is restricted to a
+// subset of environments that does not include the environment.
+func (s *PortainerMCPServer) checkEnvironmentScope(ctx context.Context, id int) error {
+	if !s.policy.scoped() {
+		return nil
+	}
+	inScope, err := s.newScopeResolver(ctx).contains(models.Environment{ID: id})
+	if err != nil {
+		return fmt.Errorf("failed to check environment scope: %w", err)
+	}
+	if !inScope {
+		return fmt.Errorf("environment %d is out of scope: this server is restricted to a subset of environments", id)
+	}
+	return nil
+}
+
+// checkStackScope returns an error when the server is restricted to a subset
+// of environments that does not include the environment a regular stack is
+// deployed to.
+func (s *PortainerMCPServer) checkStackScope(ctx context.Context, id int) error {
+	if !s.policy.scoped() {
+		return nil
+	}
+	stack, err := s.client(ctx).InspectStack(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to inspect stack: %w", err)
+	}
+	return s.checkEnvironmentScope(ctx, stack.EndpointID)
+}
+
+// checkEdgeStackScope returns an error when the server is restricted to a
+// subset of environments and an environment group of an edge stack holds an
+// environment outside of it.
+func (s *PortainerMCPServer) checkEdgeStackScope(ctx context.Context, id int) error {
+	if !s.policy.scoped() {
+		return nil
+	}
+	stacks, err := s.client(ctx).GetStacks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get edge stacks: %w", err)
+	}
+	for _, stack := range stacks {
+		if stack.ID != id {
+			continue
+		}
+		inScope, err := s.newScopeResolver(ctx).containsGroups(stack.EnvironmentGroupIds)
+		if err != nil {
+			return fmt.Errorf("failed to check environment scope: %w", err)
+		}
+		if !inScope {
+			return fmt.Errorf("edge stack %d is out of scope: it is deployed to environments outside of the subset this server is restricted to", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestResourceServer returns a test server with resource capabilities.
+func newTestResourceServer() *PortainerMCPServer {
+	s := newTestMetaServer(false)
+	s.srv = server.NewMCPServer("test-resource-server", "0.0.1", server.WithResourceCapabilities(false, false))
+	return s
+}
+
+// readResource sends a resources/read request and returns its contents, or
+// the message of the JSON-RPC error.
+func readResource(t *testing.T, s *PortainerMCPServer, uri string) ([]mcp.TextResourceContents, string) {
+	t.Helper()
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/read",
+		"params":  map[string]any{"uri": uri},
+	})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(s.srv.HandleMessage(context.Background(), raw))
+	require.NoError(t, err)
+	var resp struct {
+		Result struct {
+			Contents []mcp.TextResourceContents `json:"contents"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(data, &resp))
+	if resp.Error != nil {
+		return nil, resp.Error.Message
+	}
+	return resp.Result.Contents, ""
+}
+
+// listResourceURIs returns the URIs of the registered resources and the URI
+// templates of the registered resource templates.
+func listResourceURIs(t *testing.T, s *PortainerMCPServer) ([]string, []string) {
+	t.Helper()
+	list := func(method string, result any) {
+		data, err := json.Marshal(s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"`+method+`"}`)))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, result))
+	}
+
+	var resources struct {
+		Result struct {
+			Resources []struct {
+				URI string `json:"uri"`
+			} `json:"resources"`
+		} `json:"result"`
+	}
+	list("resources/list", &resources)
+	var templates struct {
+		Result struct {
+			ResourceTemplates []struct {
+				URITemplate string `json:"uriTemplate"`
+			} `json:"resourceTemplates"`
+		} `json:"result"`
+	}
+	list("resources/templates/list", &templates)
+
+	var uris, uriTemplates []string
+	for _, r := range resources.Result.Resources {
+		uris = append(uris, r.URI)
+	}
+	for _, r := range templates.Result.ResourceTemplates {
+		uriTemplates = append(uriTemplates, r.URITemplate)
+	}
+	return uris, uriTemplates
+}
+
+// TestRegisterResources verifies which resources and templates are registered.
+func TestRegisterResources(t *testing.T) {
+	s := newTestResourceServer()
+	s.RegisterResources()
+
+	uris, templates := listResourceURIs(t, s)
+	assert.ElementsMatch(t, []string{"portainer://environments", "portainer://stacks"}, uris)
+	assert.ElementsMatch(t, []string{
+		"portainer://environments/{id}",
+		"portainer://environments/{id}/kubeconfig",
+		"portainer://stacks/{id}",
+		"portainer://stacks/{id}/file",
+		"portainer://edge-stacks/{id}/file",
+		"portainer://custom-templates/{id}/file",
+		"portainer://edge-jobs/{id}/file",
+	}, templates)
+
+	s = newTestResourceServer()
+	var err error
+	s.policy, err = loadPolicy(writePolicyFile(t, "tools:\n  deny: [getKubernetesConfig, manage_stacks.*]\n"))
+	require.NoError(t, err)
+	s.RegisterResources()
+
+	uris, templates = listResourceURIs(t, s)
+	assert.Equal(t, []string{"portainer://environments"}, uris)
+	assert.ElementsMatch(t, []string{
+		"portainer://environments/{id}",
+		"portainer://custom-templates/{id}/file",
+		"portainer://edge-jobs/{id}/file",
+	}, templates)
+}
+
+// TestReadResource verifies the content of each resource.
+func TestReadResource(t *testing.T) {
+	tests := []struct {
+		name             string
+		uri              string
+		setupMock        func(m *MockPortainerClient)
+		expectedMIMEType string
+		expectedText     string
+	}{
+		{
+			name: "environments",
+			uri:  "portainer://environments",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local"}}, nil)
+			},
+			expectedMIMEType: mimeTypeJSON,
+			expectedText:     `[{"id":1,"name":"local","status":"","type":"","tag_ids":null,"user_accesses":null,"team_accesses":null}]`,
+		},
+		{
+			name: "environment",
+			uri:  "portainer://environments/7",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetEnvironment", 7).Return(models.Environment{ID: 7, Name: "production"}, nil)
+			},
+			expectedMIMEType: mimeTypeJSON,
+			expectedText:     `{"id":7,"name":"production","status":"","type":"","tag_ids":null,"user_accesses":null,"team_accesses":null}`,
+		},
+		{
+			name: "kubeconfig is redacted",
+			uri:  "portainer://environments/7/kubeconfig",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetKubernetesConfig", 7).Return(map[string]any{"kind": "Config", "users": []any{
+					map[string]any{"name": "admin", "user": map[string]any{"token": "eyJhbGciOi"}},
+					map[string]any{"name": "portainer", "user": map[string]any{"client-key-data": "LS0tS0VZ", "client-certificate-data": "LS0tQ0VSVA=="}},
+				}}, nil)
+			},
+			expectedMIMEType: mimeTypeJSON,
+			expectedText:     `{"kind":"Config","users":[{"name":"admin","user":{"token":"[REDACTED]"}},{"name":"portainer","user":{"client-certificate-data":"[REDACTED]","client-key-data":"[REDACTED]"}}]}`,
+		},
+		{
+			name: "stack file",
+			uri:  "portainer://stacks/3/file",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("InspectStackFile", 3).Return("services:\n  web:\n    image: nginx\n", nil)
+			},
+			expectedMIMEType: mimeTypeYAML,
+			expectedText:     "services:\n  web:\n    image: nginx\n",
+		},
+		{
+			name: "edge stack file",
+			uri:  "portainer://edge-stacks/5/file",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetStackFile", 5).Return("services: {}\n", nil)
+			},
+			expectedMIMEType: mimeTypeYAML,
+			expectedText:     "services: {}\n",
+		},
+		{
+			name: "custom template file",
+			uri:  "portainer://custom-templates/2/file",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetCustomTemplateFile", 2).Return("apiVersion: v1\nkind: Namespace\n", nil)
+			},
+			expectedMIMEType: mimeTypeYAML,
+			expectedText:     "apiVersion: v1\nkind: Namespace\n",
+		},
+		{
+			name: "edge job script",
+			uri:  "portainer://edge-jobs/4/file",
+			setupMock: func(m *MockPortainerClient) {
+				m.On("GetEdgeJobFile", 4).Return("#!/bin/sh\ndocker system prune -f\n", nil)
+			},
+			expectedMIMEType: mimeTypeText,
+			expectedText:     "#!/bin/sh\ndocker system prune -f\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestResourceServer()
+			redactor, err := newRedactor(nil)
+			require.NoError(t, err)
+			s.redactor = redactor
+			mockClient := s.cli.(*MockPortainerClient)
+			tt.setupMock(mockClient)
+			s.RegisterResources()
+
+			contents, errMsg := readResource(t, s, tt.uri)
+
+			require.Empty(t, errMsg)
+			require.Len(t, contents, 1)
+			assert.Equal(t, tt.uri, contents[0].URI)
+			assert.Equal(t, tt.expectedMIMEType, contents[0].MIMEType)
+			assert.Equal(t, tt.expectedText, contents[0].Text)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestReadResourceErrors verifies that invalid IDs, Portainer errors, and
+// environments outside of the scope are reported as errors.
+func TestReadResourceErrors(t *testing.T) {
+	s := newTestResourceServer()
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("InspectStack", 9).Return(models.RegularStack{}, errors.New("stack not found"))
+	s.policy = &policy{Environments: environmentScope{IDs: []int{7}}}
+	s.RegisterResources()
+
+	_, errMsg := readResource(t, s, "portainer://stacks/abc")
+	assert.Contains(t, errMsg, "the id must be an integer")
+
+	_, errMsg = readResource(t, s, "portainer://stacks/0")
+	assert.Contains(t, errMsg, "id must be a positive integer")
+
+	_, errMsg = readResource(t, s, "portainer://stacks/9")
+	assert.Contains(t, errMsg, "failed to inspect stack: stack not found")
+
+	_, errMsg = readResource(t, s, "portainer://environments/8/kubeconfig")
+	assert.Contains(t, errMsg, "environment 8 is out of scope")
+
+	_, errMsg = readResource(t, s, "portainer://registries/1")
+	assert.Contains(t, errMsg, "resource not found")
+
+	mockClient.AssertNotCalled(t, "GetKubernetesConfig", 8)
+}
+
+// TestReadStackResourcesScope verifies that the stack resources leave out or
+// reject the stacks deployed to environments outside of the scope.
+func TestReadStackResourcesScope(t *testing.T) {
+	s := newTestResourceServer()
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetRegularStacks").Return([]models.RegularStack{
+		{ID: 1, Name: "web", EndpointID: 7},
+		{ID: 2, Name: "db", EndpointID: 8},
+	}, nil)
+	mockClient.On("InspectStack", 1).Return(models.RegularStack{ID: 1, Name: "web", EndpointID: 7}, nil)
+	mockClient.On("InspectStack", 2).Return(models.RegularStack{ID: 2, Name: "db", EndpointID: 8}, nil)
+	mockClient.On("InspectStackFile", 1).Return("services: {}", nil)
+	mockClient.On("GetStacks").Return([]models.Stack{{ID: 3, Name: "agents", EnvironmentGroupIds: []int{5}}}, nil)
+	mockClient.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, Name: "all", EnvironmentIds: []int{7, 8}}}, nil)
+	s.policy = &policy{Environments: environmentScope{IDs: []int{7}}}
+	s.RegisterResources()
+
+	contents, errMsg := readResource(t, s, "portainer://stacks")
+	require.Empty(t, errMsg)
+	require.Len(t, contents, 1)
+	var stacks []models.RegularStack
+	require.NoError(t, json.Unmarshal([]byte(contents[0].Text), &stacks))
+	assert.Equal(t, []models.RegularStack{{ID: 1, Name: "web", EndpointID: 7}}, stacks)
+
+	_, errMsg = readResource(t, s, "portainer://stacks/1/file")
+	assert.Empty(t, errMsg)
+
+	_, errMsg = readResource(t, s, "portainer://stacks/2")
+	assert.Contains(t, errMsg, "environment 8 is out of scope")
+
+	_, errMsg = readResource(t, s, "portainer://stacks/2/file")
+	assert.Contains(t, errMsg, "environment 8 is out of scope")
+
+	_, errMsg = readResource(t, s, "portainer://edge-stacks/3/file")
+	assert.Contains(t, errMsg, "edge stack 3 is out of scope")
+
+	mockClient.AssertNotCalled(t, "InspectStackFile", 2)
+	mockClient.AssertNotCalled(t, "GetStackFile", 3)
+}
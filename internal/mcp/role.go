@@ -15,9 +15,9 @@ func (s *PortainerMCPServer) AddRoleFeatures() {
 // HandleListRoles returns an MCP tool handler that lists roles.
 func (s *PortainerMCPServer) HandleListRoles() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		roles, err := s.cli.GetRoles()
+		roles, err := s.client(ctx).GetRoles(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list roles", err), nil
+			return toolErrorFromErr("failed to list roles", err), nil
 		}
 
 		return jsonResult(roles, "failed to marshal roles")
@@ -60,11 +60,23 @@ const (
 	ToolUpdateEnvironmentGroupTags         = "updateEnvironmentGroupTags"
 	ToolDockerProxy                        = "dockerProxy"
 	ToolGetDockerDashboard                 = "getDockerDashboard"
+	ToolListDockerContainers               = "listDockerContainers"
+	ToolInspectDockerContainer             = "inspectDockerContainer"
+	ToolStartDockerContainer               = "startDockerContainer"
+	ToolStopDockerContainer                = "stopDockerContainer"
+	ToolRestartDockerContainer             = "restartDockerContainer"
+	ToolKillDockerContainer                = "killDockerContainer"
+	ToolRemoveDockerContainer              = "removeDockerContainer"
+	ToolRenameDockerContainer              = "renameDockerContainer"
+	ToolGetDockerContainerLogs             = "getDockerContainerLogs"
+	ToolExecDockerContainer                = "execDockerContainer"
 	ToolKubernetesProxy                    = "kubernetesProxy"
 	ToolKubernetesProxyStripped            = "getKubernetesResourceStripped"
 	ToolGetKubernetesDashboard             = "getKubernetesDashboard"
 	ToolListKubernetesNamespaces           = "listKubernetesNamespaces"
 	ToolGetKubernetesConfig                = "getKubernetesConfig"
+	ToolGetKubernetesPodLogs               = "getKubernetesPodLogs"
+	ToolExecKubernetesPod                  = "execKubernetesPod"
 	ToolGetSystemStatus                    = "getSystemStatus"
 	ToolListCustomTemplates                = "listCustomTemplates"
 	ToolGetCustomTemplate                  = "getCustomTemplate"
@@ -104,6 +116,26 @@ const (
 	ToolGetHelmReleaseHistory              = "getHelmReleaseHistory"
 )
 
+// Tools whose id argument is a stack ID. The environment scope checks the
+// environments of the stack for them, and the name resolver resolves their
+// stack argument.
+var (
+	// stackTools are the tools whose id argument is a regular stack ID.
+	stackTools = []string{
+		ToolGetStack,
+		ToolDeleteStack,
+		ToolInspectStackFile,
+		ToolUpdateStackGit,
+		ToolRedeployStackGit,
+		ToolStartStack,
+		ToolStopStack,
+		ToolMigrateStack,
+	}
+
+	// edgeStackTools are the tools whose id argument is an edge stack ID.
+	edgeStackTools = []string{ToolGetStackFile, ToolUpdateStack}
+)
+
 // Access levels for users and teams
 const (
 	// AccessLevelEnvironmentAdmin represents the environment administrator access level
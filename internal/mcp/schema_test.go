@@ -1,6 +1,13 @@
 package mcp
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
 
 // TestIsValidAccessLevel verifies is valid access level behavior.
 func TestIsValidAccessLevel(t *testing.T) {
@@ -51,3 +58,29 @@ func TestIsValidUserRole(t *testing.T) {
 		})
 	}
 }
+
+// TestStackTools verifies that every tool whose id argument is a stack ID is
+// listed as a regular or edge stack tool, as the environment scope only checks
+// the stacks of the listed tools.
+func TestStackTools(t *testing.T) {
+	tools, err := toolgen.LoadToolsFromYAML("../../tools.yaml", MinimumToolsVersion)
+	require.NoError(t, err)
+
+	var regular, edge []string
+	for name, tool := range tools {
+		property, ok := tool.InputSchema.Properties["id"].(map[string]any)
+		if !ok {
+			continue
+		}
+		description, _ := property["description"].(string)
+		switch {
+		case strings.Contains(description, "edge stack"):
+			edge = append(edge, name)
+		case strings.Contains(description, "stack"):
+			regular = append(regular, name)
+		}
+	}
+
+	assert.ElementsMatch(t, regular, stackTools)
+	assert.ElementsMatch(t, edge, edgeStackTools)
+}
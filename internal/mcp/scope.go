@@ -0,0 +1,370 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// environmentScope restricts the server to the environments that match any of
+// the given IDs, tag names, environment group names, or access group names.
+// An empty scope puts every environment in scope.
+type environmentScope struct {
+	IDs          []int    `yaml:"ids"`
+	Tags         []string `yaml:"tags"`
+	Groups       []string `yaml:"groups"`
+	AccessGroups []string `yaml:"accessGroups"`
+}
+
+// empty reports whether the scope places no restriction.
+func (e environmentScope) empty() bool {
+	return len(e.IDs) == 0 && len(e.Tags) == 0 && len(e.Groups) == 0 && len(e.AccessGroups) == 0
+}
+
+// validate checks that the configured environment IDs are positive.
+func (e environmentScope) validate() error {
+	for _, id := range e.IDs {
+		if err := validatePositiveID("environment id", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	// environmentIDParams are the tool arguments that hold the ID of an environment.
+	environmentIDParams = []string{"environmentId", "targetEnvironmentId", "endpointId"}
+
+	// environmentIDListParams are the tool arguments that hold lists of environment IDs.
+	environmentIDListParams = []string{"environmentIds", "endpoints"}
+
+	// environmentGroupListParams are the tool arguments that hold lists of
+	// environment group IDs. Every environment of these groups must be in scope.
+	environmentGroupListParams = []string{"environmentGroupIds", "edgeGroups"}
+
+	// environmentTools are the tools whose id argument is an environment ID.
+	environmentTools = []string{
+		ToolGetEnvironment,
+		ToolDeleteEnvironment,
+		ToolSnapshotEnvironment,
+		ToolUpdateEnvironmentTags,
+		ToolUpdateEnvironmentUserAccesses,
+		ToolUpdateEnvironmentTeamAccesses,
+	}
+)
+
+// scoped reports whether the policy restricts the server to a subset of environments.
+func (p *policy) scoped() bool {
+	return p != nil && !p.Environments.empty()
+}
+
+// withEnvironmentScope wraps the handler of a tool so that, when the server is
+// restricted to a subset of environments, calls that target an environment
+// outside of it are rejected before the handler runs. Calls that address a
+// stack by ID are checked against the environment the stack is deployed to.
+func (s *PortainerMCPServer) withEnvironmentScope(toolName string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resolver := s.newScopeResolver(ctx)
+		ids, groupIDs := environmentArguments(toolName, request)
+		if result := resolver.check(ids, groupIDs); result != nil {
+			return result, nil
+		}
+
+		ids, groupIDs, err := resolver.stackEnvironments(toolName, request)
+		if err != nil {
+			return toolErrorFromErr("failed to check environment scope", err), nil
+		}
+		if result := resolver.check(ids, groupIDs); result != nil {
+			return result, nil
+		}
+
+		return next(ctx, request)
+	}
+}
+
+// environmentArguments returns the environment IDs and environment group IDs
+// that a tool call targets. Arguments of the wrong type are ignored here and
+// rejected by the handler.
+func environmentArguments(toolName string, request mcp.CallToolRequest) ([]int, []int) {
+	parser := toolgen.NewParameterParser(request)
+
+	var ids []int
+	params := environmentIDParams
+	if slices.Contains(environmentTools, toolName) {
+		params = append([]string{"id"}, params...)
+	}
+	for _, name := range params {
+		if id, err := parser.GetInt(name, false); err == nil && id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	for _, name := range environmentIDListParams {
+		if list, err := parser.GetArrayOfIntegers(name, false); err == nil {
+			ids = append(ids, list...)
+		}
+	}
+
+	var groupIDs []int
+	for _, name := range environmentGroupListParams {
+		if list, err := parser.GetArrayOfIntegers(name, false); err == nil {
+			groupIDs = append(groupIDs, list...)
+		}
+	}
+	return ids, groupIDs
+}
+
+// filterRegularStacks returns the regular stacks deployed to an environment in scope.
+func (s *PortainerMCPServer) filterRegularStacks(ctx context.Context, stacks []models.RegularStack) ([]models.RegularStack, error) {
+	if !s.policy.scoped() {
+		return stacks, nil
+	}
+
+	resolver := s.newScopeResolver(ctx)
+	filtered := make([]models.RegularStack, 0, len(stacks))
+	for _, stack := range stacks {
+		inScope, err := resolver.contains(models.Environment{ID: stack.EndpointID})
+		if err != nil {
+			return nil, err
+		}
+		if inScope {
+			filtered = append(filtered, stack)
+		}
+	}
+	return filtered, nil
+}
+
+// filterEdgeStacks returns the edge stacks whose environment groups hold only
+// environments in scope.
+func (s *PortainerMCPServer) filterEdgeStacks(ctx context.Context, stacks []models.Stack) ([]models.Stack, error) {
+	if !s.policy.scoped() {
+		return stacks, nil
+	}
+
+	resolver := s.newScopeResolver(ctx)
+	filtered := make([]models.Stack, 0, len(stacks))
+	for _, stack := range stacks {
+		inScope, err := resolver.containsGroups(stack.EnvironmentGroupIds)
+		if err != nil {
+			return nil, err
+		}
+		if inScope {
+			filtered = append(filtered, stack)
+		}
+	}
+	return filtered, nil
+}
+
+// filterEnvironments returns the environments that are in scope.
+func (s *PortainerMCPServer) filterEnvironments(ctx context.Context, environments []models.Environment) ([]models.Environment, error) {
+	if !s.policy.scoped() {
+		return environments, nil
+	}
+
+	resolver := s.newScopeResolver(ctx)
+	filtered := make([]models.Environment, 0, len(environments))
+	for _, environment := range environments {
+		inScope, err := resolver.contains(environment)
+		if err != nil {
+			return nil, err
+		}
+		if inScope {
+			filtered = append(filtered, environment)
+		}
+	}
+	return filtered, nil
+}
+
+// scopeResolver decides whether environments are in scope during one tool
+// call. It reads the tags and groups that the scope refers to from Portainer
+// once, when first needed.
+type scopeResolver struct {
+	ctx   context.Context
+	cli   PortainerClient
+	scope environmentScope
+
+	loaded         bool
+	tagIDs         []int
+	environmentIDs []int
+	groups         []models.Group
+}
+
+// newScopeResolver returns a resolver for the server's environment scope that
+// reads from Portainer with the identity of the caller.
+func (s *PortainerMCPServer) newScopeResolver(ctx context.Context) *scopeResolver {
+	return &scopeResolver{ctx: ctx, cli: s.client(ctx), scope: s.policy.Environments}
+}
+
+// contains reports whether an environment is in scope: its ID is listed, it
+// has a listed tag, or it belongs to a listed environment group or access group.
+func (r *scopeResolver) contains(environment models.Environment) (bool, error) {
+	if r.scope.empty() || slices.Contains(r.scope.IDs, environment.ID) {
+		return true, nil
+	}
+	if err := r.load(); err != nil {
+		return false, err
+	}
+	if slices.Contains(r.environmentIDs, environment.ID) {
+		return true, nil
+	}
+	for _, tagID := range environment.TagIds {
+		if slices.Contains(r.tagIDs, tagID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containsGroups reports whether every environment of the given environment
+// groups is in scope.
+func (r *scopeResolver) containsGroups(groupIDs []int) (bool, error) {
+	if len(groupIDs) == 0 {
+		return true, nil
+	}
+	ids, err := r.groupEnvironments(groupIDs)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range ids {
+		inScope, err := r.contains(models.Environment{ID: id})
+		if err != nil || !inScope {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// check returns an error result when one of the given environments, or of the
+// environments of the given environment groups, is out of scope, and nil when
+// they all are in scope.
+func (r *scopeResolver) check(ids, groupIDs []int) *mcp.CallToolResult {
+	if len(groupIDs) > 0 {
+		groupEnvironments, err := r.groupEnvironments(groupIDs)
+		if err != nil {
+			return toolErrorFromErr("failed to check environment scope", err)
+		}
+		ids = append(ids, groupEnvironments...)
+	}
+	for _, id := range ids {
+		inScope, err := r.contains(models.Environment{ID: id})
+		if err != nil {
+			return toolErrorFromErr("failed to check environment scope", err)
+		}
+		if !inScope {
+			return mcp.NewToolResultError(fmt.Sprintf("environment %d is out of scope: this server is restricted to a subset of environments", id))
+		}
+	}
+	return nil
+}
+
+// stackEnvironments returns the environment IDs and environment group IDs of
+// the stack that a tool call addresses by ID: the environment a regular stack
+// is deployed to, or the environment groups of an edge stack. A missing or
+// invalid ID is left to the handler to reject.
+func (r *scopeResolver) stackEnvironments(toolName string, request mcp.CallToolRequest) ([]int, []int, error) {
+	isStack, isEdgeStack := slices.Contains(stackTools, toolName), slices.Contains(edgeStackTools, toolName)
+	if !isStack && !isEdgeStack {
+		return nil, nil, nil
+	}
+	id, err := toolgen.NewParameterParser(request).GetInt("id", false)
+	if err != nil || id <= 0 {
+		return nil, nil, nil
+	}
+
+	if isStack {
+		stack, err := r.cli.InspectStack(r.ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get stack %d: %w", id, err)
+		}
+		return []int{stack.EndpointID}, nil, nil
+	}
+
+	stacks, err := r.cli.GetStacks(r.ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get edge stacks: %w", err)
+	}
+	for _, stack := range stacks {
+		if stack.ID == id {
+			return nil, stack.EnvironmentGroupIds, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// groupEnvironments returns the IDs of the environments in the given environment groups.
+func (r *scopeResolver) groupEnvironments(groupIDs []int) ([]int, error) {
+	if err := r.loadGroups(); err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, group := range r.groups {
+		if slices.Contains(groupIDs, group.ID) {
+			ids = append(ids, group.EnvironmentIds...)
+		}
+	}
+	return ids, nil
+}
+
+// load reads the tags, environment groups, and access groups named by the
+// scope, and collects the IDs of their environments.
+func (r *scopeResolver) load() error {
+	if r.loaded {
+		return nil
+	}
+
+	if len(r.scope.Tags) > 0 {
+		tags, err := r.cli.GetEnvironmentTags(r.ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get environment tags: %w", err)
+		}
+		for _, tag := range tags {
+			if slices.Contains(r.scope.Tags, tag.Name) {
+				r.tagIDs = append(r.tagIDs, tag.ID)
+				r.environmentIDs = append(r.environmentIDs, tag.EnvironmentIds...)
+			}
+		}
+	}
+
+	if len(r.scope.Groups) > 0 {
+		if err := r.loadGroups(); err != nil {
+			return err
+		}
+		for _, group := range r.groups {
+			if slices.Contains(r.scope.Groups, group.Name) {
+				r.environmentIDs = append(r.environmentIDs, group.EnvironmentIds...)
+			}
+		}
+	}
+
+	if len(r.scope.AccessGroups) > 0 {
+		accessGroups, err := r.cli.GetAccessGroups(r.ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get access groups: %w", err)
+		}
+		for _, group := range accessGroups {
+			if slices.Contains(r.scope.AccessGroups, group.Name) {
+				r.environmentIDs = append(r.environmentIDs, group.EnvironmentIds...)
+			}
+		}
+	}
+
+	r.loaded = true
+	return nil
+}
+
+// loadGroups reads the environment groups once.
+func (r *scopeResolver) loadGroups() error {
+	if r.groups != nil {
+		return nil
+	}
+	groups, err := r.cli.GetEnvironmentGroups(r.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get environment groups: %w", err)
+	}
+	r.groups = append([]models.Group{}, groups...)
+	return nil
+}
@@ -0,0 +1,390 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestScopedServer returns a test server restricted to the given environment scope.
+func newTestScopedServer(scope environmentScope) *PortainerMCPServer {
+	s := newTestMetaServer(false)
+	s.policy = &policy{Environments: scope}
+	return s
+}
+
+// okHandler is a tool handler that always succeeds.
+func okHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText("ok"), nil
+}
+
+// TestEnvironmentArguments verifies which arguments are read as environment and environment group IDs.
+func TestEnvironmentArguments(t *testing.T) {
+	tests := []struct {
+		name             string
+		tool             string
+		args             map[string]any
+		expectedIDs      []int
+		expectedGroupIDs []int
+	}{
+		{
+			name:        "environmentId",
+			tool:        ToolStartStack,
+			args:        map[string]any{"id": float64(7), "environmentId": float64(2)},
+			expectedIDs: []int{2},
+		},
+		{
+			name:        "migrate stack target",
+			tool:        ToolMigrateStack,
+			args:        map[string]any{"id": float64(7), "environmentId": float64(2), "targetEnvironmentId": float64(3)},
+			expectedIDs: []int{2, 3},
+		},
+		{
+			name:        "environment tool id",
+			tool:        ToolDeleteEnvironment,
+			args:        map[string]any{"id": float64(4)},
+			expectedIDs: []int{4},
+		},
+		{
+			name:        "webhook endpointId",
+			tool:        ToolCreateWebhook,
+			args:        map[string]any{"resourceId": "abc", "endpointId": float64(5)},
+			expectedIDs: []int{5},
+		},
+		{
+			name:             "lists",
+			tool:             ToolCreateEdgeJob,
+			args:             map[string]any{"endpoints": []any{float64(1), float64(2)}, "edgeGroups": []any{float64(9)}},
+			expectedIDs:      []int{1, 2},
+			expectedGroupIDs: []int{9},
+		},
+		{
+			name: "other tool id",
+			tool: ToolDeleteUser,
+			args: map[string]any{"id": float64(4)},
+		},
+		{
+			name: "invalid type",
+			tool: ToolStartStack,
+			args: map[string]any{"id": float64(7), "environmentId": "two"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, groupIDs := environmentArguments(tt.tool, CreateMCPRequest(tt.args))
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, tt.expectedGroupIDs, groupIDs)
+		})
+	}
+}
+
+// TestWithEnvironmentScope verifies that calls targeting environments outside
+// the scope are rejected before the handler runs.
+func TestWithEnvironmentScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		scope         environmentScope
+		tool          string
+		args          map[string]any
+		mockSetup     func(*MockPortainerClient)
+		errorContains string
+	}{
+		{
+			name:  "listed ID",
+			scope: environmentScope{IDs: []int{2}},
+			tool:  ToolStartStack,
+			args:  map[string]any{"id": float64(7), "environmentId": float64(2)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("InspectStack", 7).Return(models.RegularStack{ID: 7, EndpointID: 2}, nil)
+			},
+		},
+		{
+			name:          "unlisted ID",
+			scope:         environmentScope{IDs: []int{2}},
+			tool:          ToolStartStack,
+			args:          map[string]any{"id": float64(7), "environmentId": float64(1)},
+			errorContains: "environment 1 is out of scope",
+		},
+		{
+			name:          "migrate stack to an environment out of scope",
+			scope:         environmentScope{IDs: []int{2}},
+			tool:          ToolMigrateStack,
+			args:          map[string]any{"id": float64(7), "environmentId": float64(2), "targetEnvironmentId": float64(1)},
+			errorContains: "environment 1 is out of scope",
+		},
+		{
+			name:  "stack in scope",
+			scope: environmentScope{IDs: []int{2}},
+			tool:  ToolInspectStackFile,
+			args:  map[string]any{"id": float64(7)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("InspectStack", 7).Return(models.RegularStack{ID: 7, EndpointID: 2}, nil)
+			},
+		},
+		{
+			name:  "stack out of scope",
+			scope: environmentScope{IDs: []int{2}},
+			tool:  ToolGetStack,
+			args:  map[string]any{"id": float64(7)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("InspectStack", 7).Return(models.RegularStack{ID: 7, EndpointID: 1}, nil)
+			},
+			errorContains: "environment 1 is out of scope",
+		},
+		{
+			name:  "stack deployed out of scope with an environment in scope",
+			scope: environmentScope{IDs: []int{2}},
+			tool:  ToolStopStack,
+			args:  map[string]any{"id": float64(7), "environmentId": float64(2)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("InspectStack", 7).Return(models.RegularStack{ID: 7, EndpointID: 1}, nil)
+			},
+			errorContains: "environment 1 is out of scope",
+		},
+		{
+			name:  "stack lookup error",
+			scope: environmentScope{IDs: []int{2}},
+			tool:  ToolDeleteStack,
+			args:  map[string]any{"id": float64(7)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("InspectStack", 7).Return(nil, errors.New("stack not found"))
+			},
+			errorContains: "failed to check environment scope",
+		},
+		{
+			name:  "edge stack out of scope",
+			scope: environmentScope{IDs: []int{3}},
+			tool:  ToolGetStackFile,
+			args:  map[string]any{"id": float64(4)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetStacks").Return([]models.Stack{{ID: 4, Name: "agents", EnvironmentGroupIds: []int{5}}}, nil)
+				m.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, Name: "all", EnvironmentIds: []int{3, 4}}}, nil)
+			},
+			errorContains: "environment 4 is out of scope",
+		},
+		{
+			name:  "edge stack in scope",
+			scope: environmentScope{IDs: []int{3}},
+			tool:  ToolGetStackFile,
+			args:  map[string]any{"id": float64(4)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetStacks").Return([]models.Stack{{ID: 4, Name: "agents", EnvironmentGroupIds: []int{5}}}, nil)
+				m.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, Name: "staging", EnvironmentIds: []int{3}}}, nil)
+			},
+		},
+		{
+			name:  "tag",
+			scope: environmentScope{Tags: []string{"staging"}},
+			tool:  ToolDockerProxy,
+			args:  map[string]any{"environmentId": float64(3)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentTags").Return([]models.EnvironmentTag{
+					{ID: 1, Name: "production", EnvironmentIds: []int{1}},
+					{ID: 2, Name: "staging", EnvironmentIds: []int{3}},
+				}, nil)
+			},
+		},
+		{
+			name:  "environment group",
+			scope: environmentScope{Groups: []string{"staging"}},
+			tool:  ToolInstallHelmChart,
+			args:  map[string]any{"environmentId": float64(3)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, Name: "staging", EnvironmentIds: []int{3, 4}}}, nil)
+			},
+		},
+		{
+			name:  "access group",
+			scope: environmentScope{AccessGroups: []string{"Staging"}},
+			tool:  ToolGetEnvironment,
+			args:  map[string]any{"id": float64(1)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetAccessGroups").Return([]models.AccessGroup{{ID: 2, Name: "Staging", EnvironmentIds: []int{3}}}, nil)
+			},
+			errorContains: "environment 1 is out of scope",
+		},
+		{
+			name:  "stack deployed to an environment group out of scope",
+			scope: environmentScope{IDs: []int{3}},
+			tool:  ToolCreateStack,
+			args:  map[string]any{"name": "web", "file": "services: {}", "environmentGroupIds": []any{float64(5)}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, Name: "all", EnvironmentIds: []int{3, 4}}}, nil)
+			},
+			errorContains: "environment 4 is out of scope",
+		},
+		{
+			name:  "lookup error",
+			scope: environmentScope{Tags: []string{"staging"}},
+			tool:  ToolDockerProxy,
+			args:  map[string]any{"environmentId": float64(3)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentTags").Return(nil, errors.New("connection refused"))
+			},
+			errorContains: "failed to check environment scope",
+		},
+		{
+			name:  "no environment argument",
+			scope: environmentScope{IDs: []int{2}},
+			tool:  ToolListUsers,
+			args:  map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScopedServer(tt.scope)
+			mockClient := s.cli.(*MockPortainerClient)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockClient)
+			}
+			called := false
+			handler := s.withEnvironmentScope(tt.tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				return okHandler(ctx, request)
+			})
+
+			result, err := handler(context.Background(), CreateMCPRequest(tt.args))
+
+			require.NoError(t, err)
+			if tt.errorContains == "" {
+				assert.False(t, result.IsError, "unexpected error result: %v", result.Content)
+				assert.True(t, called)
+				return
+			}
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.errorContains)
+			assert.False(t, called, "the handler must not run for an environment out of scope")
+		})
+	}
+}
+
+// TestGetEnvironmentsScope verifies that environments out of scope are left out of the list.
+func TestGetEnvironmentsScope(t *testing.T) {
+	s := newTestScopedServer(environmentScope{IDs: []int{1}, Tags: []string{"staging"}})
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetEnvironments").Return([]models.Environment{
+		{ID: 1, Name: "local"},
+		{ID: 2, Name: "production", TagIds: []int{10}},
+		{ID: 3, Name: "staging", TagIds: []int{11}},
+	}, nil)
+	mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{{ID: 11, Name: "staging"}}, nil)
+
+	result, err := s.HandleGetEnvironments()(context.Background(), CreateMCPRequest(map[string]any{}))
+
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error result: %v", result.Content)
+	var environments []models.Environment
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &environments))
+	require.Len(t, environments, 2)
+	assert.Equal(t, "local", environments[0].Name)
+	assert.Equal(t, "staging", environments[1].Name)
+}
+
+// TestGetStacksScope verifies that stacks deployed out of scope are left out of the lists.
+func TestGetStacksScope(t *testing.T) {
+	t.Run("regular stacks", func(t *testing.T) {
+		s := newTestScopedServer(environmentScope{IDs: []int{1}})
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetRegularStacks").Return([]models.RegularStack{
+			{ID: 1, Name: "web", EndpointID: 1},
+			{ID: 2, Name: "db", EndpointID: 2},
+		}, nil)
+
+		result, err := s.HandleListRegularStacks()(context.Background(), CreateMCPRequest(map[string]any{}))
+
+		require.NoError(t, err)
+		require.False(t, result.IsError, "unexpected error result: %v", result.Content)
+		var stacks []models.RegularStack
+		require.NoError(t, json.Unmarshal([]byte(resultText(result)), &stacks))
+		assert.Equal(t, []models.RegularStack{{ID: 1, Name: "web", EndpointID: 1}}, stacks)
+	})
+
+	t.Run("edge stacks", func(t *testing.T) {
+		s := newTestScopedServer(environmentScope{IDs: []int{1, 2}})
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetStacks").Return([]models.Stack{
+			{ID: 1, Name: "in-scope", EnvironmentGroupIds: []int{5}},
+			{ID: 2, Name: "partly-out-of-scope", EnvironmentGroupIds: []int{5, 6}},
+		}, nil)
+		mockClient.On("GetEnvironmentGroups").Return([]models.Group{
+			{ID: 5, Name: "staging", EnvironmentIds: []int{1, 2}},
+			{ID: 6, Name: "production", EnvironmentIds: []int{3}},
+		}, nil)
+
+		result, err := s.HandleGetStacks()(context.Background(), CreateMCPRequest(map[string]any{}))
+
+		require.NoError(t, err)
+		require.False(t, result.IsError, "unexpected error result: %v", result.Content)
+		var stacks []models.Stack
+		require.NoError(t, json.Unmarshal([]byte(resultText(result)), &stacks))
+		require.Len(t, stacks, 1)
+		assert.Equal(t, "in-scope", stacks[0].Name)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		s := newTestScopedServer(environmentScope{Tags: []string{"staging"}})
+		mockClient := s.cli.(*MockPortainerClient)
+		mockClient.On("GetRegularStacks").Return([]models.RegularStack{{ID: 1, Name: "web", EndpointID: 1}}, nil)
+		mockClient.On("GetEnvironmentTags").Return(nil, errors.New("connection refused"))
+
+		result, err := s.HandleListRegularStacks()(context.Background(), CreateMCPRequest(map[string]any{}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "failed to check environment scope")
+	})
+}
+
+// TestEnvironmentScopeRegistration verifies that scoped meta-tool actions are
+// checked and that snapshotting all environments is not offered.
+func TestEnvironmentScopeRegistration(t *testing.T) {
+	t.Run("meta-tools", func(t *testing.T) {
+		s := newTestScopedServer(environmentScope{IDs: []int{2}})
+		s.RegisterMetaTools()
+
+		properties := listToolProperties(t, s.srv)
+		assert.NotContains(t, properties["manage_environments"]["action"].(map[string]any)["enum"], "snapshot_all_environments")
+
+		resp := s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"manage_stacks","arguments":{"action":"stop_stack","id":7,"environmentId":1}}}`))
+		data, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "environment 1 is out of scope")
+		s.cli.(*MockPortainerClient).AssertNotCalled(t, "StopStack", mock.Anything, mock.Anything)
+	})
+
+	t.Run("granular tools", func(t *testing.T) {
+		s := newTestScopedServer(environmentScope{IDs: []int{2}})
+		s.tools = map[string]mcp.Tool{
+			ToolSnapshotAllEnvironments: {Name: ToolSnapshotAllEnvironments},
+			ToolSnapshotEnvironment:     {Name: ToolSnapshotEnvironment},
+		}
+		s.addToolIfExists(ToolSnapshotAllEnvironments, okHandler)
+		s.addToolIfExists(ToolSnapshotEnvironment, okHandler)
+
+		assert.Equal(t, []string{ToolSnapshotEnvironment}, listRegisteredTools(t, s.srv))
+	})
+}
+
+// TestLoadPolicyEnvironmentScope verifies that the environment scope is read from the policy file.
+func TestLoadPolicyEnvironmentScope(t *testing.T) {
+	p, err := loadPolicy(writePolicyFile(t, "environments:\n  ids: [3]\n  tags: [staging]\n  groups: [edge-staging]\n  accessGroups: [Staging]\n"))
+	require.NoError(t, err)
+	assert.True(t, p.scoped())
+	assert.Equal(t, environmentScope{IDs: []int{3}, Tags: []string{"staging"}, Groups: []string{"edge-staging"}, AccessGroups: []string{"Staging"}}, p.Environments)
+
+	_, err = loadPolicy(writePolicyFile(t, "environments:\n  ids: [0]\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid environment scope")
+
+	var nilPolicy *policy
+	assert.False(t, nilPolicy.scoped())
+}
@@ -2,29 +2,50 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
-	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	// MinimumToolsVersion is the minimum supported version of the tools.yaml file.
 	// This uses the same "v{major}.{minor}" format as tools.yaml version strings.
 	MinimumToolsVersion = "v1.0"
+	// MinimumPromptsVersion is the minimum supported version of the prompts.yaml file.
+	MinimumPromptsVersion = "v1.0"
 	// SupportedPortainerVersion is the version of Portainer that is supported by this tool
 	SupportedPortainerVersion = "2.31.2"
 	// maxProxyResponseSize is the maximum allowed response body size (10MB) for Docker/K8s proxy calls
 	maxProxyResponseSize = 10 * 1024 * 1024
+	// shutdownTimeout bounds how long a network transport waits for in-flight requests on shutdown
+	shutdownTimeout = 10 * time.Second
+)
+
+// Transport names accepted by [WithTransport].
+const (
+	// TransportStdio serves MCP over standard input/output (default).
+	TransportStdio = "stdio"
+	// TransportStreamableHTTP serves MCP over the streamable HTTP transport on /mcp.
+	TransportStreamableHTTP = "http"
+	// TransportSSE serves MCP over the legacy SSE transport on /sse and /message.
+	TransportSSE = "sse"
+	// DefaultListenAddr is the listen address used by network transports when none is set.
+	DefaultListenAddr = ":8080"
+	// streamableHTTPEndpoint is the path on which the streamable HTTP transport is served.
+	streamableHTTPEndpoint = "/mcp"
 )
 
 // PortainerClient defines the contract between the MCP server and the Portainer API
@@ -39,171 +60,229 @@ const (
 //   - Settings: server, public, and SSL configuration
 //   - Templates: application templates and custom templates
 //   - Registries: container registry management
+//   - Docker containers: typed list, inspect, and lifecycle operations
 //   - Docker and Kubernetes proxies: raw API pass-through to container engines
 //   - Tags, roles, webhooks, backups, edge jobs, Helm, auth, and system status
 //
+// Every method takes the context of the tool call that triggered it, so that a
+// cancelled call or an ended session aborts the underlying Portainer request.
+//
 // Implementations must be safe for concurrent use by multiple MCP handler goroutines.
 type PortainerClient interface {
 	// Tag methods
-	GetEnvironmentTags() ([]models.EnvironmentTag, error)
-	CreateEnvironmentTag(name string) (int, error)
-	DeleteEnvironmentTag(id int) error
+	GetEnvironmentTags(ctx context.Context) ([]models.EnvironmentTag, error)
+	CreateEnvironmentTag(ctx context.Context, name string) (int, error)
+	DeleteEnvironmentTag(ctx context.Context, id int) error
 
 	// Environment methods
-	GetEnvironments() ([]models.Environment, error)
-	GetEnvironment(id int) (models.Environment, error)
-	DeleteEnvironment(id int) error
-	SnapshotEnvironment(id int) error
-	SnapshotAllEnvironments() error
-	UpdateEnvironmentTags(id int, tagIds []int) error
-	UpdateEnvironmentUserAccesses(id int, userAccesses map[int]string) error
-	UpdateEnvironmentTeamAccesses(id int, teamAccesses map[int]string) error
+	GetEnvironments(ctx context.Context) ([]models.Environment, error)
+	GetEnvironment(ctx context.Context, id int) (models.Environment, error)
+	DeleteEnvironment(ctx context.Context, id int) error
+	SnapshotEnvironment(ctx context.Context, id int) error
+	SnapshotAllEnvironments(ctx context.Context) error
+	UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error
+	UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error
+	UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error
 
 	// Environment Group methods
-	GetEnvironmentGroups() ([]models.Group, error)
-	CreateEnvironmentGroup(name string, environmentIds []int) (int, error)
-	UpdateEnvironmentGroupName(id int, name string) error
-	UpdateEnvironmentGroupEnvironments(id int, environmentIds []int) error
-	UpdateEnvironmentGroupTags(id int, tagIds []int) error
+	GetEnvironmentGroups(ctx context.Context) ([]models.Group, error)
+	CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error)
+	UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error
+	UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error
+	UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error
 
 	// Access Group methods
-	GetAccessGroups() ([]models.AccessGroup, error)
-	CreateAccessGroup(name string, environmentIds []int) (int, error)
-	UpdateAccessGroupName(id int, name string) error
-	UpdateAccessGroupUserAccesses(id int, userAccesses map[int]string) error
-	UpdateAccessGroupTeamAccesses(id int, teamAccesses map[int]string) error
-	AddEnvironmentToAccessGroup(id int, environmentId int) error
-	RemoveEnvironmentFromAccessGroup(id int, environmentId int) error
+	GetAccessGroups(ctx context.Context) ([]models.AccessGroup, error)
+	CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error)
+	UpdateAccessGroupName(ctx context.Context, id int, name string) error
+	UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error
+	UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error
+	AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error
+	RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error
 
 	// Stack methods
-	GetStacks() ([]models.Stack, error)
-	GetStackFile(id int) (string, error)
-	CreateStack(name string, file string, environmentGroupIds []int) (int, error)
-	UpdateStack(id int, file string, environmentGroupIds []int) error
+	GetStacks(ctx context.Context) ([]models.Stack, error)
+	GetStackFile(ctx context.Context, id int) (string, error)
+	CreateStack(ctx context.Context, name string, file string, environmentGroupIds []int) (int, error)
+	UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error
 
 	// Regular stack methods
-	GetRegularStacks() ([]models.RegularStack, error)
-	InspectStack(id int) (models.RegularStack, error)
-	DeleteStack(id int, endpointID int, removeVolumes bool) error
-	InspectStackFile(id int) (string, error)
-	UpdateStackGit(id int, endpointID int, referenceName string, prune bool) (models.RegularStack, error)
-	RedeployStackGit(id int, endpointID int, pullImage bool, prune bool) (models.RegularStack, error)
-	StartStack(id int, endpointID int) (models.RegularStack, error)
-	StopStack(id int, endpointID int) (models.RegularStack, error)
-	MigrateStack(id int, endpointID int, targetEndpointID int, name string) (models.RegularStack, error)
+	GetRegularStacks(ctx context.Context) ([]models.RegularStack, error)
+	InspectStack(ctx context.Context, id int) (models.RegularStack, error)
+	DeleteStack(ctx context.Context, id int, endpointID int, removeVolumes bool) error
+	InspectStackFile(ctx context.Context, id int) (string, error)
+	UpdateStackGit(ctx context.Context, id int, endpointID int, referenceName string, prune bool) (models.RegularStack, error)
+	RedeployStackGit(ctx context.Context, id int, endpointID int, pullImage bool, prune bool) (models.RegularStack, error)
+	StartStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error)
+	StopStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error)
+	MigrateStack(ctx context.Context, id int, endpointID int, targetEndpointID int, name string) (models.RegularStack, error)
 
 	// Team methods
-	CreateTeam(name string) (int, error)
-	GetTeam(id int) (models.Team, error)
-	GetTeams() ([]models.Team, error)
-	DeleteTeam(id int) error
-	UpdateTeamName(id int, name string) error
-	UpdateTeamMembers(id int, userIds []int) error
+	CreateTeam(ctx context.Context, name string) (int, error)
+	GetTeam(ctx context.Context, id int) (models.Team, error)
+	GetTeams(ctx context.Context) ([]models.Team, error)
+	DeleteTeam(ctx context.Context, id int) error
+	UpdateTeamName(ctx context.Context, id int, name string) error
+	UpdateTeamMembers(ctx context.Context, id int, userIds []int) error
 
 	// User methods
-	CreateUser(username, password, role string) (int, error)
-	GetUser(id int) (models.User, error)
-	GetUsers() ([]models.User, error)
-	DeleteUser(id int) error
-	UpdateUserRole(id int, role string) error
+	CreateUser(ctx context.Context, username, password, role string) (int, error)
+	GetUser(ctx context.Context, id int) (models.User, error)
+	GetUsers(ctx context.Context) ([]models.User, error)
+	DeleteUser(ctx context.Context, id int) error
+	UpdateUserRole(ctx context.Context, id int, role string) error
 
 	// Settings methods
-	GetSettings() (models.PortainerSettings, error)
-	UpdateSettings(settingsJSON map[string]interface{}) error
-	GetPublicSettings() (models.PublicSettings, error)
+	GetSettings(ctx context.Context) (models.PortainerSettings, error)
+	UpdateSettings(ctx context.Context, settingsJSON map[string]interface{}) error
+	GetPublicSettings(ctx context.Context) (models.PublicSettings, error)
 
 	// SSL methods
-	GetSSLSettings() (models.SSLSettings, error)
-	UpdateSSLSettings(cert, key string, httpEnabled *bool) error
+	GetSSLSettings(ctx context.Context) (models.SSLSettings, error)
+	UpdateSSLSettings(ctx context.Context, cert, key string, httpEnabled *bool) error
 
 	// App Template methods
-	GetAppTemplates() ([]models.AppTemplate, error)
-	GetAppTemplateFile(id int) (string, error)
+	GetAppTemplates(ctx context.Context) ([]models.AppTemplate, error)
+	GetAppTemplateFile(ctx context.Context, id int) (string, error)
 
 	// Version methods
-	GetVersion() (string, error)
+	GetVersion(ctx context.Context) (string, error)
 
 	// Docker Proxy methods
-	ProxyDockerRequest(opts models.DockerProxyRequestOptions) (*http.Response, error)
-	GetDockerDashboard(environmentId int) (models.DockerDashboard, error)
+	ProxyDockerRequest(ctx context.Context, opts models.DockerProxyRequestOptions) (*http.Response, error)
+	GetDockerDashboard(ctx context.Context, environmentId int) (models.DockerDashboard, error)
+
+	// Docker Container methods
+	ListDockerContainers(ctx context.Context, environmentId int, opts models.DockerContainerListOptions) ([]models.DockerContainer, error)
+	InspectDockerContainer(ctx context.Context, environmentId int, containerId string) (models.DockerContainerDetails, error)
+	GetDockerContainerLogs(ctx context.Context, environmentId int, containerId string, opts models.DockerContainerLogOptions) (models.DockerContainerLogs, error)
+	StartDockerContainer(ctx context.Context, environmentId int, containerId string) error
+	StopDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error
+	RestartDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error
+	KillDockerContainer(ctx context.Context, environmentId int, containerId string, signal string) error
+	RemoveDockerContainer(ctx context.Context, environmentId int, containerId string, force bool, removeVolumes bool) error
+	RenameDockerContainer(ctx context.Context, environmentId int, containerId string, name string) error
+	ExecDockerContainer(ctx context.Context, environmentId int, containerId string, opts models.ExecOptions) (models.ExecResult, error)
 
 	// Kubernetes Proxy methods
-	ProxyKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, error)
+	ProxyKubernetesRequest(ctx context.Context, opts models.KubernetesProxyRequestOptions) (*http.Response, error)
 
 	// Kubernetes Native methods
-	GetKubernetesDashboard(environmentId int) (models.KubernetesDashboard, error)
-	GetKubernetesNamespaces(environmentId int) ([]models.KubernetesNamespace, error)
-	GetKubernetesConfig(environmentId int) (interface{}, error)
+	GetKubernetesDashboard(ctx context.Context, environmentId int) (models.KubernetesDashboard, error)
+	GetKubernetesNamespaces(ctx context.Context, environmentId int) ([]models.KubernetesNamespace, error)
+	GetKubernetesConfig(ctx context.Context, environmentId int) (interface{}, error)
+	GetKubernetesPodLogs(ctx context.Context, environmentId int, namespace string, opts models.KubernetesPodLogOptions) ([]models.KubernetesPodLogs, error)
+	ExecKubernetesPod(ctx context.Context, environmentId int, namespace, pod, container string, opts models.ExecOptions) (models.ExecResult, error)
 
-	GetWebhooks() ([]models.Webhook, error)
-	CreateWebhook(resourceId string, endpointId int, webhookType int) (int, error)
-	DeleteWebhook(id int) error
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	CreateWebhook(ctx context.Context, resourceId string, endpointId int, webhookType int) (int, error)
+	DeleteWebhook(ctx context.Context, id int) error
 
 	// System methods
-	GetSystemStatus() (models.SystemStatus, error)
+	GetSystemStatus(ctx context.Context) (models.SystemStatus, error)
 
 	// Custom Template methods
-	GetCustomTemplates() ([]models.CustomTemplate, error)
-	GetCustomTemplate(id int) (models.CustomTemplate, error)
-	GetCustomTemplateFile(id int) (string, error)
-	CreateCustomTemplate(title, description, note, logo, fileContent string, platform, templateType int) (int, error)
-	DeleteCustomTemplate(id int) error
+	GetCustomTemplates(ctx context.Context) ([]models.CustomTemplate, error)
+	GetCustomTemplate(ctx context.Context, id int) (models.CustomTemplate, error)
+	GetCustomTemplateFile(ctx context.Context, id int) (string, error)
+	CreateCustomTemplate(ctx context.Context, title, description, note, logo, fileContent string, platform, templateType int) (int, error)
+	DeleteCustomTemplate(ctx context.Context, id int) error
 
 	// Registry methods
-	GetRegistries() ([]models.Registry, error)
-	GetRegistry(id int) (models.Registry, error)
-	CreateRegistry(name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error)
-	UpdateRegistry(id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error
-	DeleteRegistry(id int) error
+	GetRegistries(ctx context.Context) ([]models.Registry, error)
+	GetRegistry(ctx context.Context, id int) (models.Registry, error)
+	CreateRegistry(ctx context.Context, name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error)
+	UpdateRegistry(ctx context.Context, id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error
+	DeleteRegistry(ctx context.Context, id int) error
 
 	// Backup methods
-	GetBackupStatus() (models.BackupStatus, error)
-	GetBackupS3Settings() (models.S3BackupSettings, error)
-	CreateBackup(password string) error
-	BackupToS3(settings models.S3BackupSettings) error
-	RestoreFromS3(accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error
+	GetBackupStatus(ctx context.Context) (models.BackupStatus, error)
+	GetBackupS3Settings(ctx context.Context) (models.S3BackupSettings, error)
+	CreateBackup(ctx context.Context, password string) error
+	BackupToS3(ctx context.Context, settings models.S3BackupSettings) error
+	RestoreFromS3(ctx context.Context, accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error
 
 	// Role methods
-	GetRoles() ([]models.Role, error)
+	GetRoles(ctx context.Context) ([]models.Role, error)
 
 	// MOTD methods
-	GetMOTD() (models.MOTD, error)
+	GetMOTD(ctx context.Context) (models.MOTD, error)
 
 	// Edge Job methods
-	GetEdgeJobs() ([]models.EdgeJob, error)
-	GetEdgeJob(id int) (models.EdgeJob, error)
-	GetEdgeJobFile(id int) (string, error)
-	CreateEdgeJob(name, cronExpression, fileContent string, endpoints []int, edgeGroups []int, recurring bool) (int, error)
-	DeleteEdgeJob(id int) error
+	GetEdgeJobs(ctx context.Context) ([]models.EdgeJob, error)
+	GetEdgeJob(ctx context.Context, id int) (models.EdgeJob, error)
+	GetEdgeJobFile(ctx context.Context, id int) (string, error)
+	CreateEdgeJob(ctx context.Context, name, cronExpression, fileContent string, endpoints []int, edgeGroups []int, recurring bool) (int, error)
+	DeleteEdgeJob(ctx context.Context, id int) error
 
 	// Edge Update Schedule methods
-	GetEdgeUpdateSchedules() ([]models.EdgeUpdateSchedule, error)
+	GetEdgeUpdateSchedules(ctx context.Context) ([]models.EdgeUpdateSchedule, error)
 
 	// Auth methods
-	AuthenticateUser(username, password string) (models.AuthResponse, error)
-	Logout() error
+	AuthenticateUser(ctx context.Context, username, password string) (models.AuthResponse, error)
+	Logout(ctx context.Context) error
 
 	// Helm methods
-	GetHelmRepositories(userId int) (models.HelmRepositoryList, error)
-	CreateHelmRepository(userId int, url string) (models.HelmRepository, error)
-	DeleteHelmRepository(userId int, repositoryId int) error
-	SearchHelmCharts(repo string, chart string) (string, error)
-	InstallHelmChart(environmentId int, chart, name, namespace, repo, values, version string) (models.HelmReleaseDetails, error)
-	GetHelmReleases(environmentId int, namespace, filter, selector string) ([]models.HelmRelease, error)
-	DeleteHelmRelease(environmentId int, release, namespace string) error
-	GetHelmReleaseHistory(environmentId int, name, namespace string) ([]models.HelmReleaseDetails, error)
+	GetHelmRepositories(ctx context.Context, userId int) (models.HelmRepositoryList, error)
+	CreateHelmRepository(ctx context.Context, userId int, url string) (models.HelmRepository, error)
+	DeleteHelmRepository(ctx context.Context, userId int, repositoryId int) error
+	SearchHelmCharts(ctx context.Context, repo string, chart string) (string, error)
+	InstallHelmChart(ctx context.Context, environmentId int, chart, name, namespace, repo, values, version string) (models.HelmReleaseDetails, error)
+	GetHelmReleases(ctx context.Context, environmentId int, namespace, filter, selector string) ([]models.HelmRelease, error)
+	DeleteHelmRelease(ctx context.Context, environmentId int, release, namespace string) error
+	GetHelmReleaseHistory(ctx context.Context, environmentId int, name, namespace string) ([]models.HelmReleaseDetails, error)
 }
 
 // PortainerMCPServer is the main MCP server that bridges AI assistants and the
 // Portainer API. It registers tool definitions loaded from a YAML file, routes
 // incoming MCP tool-call requests to the appropriate handlers, and communicates
 // with Portainer through the [PortainerClient] interface. The server supports
-// read-only mode to prevent modifications and listens on stdio for MCP messages.
+// read-only mode to prevent modifications and listens for MCP messages on stdio,
+// streamable HTTP, or SSE depending on the configured transport.
 type PortainerMCPServer struct {
-	srv      *server.MCPServer
-	cli      PortainerClient
-	tools    map[string]mcp.Tool
-	readOnly bool
+	srv        *server.MCPServer
+	cli        PortainerClient
+	tools      map[string]mcp.Tool
+	readOnly   bool
+	dryRun     bool
+	transport  string
+	listenAddr string
+	// sessionClients caches per-session Portainer clients when session
+	// authentication is enabled; nil otherwise.
+	sessionClients *sessionClientCache
+	// audit records every tool call when an audit log is configured; nil otherwise.
+	audit *auditLogger
+	// confirmations holds the tokens of pending destructive actions when
+	// confirmation is required; nil otherwise.
+	confirmations *confirmationStore
+	// policy restricts the registered tools and actions when a policy file is configured; nil otherwise.
+	policy *policy
+	// redactor removes secrets from tool results.
+	redactor *redactor
+	// limiter bounds the calls in flight and the call rate of tools when
+	// limits are configured; nil otherwise.
+	limiter *rateLimiter
+	// cacheStats counts the hits and misses of the client cache when caching
+	// is enabled; nil otherwise.
+	cacheStats *cacheStats
+	// metrics records Prometheus metrics, served on metricsAddr, when a
+	// metrics address is configured; nil otherwise.
+	metrics     *serverMetrics
+	metricsAddr string
+	// tracer records a span for each tool call when tracing is enabled; nil
+	// otherwise. shutdownTracing flushes the spans of a tracer provider
+	// created from the environment when the server stops.
+	tracer          trace.Tracer
+	shutdownTracing func(context.Context) error
+	// prompts are the definitions of the prompts file, registered by
+	// RegisterPrompts; nil when no prompts file is configured.
+	prompts []toolgen.PromptDefinition
+	// granularTools is true when the server registers granular tools
+	// instead of meta-tools, so that prompts name the granular tools.
+	granularTools bool
+	// watcher polls Portainer and notifies clients of status changes while
+	// the server runs when a watch interval is configured; nil otherwise.
+	watcher *watcher
 }
 
 // ServerOption is a functional option for configuring a [PortainerMCPServer].
@@ -214,9 +293,27 @@ type ServerOption func(*serverOptions)
 type serverOptions struct {
 	client              PortainerClient
 	readOnly            bool
+	dryRun              bool
+	requireConfirmation bool
+	policyPath          string
+	maxInFlight         int
+	cacheTTL            time.Duration
+	cacheMethodTTLs     map[string]time.Duration
 	granularTools       bool
 	disableVersionCheck bool
 	skipTLSVerify       bool
+	transport           string
+	listenAddr          string
+	sessionAuth         bool
+	clientFactory       ClientFactory
+	auditLogPath        string
+	auditLogMaxSizeMB   int
+	auditLogMaxBackups  int
+	metricsAddr         string
+	tracerProvider      trace.TracerProvider
+	promptsPath         string
+	watchInterval       time.Duration
+	watchScope          []string
 }
 
 // WithClient sets a custom client for the server.
@@ -235,7 +332,58 @@ func WithReadOnly(readOnly bool) ServerOption {
 	}
 }
 
-// WithGranularTools enables granular tool mode, registering all ~98 individual
+// WithDryRun sets the server to dry-run mode. Write tools then validate their
+// parameters, read the current state from Portainer, and return a plan of the
+// changes they would make instead of applying them. Without it, a single call
+// can request the same behaviour with the dryRun argument.
+func WithDryRun(dryRun bool) ServerOption {
+	return func(opts *serverOptions) {
+		opts.dryRun = dryRun
+	}
+}
+
+// WithRequireConfirmation enables two-phase confirmation of destructive tools,
+// those annotated with destructiveHint in tools.yaml. The first call of such a
+// tool returns the operations it would perform and a short-lived token bound
+// to its arguments; the tool only runs when called again with that token.
+func WithRequireConfirmation(required bool) ServerOption {
+	return func(opts *serverOptions) {
+		opts.requireConfirmation = required
+	}
+}
+
+// WithPolicyFile configures a YAML policy file that allows or denies individual
+// meta-tools, meta-tool actions, and granular tools. Denied actions are left out
+// of the action enum of their meta-tool, and denied tools are not registered.
+func WithPolicyFile(path string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.policyPath = path
+	}
+}
+
+// WithMaxInFlight limits the number of tool calls that run at once across all
+// tools. Calls beyond the limit are rejected with a result that asks the
+// caller to retry. Zero, the default, means no limit.
+func WithMaxInFlight(maxInFlight int) ServerOption {
+	return func(opts *serverOptions) {
+		opts.maxInFlight = maxInFlight
+	}
+}
+
+// WithCache enables a cache of frequently repeated reads, such as the lists of
+// environments, tags, users, and roles, that keeps each result for ttl.
+// methodTTLs overrides the TTL of individual PortainerClient methods; a zero
+// TTL disables caching for that method. Writes clear the cached reads of the
+// resources they change, and read-only tools accept a refresh argument that
+// bypasses the cache.
+func WithCache(ttl time.Duration, methodTTLs map[string]time.Duration) ServerOption {
+	return func(opts *serverOptions) {
+		opts.cacheTTL = ttl
+		opts.cacheMethodTTLs = methodTTLs
+	}
+}
+
+// WithGranularTools enables granular tool mode, registering all ~110 individual
 // tools instead of the default ~15 grouped meta-tools.
 func WithGranularTools(granular bool) ServerOption {
 	return func(opts *serverOptions) {
@@ -259,6 +407,100 @@ func WithSkipTLSVerify(skip bool) ServerOption {
 	}
 }
 
+// WithTransport selects the MCP transport used by [PortainerMCPServer.Start].
+// Valid values are [TransportStdio], [TransportStreamableHTTP], and [TransportSSE].
+// An empty value selects stdio.
+func WithTransport(transport string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.transport = transport
+	}
+}
+
+// WithListenAddr sets the listen address (e.g. ":8080") for network transports.
+// It is ignored when the stdio transport is used.
+func WithListenAddr(addr string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.listenAddr = addr
+	}
+}
+
+// WithSessionAuth enables per-session authentication on network transports.
+// Each MCP client must then send its own Portainer API key (X-API-Key header or
+// Authorization: Bearer) or user JWT (Authorization: Bearer), and tool calls are
+// executed against Portainer with that identity instead of the server token.
+// It is rejected when the stdio transport is used.
+func WithSessionAuth(enabled bool) ServerOption {
+	return func(opts *serverOptions) {
+		opts.sessionAuth = enabled
+	}
+}
+
+// WithClientFactory sets the factory used to build per-session clients when
+// session authentication is enabled. This is primarily used for testing.
+func WithClientFactory(factory ClientFactory) ServerOption {
+	return func(opts *serverOptions) {
+		opts.clientFactory = factory
+	}
+}
+
+// WithAuditLog enables the audit log: every tool call is appended to the file
+// at path as a JSON line with its arguments (secrets redacted), outcome,
+// duration, and the Portainer identity used. An empty path disables it.
+func WithAuditLog(path string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.auditLogPath = path
+	}
+}
+
+// WithAuditLogRotation sets the size in megabytes at which the audit log is
+// rotated and the number of rotated files to keep. A maxSizeMB of zero
+// disables rotation.
+func WithAuditLogRotation(maxSizeMB, maxBackups int) ServerOption {
+	return func(opts *serverOptions) {
+		opts.auditLogMaxSizeMB = maxSizeMB
+		opts.auditLogMaxBackups = maxBackups
+	}
+}
+
+// WithMetricsAddr enables Prometheus metrics, served on /metrics at addr
+// (e.g. ":9090") by a listener separate from the MCP transport. The metrics
+// cover tool calls, the requests sent to Portainer, and the client cache. An
+// empty addr disables them.
+func WithMetricsAddr(addr string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.metricsAddr = addr
+	}
+}
+
+// WithTracerProvider records tool calls and the Portainer requests they make
+// as spans of tp. Without this option, spans are exported over OTLP when the
+// standard OTEL_EXPORTER_OTLP_* environment variables configure an endpoint.
+func WithTracerProvider(tp trace.TracerProvider) ServerOption {
+	return func(opts *serverOptions) {
+		opts.tracerProvider = tp
+	}
+}
+
+// WithPromptsFile loads the prompts registered by RegisterPrompts from the
+// YAML file at path. An empty path registers no prompts.
+func WithPromptsFile(path string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.promptsPath = path
+	}
+}
+
+// WithWatch polls Portainer every interval while the server runs and notifies
+// the connected clients when an environment, stack, or Helm release changes
+// status. scope lists the kinds of objects to watch, among [WatchEnvironments],
+// [WatchStacks], and [WatchHelm]; an empty scope watches environments and
+// stacks. A zero interval disables watching.
+func WithWatch(interval time.Duration, scope []string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.watchInterval = interval
+		opts.watchScope = scope
+	}
+}
+
 // NewPortainerMCPServer creates a new Portainer MCP server.
 //
 // This server provides an implementation of the MCP protocol for Portainer,
@@ -266,7 +508,7 @@ func WithSkipTLSVerify(skip bool) ServerOption {
 //
 // Parameters:
 //   - serverURL: The base URL of the Portainer server (e.g., "https://portainer.example.com")
-//   - token: The API token for authenticating with the Portainer server
+//   - token: The API token for authenticating with the Portainer server (optional with WithSessionAuth)
 //   - toolsPath: Path to the tools.yaml file that defines the available MCP tools
 //   - options: Optional functional options for customizing server behavior (e.g., WithClient)
 //
@@ -276,29 +518,133 @@ func WithSkipTLSVerify(skip bool) ServerOption {
 //
 // Possible errors:
 //   - Failed to load tools from the specified path
+//   - Failed to load prompts from the prompts file
 //   - Failed to communicate with the Portainer server
 //   - Incompatible Portainer server version
+//   - Unsupported transport
+//   - Session authentication requested on the stdio transport
+//   - Failed to open the audit log
+//   - Watching requested without a server token, or with session authentication
 func NewPortainerMCPServer(serverURL, token, toolsPath string, options ...ServerOption) (*PortainerMCPServer, error) {
-	opts := &serverOptions{}
+	opts := &serverOptions{
+		transport:          TransportStdio,
+		listenAddr:         DefaultListenAddr,
+		auditLogMaxSizeMB:  DefaultAuditLogMaxSizeMB,
+		auditLogMaxBackups: DefaultAuditLogMaxBackups,
+	}
 
 	for _, option := range options {
 		option(opts)
 	}
 
+	if opts.transport == "" {
+		opts.transport = TransportStdio
+	}
+	if !isValidTransport(opts.transport) {
+		return nil, fmt.Errorf("unsupported transport: %s, must be one of %s, %s, %s", opts.transport, TransportStdio, TransportStreamableHTTP, TransportSSE)
+	}
+	if opts.listenAddr == "" {
+		opts.listenAddr = DefaultListenAddr
+	}
+	if opts.sessionAuth && opts.transport == TransportStdio {
+		return nil, fmt.Errorf("session authentication requires the %s or %s transport", TransportStreamableHTTP, TransportSSE)
+	}
+	if opts.auditLogMaxSizeMB < 0 || opts.auditLogMaxBackups < 0 {
+		return nil, errors.New("audit log max size and max backups cannot be negative")
+	}
+	if opts.watchInterval < 0 {
+		return nil, fmt.Errorf("invalid watch interval %s: must not be negative", opts.watchInterval)
+	}
+	// The watcher polls with the server token, as there is no client session
+	// to take a credential from, and notifies every session. With session
+	// authentication, sessions would learn of changes they may not see.
+	if opts.watchInterval > 0 && opts.sessionAuth {
+		return nil, errors.New("watching Portainer cannot be combined with session authentication")
+	}
+	if opts.watchInterval > 0 && token == "" {
+		return nil, errors.New("watching Portainer requires a server token")
+	}
+	if opts.watchInterval > 0 && len(opts.watchScope) == 0 {
+		opts.watchScope, _ = ParseWatchScope(DefaultWatchScope)
+	}
+
 	tools, err := toolgen.LoadToolsFromYAML(toolsPath, MinimumToolsVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load tools: %w", err)
 	}
 
+	var prompts []toolgen.PromptDefinition
+	if opts.promptsPath != "" {
+		prompts, err = toolgen.LoadPromptsFromYAML(opts.promptsPath, MinimumPromptsVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load prompts: %w", err)
+		}
+	}
+
+	cache, err := newCacheConfig(opts.cacheTTL, opts.cacheMethodTTLs)
+	if err != nil {
+		return nil, err
+	}
+	var stats *cacheStats
+	if cache != nil {
+		stats = newCacheStats()
+	}
+
+	var toolPolicy *policy
+	if opts.policyPath != "" {
+		toolPolicy, err = loadPolicy(opts.policyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load policy: %w", err)
+		}
+	}
+
+	var metrics *serverMetrics
+	clientOptions := []client.ClientOption{client.WithSkipTLSVerify(opts.skipTLSVerify)}
+	if toolPolicy.hasProxyRules() {
+		clientOptions = append(clientOptions, client.WithProxyRequestCheck(toolPolicy.checkProxyRequest))
+	}
+	if opts.metricsAddr != "" {
+		metrics = newServerMetrics(stats)
+		clientOptions = append(clientOptions, client.WithTransportWrapper(metrics.wrapTransport))
+	}
+
+	tracerProvider := opts.tracerProvider
+	var shutdownTracing func(context.Context) error
+	if tracerProvider == nil {
+		envProvider, err := newTracerProviderFromEnv(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if envProvider != nil {
+			tracerProvider, shutdownTracing = envProvider, envProvider.Shutdown
+			log.Info().Msg("Exporting traces over OTLP")
+		}
+	}
+	var tracer trace.Tracer
+	if tracerProvider != nil {
+		tracer = tracerProvider.Tracer(tracerName)
+		clientOptions = append(clientOptions, client.WithTracerProvider(tracerProvider))
+	}
+
 	var portainerClient PortainerClient
 	if opts.client != nil {
 		portainerClient = opts.client
 	} else {
-		portainerClient = client.NewPortainerClient(serverURL, token, client.WithSkipTLSVerify(opts.skipTLSVerify))
+		portainerClient = client.NewPortainerClient(serverURL, token, clientOptions...)
+	}
+	if cache != nil {
+		portainerClient = newCachingClient(portainerClient, cache, stats)
+	}
+
+	// With session authentication the server token is optional; without it
+	// there is no identity to check the Portainer version with.
+	if opts.sessionAuth && token == "" && !opts.disableVersionCheck {
+		log.Warn().Msg("No server token configured with session authentication, skipping Portainer version check")
+		opts.disableVersionCheck = true
 	}
 
 	if !opts.disableVersionCheck {
-		version, err := portainerClient.GetVersion()
+		version, err := portainerClient.GetVersion(context.Background())
 		if err != nil {
 			return nil, fmt.Errorf("failed to get Portainer server version: %w", err)
 		}
@@ -308,43 +654,277 @@ func NewPortainerMCPServer(serverURL, token, toolsPath string, options ...Server
 		}
 	}
 
-	return &PortainerMCPServer{
+	var sessionClients *sessionClientCache
+	hooks := &server.Hooks{}
+	if opts.sessionAuth {
+		factory := opts.clientFactory
+		if factory == nil {
+			factory = newDefaultClientFactory(serverURL, clientOptions...)
+		}
+		if cache != nil {
+			newClient := factory
+			factory = func(token string, jwt bool) PortainerClient {
+				return newCachingClient(newClient(token, jwt), cache, stats)
+			}
+		}
+		sessionClients = newSessionClientCache(factory)
+		hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+			sessionClients.evictSession(session.SessionID())
+		})
+	}
+
+	redactor, err := newRedactor(toolPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy: %w", err)
+	}
+
+	if opts.maxInFlight < 0 {
+		return nil, fmt.Errorf("invalid max in flight %d: must not be negative", opts.maxInFlight)
+	}
+	var rateLimits []rateLimit
+	if toolPolicy != nil {
+		rateLimits = toolPolicy.RateLimits
+	}
+
+	var confirmations *confirmationStore
+	if opts.requireConfirmation {
+		confirmations = newConfirmationStore(confirmationTokenTTL)
+	}
+
+	var audit *auditLogger
+	if opts.auditLogPath != "" {
+		audit, err = newAuditLogger(opts.auditLogPath, int64(opts.auditLogMaxSizeMB)<<20, opts.auditLogMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		audit.serverIdentity.Fingerprint = tokenFingerprint(token)
+	}
+
+	s := &PortainerMCPServer{
 		srv: server.NewMCPServer(
 			"Portainer MCP Server",
 			"0.5.1",
 			server.WithToolCapabilities(true),
+			server.WithResourceCapabilities(false, false),
+			server.WithPromptCapabilities(false),
 			server.WithLogging(),
+			server.WithHooks(hooks),
 		),
-		cli:      portainerClient,
-		tools:    tools,
-		readOnly: opts.readOnly,
-	}, nil
+		cli:             portainerClient,
+		tools:           tools,
+		readOnly:        opts.readOnly,
+		dryRun:          opts.dryRun,
+		transport:       opts.transport,
+		listenAddr:      opts.listenAddr,
+		sessionClients:  sessionClients,
+		audit:           audit,
+		confirmations:   confirmations,
+		policy:          toolPolicy,
+		redactor:        redactor,
+		limiter:         newRateLimiter(opts.maxInFlight, rateLimits),
+		cacheStats:      stats,
+		metrics:         metrics,
+		metricsAddr:     opts.metricsAddr,
+		tracer:          tracer,
+		shutdownTracing: shutdownTracing,
+		prompts:         prompts,
+		granularTools:   opts.granularTools,
+	}
+	if opts.watchInterval > 0 {
+		s.watcher = newWatcher(s, opts.watchInterval, opts.watchScope)
+	}
+	s.logEffectivePolicy(opts.policyPath)
+
+	return s, nil
 }
 
-// Start begins listening for MCP protocol messages on standard input/output.
-// It handles SIGINT and SIGTERM for graceful shutdown.
+// Start begins listening for MCP protocol messages on the configured transport:
+// standard input/output, streamable HTTP, or SSE.
+// When metrics are enabled, they are served on their own listener while the
+// transport runs. It handles SIGINT and SIGTERM for graceful shutdown, and
+// closes the audit log, if any, logs the client cache statistics, and flushes
+// the pending spans once the transport has stopped. The watcher, if any, polls
+// Portainer until then.
 func (s *PortainerMCPServer) Start() error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	if s.shutdownTracing != nil {
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := s.shutdownTracing(shutdownCtx); err != nil {
+				log.Error().Err(err).Msg("Failed to flush traces")
+			}
+		}()
+	}
+	if s.cacheStats != nil {
+		defer s.cacheStats.logSummary()
+	}
+	if s.metrics != nil {
+		stopMetrics, err := s.serveMetrics(s.metricsAddr)
+		if err != nil {
+			return err
+		}
+		defer stopMetrics()
+	}
+	if s.audit != nil {
+		defer func() {
+			if err := s.audit.close(); err != nil {
+				log.Error().Err(err).Msg("Failed to close audit log")
+			}
+		}()
+	}
+
+	if s.watcher != nil {
+		go s.watcher.run(ctx)
+	}
+
+	return s.serve(ctx)
+}
+
+// serve runs the configured transport until it fails or ctx is cancelled.
+func (s *PortainerMCPServer) serve(ctx context.Context) error {
+	switch s.transport {
+	case TransportStreamableHTTP:
+		httpSrv := &http.Server{Addr: s.listenAddr}
+		transport := server.NewStreamableHTTPServer(s.srv,
+			server.WithStreamableHTTPServer(httpSrv),
+			server.WithHTTPContextFunc(httpContextFunc),
+		)
+		mux := http.NewServeMux()
+		mux.Handle(streamableHTTPEndpoint, s.authenticate(transport))
+		httpSrv.Handler = mux
+
+		log.Info().Str("addr", s.listenAddr).Str("endpoint", streamableHTTPEndpoint).Msg("Serving MCP over streamable HTTP")
+		return serveHTTPTransport(ctx, httpSrv, transport)
+	case TransportSSE:
+		httpSrv := &http.Server{Addr: s.listenAddr}
+		transport := server.NewSSEServer(s.srv,
+			server.WithHTTPServer(httpSrv),
+			server.WithSSEContextFunc(httpContextFunc),
+		)
+		httpSrv.Handler = s.authenticate(transport)
+
+		log.Info().Str("addr", s.listenAddr).Msg("Serving MCP over SSE on /sse and /message")
+		return serveHTTPTransport(ctx, httpSrv, transport)
+	default:
+		return serveStdio(ctx, s.srv)
+	}
+}
+
+// authenticate wraps a network transport handler so that requests without a
+// Portainer credential are rejected when session authentication is enabled.
+func (s *PortainerMCPServer) authenticate(next http.Handler) http.Handler {
+	if s.sessionClients == nil {
+		return next
+	}
+	return requireCredential(next)
+}
+
+// serveStdio serves MCP over standard input/output until it fails or ctx is cancelled.
+func serveStdio(ctx context.Context, srv *server.MCPServer) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ServeStdio(srv)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		log.Info().Msg("Received shutdown signal, stopping server")
+		return nil
+	}
+}
+
+// transportShutdowner is implemented by the mcp-go network transports. Their
+// Shutdown closes open sessions and then shuts down the underlying http.Server.
+type transportShutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// serveHTTPTransport listens on httpSrv until it fails or ctx is cancelled.
+// On cancellation the transport is shut down, giving in-flight requests
+// shutdownTimeout to complete before the listener is closed.
+func serveHTTPTransport(ctx context.Context, httpSrv *http.Server, transport transportShutdowner) error {
 	errCh := make(chan error, 1)
 	go func() {
-		errCh <- server.ServeStdio(s.srv)
+		errCh <- httpSrv.ListenAndServe()
 	}()
 
 	select {
 	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
 		return err
 	case <-ctx.Done():
 		log.Info().Msg("Received shutdown signal, stopping server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := transport.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down transport: %w", err)
+		}
 		return nil
 	}
 }
 
-// addToolIfExists adds a tool to the server if it exists in the tools map
+// isValidTransport checks if a given string is a supported transport name.
+func isValidTransport(transport string) bool {
+	switch transport {
+	case TransportStdio, TransportStreamableHTTP, TransportSSE:
+		return true
+	default:
+		return false
+	}
+}
+
+// addToolIfExists adds a tool to the server if it exists in the tools map and
+// the policy allows it. List tools are paginated, read-only tools accept the
+// refresh argument when caching is enabled, other tools accept the dryRun argument, destructive tools require a confirmation token when
+// confirmation is enabled, calls are checked against the environment scope,
+// secrets are redacted from results, and calls beyond the configured limits are rejected.
 func (s *PortainerMCPServer) addToolIfExists(toolName string, handler server.ToolHandlerFunc) {
+	if !s.policy.allowsTool(toolName) || (toolName == ToolSnapshotAllEnvironments && s.policy.scoped()) {
+		log.Debug().Str("tool", toolName).Msg("Tool denied by policy, will not be registered for MCP usage")
+		return
+	}
 	if tool, exists := s.tools[toolName]; exists {
-		s.srv.AddTool(tool, handler)
+		if isListTool(toolName) {
+			handler = s.withPagination(hasOwnFilter(tool), handler)
+			tool = withPaginationParameters(tool)
+		}
+		if s.cacheStats != nil && isReadOnlyTool(tool) {
+			tool = withRefreshParameter(tool)
+			handler = withCacheRefreshArgument(handler)
+		}
+		if !isReadOnlyTool(tool) {
+			tool = withDryRunParameter(tool)
+			handler = s.withDryRun(handler)
+		}
+		if s.requiresConfirmation(toolName) {
+			tool = withConfirmationTokenParameter(tool)
+			handler = s.withConfirmation(handler)
+		}
+		if s.policy.scoped() {
+			handler = s.withEnvironmentScope(toolName, handler)
+		}
+		if s.redactor != nil {
+			target := s.policy.target(toolName)
+			if s.policy.allowsOptOut(target) {
+				tool = withIncludeSecretsParameter(tool)
+			}
+			handler = s.withRedaction(target, handler)
+		}
+		if params := nameParams(toolName, tool); len(params) > 0 {
+			tool = withNameParameters(tool, params)
+			handler = s.withNameResolution(params, handler)
+		}
+		if s.limiter != nil {
+			handler = s.withRateLimit(s.policy.target(toolName), handler)
+		}
+		s.srv.AddTool(tool, s.withTracing(toolName, nil, s.withMetrics(toolName, nil, s.withAudit(toolName, handler))))
 	} else {
 		log.Warn().Str("tool", toolName).Msg("Tool not found, will not be registered for MCP usage")
 	}
@@ -3,7 +3,9 @@ package mcp
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -196,3 +198,91 @@ func TestAddToolIfExists(t *testing.T) {
 		})
 	}
 }
+
+// TestNewPortainerMCPServerTransport verifies transport option validation and defaults.
+func TestNewPortainerMCPServerTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []ServerOption
+		wantTransport string
+		wantAddr      string
+		expectError   bool
+	}{
+		{
+			name:          "defaults to stdio",
+			wantTransport: TransportStdio,
+			wantAddr:      DefaultListenAddr,
+		},
+		{
+			name:          "streamable http with custom address",
+			options:       []ServerOption{WithTransport(TransportStreamableHTTP), WithListenAddr("127.0.0.1:9090")},
+			wantTransport: TransportStreamableHTTP,
+			wantAddr:      "127.0.0.1:9090",
+		},
+		{
+			name:          "sse with empty address falls back to default",
+			options:       []ServerOption{WithTransport(TransportSSE), WithListenAddr("")},
+			wantTransport: TransportSSE,
+			wantAddr:      DefaultListenAddr,
+		},
+		{
+			name:        "unsupported transport",
+			options:     []ServerOption{WithTransport("websocket")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]ServerOption{WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true)}, tt.options...)
+
+			s, err := NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml", options...)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unsupported transport")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTransport, s.transport)
+			assert.Equal(t, tt.wantAddr, s.listenAddr)
+		})
+	}
+}
+
+// TestServeNetworkTransportsShutdown verifies that the streamable HTTP and SSE
+// transports start listening and stop cleanly when the context is cancelled.
+func TestServeNetworkTransportsShutdown(t *testing.T) {
+	for _, transport := range []string{TransportStreamableHTTP, TransportSSE} {
+		t.Run(transport, func(t *testing.T) {
+			s := &PortainerMCPServer{
+				srv:        server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true)),
+				transport:  transport,
+				listenAddr: "127.0.0.1:0",
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- s.serve(ctx)
+			}()
+
+			cancel()
+			select {
+			case err := <-errCh:
+				assert.NoError(t, err)
+			case <-time.After(shutdownTimeout + time.Second):
+				t.Fatal("transport did not shut down")
+			}
+		})
+	}
+}
+
+// TestServeHTTPTransportListenError verifies that listener errors are returned.
+func TestServeHTTPTransportListenError(t *testing.T) {
+	httpSrv := &http.Server{Addr: "invalid-address"}
+	transport := server.NewStreamableHTTPServer(server.NewMCPServer("test", "0.0.1"), server.WithStreamableHTTPServer(httpSrv))
+
+	err := serveHTTPTransport(context.Background(), httpSrv, transport)
+	assert.Error(t, err)
+}
@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// headerAPIKey carries a Portainer API key on network transports.
+	headerAPIKey = "X-API-Key"
+	// headerAuthorization carries a Portainer API key or JWT as a bearer token.
+	headerAuthorization = "Authorization"
+	// portainerAPIKeyPrefix is the prefix of Portainer-issued API keys.
+	portainerAPIKeyPrefix = "ptr_"
+	// sessionClientIdleTTL is how long an unused per-session client is kept cached.
+	sessionClientIdleTTL = 30 * time.Minute
+)
+
+// ClientFactory builds a [PortainerClient] for a single caller identity.
+// The token is a Portainer API key, or a user JWT when jwt is true.
+type ClientFactory func(token string, jwt bool) PortainerClient
+
+// portainerCredential is the Portainer identity presented by an MCP client.
+type portainerCredential struct {
+	token string
+	jwt   bool
+}
+
+// credentialContextKey is the context key under which a portainerCredential is stored.
+type credentialContextKey struct{}
+
+// credentialFromRequest extracts a Portainer credential from the request headers.
+// An X-API-Key header is always treated as an API key. A bearer token in the
+// Authorization header is treated as an API key if it has the "ptr_" prefix,
+// and as a JWT otherwise.
+func credentialFromRequest(r *http.Request) (portainerCredential, bool) {
+	if key := strings.TrimSpace(r.Header.Get(headerAPIKey)); key != "" {
+		return portainerCredential{token: key}, true
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get(headerAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return portainerCredential{}, false
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return portainerCredential{}, false
+	}
+
+	return portainerCredential{token: token, jwt: !strings.HasPrefix(token, portainerAPIKeyPrefix)}, true
+}
+
+// withCredential returns a copy of ctx carrying the given credential.
+func withCredential(ctx context.Context, cred portainerCredential) context.Context {
+	return context.WithValue(ctx, credentialContextKey{}, cred)
+}
+
+// credentialFromContext returns the credential stored in ctx, if any.
+func credentialFromContext(ctx context.Context) (portainerCredential, bool) {
+	cred, ok := ctx.Value(credentialContextKey{}).(portainerCredential)
+	return cred, ok
+}
+
+// httpContextFunc copies the Portainer credential of an HTTP request into the
+// context passed to MCP handlers. It is used by both network transports.
+func httpContextFunc(ctx context.Context, r *http.Request) context.Context {
+	if cred, ok := credentialFromRequest(r); ok {
+		return withCredential(ctx, cred)
+	}
+	return ctx
+}
+
+// requireCredential rejects HTTP requests that do not carry a Portainer credential.
+func requireCredential(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := credentialFromRequest(r); !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="portainer"`)
+			http.Error(w, "missing Portainer credential: send an X-API-Key header or an Authorization: Bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sessionClientCache caches one PortainerClient per MCP session and credential,
+// so that repeated tool calls from a session reuse the same HTTP connections.
+// Entries unused for longer than idleTTL are evicted lazily on access.
+type sessionClientCache struct {
+	mu      sync.Mutex
+	factory ClientFactory
+	idleTTL time.Duration
+	now     func() time.Time
+	entries map[string]*sessionClientEntry
+}
+
+// sessionClientEntry is a cached client and the time it was last used.
+type sessionClientEntry struct {
+	sessionID string
+	client    PortainerClient
+	lastUsed  time.Time
+}
+
+// newSessionClientCache creates an empty cache that builds clients with factory.
+func newSessionClientCache(factory ClientFactory) *sessionClientCache {
+	return &sessionClientCache{
+		factory: factory,
+		idleTTL: sessionClientIdleTTL,
+		now:     time.Now,
+		entries: make(map[string]*sessionClientEntry),
+	}
+}
+
+// get returns the cached client for the session and credential, creating it if needed.
+// The cache key includes a digest of the token so that a session that switches
+// credentials never reuses a client built for the previous identity.
+func (c *sessionClientCache) get(sessionID string, cred portainerCredential) PortainerClient {
+	digest := sha256.Sum256([]byte(cred.token))
+	key := sessionID + ":" + hex.EncodeToString(digest[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if now.Sub(entry.lastUsed) > c.idleTTL {
+			delete(c.entries, k)
+		}
+	}
+
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &sessionClientEntry{sessionID: sessionID, client: c.factory(cred.token, cred.jwt)}
+		c.entries[key] = entry
+	}
+	entry.lastUsed = now
+
+	return entry.client
+}
+
+// evictSession removes all cached clients of the given session.
+func (c *sessionClientCache) evictSession(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, entry := range c.entries {
+		if entry.sessionID == sessionID {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// len returns the number of cached clients.
+func (c *sessionClientCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// newDefaultClientFactory returns a ClientFactory that builds real Portainer clients for serverURL.
+func newDefaultClientFactory(serverURL string, options ...client.ClientOption) ClientFactory {
+	return func(token string, jwt bool) PortainerClient {
+		return client.NewPortainerClient(serverURL, token, append([]client.ClientOption{client.WithJWT(jwt)}, options...)...)
+	}
+}
+
+// client returns the PortainerClient to use for a tool call. When per-session
+// authentication is enabled and the call carries a credential, a client bound
+// to that identity is returned; otherwise the server-wide client is used.
+// During a dry run, the client records writes instead of sending them.
+func (s *PortainerMCPServer) client(ctx context.Context) PortainerClient {
+	cli := s.identityClient(ctx)
+	if plan, ok := dryRunPlanFromContext(ctx); ok {
+		return newDryRunClient(cli, plan)
+	}
+	return cli
+}
+
+// identityClient returns the server-wide client or the per-session client of the caller.
+func (s *PortainerMCPServer) identityClient(ctx context.Context) PortainerClient {
+	if s.sessionClients == nil {
+		return s.cli
+	}
+
+	cred, ok := credentialFromContext(ctx)
+	if !ok {
+		return s.cli
+	}
+
+	sessionID := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+
+	return s.sessionClients.get(sessionID, cred)
+}
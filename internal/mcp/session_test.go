@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession is a minimal server.ClientSession used to attach a session ID to a context.
+type testSession struct {
+	id string
+}
+
+func (s testSession) SessionID() string                                   { return s.id }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+
+func TestCredentialFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    portainerCredential
+		wantOK  bool
+	}{
+		{
+			name:    "api key header",
+			headers: map[string]string{"X-API-Key": "ptr_abc"},
+			want:    portainerCredential{token: "ptr_abc"},
+			wantOK:  true,
+		},
+		{
+			name:    "api key header without prefix is still an api key",
+			headers: map[string]string{"X-API-Key": "abc"},
+			want:    portainerCredential{token: "abc"},
+			wantOK:  true,
+		},
+		{
+			name:    "api key header takes precedence over bearer",
+			headers: map[string]string{"X-API-Key": "ptr_abc", "Authorization": "Bearer eyJ.a.b"},
+			want:    portainerCredential{token: "ptr_abc"},
+			wantOK:  true,
+		},
+		{
+			name:    "bearer api key",
+			headers: map[string]string{"Authorization": "Bearer ptr_abc"},
+			want:    portainerCredential{token: "ptr_abc"},
+			wantOK:  true,
+		},
+		{
+			name:    "bearer jwt",
+			headers: map[string]string{"Authorization": "bearer eyJ.a.b"},
+			want:    portainerCredential{token: "eyJ.a.b", jwt: true},
+			wantOK:  true,
+		},
+		{
+			name:    "basic auth is ignored",
+			headers: map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+		},
+		{
+			name:    "empty bearer",
+			headers: map[string]string{"Authorization": "Bearer  "},
+		},
+		{
+			name: "no credential",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			got, ok := credentialFromRequest(req)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+
+			ctx := httpContextFunc(context.Background(), req)
+			fromCtx, ok := credentialFromContext(ctx)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, fromCtx)
+		})
+	}
+}
+
+func TestRequireCredential(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := requireCredential(next)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("X-API-Key", "ptr_abc")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestSessionClientCache(t *testing.T) {
+	var built []portainerCredential
+	cache := newSessionClientCache(func(token string, jwt bool) PortainerClient {
+		built = append(built, portainerCredential{token: token, jwt: jwt})
+		return new(MockPortainerClient)
+	})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	alice := portainerCredential{token: "ptr_alice"}
+	bob := portainerCredential{token: "eyJ.b.c", jwt: true}
+
+	first := cache.get("s1", alice)
+	assert.Same(t, first, cache.get("s1", alice), "same session and credential should reuse the client")
+	assert.NotSame(t, first, cache.get("s1", bob), "a new credential should get its own client")
+	assert.NotSame(t, first, cache.get("s2", alice), "a new session should get its own client")
+	assert.Equal(t, []portainerCredential{alice, bob, alice}, built)
+	assert.Equal(t, 3, cache.len())
+
+	cache.evictSession("s1")
+	assert.Equal(t, 1, cache.len())
+
+	now = now.Add(sessionClientIdleTTL + time.Second)
+	cache.get("s3", alice)
+	assert.Equal(t, 1, cache.len(), "idle clients should be evicted on access")
+}
+
+func TestServerClientSelection(t *testing.T) {
+	serverClient := new(MockPortainerClient)
+	sessionClient := new(MockPortainerClient)
+
+	var gotToken string
+	s, err := NewPortainerMCPServer("https://portainer.example.com", "", "testdata/valid_tools.yaml",
+		WithClient(serverClient),
+		WithTransport(TransportStreamableHTTP),
+		WithSessionAuth(true),
+		WithClientFactory(func(token string, jwt bool) PortainerClient {
+			gotToken = token
+			return sessionClient
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Same(t, serverClient, s.client(context.Background()), "calls without a credential use the server client")
+
+	ctx := s.srv.WithContext(withCredential(context.Background(), portainerCredential{token: "ptr_user"}), testSession{id: "abc"})
+	assert.Same(t, sessionClient, s.client(ctx))
+	assert.Equal(t, "ptr_user", gotToken)
+	assert.Equal(t, 1, s.sessionClients.len())
+
+	withoutAuth := &PortainerMCPServer{cli: serverClient}
+	assert.Same(t, serverClient, withoutAuth.client(ctx), "credentials are ignored when session auth is disabled")
+}
+
+func TestSessionAuthUnregisterEvictsClients(t *testing.T) {
+	s, err := NewPortainerMCPServer("https://portainer.example.com", "", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)),
+		WithTransport(TransportSSE),
+		WithSessionAuth(true),
+		WithClientFactory(func(string, bool) PortainerClient { return new(MockPortainerClient) }),
+	)
+	require.NoError(t, err)
+
+	session := testSession{id: "abc"}
+	require.NoError(t, s.srv.RegisterSession(context.Background(), session))
+	s.sessionClients.get(session.id, portainerCredential{token: "ptr_user"})
+	require.Equal(t, 1, s.sessionClients.len())
+
+	s.srv.UnregisterSession(context.Background(), session.id)
+	assert.Equal(t, 0, s.sessionClients.len())
+}
+
+func TestWithSessionAuthRequiresNetworkTransport(t *testing.T) {
+	_, err := NewPortainerMCPServer("https://portainer.example.com", "", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)),
+		WithSessionAuth(true),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session authentication requires")
+}
+
+// Compile-time check that testSession satisfies the interface used by the server.
+var _ server.ClientSession = testSession{}
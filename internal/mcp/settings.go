@@ -22,9 +22,9 @@ func (s *PortainerMCPServer) AddSettingsFeatures() {
 // HandleGetSettings returns an MCP tool handler that retrieves settings.
 func (s *PortainerMCPServer) HandleGetSettings() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		settings, err := s.cli.GetSettings()
+		settings, err := s.client(ctx).GetSettings(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get settings", err), nil
+			return toolErrorFromErr("failed to get settings", err), nil
 		}
 
 		return jsonResult(settings, "failed to marshal settings")
@@ -46,16 +46,16 @@ func (s *PortainerMCPServer) HandleUpdateSettings() server.ToolHandlerFunc {
 
 		settingsJSON, err := parser.GetString("settings", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid settings parameter", err), nil
+			return toolErrorFromErr("invalid settings parameter", err), nil
 		}
 
 		var settingsMap map[string]interface{}
 		if err := json.Unmarshal([]byte(settingsJSON), &settingsMap); err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to parse settings JSON", err), nil
+			return toolErrorFromErr("failed to parse settings JSON", err), nil
 		}
 
-		if err := s.cli.UpdateSettings(settingsMap); err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update settings", err), nil
+		if err := s.client(ctx).UpdateSettings(ctx, settingsMap); err != nil {
+			return toolErrorFromErr("failed to update settings", err), nil
 		}
 
 		return mcp.NewToolResultText("Settings updated successfully"), nil
@@ -65,9 +65,9 @@ func (s *PortainerMCPServer) HandleUpdateSettings() server.ToolHandlerFunc {
 // HandleGetPublicSettings handles the getPublicSettings tool call.
 func (s *PortainerMCPServer) HandleGetPublicSettings() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		publicSettings, err := s.cli.GetPublicSettings()
+		publicSettings, err := s.client(ctx).GetPublicSettings(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get public settings", err), nil
+			return toolErrorFromErr("failed to get public settings", err), nil
 		}
 
 		return jsonResult(publicSettings, "failed to marshal public settings")
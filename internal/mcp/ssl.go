@@ -23,9 +23,9 @@ func (s *PortainerMCPServer) AddSSLFeatures() {
 // HandleGetSSLSettings handles the getSSLSettings tool call.
 func (s *PortainerMCPServer) HandleGetSSLSettings() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		sslSettings, err := s.cli.GetSSLSettings()
+		sslSettings, err := s.client(ctx).GetSSLSettings(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get SSL settings", err), nil
+			return toolErrorFromErr("failed to get SSL settings", err), nil
 		}
 
 		return jsonResult(sslSettings, "failed to marshal SSL settings")
@@ -39,12 +39,12 @@ func (s *PortainerMCPServer) HandleUpdateSSLSettings() server.ToolHandlerFunc {
 
 		cert, err := parser.GetString("cert", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid cert parameter", err), nil
+			return toolErrorFromErr("invalid cert parameter", err), nil
 		}
 
 		key, err := parser.GetString("key", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid key parameter", err), nil
+			return toolErrorFromErr("invalid key parameter", err), nil
 		}
 
 		var httpEnabled *bool
@@ -52,7 +52,7 @@ func (s *PortainerMCPServer) HandleUpdateSSLSettings() server.ToolHandlerFunc {
 			if val, ok := args["httpEnabled"]; ok && val != nil {
 				boolVal, ok := val.(bool)
 				if !ok {
-					return mcp.NewToolResultErrorFromErr("invalid httpEnabled parameter", fmt.Errorf("httpEnabled must be a boolean")), nil
+					return toolErrorFromErr("invalid httpEnabled parameter", fmt.Errorf("httpEnabled must be a boolean")), nil
 				}
 				httpEnabled = &boolVal
 			}
@@ -61,22 +61,22 @@ func (s *PortainerMCPServer) HandleUpdateSSLSettings() server.ToolHandlerFunc {
 		if cert != "" {
 			block, _ := pem.Decode([]byte(cert))
 			if block == nil {
-				return mcp.NewToolResultErrorFromErr("invalid cert parameter", fmt.Errorf("certificate is not valid PEM format")), nil
+				return toolErrorFromErr("invalid cert parameter", fmt.Errorf("certificate is not valid PEM format")), nil
 			}
 			if _, err := x509.ParseCertificate(block.Bytes); err != nil {
-				return mcp.NewToolResultErrorFromErr("invalid cert parameter", fmt.Errorf("certificate is not a valid X.509 certificate: %w", err)), nil
+				return toolErrorFromErr("invalid cert parameter", fmt.Errorf("certificate is not a valid X.509 certificate: %w", err)), nil
 			}
 		}
 
 		if key != "" {
 			block, _ := pem.Decode([]byte(key))
 			if block == nil {
-				return mcp.NewToolResultErrorFromErr("invalid key parameter", fmt.Errorf("key is not valid PEM format")), nil
+				return toolErrorFromErr("invalid key parameter", fmt.Errorf("key is not valid PEM format")), nil
 			}
 		}
 
-		if err := s.cli.UpdateSSLSettings(cert, key, httpEnabled); err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update SSL settings", err), nil
+		if err := s.client(ctx).UpdateSSLSettings(ctx, cert, key, httpEnabled); err != nil {
+			return toolErrorFromErr("failed to update SSL settings", err), nil
 		}
 
 		return mcp.NewToolResultText("SSL settings updated successfully"), nil
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/toolgen"
 )
 
@@ -32,9 +33,14 @@ func (s *PortainerMCPServer) AddStackFeatures() {
 // HandleGetStacks returns an MCP tool handler that retrieves stacks.
 func (s *PortainerMCPServer) HandleGetStacks() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		stacks, err := s.cli.GetStacks()
+		stacks, err := s.client(ctx).GetStacks(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get stacks", err), nil
+			return toolErrorFromErr("failed to get stacks", err), nil
+		}
+
+		stacks, err = s.filterEdgeStacks(ctx, stacks)
+		if err != nil {
+			return toolErrorFromErr("failed to check environment scope", err), nil
 		}
 
 		return jsonResult(stacks, "failed to marshal stacks")
@@ -44,9 +50,14 @@ func (s *PortainerMCPServer) HandleGetStacks() server.ToolHandlerFunc {
 // HandleListRegularStacks returns an MCP tool handler that lists regular stacks.
 func (s *PortainerMCPServer) HandleListRegularStacks() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		stacks, err := s.cli.GetRegularStacks()
+		stacks, err := s.client(ctx).GetRegularStacks(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to list regular stacks", err), nil
+			return toolErrorFromErr("failed to list regular stacks", err), nil
+		}
+
+		stacks, err = s.filterRegularStacks(ctx, stacks)
+		if err != nil {
+			return toolErrorFromErr("failed to check environment scope", err), nil
 		}
 
 		return jsonResult(stacks, "failed to marshal regular stacks")
@@ -60,15 +71,15 @@ func (s *PortainerMCPServer) HandleGetStackFile() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		stackFile, err := s.cli.GetStackFile(id)
+		stackFile, err := s.client(ctx).GetStackFile(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get stack file", err), nil
+			return toolErrorFromErr("failed to get stack file", err), nil
 		}
 
 		return mcp.NewToolResultText(stackFile), nil
@@ -82,7 +93,7 @@ func (s *PortainerMCPServer) HandleCreateStack() server.ToolHandlerFunc {
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -90,7 +101,7 @@ func (s *PortainerMCPServer) HandleCreateStack() server.ToolHandlerFunc {
 
 		file, err := parser.GetString("file", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid file parameter", err), nil
+			return toolErrorFromErr("invalid file parameter", err), nil
 		}
 		if err := validateComposeYAML(file); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -98,12 +109,12 @@ func (s *PortainerMCPServer) HandleCreateStack() server.ToolHandlerFunc {
 
 		environmentGroupIds, err := parser.GetArrayOfIntegers("environmentGroupIds", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentGroupIds parameter", err), nil
+			return toolErrorFromErr("invalid environmentGroupIds parameter", err), nil
 		}
 
-		id, err := s.cli.CreateStack(name, file, environmentGroupIds)
+		id, err := s.client(ctx).CreateStack(ctx, name, file, environmentGroupIds)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("error creating stack", err), nil
+			return toolErrorFromErr("error creating stack", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Stack created successfully with ID: %d", id)), nil
@@ -117,7 +128,7 @@ func (s *PortainerMCPServer) HandleUpdateStack() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -125,7 +136,7 @@ func (s *PortainerMCPServer) HandleUpdateStack() server.ToolHandlerFunc {
 
 		file, err := parser.GetString("file", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid file parameter", err), nil
+			return toolErrorFromErr("invalid file parameter", err), nil
 		}
 		if err := validateComposeYAML(file); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -133,12 +144,12 @@ func (s *PortainerMCPServer) HandleUpdateStack() server.ToolHandlerFunc {
 
 		environmentGroupIds, err := parser.GetArrayOfIntegers("environmentGroupIds", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentGroupIds parameter", err), nil
+			return toolErrorFromErr("invalid environmentGroupIds parameter", err), nil
 		}
 
-		err = s.cli.UpdateStack(id, file, environmentGroupIds)
+		err = s.client(ctx).UpdateStack(ctx, id, file, environmentGroupIds)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update stack", err), nil
+			return toolErrorFromErr("failed to update stack", err), nil
 		}
 
 		return mcp.NewToolResultText("Stack updated successfully"), nil
@@ -152,15 +163,15 @@ func (s *PortainerMCPServer) HandleInspectStack() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		stack, err := s.cli.InspectStack(id)
+		stack, err := s.client(ctx).InspectStack(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to inspect stack", err), nil
+			return toolErrorFromErr("failed to inspect stack", err), nil
 		}
 
 		return jsonResult(stack, "failed to marshal stack")
@@ -174,7 +185,7 @@ func (s *PortainerMCPServer) HandleDeleteStack() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -182,7 +193,7 @@ func (s *PortainerMCPServer) HandleDeleteStack() server.ToolHandlerFunc {
 
 		endpointID, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", endpointID); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -190,12 +201,12 @@ func (s *PortainerMCPServer) HandleDeleteStack() server.ToolHandlerFunc {
 
 		removeVolumes, err := parser.GetBoolean("removeVolumes", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid removeVolumes parameter", err), nil
+			return toolErrorFromErr("invalid removeVolumes parameter", err), nil
 		}
 
-		err = s.cli.DeleteStack(id, endpointID, removeVolumes)
+		err = s.client(ctx).DeleteStack(ctx, id, endpointID, removeVolumes)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete stack", err), nil
+			return toolErrorFromErr("failed to delete stack", err), nil
 		}
 
 		return mcp.NewToolResultText("Stack deleted successfully"), nil
@@ -209,15 +220,15 @@ func (s *PortainerMCPServer) HandleInspectStackFile() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		content, err := s.cli.InspectStackFile(id)
+		content, err := s.client(ctx).InspectStackFile(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to inspect stack file", err), nil
+			return toolErrorFromErr("failed to inspect stack file", err), nil
 		}
 
 		return mcp.NewToolResultText(content), nil
@@ -231,7 +242,7 @@ func (s *PortainerMCPServer) HandleUpdateStackGit() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -239,7 +250,7 @@ func (s *PortainerMCPServer) HandleUpdateStackGit() server.ToolHandlerFunc {
 
 		endpointID, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", endpointID); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -247,17 +258,17 @@ func (s *PortainerMCPServer) HandleUpdateStackGit() server.ToolHandlerFunc {
 
 		referenceName, err := parser.GetString("referenceName", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid referenceName parameter", err), nil
+			return toolErrorFromErr("invalid referenceName parameter", err), nil
 		}
 
 		prune, err := parser.GetBoolean("prune", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid prune parameter", err), nil
+			return toolErrorFromErr("invalid prune parameter", err), nil
 		}
 
-		stack, err := s.cli.UpdateStackGit(id, endpointID, referenceName, prune)
+		stack, err := s.client(ctx).UpdateStackGit(ctx, id, endpointID, referenceName, prune)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update stack git", err), nil
+			return toolErrorFromErr("failed to update stack git", err), nil
 		}
 
 		return jsonResult(stack, "failed to marshal stack")
@@ -271,7 +282,7 @@ func (s *PortainerMCPServer) HandleRedeployStackGit() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -279,7 +290,7 @@ func (s *PortainerMCPServer) HandleRedeployStackGit() server.ToolHandlerFunc {
 
 		endpointID, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", endpointID); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -287,17 +298,27 @@ func (s *PortainerMCPServer) HandleRedeployStackGit() server.ToolHandlerFunc {
 
 		pullImage, err := parser.GetBoolean("pullImage", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid pullImage parameter", err), nil
+			return toolErrorFromErr("invalid pullImage parameter", err), nil
 		}
 
 		prune, err := parser.GetBoolean("prune", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid prune parameter", err), nil
+			return toolErrorFromErr("invalid prune parameter", err), nil
 		}
 
-		stack, err := s.cli.RedeployStackGit(id, endpointID, pullImage, prune)
+		message := fmt.Sprintf("Redeploying stack %d", id)
+		if pullImage {
+			message += " and pulling its images"
+		}
+		var stack models.RegularStack
+		progress := s.newProgressReporter(ctx, request)
+		err = progress.run(message, func() error {
+			var err error
+			stack, err = s.client(ctx).RedeployStackGit(ctx, id, endpointID, pullImage, prune)
+			return err
+		})
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to redeploy stack", err), nil
+			return toolErrorFromErr("failed to redeploy stack", err), nil
 		}
 
 		return jsonResult(stack, "failed to marshal stack")
@@ -311,7 +332,7 @@ func (s *PortainerMCPServer) HandleStartStack() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -319,15 +340,15 @@ func (s *PortainerMCPServer) HandleStartStack() server.ToolHandlerFunc {
 
 		endpointID, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", endpointID); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		stack, err := s.cli.StartStack(id, endpointID)
+		stack, err := s.client(ctx).StartStack(ctx, id, endpointID)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to start stack", err), nil
+			return toolErrorFromErr("failed to start stack", err), nil
 		}
 
 		return jsonResult(stack, "failed to marshal stack")
@@ -341,7 +362,7 @@ func (s *PortainerMCPServer) HandleStopStack() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -349,15 +370,15 @@ func (s *PortainerMCPServer) HandleStopStack() server.ToolHandlerFunc {
 
 		endpointID, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", endpointID); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		stack, err := s.cli.StopStack(id, endpointID)
+		stack, err := s.client(ctx).StopStack(ctx, id, endpointID)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to stop stack", err), nil
+			return toolErrorFromErr("failed to stop stack", err), nil
 		}
 
 		return jsonResult(stack, "failed to marshal stack")
@@ -371,7 +392,7 @@ func (s *PortainerMCPServer) HandleMigrateStack() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -379,7 +400,7 @@ func (s *PortainerMCPServer) HandleMigrateStack() server.ToolHandlerFunc {
 
 		endpointID, err := parser.GetInt("environmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return toolErrorFromErr("invalid environmentId parameter", err), nil
 		}
 		if err := validatePositiveID("environmentId", endpointID); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -387,7 +408,7 @@ func (s *PortainerMCPServer) HandleMigrateStack() server.ToolHandlerFunc {
 
 		targetEndpointID, err := parser.GetInt("targetEnvironmentId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid targetEnvironmentId parameter", err), nil
+			return toolErrorFromErr("invalid targetEnvironmentId parameter", err), nil
 		}
 		if err := validatePositiveID("targetEnvironmentId", targetEndpointID); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -395,12 +416,12 @@ func (s *PortainerMCPServer) HandleMigrateStack() server.ToolHandlerFunc {
 
 		name, err := parser.GetString("name", false)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 
-		stack, err := s.cli.MigrateStack(id, endpointID, targetEndpointID, name)
+		stack, err := s.client(ctx).MigrateStack(ctx, id, endpointID, targetEndpointID, name)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to migrate stack", err), nil
+			return toolErrorFromErr("failed to migrate stack", err), nil
 		}
 
 		return jsonResult(stack, "failed to marshal stack")
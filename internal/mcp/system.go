@@ -15,9 +15,9 @@ func (s *PortainerMCPServer) AddSystemFeatures() {
 // HandleGetSystemStatus returns an MCP tool handler that retrieves system status.
 func (s *PortainerMCPServer) HandleGetSystemStatus() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		status, err := s.cli.GetSystemStatus()
+		status, err := s.client(ctx).GetSystemStatus(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get system status", err), nil
+			return toolErrorFromErr("failed to get system status", err), nil
 		}
 
 		return jsonResult(status, "failed to marshal system status")
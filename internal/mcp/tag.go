@@ -22,9 +22,9 @@ func (s *PortainerMCPServer) AddTagFeatures() {
 // HandleGetEnvironmentTags returns an MCP tool handler that retrieves environment tags.
 func (s *PortainerMCPServer) HandleGetEnvironmentTags() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		environmentTags, err := s.cli.GetEnvironmentTags()
+		environmentTags, err := s.client(ctx).GetEnvironmentTags(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get environment tags", err), nil
+			return toolErrorFromErr("failed to get environment tags", err), nil
 		}
 
 		return jsonResult(environmentTags, "failed to marshal environment tags")
@@ -38,15 +38,15 @@ func (s *PortainerMCPServer) HandleCreateEnvironmentTag() server.ToolHandlerFunc
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		id, err := s.cli.CreateEnvironmentTag(name)
+		id, err := s.client(ctx).CreateEnvironmentTag(ctx, name)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create environment tag", err), nil
+			return toolErrorFromErr("failed to create environment tag", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Environment tag created successfully with ID: %d", id)), nil
@@ -60,12 +60,12 @@ func (s *PortainerMCPServer) HandleDeleteEnvironmentTag() server.ToolHandlerFunc
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
-		err = s.cli.DeleteEnvironmentTag(id)
+		err = s.client(ctx).DeleteEnvironmentTag(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete environment tag", err), nil
+			return toolErrorFromErr("failed to delete environment tag", err), nil
 		}
 
 		return mcp.NewToolResultText("Environment tag deleted successfully"), nil
@@ -29,15 +29,15 @@ func (s *PortainerMCPServer) HandleCreateTeam() server.ToolHandlerFunc {
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		teamID, err := s.cli.CreateTeam(name)
+		teamID, err := s.client(ctx).CreateTeam(ctx, name)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create team", err), nil
+			return toolErrorFromErr("failed to create team", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Team created successfully with ID: %d", teamID)), nil
@@ -47,9 +47,9 @@ func (s *PortainerMCPServer) HandleCreateTeam() server.ToolHandlerFunc {
 // HandleGetTeams returns an MCP tool handler that retrieves teams.
 func (s *PortainerMCPServer) HandleGetTeams() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		teams, err := s.cli.GetTeams()
+		teams, err := s.client(ctx).GetTeams(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get teams", err), nil
+			return toolErrorFromErr("failed to get teams", err), nil
 		}
 
 		return jsonResult(teams, "failed to marshal teams")
@@ -63,12 +63,12 @@ func (s *PortainerMCPServer) HandleGetTeam() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
-		team, err := s.cli.GetTeam(id)
+		team, err := s.client(ctx).GetTeam(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get team", err), nil
+			return toolErrorFromErr("failed to get team", err), nil
 		}
 
 		return jsonResult(team, "failed to marshal team")
@@ -82,12 +82,12 @@ func (s *PortainerMCPServer) HandleDeleteTeam() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
-		err = s.cli.DeleteTeam(id)
+		err = s.client(ctx).DeleteTeam(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete team", err), nil
+			return toolErrorFromErr("failed to delete team", err), nil
 		}
 
 		return mcp.NewToolResultText("Team deleted successfully"), nil
@@ -101,20 +101,20 @@ func (s *PortainerMCPServer) HandleUpdateTeamName() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		name, err := parser.GetString("name", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return toolErrorFromErr("invalid name parameter", err), nil
 		}
 		if err := validateName(name); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateTeamName(id, name)
+		err = s.client(ctx).UpdateTeamName(ctx, id, name)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update team name", err), nil
+			return toolErrorFromErr("failed to update team name", err), nil
 		}
 
 		return mcp.NewToolResultText("Team name updated successfully"), nil
@@ -128,17 +128,17 @@ func (s *PortainerMCPServer) HandleUpdateTeamMembers() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 
 		userIDs, err := parser.GetArrayOfIntegers("userIds", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userIds parameter", err), nil
+			return toolErrorFromErr("invalid userIds parameter", err), nil
 		}
 
-		err = s.cli.UpdateTeamMembers(id, userIDs)
+		err = s.client(ctx).UpdateTeamMembers(ctx, id, userIDs)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update team members", err), nil
+			return toolErrorFromErr("failed to update team members", err), nil
 		}
 
 		return mcp.NewToolResultText("Team members updated successfully"), nil
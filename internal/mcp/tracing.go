@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the instrumentation scope of the tool call spans.
+	tracerName = "github.com/jmrplens/portainer-mcp-enhanced/internal/mcp"
+	// defaultServiceName is the service.name of the traces unless
+	// OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES sets another one.
+	defaultServiceName = "portainer-mcp-enhanced"
+)
+
+// Attributes of the tool call spans.
+const (
+	attrToolName       = attribute.Key("mcp.tool.name")
+	attrToolAction     = attribute.Key("mcp.tool.action")
+	attrEnvironmentID  = attribute.Key("portainer.environment.id")
+	attrEnvironmentIDs = attribute.Key("portainer.environment.ids")
+	attrErrorType      = attribute.Key("error.type")
+)
+
+// tracingEnabled reports whether the standard OpenTelemetry environment
+// variables ask for traces to be exported over OTLP: an OTLP endpoint must be
+// set, the SDK must not be disabled, and the traces exporter must be otlp.
+func tracingEnabled(getenv func(string) string) bool {
+	if strings.EqualFold(getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	if exporter := getenv("OTEL_TRACES_EXPORTER"); exporter != "" && exporter != "otlp" {
+		return false
+	}
+	return getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// newTracerProviderFromEnv returns a tracer provider exporting spans over
+// OTLP, or nil when tracing is not enabled (see tracingEnabled). The exporter
+// reads its endpoint, headers, timeout, and TLS settings from the standard
+// OTEL_EXPORTER_OTLP_* variables, and OTEL_EXPORTER_OTLP_PROTOCOL selects
+// http/protobuf (the default) or grpc.
+func newTracerProviderFromEnv(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	if !tracingEnabled(os.Getenv) {
+		return nil, nil
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	var exporter *otlptrace.Exporter
+	var err error
+	switch protocol {
+	case "", "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q: must be http/protobuf or grpc", protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	// Attributes from the environment are detected last, so that they
+	// override the default service name.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// withTracing wraps a tool handler so that each call is recorded as a span,
+// the parent of the spans of the Portainer requests made by the handler. The
+// span is tagged with the tool, the meta-tool action, and the environments
+// the call targets. For meta-tools, actionTools maps each action to its
+// granular tool, whose arguments name the environments.
+func (s *PortainerMCPServer) withTracing(toolName string, actionTools map[string]string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if s.tracer == nil {
+		return next
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		attrs := []attribute.KeyValue{attrToolName.String(toolName)}
+		tool := toolName
+		if actionTools != nil {
+			action := "unknown"
+			if name, ok := request.GetArguments()["action"].(string); ok {
+				if actionTool, known := actionTools[name]; known {
+					action, tool = name, actionTool
+				}
+			}
+			attrs = append(attrs, attrToolAction.String(action))
+		}
+		if ids, _ := environmentArguments(tool, request); len(ids) == 1 {
+			attrs = append(attrs, attrEnvironmentID.Int(ids[0]))
+		} else if len(ids) > 1 {
+			attrs = append(attrs, attrEnvironmentIDs.IntSlice(ids))
+		}
+
+		ctx, span := s.tracer.Start(ctx, "tools/call "+toolName, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		result, err := next(ctx, request)
+		if class := toolErrorClass(result, err); class != "" {
+			span.SetAttributes(attrErrorType.String(class))
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			} else {
+				span.SetStatus(codes.Error, resultText(result))
+			}
+		}
+		return result, err
+	}
+}
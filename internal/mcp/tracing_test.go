@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestTracer returns a tracer whose ended spans are kept by the returned recorder.
+func newTestTracer() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+// callMetaTool calls a meta-tool through the MCP server with the given JSON arguments.
+func callMetaTool(t *testing.T, s *PortainerMCPServer, name, arguments string) {
+	t.Helper()
+	resp := s.srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+name+`","arguments":`+arguments+`}}`))
+	_, err := json.Marshal(resp)
+	require.NoError(t, err)
+}
+
+// spanAttributes returns the attributes of a span as a map.
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// TestTracingEnabled verifies which environment variables enable the OTLP exporter.
+func TestTracingEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{name: "no endpoint", env: map[string]string{}, expected: false},
+		{name: "endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}, expected: true},
+		{name: "traces endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces"}, expected: true},
+		{name: "otlp exporter", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_TRACES_EXPORTER": "otlp"}, expected: true},
+		{name: "no exporter", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_TRACES_EXPORTER": "none"}, expected: false},
+		{name: "sdk disabled", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_SDK_DISABLED": "TRUE"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tracingEnabled(func(key string) string { return tt.env[key] }))
+		})
+	}
+}
+
+// TestWithTracing verifies that tool calls are recorded as spans tagged with
+// the tool, action, and environment, and that failed calls have an error status.
+func TestWithTracing(t *testing.T) {
+	tp, recorder := newTestTracer()
+	s := newTestMetaServer(false)
+	s.tracer = tp.Tracer(tracerName)
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetEnvironment", 7).Return(models.Environment{ID: 7, Name: "production"}, nil)
+	mockClient.On("GetEnvironment", 8).Return(models.Environment{}, &client.APIError{StatusCode: 404, Kind: client.ErrNotFound, Err: assert.AnError})
+	s.RegisterMetaTools()
+
+	callMetaTool(t, s, "manage_environments", `{"action":"get_environment","id":7}`)
+	callMetaTool(t, s, "manage_environments", `{"action":"get_environment","id":8}`)
+	callMetaTool(t, s, "manage_environments", `{"action":"format_disk"}`)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "tools/call manage_environments", spans[0].Name())
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "manage_environments", attrs[attrToolName].AsString())
+	assert.Equal(t, "get_environment", attrs[attrToolAction].AsString())
+	assert.Equal(t, int64(7), attrs[attrEnvironmentID].AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	attrs = spanAttributes(spans[1])
+	assert.Equal(t, int64(8), attrs[attrEnvironmentID].AsInt64())
+	assert.Equal(t, errorClassNotFound, attrs[attrErrorType].AsString())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Status().Description, "failed to get environment")
+
+	attrs = spanAttributes(spans[2])
+	assert.Equal(t, "unknown", attrs[attrToolAction].AsString())
+	assert.NotContains(t, attrs, attrEnvironmentID)
+}
+
+// TestTracingPortainerRequests verifies that the Portainer requests of a
+// composite tool call, here the membership requests of update_team_members,
+// are children of the tool call span.
+func TestTracingPortainerRequests(t *testing.T) {
+	portainer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/team_memberships":
+			_, _ = io.WriteString(w, `[{"Id":1,"TeamID":2,"UserID":3,"Role":2},{"Id":2,"TeamID":2,"UserID":4,"Role":2}]`)
+		case "DELETE /api/team_memberships/1", "DELETE /api/team_memberships/2":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST /api/team_memberships":
+			_, _ = io.WriteString(w, `{"Id":3,"TeamID":2,"UserID":5,"Role":2}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer portainer.Close()
+
+	tp, recorder := newTestTracer()
+	s := newTestMetaServer(false)
+	s.tracer = tp.Tracer(tracerName)
+	s.cli = client.NewPortainerClient(portainer.URL, "ptr_token", client.WithTracerProvider(tp))
+	s.RegisterMetaTools()
+
+	callMetaTool(t, s, "manage_teams", `{"action":"update_team_members","id":2,"userIds":[5]}`)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+	root := spans[len(spans)-1]
+	assert.Equal(t, "tools/call manage_teams", root.Name())
+	assert.Equal(t, codes.Unset, root.Status().Code)
+
+	var names []string
+	for _, span := range spans[:len(spans)-1] {
+		names = append(names, span.Name())
+		assert.Equal(t, root.SpanContext().SpanID(), span.Parent().SpanID(), span.Name())
+	}
+	assert.Equal(t, []string{
+		"GET /api/team_memberships",
+		"DELETE /api/team_memberships/{id}",
+		"DELETE /api/team_memberships/{id}",
+		"POST /api/team_memberships",
+	}, names)
+}
+
+// TestNewTracerProviderFromEnv verifies that spans are exported over OTLP/HTTP
+// to the endpoint set in the environment, here a stand-in collector.
+func TestNewTracerProviderFromEnv(t *testing.T) {
+	var mu sync.Mutex
+	var exported []*coltracepb.ExportTraceServiceRequest
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		request := &coltracepb.ExportTraceServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, request))
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+
+		mu.Lock()
+		exported = append(exported, request)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer collector.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collector.URL)
+	t.Setenv("OTEL_SERVICE_NAME", "portainer-mcp-test")
+
+	tp, err := newTracerProviderFromEnv(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, tp)
+	_, span := tp.Tracer(tracerName).Start(context.Background(), "tools/call manage_teams")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, exported, 1)
+	resourceSpans := exported[0].GetResourceSpans()
+	require.Len(t, resourceSpans, 1)
+	var serviceName string
+	for _, kv := range resourceSpans[0].GetResource().GetAttributes() {
+		if kv.GetKey() == "service.name" {
+			serviceName = kv.GetValue().GetStringValue()
+		}
+	}
+	assert.Equal(t, "portainer-mcp-test", serviceName)
+	assert.Equal(t, "tools/call manage_teams", resourceSpans[0].GetScopeSpans()[0].GetSpans()[0].GetName())
+}
+
+// TestNewTracerProviderFromEnvDisabled verifies the configurations that do not export spans.
+func TestNewTracerProviderFromEnvDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	tp, err := newTracerProviderFromEnv(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, tp)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+	_, err = newTracerProviderFromEnv(context.Background())
+	assert.ErrorContains(t, err, `unsupported OTLP protocol "http/json"`)
+}
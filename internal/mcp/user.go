@@ -24,9 +24,9 @@ func (s *PortainerMCPServer) AddUserFeatures() {
 // HandleGetUsers returns an MCP tool handler that retrieves users.
 func (s *PortainerMCPServer) HandleGetUsers() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		users, err := s.cli.GetUsers()
+		users, err := s.client(ctx).GetUsers(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get users", err), nil
+			return toolErrorFromErr("failed to get users", err), nil
 		}
 
 		return jsonResult(users, "failed to marshal users")
@@ -40,7 +40,7 @@ func (s *PortainerMCPServer) HandleUpdateUserRole() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -48,16 +48,16 @@ func (s *PortainerMCPServer) HandleUpdateUserRole() server.ToolHandlerFunc {
 
 		role, err := parser.GetString("role", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid role parameter", err), nil
+			return toolErrorFromErr("invalid role parameter", err), nil
 		}
 
 		if !isValidUserRole(role) {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid role %s: must be one of: %v", role, AllUserRoles)), nil
 		}
 
-		err = s.cli.UpdateUserRole(id, role)
+		err = s.client(ctx).UpdateUserRole(ctx, id, role)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to update user role", err), nil
+			return toolErrorFromErr("failed to update user role", err), nil
 		}
 
 		return mcp.NewToolResultText("User updated successfully"), nil
@@ -71,26 +71,26 @@ func (s *PortainerMCPServer) HandleCreateUser() server.ToolHandlerFunc {
 
 		username, err := parser.GetString("username", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid username parameter", err), nil
+			return toolErrorFromErr("invalid username parameter", err), nil
 		}
 
 		password, err := parser.GetString("password", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+			return toolErrorFromErr("invalid password parameter", err), nil
 		}
 
 		role, err := parser.GetString("role", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid role parameter", err), nil
+			return toolErrorFromErr("invalid role parameter", err), nil
 		}
 
 		if !isValidUserRole(role) {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid role %s: must be one of: %v", role, AllUserRoles)), nil
 		}
 
-		id, err := s.cli.CreateUser(username, password, role)
+		id, err := s.client(ctx).CreateUser(ctx, username, password, role)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create user", err), nil
+			return toolErrorFromErr("failed to create user", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("User created successfully with ID: %d", id)), nil
@@ -104,15 +104,15 @@ func (s *PortainerMCPServer) HandleGetUser() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		user, err := s.cli.GetUser(id)
+		user, err := s.client(ctx).GetUser(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get user", err), nil
+			return toolErrorFromErr("failed to get user", err), nil
 		}
 
 		return jsonResult(user, "failed to marshal user")
@@ -126,15 +126,15 @@ func (s *PortainerMCPServer) HandleDeleteUser() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteUser(id)
+		err = s.client(ctx).DeleteUser(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete user", err), nil
+			return toolErrorFromErr("failed to delete user", err), nil
 		}
 
 		return mcp.NewToolResultText("User deleted successfully"), nil
@@ -2,10 +2,12 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
 
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
 	"github.com/mark3labs/mcp-go/mcp"
 	"gopkg.in/yaml.v3"
 )
@@ -14,11 +16,35 @@ import (
 func jsonResult(obj any, errMsg string) (*mcp.CallToolResult, error) {
 	data, err := json.Marshal(obj)
 	if err != nil {
-		return mcp.NewToolResultErrorFromErr(errMsg, err), nil
+		return toolErrorFromErr(errMsg, err), nil
 	}
 	return mcp.NewToolResultText(string(data)), nil
 }
 
+// apiErrorHints tells the caller what to do about each kind of Portainer API error.
+var apiErrorHints = []struct {
+	kind  error
+	class string
+	hint  string
+}{
+	{client.ErrNotFound, errorClassNotFound, "The resource does not exist in Portainer. Check the ID, or list the resources to find the right one."},
+	{client.ErrForbidden, errorClassForbidden, "Portainer denied the request. Check that the API token is valid and that its user has access to this resource and environment."},
+	{client.ErrConflict, errorClassConflict, "The request conflicts with the current state in Portainer, such as a name already in use. Read the current state and adjust the request."},
+	{client.ErrUnavailable, errorClassUnavailable, "Portainer is unreachable or overloaded, and retrying did not help. Try again later."},
+}
+
+// toolErrorFromErr returns an error result like mcp.NewToolResultErrorFromErr.
+// When err is a classified Portainer API error, a line telling the caller what
+// to do about it is appended.
+func toolErrorFromErr(text string, err error) *mcp.CallToolResult {
+	for _, h := range apiErrorHints {
+		if errors.Is(err, h.kind) {
+			return mcp.NewToolResultError(fmt.Sprintf("%s: %v\n%s", text, err, h.hint))
+		}
+	}
+	return mcp.NewToolResultErrorFromErr(text, err)
+}
+
 // validateName checks that a name string is non-empty after trimming whitespace.
 func validateName(name string) error {
 	if strings.TrimSpace(name) == "" {
@@ -120,6 +146,33 @@ func parseKeyValueMap(items []any) (map[string]string, error) {
 	return resultMap, nil
 }
 
+// parseKeyValuesMap parses a slice of {key, value} objects into a map of keys to
+// all their values, so that a key may be repeated (e.g. several label filters).
+func parseKeyValuesMap(items []any) (map[string][]string, error) {
+	resultMap := map[string][]string{}
+
+	for _, item := range items {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid item: %v", item)
+		}
+
+		key, ok := itemMap["key"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid key: %v", itemMap["key"])
+		}
+
+		value, ok := itemMap["value"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value: %v", itemMap["value"])
+		}
+
+		resultMap[key] = append(resultMap[key], value)
+	}
+
+	return resultMap, nil
+}
+
 // CreateMCPRequest creates a new MCP tool request with the given arguments.
 // Used by test code only.
 func CreateMCPRequest(args map[string]any) mcp.CallToolRequest {
@@ -129,3 +182,13 @@ func CreateMCPRequest(args map[string]any) mcp.CallToolRequest {
 		},
 	}
 }
+
+// resultText returns the text of the first text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			return textContent.Text
+		}
+	}
+	return ""
+}
@@ -1,8 +1,14 @@
 package mcp
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/client"
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // TestParseAccessMap verifies parse access map behavior.
@@ -247,3 +253,107 @@ func TestParseKeyValueMap(t *testing.T) {
 		})
 	}
 }
+
+func TestParseKeyValuesMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []any
+		want    map[string][]string
+		wantErr bool
+	}{
+		{
+			name: "Repeated keys accumulate values",
+			items: []any{
+				map[string]any{"key": "label", "value": "a=1"},
+				map[string]any{"key": "status", "value": "exited"},
+				map[string]any{"key": "label", "value": "b=2"},
+			},
+			want: map[string][]string{
+				"label":  {"a=1", "b=2"},
+				"status": {"exited"},
+			},
+		},
+		{
+			name:  "Empty items",
+			items: []any{},
+			want:  map[string][]string{},
+		},
+		{
+			name:    "Invalid item type",
+			items:   []any{"not a map"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid key type",
+			items:   []any{map[string]any{"key": 1, "value": "v"}},
+			wantErr: true,
+		},
+		{
+			name:    "Missing value field",
+			items:   []any{map[string]any{"key": "k"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeyValuesMap(tt.items)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseKeyValuesMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeyValuesMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestToolErrorFromErr verifies that classified Portainer API errors get a hint.
+func TestToolErrorFromErr(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "not found",
+			err:  fmt.Errorf("failed to get stack: %w", &client.APIError{StatusCode: 404, Kind: client.ErrNotFound, Err: errors.New("stackInspectNotFound")}),
+			want: "failed to get stack: failed to get stack: stackInspectNotFound\nThe resource does not exist in Portainer. Check the ID, or list the resources to find the right one.",
+		},
+		{
+			name: "unavailable",
+			err:  &client.APIError{Kind: client.ErrUnavailable, Err: errors.New("connection reset by peer")},
+			want: "failed to get stack: connection reset by peer\nPortainer is unreachable or overloaded, and retrying did not help. Try again later.",
+		},
+		{
+			name: "unclassified",
+			err:  &client.APIError{StatusCode: 400, Err: errors.New("invalid payload")},
+			want: "failed to get stack: invalid payload",
+		},
+		{
+			name: "other error",
+			err:  errors.New("id must be a positive integer"),
+			want: "failed to get stack: id must be a positive integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := toolErrorFromErr("failed to get stack", tt.err)
+			if !result.IsError {
+				t.Fatal("toolErrorFromErr() did not return an error result")
+			}
+			if got := result.Content[0].(mcp.TextContent).Text; got != tt.want {
+				t.Errorf("toolErrorFromErr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, kind := range []error{client.ErrForbidden, client.ErrConflict} {
+		text := toolErrorFromErr("failed", &client.APIError{Kind: kind, Err: errors.New("denied")}).Content[0].(mcp.TextContent).Text
+		if !strings.Contains(text, "\n") {
+			t.Errorf("toolErrorFromErr() for %v has no hint: %q", kind, text)
+		}
+	}
+}
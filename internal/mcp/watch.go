@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// Kinds of Portainer objects the watcher can poll, accepted by [WithWatch]
+// and [ParseWatchScope].
+const (
+	WatchEnvironments = "environments"
+	WatchStacks       = "stacks"
+	WatchHelm         = "helm"
+)
+
+// DefaultWatchScope is the default value of the -watch-scope flag. Helm
+// releases are left out because polling them costs one request per
+// Kubernetes environment.
+const DefaultWatchScope = WatchEnvironments + "," + WatchStacks
+
+const (
+	// watchLogger is the logger name of the log notifications sent by the watcher.
+	watchLogger = "portainer.watch"
+	// methodNotificationMessage is the method of MCP log notifications.
+	methodNotificationMessage = "notifications/message"
+	// helmReleaseStatusFailed is the status of a Helm release whose last
+	// install, upgrade, or rollback failed.
+	helmReleaseStatusFailed = "failed"
+)
+
+// ParseWatchScope parses the kinds of objects to watch, given as a
+// comma-separated list such as "environments,stacks,helm".
+func ParseWatchScope(value string) ([]string, error) {
+	var kinds []string
+	for _, kind := range strings.Split(value, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		if kind != WatchEnvironments && kind != WatchStacks && kind != WatchHelm {
+			return nil, fmt.Errorf("invalid watch scope %q: must be %s, %s, or %s", kind, WatchEnvironments, WatchStacks, WatchHelm)
+		}
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("invalid watch scope %q: at least one of %s, %s, or %s is required", value, WatchEnvironments, WatchStacks, WatchHelm)
+	}
+	return kinds, nil
+}
+
+// watchEvent is a change found by the watcher: the log notification that
+// describes it and, when the change is visible in a resource, the URI of that
+// resource.
+type watchEvent struct {
+	level   mcp.LoggingLevel
+	uri     string
+	message string
+	data    map[string]any
+}
+
+// watcher polls Portainer in the background and notifies the connected MCP
+// clients when an environment changes status, for example when an edge
+// environment stops sending heartbeats, when a stack stops or starts, and
+// when a Helm release changes status. The first poll records the current
+// state without notifying.
+//
+// The changes are sent to every initialized session as notifications/message
+// log notifications. The server does not offer resources/subscribe, as the
+// mcp-go server does not route it, so no notifications/resources/updated is
+// sent. The watcher polls with the server token, which is why it cannot be
+// combined with session authentication: every session would be told of the
+// changes the server's identity sees.
+type watcher struct {
+	s        *PortainerMCPServer
+	interval time.Duration
+	kinds    []string
+	// notify sends a notification to the connected clients.
+	notify func(method string, params map[string]any)
+
+	// The state seen by the previous poll; nil before the first poll of
+	// each kind.
+	environments map[int]models.Environment
+	stacks       map[int]models.RegularStack
+	releases     map[string]models.HelmRelease
+}
+
+// newWatcher returns a watcher of the given kinds of objects.
+func newWatcher(s *PortainerMCPServer, interval time.Duration, kinds []string) *watcher {
+	return &watcher{
+		s:        s,
+		interval: interval,
+		kinds:    kinds,
+		notify:   s.srv.SendNotificationToAllClients,
+	}
+}
+
+// watches reports whether the watcher polls a kind of object.
+func (w *watcher) watches(kind string) bool {
+	return slices.Contains(w.kinds, kind)
+}
+
+// run polls Portainer every interval until ctx is cancelled.
+func (w *watcher) run(ctx context.Context) {
+	log.Info().Dur("interval", w.interval).Strs("scope", w.kinds).Msg("Watching Portainer for status changes")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads the watched objects once and sends the notifications of the
+// changes since the previous poll. Objects that cannot be read keep their
+// previous state, so that a failed poll does not report changes.
+func (w *watcher) poll(ctx context.Context) {
+	// The watcher needs the current state, not the cached lists.
+	ctx = withCacheRefresh(ctx)
+
+	environments, err := w.s.cli.GetEnvironments(ctx)
+	if err == nil {
+		environments, err = w.s.filterEnvironments(ctx, environments)
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to poll environments")
+		return
+	}
+
+	var events []watchEvent
+	if w.watches(WatchEnvironments) {
+		events = append(events, w.environmentEvents(environments)...)
+	}
+	if w.watches(WatchStacks) {
+		stacks, err := w.s.cli.GetRegularStacks(ctx)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to poll stacks")
+		} else {
+			events = append(events, w.stackEvents(environments, stacks)...)
+		}
+	}
+	if w.watches(WatchHelm) {
+		events = append(events, w.helmEvents(ctx, environments)...)
+	}
+
+	w.send(events)
+}
+
+// environmentEvents returns the status changes of the environments.
+func (w *watcher) environmentEvents(environments []models.Environment) []watchEvent {
+	previous := w.environments
+	w.environments = make(map[int]models.Environment, len(environments))
+	for _, environment := range environments {
+		w.environments[environment.ID] = environment
+	}
+	if previous == nil {
+		return nil
+	}
+
+	var events []watchEvent
+	for _, environment := range environments {
+		before, ok := previous[environment.ID]
+		if !ok || before.Status == environment.Status {
+			continue
+		}
+		level := mcp.LoggingLevelInfo
+		if environment.Status != models.EnvironmentStatusActive {
+			level = mcp.LoggingLevelWarning
+		}
+		events = append(events, watchEvent{
+			level:   level,
+			uri:     fmt.Sprintf("%senvironments/%d", resourceScheme, environment.ID),
+			message: fmt.Sprintf("Environment %s (%d) changed from %s to %s", environment.Name, environment.ID, before.Status, environment.Status),
+			data: map[string]any{
+				"event":         "environment_status_changed",
+				"environmentId": environment.ID,
+				"environment":   environment.Name,
+				"type":          environment.Type,
+				"from":          before.Status,
+				"to":            environment.Status,
+			},
+		})
+	}
+	return events
+}
+
+// stackEvents returns the stacks of the environments in scope that stopped or started.
+func (w *watcher) stackEvents(environments []models.Environment, stacks []models.RegularStack) []watchEvent {
+	inScope := make(map[int]bool, len(environments))
+	for _, environment := range environments {
+		inScope[environment.ID] = true
+	}
+
+	previous := w.stacks
+	w.stacks = make(map[int]models.RegularStack, len(stacks))
+	for _, stack := range stacks {
+		if inScope[stack.EndpointID] {
+			w.stacks[stack.ID] = stack
+		}
+	}
+	if previous == nil {
+		return nil
+	}
+
+	var events []watchEvent
+	for _, stack := range stacks {
+		before, ok := previous[stack.ID]
+		if !ok || !inScope[stack.EndpointID] || before.Status == stack.Status {
+			continue
+		}
+		level, change := mcp.LoggingLevelInfo, "started"
+		if stack.Status != models.RegularStackStatusActive {
+			level, change = mcp.LoggingLevelWarning, "stopped"
+		}
+		events = append(events, watchEvent{
+			level:   level,
+			uri:     fmt.Sprintf("%sstacks/%d", resourceScheme, stack.ID),
+			message: fmt.Sprintf("Stack %s (%d) on environment %d %s", stack.Name, stack.ID, stack.EndpointID, change),
+			data: map[string]any{
+				"event":         "stack_" + change,
+				"stackId":       stack.ID,
+				"stack":         stack.Name,
+				"environmentId": stack.EndpointID,
+			},
+		})
+	}
+	return events
+}
+
+// helmEvents returns the status changes of the Helm releases of the active
+// Kubernetes environments.
+func (w *watcher) helmEvents(ctx context.Context, environments []models.Environment) []watchEvent {
+	previous := w.releases
+	w.releases = make(map[string]models.HelmRelease)
+
+	var events []watchEvent
+	for _, environment := range environments {
+		if !strings.HasPrefix(environment.Type, "kubernetes") || environment.Status != models.EnvironmentStatusActive {
+			continue
+		}
+		releases, err := w.s.cli.GetHelmReleases(ctx, environment.ID, "", "", "")
+		if err != nil {
+			log.Warn().Err(err).Int("environment", environment.ID).Msg("Failed to poll Helm releases")
+			// Keep the previous state of the environment's releases.
+			for key, release := range previous {
+				if strings.HasPrefix(key, fmt.Sprintf("%d/", environment.ID)) {
+					w.releases[key] = release
+				}
+			}
+			continue
+		}
+
+		for _, release := range releases {
+			key := fmt.Sprintf("%d/%s/%s", environment.ID, release.Namespace, release.Name)
+			w.releases[key] = release
+			before, ok := previous[key]
+			if !ok || before.Status == release.Status {
+				continue
+			}
+			level := mcp.LoggingLevelInfo
+			if release.Status == helmReleaseStatusFailed {
+				level = mcp.LoggingLevelWarning
+			}
+			events = append(events, watchEvent{
+				level:   level,
+				message: fmt.Sprintf("Helm release %s/%s on environment %s (%d) changed from %s to %s", release.Namespace, release.Name, environment.Name, environment.ID, before.Status, release.Status),
+				data: map[string]any{
+					"event":         "helm_release_status_changed",
+					"environmentId": environment.ID,
+					"namespace":     release.Namespace,
+					"release":       release.Name,
+					"from":          before.Status,
+					"to":            release.Status,
+				},
+			})
+		}
+	}
+	return events
+}
+
+// send logs the events and notifies the clients with a log notification for
+// each event. The notification of a change visible in a resource carries the
+// URI of that resource, so that clients can read it again.
+func (w *watcher) send(events []watchEvent) {
+	for _, event := range events {
+		log.Info().Str("level", string(event.level)).Msg(event.message)
+
+		data := map[string]any{"message": event.message}
+		if event.uri != "" {
+			data["uri"] = event.uri
+		}
+		for key, value := range event.data {
+			data[key] = value
+		}
+		w.notify(methodNotificationMessage, map[string]any{
+			"level":  event.level,
+			"logger": watchLogger,
+			"data":   data,
+		})
+	}
+}
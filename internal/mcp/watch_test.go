@@ -0,0 +1,256 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentNotification is a notification captured from a watcher.
+type sentNotification struct {
+	method string
+	params map[string]any
+}
+
+// newTestWatcher returns a watcher of the given kinds whose notifications are
+// appended to the returned slice.
+func newTestWatcher(s *PortainerMCPServer, kinds ...string) (*watcher, *[]sentNotification) {
+	w := newWatcher(s, time.Minute, kinds)
+	sent := &[]sentNotification{}
+	w.notify = func(method string, params map[string]any) {
+		*sent = append(*sent, sentNotification{method: method, params: params})
+	}
+	return w, sent
+}
+
+// updatedURIs returns the URIs of the resources named by the log notifications.
+func updatedURIs(sent []sentNotification) []string {
+	var uris []string
+	for _, n := range sent {
+		if uri, ok := n.params["data"].(map[string]any)["uri"].(string); ok {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// logMessages returns the level and data of the log notifications.
+func logMessages(t *testing.T, sent []sentNotification) []map[string]any {
+	t.Helper()
+	var messages []map[string]any
+	for _, n := range sent {
+		if n.method == methodNotificationMessage {
+			assert.Equal(t, watchLogger, n.params["logger"])
+			data := n.params["data"].(map[string]any)
+			data["level"] = n.params["level"]
+			messages = append(messages, data)
+		}
+	}
+	return messages
+}
+
+// TestParseWatchScope verifies the parsing of the -watch-scope flag.
+func TestParseWatchScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      []string
+		expectedError string
+	}{
+		{name: "default", value: DefaultWatchScope, expected: []string{WatchEnvironments, WatchStacks}},
+		{name: "all kinds with spaces and duplicates", value: " helm, stacks ,environments,helm", expected: []string{WatchHelm, WatchStacks, WatchEnvironments}},
+		{name: "unknown kind", value: "environments,volumes", expectedError: `invalid watch scope "volumes"`},
+		{name: "empty", value: " , ", expectedError: "at least one of environments, stacks, or helm is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds, err := ParseWatchScope(tt.value)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, kinds)
+		})
+	}
+}
+
+// TestWatcherPoll verifies that the first poll only records the state, and
+// that the following polls notify the status changes of environments,
+// stacks, and Helm releases.
+func TestWatcherPoll(t *testing.T) {
+	s := newTestMetaServer(false)
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetEnvironments").Return([]models.Environment{
+		{ID: 1, Name: "local", Status: models.EnvironmentStatusActive, Type: models.EnvironmentTypeDockerLocal},
+		{ID: 2, Name: "edge-01", Status: models.EnvironmentStatusActive, Type: models.EnvironmentTypeDockerEdgeAgent},
+		{ID: 3, Name: "k8s", Status: models.EnvironmentStatusActive, Type: models.EnvironmentTypeKubernetesAgent},
+	}, nil).Once()
+	mockClient.On("GetEnvironments").Return([]models.Environment{
+		{ID: 1, Name: "local", Status: models.EnvironmentStatusActive, Type: models.EnvironmentTypeDockerLocal},
+		{ID: 2, Name: "edge-01", Status: models.EnvironmentStatusInactive, Type: models.EnvironmentTypeDockerEdgeAgent},
+		{ID: 3, Name: "k8s", Status: models.EnvironmentStatusActive, Type: models.EnvironmentTypeKubernetesAgent},
+		{ID: 4, Name: "new", Status: models.EnvironmentStatusInactive, Type: models.EnvironmentTypeDockerAgent},
+	}, nil)
+	mockClient.On("GetRegularStacks").Return([]models.RegularStack{
+		{ID: 5, Name: "shop", Status: models.RegularStackStatusActive, EndpointID: 1},
+		{ID: 6, Name: "blog", Status: models.RegularStackStatusInactive, EndpointID: 1},
+	}, nil).Once()
+	mockClient.On("GetRegularStacks").Return([]models.RegularStack{
+		{ID: 5, Name: "shop", Status: models.RegularStackStatusInactive, EndpointID: 1},
+		{ID: 6, Name: "blog", Status: models.RegularStackStatusActive, EndpointID: 1},
+	}, nil)
+	mockClient.On("GetHelmReleases", 3, "", "", "").Return([]models.HelmRelease{
+		{Name: "redis", Namespace: "cache", Status: "deployed"},
+	}, nil).Once()
+	mockClient.On("GetHelmReleases", 3, "", "", "").Return([]models.HelmRelease{
+		{Name: "redis", Namespace: "cache", Status: "failed"},
+	}, nil)
+
+	w, sent := newTestWatcher(s, WatchEnvironments, WatchStacks, WatchHelm)
+
+	w.poll(context.Background())
+	assert.Empty(t, *sent)
+
+	w.poll(context.Background())
+	assert.Equal(t, []string{
+		"portainer://environments/2",
+		"portainer://stacks/5",
+		"portainer://stacks/6",
+	}, updatedURIs(*sent))
+	for _, n := range *sent {
+		assert.Equal(t, methodNotificationMessage, n.method, "only log notifications are sent, as resources cannot be subscribed to")
+	}
+
+	messages := logMessages(t, *sent)
+	require.Len(t, messages, 4)
+	assert.Equal(t, map[string]any{
+		"level":         mcp.LoggingLevelWarning,
+		"message":       "Environment edge-01 (2) changed from active to inactive",
+		"uri":           "portainer://environments/2",
+		"event":         "environment_status_changed",
+		"environmentId": 2,
+		"environment":   "edge-01",
+		"type":          models.EnvironmentTypeDockerEdgeAgent,
+		"from":          models.EnvironmentStatusActive,
+		"to":            models.EnvironmentStatusInactive,
+	}, messages[0])
+	assert.Equal(t, mcp.LoggingLevelWarning, messages[1]["level"])
+	assert.Equal(t, "stack_stopped", messages[1]["event"])
+	assert.Equal(t, "Stack shop (5) on environment 1 stopped", messages[1]["message"])
+	assert.Equal(t, mcp.LoggingLevelInfo, messages[2]["level"])
+	assert.Equal(t, "stack_started", messages[2]["event"])
+	assert.Equal(t, mcp.LoggingLevelWarning, messages[3]["level"])
+	assert.Equal(t, "Helm release cache/redis on environment k8s (3) changed from deployed to failed", messages[3]["message"])
+
+	// Nothing changed since the previous poll.
+	*sent = nil
+	w.poll(context.Background())
+	assert.Empty(t, *sent)
+	mockClient.AssertExpectations(t)
+}
+
+// TestWatcherPollScope verifies that the watched kinds and the policy's
+// environment scope limit the notifications.
+func TestWatcherPollScope(t *testing.T) {
+	s := newTestMetaServer(false)
+	s.policy = &policy{Environments: environmentScope{IDs: []int{1}}}
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetEnvironments").Return([]models.Environment{
+		{ID: 1, Name: "local", Status: models.EnvironmentStatusActive},
+		{ID: 2, Name: "edge-01", Status: models.EnvironmentStatusActive},
+	}, nil).Once()
+	mockClient.On("GetEnvironments").Return([]models.Environment{
+		{ID: 1, Name: "local", Status: models.EnvironmentStatusInactive},
+		{ID: 2, Name: "edge-01", Status: models.EnvironmentStatusInactive},
+	}, nil)
+
+	w, sent := newTestWatcher(s, WatchEnvironments)
+	w.poll(context.Background())
+	w.poll(context.Background())
+
+	assert.Equal(t, []string{"portainer://environments/1"}, updatedURIs(*sent))
+	mockClient.AssertNotCalled(t, "GetRegularStacks")
+	mockClient.AssertNotCalled(t, "GetHelmReleases", 1, "", "", "")
+}
+
+// TestWatcherPollErrors verifies that a failed poll keeps the previous state,
+// so that it reports no changes.
+func TestWatcherPollErrors(t *testing.T) {
+	s := newTestMetaServer(false)
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local", Status: models.EnvironmentStatusActive}}, nil).Once()
+	mockClient.On("GetEnvironments").Return(nil, errors.New("connection refused")).Once()
+	mockClient.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "local", Status: models.EnvironmentStatusActive}}, nil)
+	mockClient.On("GetRegularStacks").Return([]models.RegularStack{{ID: 5, Name: "shop", Status: models.RegularStackStatusActive, EndpointID: 1}}, nil).Once()
+	mockClient.On("GetRegularStacks").Return(nil, errors.New("connection refused")).Once()
+	mockClient.On("GetRegularStacks").Return([]models.RegularStack{{ID: 5, Name: "shop", Status: models.RegularStackStatusInactive, EndpointID: 1}}, nil)
+
+	w, sent := newTestWatcher(s, WatchEnvironments, WatchStacks)
+	w.poll(context.Background())
+	w.poll(context.Background())
+	w.poll(context.Background())
+	assert.Empty(t, *sent)
+
+	w.poll(context.Background())
+	assert.Equal(t, []string{"portainer://stacks/5"}, updatedURIs(*sent))
+}
+
+// TestWatcherRun verifies that the watcher polls once when started and stops
+// when its context is cancelled.
+func TestWatcherRun(t *testing.T) {
+	s := newTestMetaServer(false)
+	mockClient := s.cli.(*MockPortainerClient)
+	mockClient.On("GetEnvironments").Return([]models.Environment{}, nil)
+
+	w, _ := newTestWatcher(s, WatchEnvironments)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop")
+	}
+	mockClient.AssertNumberOfCalls(t, "GetEnvironments", 1)
+}
+
+// TestNewPortainerMCPServerWatch verifies the validation of the watch options.
+func TestNewPortainerMCPServerWatch(t *testing.T) {
+	s, err := NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true), WithWatch(30*time.Second, nil))
+	require.NoError(t, err)
+	require.NotNil(t, s.watcher)
+	assert.Equal(t, 30*time.Second, s.watcher.interval)
+	assert.Equal(t, []string{WatchEnvironments, WatchStacks}, s.watcher.kinds)
+
+	s, err = NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true))
+	require.NoError(t, err)
+	assert.Nil(t, s.watcher)
+
+	_, err = NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true), WithWatch(-time.Second, nil))
+	assert.ErrorContains(t, err, "invalid watch interval")
+
+	_, err = NewPortainerMCPServer("https://portainer.example.com", "", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)), WithWatch(time.Minute, nil))
+	assert.ErrorContains(t, err, "watching Portainer requires a server token")
+
+	// Every session would be notified of what the server token sees.
+	_, err = NewPortainerMCPServer("https://portainer.example.com", "token", "testdata/valid_tools.yaml",
+		WithClient(new(MockPortainerClient)), WithTransport(TransportStreamableHTTP), WithSessionAuth(true), WithWatch(time.Minute, nil))
+	assert.ErrorContains(t, err, "watching Portainer cannot be combined with session authentication")
+}
@@ -22,9 +22,9 @@ func (s *PortainerMCPServer) AddWebhookFeatures() {
 // HandleListWebhooks returns an MCP tool handler that lists webhooks.
 func (s *PortainerMCPServer) HandleListWebhooks() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		webhooks, err := s.cli.GetWebhooks()
+		webhooks, err := s.client(ctx).GetWebhooks(ctx)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get webhooks", err), nil
+			return toolErrorFromErr("failed to get webhooks", err), nil
 		}
 
 		return jsonResult(webhooks, "failed to marshal webhooks")
@@ -38,12 +38,12 @@ func (s *PortainerMCPServer) HandleCreateWebhook() server.ToolHandlerFunc {
 
 		resourceId, err := parser.GetString("resourceId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid resourceId parameter", err), nil
+			return toolErrorFromErr("invalid resourceId parameter", err), nil
 		}
 
 		endpointId, err := parser.GetInt("endpointId", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid endpointId parameter", err), nil
+			return toolErrorFromErr("invalid endpointId parameter", err), nil
 		}
 		if err := validatePositiveID("endpointId", endpointId); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -51,15 +51,15 @@ func (s *PortainerMCPServer) HandleCreateWebhook() server.ToolHandlerFunc {
 
 		webhookType, err := parser.GetInt("webhookType", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid webhookType parameter", err), nil
+			return toolErrorFromErr("invalid webhookType parameter", err), nil
 		}
 		if !isValidWebhookType(webhookType) {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid webhookType: %d (must be 1=service or 2=container)", webhookType)), nil
 		}
 
-		id, err := s.cli.CreateWebhook(resourceId, endpointId, webhookType)
+		id, err := s.client(ctx).CreateWebhook(ctx, resourceId, endpointId, webhookType)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to create webhook", err), nil
+			return toolErrorFromErr("failed to create webhook", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Webhook created successfully with ID: %d", id)), nil
@@ -73,15 +73,15 @@ func (s *PortainerMCPServer) HandleDeleteWebhook() server.ToolHandlerFunc {
 
 		id, err := parser.GetInt("id", true)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return toolErrorFromErr("invalid id parameter", err), nil
 		}
 		if err := validatePositiveID("id", id); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteWebhook(id)
+		err = s.client(ctx).DeleteWebhook(ctx, id)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to delete webhook", err), nil
+			return toolErrorFromErr("failed to delete webhook", err), nil
 		}
 
 		return mcp.NewToolResultText("Webhook deleted successfully"), nil
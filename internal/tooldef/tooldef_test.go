@@ -67,3 +67,34 @@ func TestCreateToolsFileIfNotExists(t *testing.T) {
 		assert.False(t, exists, "Function should return false when an error occurs")
 	})
 }
+
+// TestCreatePromptsFileIfNotExists verifies create prompts file if not exists behavior.
+func TestCreatePromptsFileIfNotExists(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("File Does Not Exist", func(t *testing.T) {
+		filePath := filepath.Join(tempDir, "prompts.yaml")
+
+		exists, err := CreatePromptsFileIfNotExists(filePath)
+		require.NoError(t, err, "Function should not return an error")
+		assert.False(t, exists, "Function should return false when creating a new file")
+
+		content, err := os.ReadFile(filePath)
+		require.NoError(t, err, "Should be able to read the created file")
+		assert.Equal(t, PromptsFile, content, "File should contain the embedded prompts content")
+	})
+
+	t.Run("File Already Exists", func(t *testing.T) {
+		filePath := filepath.Join(tempDir, "existing-prompts.yaml")
+		customContent := []byte("# Custom prompts file content")
+		require.NoError(t, os.WriteFile(filePath, customContent, 0644), "Failed to create test file")
+
+		exists, err := CreatePromptsFileIfNotExists(filePath)
+		require.NoError(t, err, "Function should not return an error")
+		assert.True(t, exists, "Function should return true when file already exists")
+
+		content, err := os.ReadFile(filePath)
+		require.NoError(t, err, "Should be able to read the file")
+		assert.Equal(t, customContent, content, "File content should not be modified")
+	})
+}
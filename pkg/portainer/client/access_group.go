@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -13,13 +14,13 @@ import (
 // Returns:
 //   - A slice of AccessGroup objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetAccessGroups() ([]models.AccessGroup, error) {
-	groups, err := c.cli.ListEndpointGroups()
+func (c *PortainerClient) GetAccessGroups(ctx context.Context) ([]models.AccessGroup, error) {
+	groups, err := c.cli.ListEndpointGroups(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list endpoint groups: %w", err)
 	}
 
-	endpoints, err := c.cli.ListEndpoints()
+	endpoints, err := c.cli.ListEndpoints(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list endpoints: %w", err)
 	}
@@ -40,8 +41,8 @@ func (c *PortainerClient) GetAccessGroups() ([]models.AccessGroup, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) CreateAccessGroup(name string, environmentIds []int) (int, error) {
-	groupID, err := c.cli.CreateEndpointGroup(name, utils.IntToInt64Slice(environmentIds))
+func (c *PortainerClient) CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	groupID, err := c.cli.CreateEndpointGroup(ctx, name, utils.IntToInt64Slice(environmentIds))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create access group: %w", err)
 	}
@@ -57,8 +58,8 @@ func (c *PortainerClient) CreateAccessGroup(name string, environmentIds []int) (
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateAccessGroupName(id int, name string) error {
-	err := c.cli.UpdateEndpointGroup(int64(id), &name, nil, nil)
+func (c *PortainerClient) UpdateAccessGroupName(ctx context.Context, id int, name string) error {
+	err := c.cli.UpdateEndpointGroup(ctx, int64(id), &name, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to update access group name: %w", err)
 	}
@@ -80,9 +81,9 @@ func (c *PortainerClient) UpdateAccessGroupName(id int, name string) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateAccessGroupUserAccesses(id int, userAccesses map[int]string) error {
+func (c *PortainerClient) UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
 	uac := utils.IntToInt64Map(userAccesses)
-	err := c.cli.UpdateEndpointGroup(int64(id), nil, &uac, nil)
+	err := c.cli.UpdateEndpointGroup(ctx, int64(id), nil, &uac, nil)
 	if err != nil {
 		return fmt.Errorf("failed to update access group user accesses: %w", err)
 	}
@@ -104,9 +105,9 @@ func (c *PortainerClient) UpdateAccessGroupUserAccesses(id int, userAccesses map
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateAccessGroupTeamAccesses(id int, teamAccesses map[int]string) error {
+func (c *PortainerClient) UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
 	tac := utils.IntToInt64Map(teamAccesses)
-	err := c.cli.UpdateEndpointGroup(int64(id), nil, nil, &tac)
+	err := c.cli.UpdateEndpointGroup(ctx, int64(id), nil, nil, &tac)
 	if err != nil {
 		return fmt.Errorf("failed to update access group team accesses: %w", err)
 	}
@@ -121,8 +122,8 @@ func (c *PortainerClient) UpdateAccessGroupTeamAccesses(id int, teamAccesses map
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) AddEnvironmentToAccessGroup(id int, environmentId int) error {
-	return c.cli.AddEnvironmentToEndpointGroup(int64(id), int64(environmentId))
+func (c *PortainerClient) AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error {
+	return c.cli.AddEnvironmentToEndpointGroup(ctx, int64(id), int64(environmentId))
 }
 
 // RemoveEnvironmentFromAccessGroup removes an environment from an access group
@@ -133,6 +134,6 @@ func (c *PortainerClient) AddEnvironmentToAccessGroup(id int, environmentId int)
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) RemoveEnvironmentFromAccessGroup(id int, environmentId int) error {
-	return c.cli.RemoveEnvironmentFromEndpointGroup(int64(id), int64(environmentId))
+func (c *PortainerClient) RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error {
+	return c.cli.RemoveEnvironmentFromEndpointGroup(ctx, int64(id), int64(environmentId))
 }
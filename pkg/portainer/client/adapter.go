@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
@@ -38,16 +39,16 @@ const (
 	defaultHTTPTimeout = 30 * time.Second
 )
 
-// portainerAPIAdapter wraps the SDK PortainerClient and adds methods
-// that are available in the Swagger-generated client but not exposed
-// by the SDK's high-level client (e.g., delete operations).
+// portainerAPIAdapter implements PortainerAPIClient on top of the low-level
+// Swagger-generated client. It owns authentication for every request so the
+// same adapter works with both Portainer API keys and user JWTs.
 type portainerAPIAdapter struct {
-	*sdkclient.PortainerClient
 	swagger       *swaggerclient.PortainerClientAPI
 	httpTransport *httptransport.Runtime
 	scheme        string
 	cleanHost     string
-	apiKey        string
+	token         string
+	useJWT        bool
 	proxyClient   *http.Client
 }
 
@@ -74,55 +75,71 @@ func parseHostScheme(host string) (scheme, cleanHost string) {
 	return "https", host
 }
 
-// newPortainerAPIAdapter creates a new adapter that embeds the SDK high-level
-// client and also holds a reference to the low-level Swagger client for
-// operations not exposed by the SDK.
-func newPortainerAPIAdapter(host, apiKey string, skipTLSVerify bool) *portainerAPIAdapter {
+// newPortainerAPIAdapter creates a new adapter backed by the low-level Swagger
+// client. The token is sent as an X-API-Key header, or as an
+// "Authorization: Bearer" header when options.useJWT is set.
+// Idempotent requests are retried after transient failures (see retryTransport),
+// each attempt is traced when options.tracerProvider is set,
+// and errors of the Swagger client are returned as *APIError (see classifyError).
+func newPortainerAPIAdapter(host, token string, options clientOptions) *portainerAPIAdapter {
 	scheme, cleanHost := parseHostScheme(host)
-	sdkCli := sdkclient.NewPortainerClient(cleanHost, apiKey,
-		sdkclient.WithSkipTLSVerify(skipTLSVerify),
-		sdkclient.WithScheme(scheme),
-	)
 
+	var rt http.RoundTripper = newHTTPTransport(options.skipTLSVerify)
+	if options.wrapTransport != nil {
+		rt = options.wrapTransport(rt)
+	}
+	if options.tracerProvider != nil {
+		rt = newTracingTransport(rt, options.tracerProvider)
+	}
 	httpClient := &http.Client{
 		Timeout:   defaultHTTPTimeout,
-		Transport: newHTTPTransport(skipTLSVerify),
+		Transport: newRetryTransport(rt),
 	}
 	transport := httptransport.NewWithClient(cleanHost, "/api", []string{scheme}, httpClient)
-	apiKeyAuth := runtime.ClientAuthInfoWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
-		return r.SetHeaderParam("x-api-key", apiKey)
+
+	a := &portainerAPIAdapter{
+		swagger:       swaggerclient.New(transport, nil),
+		httpTransport: transport,
+		scheme:        scheme,
+		cleanHost:     cleanHost,
+		token:         token,
+		useJWT:        options.useJWT,
+		proxyClient:   httpClient,
+	}
+	transport.DefaultAuthentication = runtime.ClientAuthInfoWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+		name, value := a.authHeader()
+		return r.SetHeaderParam(name, value)
 	})
-	transport.DefaultAuthentication = apiKeyAuth
 
-	return &portainerAPIAdapter{
-		PortainerClient: sdkCli,
-		swagger:         swaggerclient.New(transport, nil),
-		httpTransport:   transport,
-		scheme:          scheme,
-		cleanHost:       cleanHost,
-		apiKey:          apiKey,
-		proxyClient:     httpClient,
+	return a
+}
+
+// authHeader returns the header name and value used to authenticate with Portainer.
+func (a *portainerAPIAdapter) authHeader() (string, string) {
+	if a.useJWT {
+		return "Authorization", "Bearer " + a.token
 	}
+	return "x-api-key", a.token
 }
 
 // ProxyDockerRequest overrides the SDK method to use the correct scheme
 // instead of the hardcoded "https://" in the upstream SDK.
-func (a *portainerAPIAdapter) ProxyDockerRequest(environmentId int, opts sdkclient.ProxyRequestOptions) (*http.Response, error) {
+func (a *portainerAPIAdapter) ProxyDockerRequest(ctx context.Context, environmentId int, opts sdkclient.ProxyRequestOptions) (*http.Response, error) {
 	baseURL := fmt.Sprintf("%s://%s/api/endpoints/%d/docker%s", a.scheme, a.cleanHost, environmentId, opts.APIPath)
-	return a.proxyRequest(baseURL, opts)
+	return a.proxyRequest(ctx, baseURL, opts)
 }
 
 // ProxyKubernetesRequest overrides the SDK method to use the correct scheme
 // instead of the hardcoded "https://" in the upstream SDK.
-func (a *portainerAPIAdapter) ProxyKubernetesRequest(environmentId int, opts sdkclient.ProxyRequestOptions) (*http.Response, error) {
+func (a *portainerAPIAdapter) ProxyKubernetesRequest(ctx context.Context, environmentId int, opts sdkclient.ProxyRequestOptions) (*http.Response, error) {
 	baseURL := fmt.Sprintf("%s://%s/api/endpoints/%d/kubernetes%s", a.scheme, a.cleanHost, environmentId, opts.APIPath)
-	return a.proxyRequest(baseURL, opts)
+	return a.proxyRequest(ctx, baseURL, opts)
 }
 
-func (a *portainerAPIAdapter) proxyRequest(baseURL string, opts sdkclient.ProxyRequestOptions) (*http.Response, error) {
-	req, err := http.NewRequest(opts.Method, baseURL, opts.Body)
+func (a *portainerAPIAdapter) proxyRequest(ctx context.Context, baseURL string, opts sdkclient.ProxyRequestOptions) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, opts.Method, baseURL, opts.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create proxy request: %w", err)
+		return nil, fmt.Errorf("failed to create proxy request: %w", classifyError(err))
 	}
 	if opts.QueryParams != nil {
 		q := req.URL.Query()
@@ -131,277 +148,277 @@ func (a *portainerAPIAdapter) proxyRequest(baseURL string, opts sdkclient.ProxyR
 		}
 		req.URL.RawQuery = q.Encode()
 	}
-	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set(a.authHeader())
 	for k, v := range opts.Headers {
 		req.Header.Set(k, v)
 	}
 	resp, err := a.proxyClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send proxy request: %w", err)
+		return nil, fmt.Errorf("failed to send proxy request: %w", classifyError(err))
 	}
 	return resp, nil
 }
 
 // DeleteTag deletes a tag by ID using the low-level Swagger client.
-func (a *portainerAPIAdapter) DeleteTag(id int64) error {
-	params := tags.NewTagDeleteParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteTag(ctx context.Context, id int64) error {
+	params := tags.NewTagDeleteParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.Tags.TagDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete tag: %w", err)
+		return fmt.Errorf("failed to delete tag: %w", classifyError(err))
 	}
 	return nil
 }
 
 // DeleteTeam deletes a team by ID using the low-level Swagger client.
-func (a *portainerAPIAdapter) DeleteTeam(id int64) error {
-	params := teams.NewTeamDeleteParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteTeam(ctx context.Context, id int64) error {
+	params := teams.NewTeamDeleteParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.Teams.TeamDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete team: %w", err)
+		return fmt.Errorf("failed to delete team: %w", classifyError(err))
 	}
 	return nil
 }
 
 // DeleteUser deletes a user by ID using the low-level Swagger client.
-func (a *portainerAPIAdapter) DeleteUser(id int64) error {
-	params := users.NewUserDeleteParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteUser(ctx context.Context, id int64) error {
+	params := users.NewUserDeleteParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.Users.UserDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return fmt.Errorf("failed to delete user: %w", classifyError(err))
 	}
 	return nil
 }
 
 // DeleteEndpoint deletes an endpoint by ID using the low-level Swagger client.
-func (a *portainerAPIAdapter) DeleteEndpoint(id int64) error {
-	params := endpoints.NewEndpointDeleteParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteEndpoint(ctx context.Context, id int64) error {
+	params := endpoints.NewEndpointDeleteParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.Endpoints.EndpointDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete endpoint: %w", err)
+		return fmt.Errorf("failed to delete endpoint: %w", classifyError(err))
 	}
 	return nil
 }
 
 // SnapshotEndpoint triggers a snapshot for a single endpoint.
-func (a *portainerAPIAdapter) SnapshotEndpoint(id int64) error {
-	params := endpoints.NewEndpointSnapshotParams().WithID(id)
+func (a *portainerAPIAdapter) SnapshotEndpoint(ctx context.Context, id int64) error {
+	params := endpoints.NewEndpointSnapshotParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.Endpoints.EndpointSnapshot(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to snapshot endpoint: %w", err)
+		return fmt.Errorf("failed to snapshot endpoint: %w", classifyError(err))
 	}
 	return nil
 }
 
 // SnapshotAllEndpoints triggers a snapshot for all endpoints.
-func (a *portainerAPIAdapter) SnapshotAllEndpoints() error {
-	params := endpoints.NewEndpointSnapshotsParams()
+func (a *portainerAPIAdapter) SnapshotAllEndpoints(ctx context.Context) error {
+	params := endpoints.NewEndpointSnapshotsParamsWithContext(ctx)
 	_, err := a.swagger.Endpoints.EndpointSnapshots(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to snapshot all endpoints: %w", err)
+		return fmt.Errorf("failed to snapshot all endpoints: %w", classifyError(err))
 	}
 	return nil
 }
 
 // ListWebhooks retrieves all webhooks using the low-level Swagger client.
-func (a *portainerAPIAdapter) ListWebhooks() ([]*apimodels.PortainerWebhook, error) {
-	params := webhooks.NewGetWebhooksParams()
+func (a *portainerAPIAdapter) ListWebhooks(ctx context.Context) ([]*apimodels.PortainerWebhook, error) {
+	params := webhooks.NewGetWebhooksParamsWithContext(ctx)
 	resp, err := a.swagger.Webhooks.GetWebhooks(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		return nil, fmt.Errorf("failed to list webhooks: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // CreateWebhook creates a new webhook using the low-level Swagger client.
-func (a *portainerAPIAdapter) CreateWebhook(resourceId string, endpointId int64, webhookType int64) (int64, error) {
+func (a *portainerAPIAdapter) CreateWebhook(ctx context.Context, resourceId string, endpointId int64, webhookType int64) (int64, error) {
 	payload := &apimodels.WebhooksWebhookCreatePayload{
 		ResourceID:  resourceId,
 		EndpointID:  endpointId,
 		WebhookType: webhookType,
 	}
-	params := webhooks.NewPostWebhooksParams().WithBody(payload)
+	params := webhooks.NewPostWebhooksParamsWithContext(ctx).WithBody(payload)
 	resp, err := a.swagger.Webhooks.PostWebhooks(params, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create webhook: %w", err)
+		return 0, fmt.Errorf("failed to create webhook: %w", classifyError(err))
 	}
 	return resp.Payload.ID, nil
 }
 
 // DeleteWebhook deletes a webhook by ID using the low-level Swagger client.
-func (a *portainerAPIAdapter) DeleteWebhook(id int64) error {
-	params := webhooks.NewDeleteWebhooksIDParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteWebhook(ctx context.Context, id int64) error {
+	params := webhooks.NewDeleteWebhooksIDParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.Webhooks.DeleteWebhooksID(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete webhook: %w", err)
+		return fmt.Errorf("failed to delete webhook: %w", classifyError(err))
 	}
 	return nil
 }
 
 // ListCustomTemplates lists all custom templates.
-func (a *portainerAPIAdapter) ListCustomTemplates() ([]*apimodels.PortainereeCustomTemplate, error) {
-	params := custom_templates.NewCustomTemplateListParams()
+func (a *portainerAPIAdapter) ListCustomTemplates(ctx context.Context) ([]*apimodels.PortainereeCustomTemplate, error) {
+	params := custom_templates.NewCustomTemplateListParamsWithContext(ctx)
 	resp, err := a.swagger.CustomTemplates.CustomTemplateList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list custom templates: %w", err)
+		return nil, fmt.Errorf("failed to list custom templates: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetCustomTemplate retrieves a custom template by ID.
-func (a *portainerAPIAdapter) GetCustomTemplate(id int64) (*apimodels.PortainereeCustomTemplate, error) {
-	params := custom_templates.NewCustomTemplateInspectParams().WithID(id)
+func (a *portainerAPIAdapter) GetCustomTemplate(ctx context.Context, id int64) (*apimodels.PortainereeCustomTemplate, error) {
+	params := custom_templates.NewCustomTemplateInspectParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.CustomTemplates.CustomTemplateInspect(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get custom template: %w", err)
+		return nil, fmt.Errorf("failed to get custom template: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetCustomTemplateFile retrieves the file content of a custom template.
-func (a *portainerAPIAdapter) GetCustomTemplateFile(id int64) (string, error) {
-	params := custom_templates.NewCustomTemplateFileParams().WithID(id)
+func (a *portainerAPIAdapter) GetCustomTemplateFile(ctx context.Context, id int64) (string, error) {
+	params := custom_templates.NewCustomTemplateFileParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.CustomTemplates.CustomTemplateFile(params, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to get custom template file: %w", err)
+		return "", fmt.Errorf("failed to get custom template file: %w", classifyError(err))
 	}
 	return resp.Payload.FileContent, nil
 }
 
 // CreateCustomTemplate creates a new custom template from file content.
-func (a *portainerAPIAdapter) CreateCustomTemplate(payload *apimodels.CustomtemplatesCustomTemplateFromFileContentPayload) (*apimodels.PortainereeCustomTemplate, error) {
-	params := custom_templates.NewCustomTemplateCreateStringParams().WithBody(payload)
+func (a *portainerAPIAdapter) CreateCustomTemplate(ctx context.Context, payload *apimodels.CustomtemplatesCustomTemplateFromFileContentPayload) (*apimodels.PortainereeCustomTemplate, error) {
+	params := custom_templates.NewCustomTemplateCreateStringParamsWithContext(ctx).WithBody(payload)
 	resp, err := a.swagger.CustomTemplates.CustomTemplateCreateString(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create custom template: %w", err)
+		return nil, fmt.Errorf("failed to create custom template: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // DeleteCustomTemplate deletes a custom template by ID.
-func (a *portainerAPIAdapter) DeleteCustomTemplate(id int64) error {
-	params := custom_templates.NewCustomTemplateDeleteParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteCustomTemplate(ctx context.Context, id int64) error {
+	params := custom_templates.NewCustomTemplateDeleteParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.CustomTemplates.CustomTemplateDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete custom template: %w", err)
+		return fmt.Errorf("failed to delete custom template: %w", classifyError(err))
 	}
 	return nil
 }
 
 // ListRegistries lists all registries.
-func (a *portainerAPIAdapter) ListRegistries() ([]*apimodels.PortainereeRegistry, error) {
-	params := registries.NewRegistryListParams()
+func (a *portainerAPIAdapter) ListRegistries(ctx context.Context) ([]*apimodels.PortainereeRegistry, error) {
+	params := registries.NewRegistryListParamsWithContext(ctx)
 	resp, err := a.swagger.Registries.RegistryList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list registries: %w", err)
+		return nil, fmt.Errorf("failed to list registries: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetRegistryByID retrieves a registry by ID.
-func (a *portainerAPIAdapter) GetRegistryByID(id int64) (*apimodels.PortainereeRegistry, error) {
-	params := registries.NewRegistryInspectParams().WithID(id)
+func (a *portainerAPIAdapter) GetRegistryByID(ctx context.Context, id int64) (*apimodels.PortainereeRegistry, error) {
+	params := registries.NewRegistryInspectParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.Registries.RegistryInspect(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get registry: %w", err)
+		return nil, fmt.Errorf("failed to get registry: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // CreateRegistry creates a new registry.
-func (a *portainerAPIAdapter) CreateRegistry(body *apimodels.RegistriesRegistryCreatePayload) (int64, error) {
-	params := registries.NewRegistryCreateParams().WithBody(body)
+func (a *portainerAPIAdapter) CreateRegistry(ctx context.Context, body *apimodels.RegistriesRegistryCreatePayload) (int64, error) {
+	params := registries.NewRegistryCreateParamsWithContext(ctx).WithBody(body)
 	resp, err := a.swagger.Registries.RegistryCreate(params, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create registry: %w", err)
+		return 0, fmt.Errorf("failed to create registry: %w", classifyError(err))
 	}
 	return resp.Payload.ID, nil
 }
 
 // UpdateRegistry updates an existing registry.
-func (a *portainerAPIAdapter) UpdateRegistry(id int64, body *apimodels.RegistriesRegistryUpdatePayload) error {
-	params := registries.NewRegistryUpdateParams().WithID(id).WithBody(body)
+func (a *portainerAPIAdapter) UpdateRegistry(ctx context.Context, id int64, body *apimodels.RegistriesRegistryUpdatePayload) error {
+	params := registries.NewRegistryUpdateParamsWithContext(ctx).WithID(id).WithBody(body)
 	_, err := a.swagger.Registries.RegistryUpdate(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update registry: %w", err)
+		return fmt.Errorf("failed to update registry: %w", classifyError(err))
 	}
 	return nil
 }
 
 // DeleteRegistry deletes a registry by ID.
-func (a *portainerAPIAdapter) DeleteRegistry(id int64) error {
-	params := registries.NewRegistryDeleteParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteRegistry(ctx context.Context, id int64) error {
+	params := registries.NewRegistryDeleteParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.Registries.RegistryDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete registry: %w", err)
+		return fmt.Errorf("failed to delete registry: %w", classifyError(err))
 	}
 	return nil
 }
 
 // GetBackupStatus retrieves the status of the last backup.
-func (a *portainerAPIAdapter) GetBackupStatus() (*apimodels.BackupBackupStatus, error) {
-	params := backup.NewBackupStatusFetchParams()
+func (a *portainerAPIAdapter) GetBackupStatus(ctx context.Context) (*apimodels.BackupBackupStatus, error) {
+	params := backup.NewBackupStatusFetchParamsWithContext(ctx)
 	resp, err := a.swagger.Backup.BackupStatusFetch(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get backup status: %w", err)
+		return nil, fmt.Errorf("failed to get backup status: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetBackupSettings retrieves the S3 backup settings.
-func (a *portainerAPIAdapter) GetBackupSettings() (*apimodels.PortainereeS3BackupSettings, error) {
-	params := backup.NewBackupSettingsFetchParams()
+func (a *portainerAPIAdapter) GetBackupSettings(ctx context.Context) (*apimodels.PortainereeS3BackupSettings, error) {
+	params := backup.NewBackupSettingsFetchParamsWithContext(ctx)
 	resp, err := a.swagger.Backup.BackupSettingsFetch(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get backup settings: %w", err)
+		return nil, fmt.Errorf("failed to get backup settings: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // CreateBackup triggers a backup with an optional password.
-func (a *portainerAPIAdapter) CreateBackup(password string) error {
+func (a *portainerAPIAdapter) CreateBackup(ctx context.Context, password string) error {
 	body := &apimodels.BackupBackupPayload{
 		Password: password,
 	}
-	params := backup.NewBackupParams().WithBody(body)
+	params := backup.NewBackupParamsWithContext(ctx).WithBody(body)
 	_, err := a.swagger.Backup.Backup(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+		return fmt.Errorf("failed to create backup: %w", classifyError(err))
 	}
 	return nil
 }
 
 // BackupToS3 triggers a backup to S3.
-func (a *portainerAPIAdapter) BackupToS3(body *apimodels.BackupS3BackupPayload) error {
-	params := backup.NewBackupToS3Params().WithBody(body)
+func (a *portainerAPIAdapter) BackupToS3(ctx context.Context, body *apimodels.BackupS3BackupPayload) error {
+	params := backup.NewBackupToS3ParamsWithContext(ctx).WithBody(body)
 	_, err := a.swagger.Backup.BackupToS3(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to backup to S3: %w", err)
+		return fmt.Errorf("failed to backup to S3: %w", classifyError(err))
 	}
 	return nil
 }
 
 // RestoreFromS3 triggers a restore from S3.
-func (a *portainerAPIAdapter) RestoreFromS3(body *apimodels.BackupRestoreS3Settings) error {
-	params := backup.NewRestoreFromS3Params().WithBody(body)
+func (a *portainerAPIAdapter) RestoreFromS3(ctx context.Context, body *apimodels.BackupRestoreS3Settings) error {
+	params := backup.NewRestoreFromS3ParamsWithContext(ctx).WithBody(body)
 	_, err := a.swagger.Backup.RestoreFromS3(params)
 	if err != nil {
-		return fmt.Errorf("failed to restore from S3: %w", err)
+		return fmt.Errorf("failed to restore from S3: %w", classifyError(err))
 	}
 	return nil
 }
 
 // ListRoles lists all roles.
-func (a *portainerAPIAdapter) ListRoles() ([]*apimodels.PortainereeRole, error) {
-	params := roles.NewRoleListParams()
+func (a *portainerAPIAdapter) ListRoles(ctx context.Context) ([]*apimodels.PortainereeRole, error) {
+	params := roles.NewRoleListParamsWithContext(ctx)
 	resp, err := a.swagger.Roles.RoleList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list roles: %w", err)
+		return nil, fmt.Errorf("failed to list roles: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetMOTD retrieves the message of the day.
-func (a *portainerAPIAdapter) GetMOTD() (map[string]any, error) {
+func (a *portainerAPIAdapter) GetMOTD(ctx context.Context) (map[string]any, error) {
 	// Use raw HTTP to avoid SDK Hash type mismatch
 	// (SDK defines Hash as []int64, but newer API versions return a string).
 	op := &runtime.ClientOperation{
@@ -415,6 +432,7 @@ func (a *portainerAPIAdapter) GetMOTD() (map[string]any, error) {
 			return nil
 		}),
 		AuthInfo: a.httpTransport.DefaultAuthentication,
+		Context:  ctx,
 		Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
 			var result map[string]any
 			if err := consumer.Consume(resp.Body(), &result); err != nil {
@@ -425,211 +443,211 @@ func (a *portainerAPIAdapter) GetMOTD() (map[string]any, error) {
 	}
 	res, err := a.httpTransport.Submit(op)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get MOTD: %w", err)
+		return nil, fmt.Errorf("failed to get MOTD: %w", classifyError(err))
 	}
 	return res.(map[string]any), nil
 }
 
 // ListEdgeJobs lists all edge jobs.
-func (a *portainerAPIAdapter) ListEdgeJobs() ([]*apimodels.PortainerEdgeJob, error) {
-	params := edge_jobs.NewEdgeJobListParams()
+func (a *portainerAPIAdapter) ListEdgeJobs(ctx context.Context) ([]*apimodels.PortainerEdgeJob, error) {
+	params := edge_jobs.NewEdgeJobListParamsWithContext(ctx)
 	resp, err := a.swagger.EdgeJobs.EdgeJobList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list edge jobs: %w", err)
+		return nil, fmt.Errorf("failed to list edge jobs: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetEdgeJob retrieves an edge job by ID.
-func (a *portainerAPIAdapter) GetEdgeJob(id int64) (*apimodels.PortainerEdgeJob, error) {
-	params := edge_jobs.NewEdgeJobInspectParams().WithID(id)
+func (a *portainerAPIAdapter) GetEdgeJob(ctx context.Context, id int64) (*apimodels.PortainerEdgeJob, error) {
+	params := edge_jobs.NewEdgeJobInspectParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.EdgeJobs.EdgeJobInspect(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get edge job: %w", err)
+		return nil, fmt.Errorf("failed to get edge job: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetEdgeJobFile retrieves the file content of an edge job.
-func (a *portainerAPIAdapter) GetEdgeJobFile(id int64) (string, error) {
-	params := edge_jobs.NewEdgeJobFileParams().WithID(id)
+func (a *portainerAPIAdapter) GetEdgeJobFile(ctx context.Context, id int64) (string, error) {
+	params := edge_jobs.NewEdgeJobFileParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.EdgeJobs.EdgeJobFile(params, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to get edge job file: %w", err)
+		return "", fmt.Errorf("failed to get edge job file: %w", classifyError(err))
 	}
 	return resp.Payload.FileContent, nil
 }
 
 // CreateEdgeJob creates a new edge job from file content.
-func (a *portainerAPIAdapter) CreateEdgeJob(payload *apimodels.EdgejobsEdgeJobCreateFromFileContentPayload) (int64, error) {
-	params := edge_jobs.NewEdgeJobCreateStringParams().WithBody(payload)
+func (a *portainerAPIAdapter) CreateEdgeJob(ctx context.Context, payload *apimodels.EdgejobsEdgeJobCreateFromFileContentPayload) (int64, error) {
+	params := edge_jobs.NewEdgeJobCreateStringParamsWithContext(ctx).WithBody(payload)
 	resp, err := a.swagger.EdgeJobs.EdgeJobCreateString(params, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create edge job: %w", err)
+		return 0, fmt.Errorf("failed to create edge job: %w", classifyError(err))
 	}
 	return resp.Payload.ID, nil
 }
 
 // DeleteEdgeJob deletes an edge job by ID.
-func (a *portainerAPIAdapter) DeleteEdgeJob(id int64) error {
-	params := edge_jobs.NewEdgeJobDeleteParams().WithID(id)
+func (a *portainerAPIAdapter) DeleteEdgeJob(ctx context.Context, id int64) error {
+	params := edge_jobs.NewEdgeJobDeleteParamsWithContext(ctx).WithID(id)
 	_, err := a.swagger.EdgeJobs.EdgeJobDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete edge job: %w", err)
+		return fmt.Errorf("failed to delete edge job: %w", classifyError(err))
 	}
 	return nil
 }
 
 // UpdateSettings updates the Portainer settings using the provided payload.
-func (a *portainerAPIAdapter) UpdateSettings(payload *apimodels.SettingsSettingsUpdatePayload) error {
-	params := settings.NewSettingsUpdateParams().WithBody(payload)
+func (a *portainerAPIAdapter) UpdateSettings(ctx context.Context, payload *apimodels.SettingsSettingsUpdatePayload) error {
+	params := settings.NewSettingsUpdateParamsWithContext(ctx).WithBody(payload)
 	_, err := a.swagger.Settings.SettingsUpdate(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update settings: %w", err)
+		return fmt.Errorf("failed to update settings: %w", classifyError(err))
 	}
 	return nil
 }
 
 // GetPublicSettings retrieves the public settings from the Portainer server.
-func (a *portainerAPIAdapter) GetPublicSettings() (*apimodels.SettingsPublicSettingsResponse, error) {
-	params := settings.NewSettingsPublicParams()
+func (a *portainerAPIAdapter) GetPublicSettings(ctx context.Context) (*apimodels.SettingsPublicSettingsResponse, error) {
+	params := settings.NewSettingsPublicParamsWithContext(ctx)
 	resp, err := a.swagger.Settings.SettingsPublic(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get public settings: %w", err)
+		return nil, fmt.Errorf("failed to get public settings: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetSSLSettings retrieves the SSL settings from the Portainer server.
-func (a *portainerAPIAdapter) GetSSLSettings() (*apimodels.PortainereeSSLSettings, error) {
-	params := ssl.NewSSLInspectParams()
+func (a *portainerAPIAdapter) GetSSLSettings(ctx context.Context) (*apimodels.PortainereeSSLSettings, error) {
+	params := ssl.NewSSLInspectParamsWithContext(ctx)
 	resp, err := a.swagger.Ssl.SSLInspect(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get SSL settings: %w", err)
+		return nil, fmt.Errorf("failed to get SSL settings: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // UpdateSSLSettings updates the SSL settings.
-func (a *portainerAPIAdapter) UpdateSSLSettings(payload *apimodels.SslSslUpdatePayload) error {
-	params := ssl.NewSSLUpdateParams().WithBody(payload)
+func (a *portainerAPIAdapter) UpdateSSLSettings(ctx context.Context, payload *apimodels.SslSslUpdatePayload) error {
+	params := ssl.NewSSLUpdateParamsWithContext(ctx).WithBody(payload)
 	_, err := a.swagger.Ssl.SSLUpdate(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update SSL settings: %w", err)
+		return fmt.Errorf("failed to update SSL settings: %w", classifyError(err))
 	}
 	return nil
 }
 
 // ListAppTemplates lists all application templates.
-func (a *portainerAPIAdapter) ListAppTemplates() ([]*apimodels.PortainerTemplate, error) {
-	params := templates.NewTemplateListParams()
+func (a *portainerAPIAdapter) ListAppTemplates(ctx context.Context) ([]*apimodels.PortainerTemplate, error) {
+	params := templates.NewTemplateListParamsWithContext(ctx)
 	resp, err := a.swagger.Templates.TemplateList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list app templates: %w", err)
+		return nil, fmt.Errorf("failed to list app templates: %w", classifyError(err))
 	}
 	return resp.Payload.Templates, nil
 }
 
 // GetAppTemplateFile retrieves the file content of an application template.
-func (a *portainerAPIAdapter) GetAppTemplateFile(id int64) (string, error) {
-	params := templates.NewTemplateFileParams().WithID(id)
+func (a *portainerAPIAdapter) GetAppTemplateFile(ctx context.Context, id int64) (string, error) {
+	params := templates.NewTemplateFileParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.Templates.TemplateFile(params, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to get app template file: %w", err)
+		return "", fmt.Errorf("failed to get app template file: %w", classifyError(err))
 	}
 	return resp.Payload.FileContent, nil
 }
 
 // ListEdgeUpdateSchedules lists all edge update schedules.
-func (a *portainerAPIAdapter) ListEdgeUpdateSchedules() ([]*apimodels.EdgeupdateschedulesDecoratedUpdateSchedule, error) {
-	params := edge_update_schedules.NewEdgeUpdateScheduleListParams()
+func (a *portainerAPIAdapter) ListEdgeUpdateSchedules(ctx context.Context) ([]*apimodels.EdgeupdateschedulesDecoratedUpdateSchedule, error) {
+	params := edge_update_schedules.NewEdgeUpdateScheduleListParamsWithContext(ctx)
 	resp, err := a.swagger.EdgeUpdateSchedules.EdgeUpdateScheduleList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list edge update schedules: %w", err)
+		return nil, fmt.Errorf("failed to list edge update schedules: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // AuthenticateUser authenticates a user using the Swagger client.
-func (a *portainerAPIAdapter) AuthenticateUser(username, password string) (*apimodels.AuthAuthenticateResponse, error) {
-	params := auth.NewAuthenticateUserParams()
+func (a *portainerAPIAdapter) AuthenticateUser(ctx context.Context, username, password string) (*apimodels.AuthAuthenticateResponse, error) {
+	params := auth.NewAuthenticateUserParamsWithContext(ctx)
 	params.Body = &apimodels.AuthAuthenticatePayload{
 		Username: &username,
 		Password: &password,
 	}
 	resp, err := a.swagger.Auth.AuthenticateUser(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to authenticate user: %w", err)
+		return nil, fmt.Errorf("failed to authenticate user: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // Logout logs out the current user session.
-func (a *portainerAPIAdapter) Logout() error {
-	params := auth.NewLogoutParams()
+func (a *portainerAPIAdapter) Logout(ctx context.Context) error {
+	params := auth.NewLogoutParamsWithContext(ctx)
 	_, err := a.swagger.Auth.Logout(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to logout: %w", err)
+		return fmt.Errorf("failed to logout: %w", classifyError(err))
 	}
 	return nil
 }
 
 // ListHelmRepositories lists helm repositories for a user.
-func (a *portainerAPIAdapter) ListHelmRepositories(userId int64) (*apimodels.UsersHelmUserRepositoryResponse, error) {
-	params := helm.NewHelmUserRepositoriesListParams().WithID(userId)
+func (a *portainerAPIAdapter) ListHelmRepositories(ctx context.Context, userId int64) (*apimodels.UsersHelmUserRepositoryResponse, error) {
+	params := helm.NewHelmUserRepositoriesListParamsWithContext(ctx).WithID(userId)
 	resp, err := a.swagger.Helm.HelmUserRepositoriesList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list helm repositories: %w", err)
+		return nil, fmt.Errorf("failed to list helm repositories: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // CreateHelmRepository creates a helm repository for a user.
-func (a *portainerAPIAdapter) CreateHelmRepository(userId int64, url string) (*apimodels.PortainerHelmUserRepository, error) {
-	params := helm.NewHelmUserRepositoryCreateParams().WithID(userId).WithPayload(&apimodels.UsersAddHelmRepoURLPayload{URL: url})
+func (a *portainerAPIAdapter) CreateHelmRepository(ctx context.Context, userId int64, url string) (*apimodels.PortainerHelmUserRepository, error) {
+	params := helm.NewHelmUserRepositoryCreateParamsWithContext(ctx).WithID(userId).WithPayload(&apimodels.UsersAddHelmRepoURLPayload{URL: url})
 	resp, err := a.swagger.Helm.HelmUserRepositoryCreate(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create helm repository: %w", err)
+		return nil, fmt.Errorf("failed to create helm repository: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // DeleteHelmRepository deletes a helm repository for a user.
-func (a *portainerAPIAdapter) DeleteHelmRepository(userId int64, repositoryId int64) error {
-	params := helm.NewHelmUserRepositoryDeleteParams().WithID(userId).WithRepositoryID(repositoryId)
+func (a *portainerAPIAdapter) DeleteHelmRepository(ctx context.Context, userId int64, repositoryId int64) error {
+	params := helm.NewHelmUserRepositoryDeleteParamsWithContext(ctx).WithID(userId).WithRepositoryID(repositoryId)
 	_, err := a.swagger.Helm.HelmUserRepositoryDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete helm repository: %w", err)
+		return fmt.Errorf("failed to delete helm repository: %w", classifyError(err))
 	}
 	return nil
 }
 
 // SearchHelmCharts searches for helm charts in a repository.
-func (a *portainerAPIAdapter) SearchHelmCharts(repo string, chart *string) (string, error) {
-	params := helm.NewHelmRepoSearchParams().WithRepo(repo)
+func (a *portainerAPIAdapter) SearchHelmCharts(ctx context.Context, repo string, chart *string) (string, error) {
+	params := helm.NewHelmRepoSearchParamsWithContext(ctx).WithRepo(repo)
 	if chart != nil {
 		params = params.WithChart(chart)
 	}
 	resp, err := a.swagger.Helm.HelmRepoSearch(params, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to search helm charts: %w", err)
+		return "", fmt.Errorf("failed to search helm charts: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // InstallHelmChart installs a helm chart on an environment.
-func (a *portainerAPIAdapter) InstallHelmChart(environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error) {
-	params := helm.NewHelmInstallParams().WithID(environmentId).WithPayload(payload)
+func (a *portainerAPIAdapter) InstallHelmChart(ctx context.Context, environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error) {
+	params := helm.NewHelmInstallParamsWithContext(ctx).WithID(environmentId).WithPayload(payload)
 	resp, err := a.swagger.Helm.HelmInstall(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to install helm chart: %w", err)
+		return nil, fmt.Errorf("failed to install helm chart: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // ListHelmReleases lists helm releases on an environment.
-func (a *portainerAPIAdapter) ListHelmReleases(environmentId int64, namespace *string, filter *string, selector *string) ([]*apimodels.ReleaseReleaseElement, error) {
-	params := helm.NewHelmListParams().WithID(environmentId)
+func (a *portainerAPIAdapter) ListHelmReleases(ctx context.Context, environmentId int64, namespace *string, filter *string, selector *string) ([]*apimodels.ReleaseReleaseElement, error) {
+	params := helm.NewHelmListParamsWithContext(ctx).WithID(environmentId)
 	if namespace != nil {
 		params = params.WithNamespace(namespace)
 	}
@@ -641,40 +659,40 @@ func (a *portainerAPIAdapter) ListHelmReleases(environmentId int64, namespace *s
 	}
 	resp, err := a.swagger.Helm.HelmList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list helm releases: %w", err)
+		return nil, fmt.Errorf("failed to list helm releases: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // DeleteHelmRelease deletes a helm release from an environment.
-func (a *portainerAPIAdapter) DeleteHelmRelease(environmentId int64, release string, namespace *string) error {
-	params := helm.NewHelmDeleteParams().WithID(environmentId).WithRelease(release)
+func (a *portainerAPIAdapter) DeleteHelmRelease(ctx context.Context, environmentId int64, release string, namespace *string) error {
+	params := helm.NewHelmDeleteParamsWithContext(ctx).WithID(environmentId).WithRelease(release)
 	if namespace != nil {
 		params = params.WithNamespace(namespace)
 	}
 	_, err := a.swagger.Helm.HelmDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete helm release: %w", err)
+		return fmt.Errorf("failed to delete helm release: %w", classifyError(err))
 	}
 	return nil
 }
 
 // GetHelmReleaseHistory gets the history of a helm release.
-func (a *portainerAPIAdapter) GetHelmReleaseHistory(environmentId int64, name string, namespace *string) ([]*apimodels.ReleaseRelease, error) {
-	params := helm.NewHelmGetHistoryParams().WithID(environmentId).WithName(name)
+func (a *portainerAPIAdapter) GetHelmReleaseHistory(ctx context.Context, environmentId int64, name string, namespace *string) ([]*apimodels.ReleaseRelease, error) {
+	params := helm.NewHelmGetHistoryParamsWithContext(ctx).WithID(environmentId).WithName(name)
 	if namespace != nil {
 		params = params.WithNamespace(namespace)
 	}
 	resp, err := a.swagger.Helm.HelmGetHistory(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get helm release history: %w", err)
+		return nil, fmt.Errorf("failed to get helm release history: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetDockerDashboard retrieves the Docker dashboard data for a specific environment.
 // Uses raw HTTP GET because the SDK sends POST but newer Portainer versions require GET.
-func (a *portainerAPIAdapter) GetDockerDashboard(environmentId int64) (*apimodels.DockerDashboardResponse, error) {
+func (a *portainerAPIAdapter) GetDockerDashboard(ctx context.Context, environmentId int64) (*apimodels.DockerDashboardResponse, error) {
 	op := &runtime.ClientOperation{
 		ID:                 "DockerDashboard",
 		Method:             "GET",
@@ -686,6 +704,7 @@ func (a *portainerAPIAdapter) GetDockerDashboard(environmentId int64) (*apimodel
 			return nil
 		}),
 		AuthInfo: a.httpTransport.DefaultAuthentication,
+		Context:  ctx,
 		Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
 			var result apimodels.DockerDashboardResponse
 			if err := consumer.Consume(resp.Body(), &result); err != nil {
@@ -696,14 +715,14 @@ func (a *portainerAPIAdapter) GetDockerDashboard(environmentId int64) (*apimodel
 	}
 	res, err := a.httpTransport.Submit(op)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get docker dashboard: %w", err)
+		return nil, fmt.Errorf("failed to get docker dashboard: %w", classifyError(err))
 	}
 	return res.(*apimodels.DockerDashboardResponse), nil
 }
 
 // GetKubernetesDashboard retrieves the Kubernetes dashboard data for a specific environment.
 // Uses raw HTTP GET because the SDK expects an array but the API returns a single object.
-func (a *portainerAPIAdapter) GetKubernetesDashboard(environmentId int64) (*apimodels.KubernetesK8sDashboard, error) {
+func (a *portainerAPIAdapter) GetKubernetesDashboard(ctx context.Context, environmentId int64) (*apimodels.KubernetesK8sDashboard, error) {
 	op := &runtime.ClientOperation{
 		ID:                 "KubernetesDashboard",
 		Method:             "GET",
@@ -715,6 +734,7 @@ func (a *portainerAPIAdapter) GetKubernetesDashboard(environmentId int64) (*apim
 			return nil
 		}),
 		AuthInfo: a.httpTransport.DefaultAuthentication,
+		Context:  ctx,
 		Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
 			var result apimodels.KubernetesK8sDashboard
 			if err := consumer.Consume(resp.Body(), &result); err != nil {
@@ -725,37 +745,37 @@ func (a *portainerAPIAdapter) GetKubernetesDashboard(environmentId int64) (*apim
 	}
 	res, err := a.httpTransport.Submit(op)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get kubernetes dashboard: %w", err)
+		return nil, fmt.Errorf("failed to get kubernetes dashboard: %w", classifyError(err))
 	}
 	return res.(*apimodels.KubernetesK8sDashboard), nil
 }
 
 // GetKubernetesNamespaces retrieves the Kubernetes namespaces for a specific environment.
-func (a *portainerAPIAdapter) GetKubernetesNamespaces(environmentId int64) ([]*apimodels.PortainerK8sNamespaceInfo, error) {
-	params := kubernetes.NewGetKubernetesNamespacesParams().WithID(environmentId)
+func (a *portainerAPIAdapter) GetKubernetesNamespaces(ctx context.Context, environmentId int64) ([]*apimodels.PortainerK8sNamespaceInfo, error) {
+	params := kubernetes.NewGetKubernetesNamespacesParamsWithContext(ctx).WithID(environmentId)
 	resp, err := a.swagger.Kubernetes.GetKubernetesNamespaces(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get kubernetes namespaces: %w", err)
+		return nil, fmt.Errorf("failed to get kubernetes namespaces: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // GetKubernetesConfig retrieves the Kubernetes config for a specific environment.
-func (a *portainerAPIAdapter) GetKubernetesConfig(environmentId int64) (interface{}, error) {
-	params := kubernetes.NewGetKubernetesConfigParams().WithIds([]int64{environmentId})
+func (a *portainerAPIAdapter) GetKubernetesConfig(ctx context.Context, environmentId int64) (interface{}, error) {
+	params := kubernetes.NewGetKubernetesConfigParamsWithContext(ctx).WithIds([]int64{environmentId})
 	resp, err := a.swagger.Kubernetes.GetKubernetesConfig(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
+		return nil, fmt.Errorf("failed to get kubernetes config: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // ListRegularStacks retrieves all regular (non-edge) stacks.
-func (a *portainerAPIAdapter) ListRegularStacks() ([]*apimodels.PortainereeStack, error) {
-	params := stacks.NewStackListParams()
+func (a *portainerAPIAdapter) ListRegularStacks(ctx context.Context) ([]*apimodels.PortainereeStack, error) {
+	params := stacks.NewStackListParamsWithContext(ctx)
 	resp, respNoContent, err := a.swagger.Stacks.StackList(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list regular stacks: %w", err)
+		return nil, fmt.Errorf("failed to list regular stacks: %w", classifyError(err))
 	}
 	if respNoContent != nil {
 		return []*apimodels.PortainereeStack{}, nil
@@ -764,81 +784,81 @@ func (a *portainerAPIAdapter) ListRegularStacks() ([]*apimodels.PortainereeStack
 }
 
 // StackInspect retrieves details of a specific stack by ID.
-func (a *portainerAPIAdapter) StackInspect(id int64) (*apimodels.PortainereeStack, error) {
-	params := stacks.NewStackInspectParams().WithID(id)
+func (a *portainerAPIAdapter) StackInspect(ctx context.Context, id int64) (*apimodels.PortainereeStack, error) {
+	params := stacks.NewStackInspectParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.Stacks.StackInspect(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to inspect stack: %w", err)
+		return nil, fmt.Errorf("failed to inspect stack: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // StackDelete removes a stack by ID.
-func (a *portainerAPIAdapter) StackDelete(id int64, endpointID int64, removeVolumes bool) error {
-	params := stacks.NewStackDeleteParams().WithID(id).WithEndpointID(endpointID).WithRemoveVolumes(&removeVolumes)
+func (a *portainerAPIAdapter) StackDelete(ctx context.Context, id int64, endpointID int64, removeVolumes bool) error {
+	params := stacks.NewStackDeleteParamsWithContext(ctx).WithID(id).WithEndpointID(endpointID).WithRemoveVolumes(&removeVolumes)
 	_, err := a.swagger.Stacks.StackDelete(params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete stack: %w", err)
+		return fmt.Errorf("failed to delete stack: %w", classifyError(err))
 	}
 	return nil
 }
 
 // StackFileInspect retrieves the compose file content for a stack.
-func (a *portainerAPIAdapter) StackFileInspect(id int64) (string, error) {
-	params := stacks.NewStackFileInspectParams().WithID(id)
+func (a *portainerAPIAdapter) StackFileInspect(ctx context.Context, id int64) (string, error) {
+	params := stacks.NewStackFileInspectParamsWithContext(ctx).WithID(id)
 	resp, err := a.swagger.Stacks.StackFileInspect(params, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to inspect stack file: %w", err)
+		return "", fmt.Errorf("failed to inspect stack file: %w", classifyError(err))
 	}
 	return resp.Payload.StackFileContent, nil
 }
 
 // StackUpdateGit updates the git configuration of a stack.
-func (a *portainerAPIAdapter) StackUpdateGit(id int64, endpointID int64, body *apimodels.StacksStackGitUpdatePayload) (*apimodels.PortainereeStack, error) {
-	params := stacks.NewStackUpdateGitParams().WithID(id).WithEndpointID(&endpointID).WithBody(body)
+func (a *portainerAPIAdapter) StackUpdateGit(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackGitUpdatePayload) (*apimodels.PortainereeStack, error) {
+	params := stacks.NewStackUpdateGitParamsWithContext(ctx).WithID(id).WithEndpointID(&endpointID).WithBody(body)
 	resp, err := a.swagger.Stacks.StackUpdateGit(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update stack git: %w", err)
+		return nil, fmt.Errorf("failed to update stack git: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // StackGitRedeploy triggers a git-based redeployment of a stack.
-func (a *portainerAPIAdapter) StackGitRedeploy(id int64, endpointID int64, body *apimodels.StacksStackGitRedployPayload) (*apimodels.PortainereeStack, error) {
-	params := stacks.NewStackGitRedeployParams().WithID(id).WithEndpointID(&endpointID).WithBody(body)
+func (a *portainerAPIAdapter) StackGitRedeploy(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackGitRedployPayload) (*apimodels.PortainereeStack, error) {
+	params := stacks.NewStackGitRedeployParamsWithContext(ctx).WithID(id).WithEndpointID(&endpointID).WithBody(body)
 	resp, err := a.swagger.Stacks.StackGitRedeploy(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to redeploy stack: %w", err)
+		return nil, fmt.Errorf("failed to redeploy stack: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // StackStart starts a stopped stack.
-func (a *portainerAPIAdapter) StackStart(id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
-	params := stacks.NewStackStartParams().WithID(id).WithEndpointID(endpointID)
+func (a *portainerAPIAdapter) StackStart(ctx context.Context, id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
+	params := stacks.NewStackStartParamsWithContext(ctx).WithID(id).WithEndpointID(endpointID)
 	resp, err := a.swagger.Stacks.StackStart(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start stack: %w", err)
+		return nil, fmt.Errorf("failed to start stack: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // StackStop stops a running stack.
-func (a *portainerAPIAdapter) StackStop(id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
-	params := stacks.NewStackStopParams().WithID(id).WithEndpointID(endpointID)
+func (a *portainerAPIAdapter) StackStop(ctx context.Context, id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
+	params := stacks.NewStackStopParamsWithContext(ctx).WithID(id).WithEndpointID(endpointID)
 	resp, err := a.swagger.Stacks.StackStop(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to stop stack: %w", err)
+		return nil, fmt.Errorf("failed to stop stack: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
 
 // StackMigrate migrates a stack to another environment.
-func (a *portainerAPIAdapter) StackMigrate(id int64, endpointID int64, body *apimodels.StacksStackMigratePayload) (*apimodels.PortainereeStack, error) {
-	params := stacks.NewStackMigrateParams().WithID(id).WithEndpointID(&endpointID).WithBody(body)
+func (a *portainerAPIAdapter) StackMigrate(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackMigratePayload) (*apimodels.PortainereeStack, error) {
+	params := stacks.NewStackMigrateParamsWithContext(ctx).WithID(id).WithEndpointID(&endpointID).WithBody(body)
 	resp, err := a.swagger.Stacks.StackMigrate(params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to migrate stack: %w", err)
+		return nil, fmt.Errorf("failed to migrate stack: %w", classifyError(err))
 	}
 	return resp.Payload, nil
 }
@@ -0,0 +1,432 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/portainer/client-api-go/v2/pkg/client/edge_groups"
+	"github.com/portainer/client-api-go/v2/pkg/client/edge_stacks"
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoint_groups"
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
+	"github.com/portainer/client-api-go/v2/pkg/client/settings"
+	"github.com/portainer/client-api-go/v2/pkg/client/system"
+	"github.com/portainer/client-api-go/v2/pkg/client/tags"
+	"github.com/portainer/client-api-go/v2/pkg/client/team_memberships"
+	"github.com/portainer/client-api-go/v2/pkg/client/teams"
+	"github.com/portainer/client-api-go/v2/pkg/client/users"
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+)
+
+// This file contains the adapter methods that were previously provided by the
+// embedded SDK high-level client. The SDK client always authenticates with the
+// X-API-Key header, so these are reimplemented on the low-level Swagger client
+// to let the adapter choose between API key and JWT authentication.
+
+// teamMemberRole is the Portainer team membership role for a regular (non-leader) member.
+const teamMemberRole = int64(2)
+
+// ListEdgeGroups lists all edge groups using the low-level Swagger client.
+func (a *portainerAPIAdapter) ListEdgeGroups(ctx context.Context) ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error) {
+	params := edge_groups.NewEdgeGroupListParamsWithContext(ctx)
+	resp, err := a.swagger.EdgeGroups.EdgeGroupList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edge groups: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// CreateEdgeGroup creates a new static edge group.
+func (a *portainerAPIAdapter) CreateEdgeGroup(ctx context.Context, name string, environmentIds []int64) (int64, error) {
+	params := edge_groups.NewEdgeGroupCreateParamsWithContext(ctx).WithBody(&apimodels.EdgegroupsEdgeGroupCreatePayload{
+		Name:      name,
+		Endpoints: environmentIds,
+		Dynamic:   false,
+	})
+	resp, err := a.swagger.EdgeGroups.EdgeGroupCreate(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create edge group: %w", classifyError(err))
+	}
+	return resp.Payload.ID, nil
+}
+
+// UpdateEdgeGroup updates an edge group. Nil arguments keep the existing value;
+// setting tagIds turns the group into a dynamic (tag-based) group.
+func (a *portainerAPIAdapter) UpdateEdgeGroup(ctx context.Context, id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error {
+	params := edge_groups.NewEdgeGroupUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EdgegroupsEdgeGroupUpdatePayload{})
+	if name != nil {
+		params.Body.Name = *name
+	}
+	if environmentIds != nil {
+		params.Body.Endpoints = *environmentIds
+	}
+	if tagIds != nil {
+		params.Body.TagIDs = *tagIds
+		params.Body.Dynamic = true
+	}
+	_, err := a.swagger.EdgeGroups.EdgeGroupUpdate(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update edge group: %w", classifyError(err))
+	}
+	return nil
+}
+
+// ListEdgeStacks lists all edge stacks.
+func (a *portainerAPIAdapter) ListEdgeStacks(ctx context.Context) ([]*apimodels.PortainereeEdgeStack, error) {
+	params := edge_stacks.NewEdgeStackListParamsWithContext(ctx)
+	resp, err := a.swagger.EdgeStacks.EdgeStackList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edge stacks: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// CreateEdgeStack creates a new edge stack from a compose file string.
+func (a *portainerAPIAdapter) CreateEdgeStack(ctx context.Context, name string, file string, environmentGroupIds []int64) (int64, error) {
+	params := edge_stacks.NewEdgeStackCreateStringParamsWithContext(ctx).WithBody(&apimodels.EdgestacksEdgeStackFromStringPayload{
+		Name:             &name,
+		StackFileContent: &file,
+		EdgeGroups:       environmentGroupIds,
+		DeploymentType:   0,
+	})
+	resp, err := a.swagger.EdgeStacks.EdgeStackCreateString(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create edge stack: %w", classifyError(err))
+	}
+	return resp.Payload.ID, nil
+}
+
+// UpdateEdgeStack updates the file and edge groups of an edge stack.
+func (a *portainerAPIAdapter) UpdateEdgeStack(ctx context.Context, id int64, file string, environmentGroupIds []int64) error {
+	params := edge_stacks.NewEdgeStackUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EdgestacksUpdateEdgeStackPayload{
+		StackFileContent: file,
+		EdgeGroups:       environmentGroupIds,
+		UpdateVersion:    true,
+	})
+	_, err := a.swagger.EdgeStacks.EdgeStackUpdate(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update edge stack: %w", classifyError(err))
+	}
+	return nil
+}
+
+// GetEdgeStackFile retrieves the compose file content of an edge stack.
+func (a *portainerAPIAdapter) GetEdgeStackFile(ctx context.Context, id int64) (string, error) {
+	params := edge_stacks.NewEdgeStackFileParamsWithContext(ctx).WithID(id)
+	resp, err := a.swagger.EdgeStacks.EdgeStackFile(params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get edge stack file: %w", classifyError(err))
+	}
+	return resp.Payload.StackFileContent, nil
+}
+
+// ListEndpointGroups lists all endpoint groups.
+func (a *portainerAPIAdapter) ListEndpointGroups(ctx context.Context) ([]*apimodels.PortainerEndpointGroup, error) {
+	params := endpoint_groups.NewEndpointGroupListParamsWithContext(ctx)
+	resp, err := a.swagger.EndpointGroups.EndpointGroupList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint groups: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// CreateEndpointGroup creates a new endpoint group with the given endpoints.
+func (a *portainerAPIAdapter) CreateEndpointGroup(ctx context.Context, name string, associatedEndpoints []int64) (int64, error) {
+	params := endpoint_groups.NewPostEndpointGroupsParamsWithContext(ctx).WithBody(&apimodels.EndpointgroupsEndpointGroupCreatePayload{
+		Name:                &name,
+		AssociatedEndpoints: associatedEndpoints,
+	})
+	resp, err := a.swagger.EndpointGroups.PostEndpointGroups(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create endpoint group: %w", classifyError(err))
+	}
+	return resp.Payload.ID, nil
+}
+
+// UpdateEndpointGroup updates an endpoint group. Nil arguments keep the existing value.
+func (a *portainerAPIAdapter) UpdateEndpointGroup(ctx context.Context, id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+	params := endpoint_groups.NewEndpointGroupUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EndpointgroupsEndpointGroupUpdatePayload{})
+	if name != nil {
+		params.Body.Name = *name
+	}
+	if userAccesses != nil {
+		params.Body.UserAccessPolicies = buildAccessPolicies[apimodels.PortainerUserAccessPolicies](*userAccesses)
+	}
+	if teamAccesses != nil {
+		params.Body.TeamAccessPolicies = buildAccessPolicies[apimodels.PortainerTeamAccessPolicies](*teamAccesses)
+	}
+	_, err := a.swagger.EndpointGroups.EndpointGroupUpdate(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update endpoint group: %w", classifyError(err))
+	}
+	return nil
+}
+
+// AddEnvironmentToEndpointGroup adds an environment to an endpoint group.
+func (a *portainerAPIAdapter) AddEnvironmentToEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
+	params := endpoint_groups.NewEndpointGroupAddEndpointParamsWithContext(ctx).WithID(groupId).WithEndpointID(environmentId)
+	_, err := a.swagger.EndpointGroups.EndpointGroupAddEndpoint(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to add environment to endpoint group: %w", classifyError(err))
+	}
+	return nil
+}
+
+// RemoveEnvironmentFromEndpointGroup removes an environment from an endpoint group.
+func (a *portainerAPIAdapter) RemoveEnvironmentFromEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
+	params := endpoint_groups.NewEndpointGroupDeleteEndpointParamsWithContext(ctx).WithID(groupId).WithEndpointID(environmentId)
+	_, err := a.swagger.EndpointGroups.EndpointGroupDeleteEndpoint(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove environment from endpoint group: %w", classifyError(err))
+	}
+	return nil
+}
+
+// ListEndpoints lists all endpoints.
+func (a *portainerAPIAdapter) ListEndpoints(ctx context.Context) ([]*apimodels.PortainereeEndpoint, error) {
+	params := endpoints.NewEndpointListParamsWithContext(ctx)
+	resp, err := a.swagger.Endpoints.EndpointList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// GetEndpoint retrieves an endpoint by ID.
+func (a *portainerAPIAdapter) GetEndpoint(ctx context.Context, id int64) (*apimodels.PortainereeEndpoint, error) {
+	params := endpoints.NewEndpointInspectParamsWithContext(ctx).WithID(id)
+	resp, err := a.swagger.Endpoints.EndpointInspect(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endpoint: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// UpdateEndpoint updates the tags and access policies of an endpoint.
+// Nil arguments keep the existing value.
+func (a *portainerAPIAdapter) UpdateEndpoint(ctx context.Context, id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+	params := endpoints.NewEndpointUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EndpointsEndpointUpdatePayload{})
+	if tagIds != nil {
+		params.Body.TagIDs = *tagIds
+	}
+	if userAccesses != nil {
+		params.Body.UserAccessPolicies = buildAccessPolicies[apimodels.PortainerUserAccessPolicies](*userAccesses)
+	}
+	if teamAccesses != nil {
+		params.Body.TeamAccessPolicies = buildAccessPolicies[apimodels.PortainerTeamAccessPolicies](*teamAccesses)
+	}
+	_, err := a.swagger.Endpoints.EndpointUpdate(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update endpoint: %w", classifyError(err))
+	}
+	return nil
+}
+
+// GetSettings retrieves the Portainer settings.
+func (a *portainerAPIAdapter) GetSettings(ctx context.Context) (*apimodels.PortainereeSettings, error) {
+	params := settings.NewSettingsInspectParamsWithContext(ctx)
+	resp, err := a.swagger.Settings.SettingsInspect(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// GetSystemStatus retrieves the Portainer system status.
+func (a *portainerAPIAdapter) GetSystemStatus(ctx context.Context) (*apimodels.GithubComPortainerPortainerEeAPIHTTPHandlerSystemStatus, error) {
+	params := system.NewSystemStatusParamsWithContext(ctx)
+	resp, err := a.swagger.System.SystemStatus(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system status: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// GetVersion retrieves the Portainer server version from the system status.
+func (a *portainerAPIAdapter) GetVersion(ctx context.Context) (string, error) {
+	status, err := a.GetSystemStatus(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get version: %w", classifyError(err))
+	}
+	return status.Version, nil
+}
+
+// ListTags lists all tags.
+func (a *portainerAPIAdapter) ListTags(ctx context.Context) ([]*apimodels.PortainerTag, error) {
+	params := tags.NewTagListParamsWithContext(ctx)
+	resp, err := a.swagger.Tags.TagList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// CreateTag creates a new tag.
+func (a *portainerAPIAdapter) CreateTag(ctx context.Context, name string) (int64, error) {
+	params := tags.NewTagCreateParamsWithContext(ctx).WithBody(&apimodels.TagsTagCreatePayload{
+		Name: &name,
+	})
+	resp, err := a.swagger.Tags.TagCreate(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create tag: %w", classifyError(err))
+	}
+	return resp.Payload.ID, nil
+}
+
+// ListTeams lists all teams.
+func (a *portainerAPIAdapter) ListTeams(ctx context.Context) ([]*apimodels.PortainerTeam, error) {
+	params := teams.NewTeamListParamsWithContext(ctx)
+	resp, err := a.swagger.Teams.TeamList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// GetTeam retrieves a team by ID.
+func (a *portainerAPIAdapter) GetTeam(ctx context.Context, id int64) (*apimodels.PortainerTeam, error) {
+	params := teams.NewTeamInspectParamsWithContext(ctx).WithID(id)
+	resp, err := a.swagger.Teams.TeamInspect(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// ListTeamMemberships lists all team memberships.
+func (a *portainerAPIAdapter) ListTeamMemberships(ctx context.Context) ([]*apimodels.PortainerTeamMembership, error) {
+	params := team_memberships.NewTeamMembershipListParamsWithContext(ctx)
+	resp, err := a.swagger.TeamMemberships.TeamMembershipList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team memberships: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// CreateTeam creates a new team.
+func (a *portainerAPIAdapter) CreateTeam(ctx context.Context, name string) (int64, error) {
+	params := teams.NewTeamCreateParamsWithContext(ctx).WithBody(&apimodels.TeamsTeamCreatePayload{
+		Name: &name,
+	})
+	resp, err := a.swagger.Teams.TeamCreate(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create team: %w", classifyError(err))
+	}
+	return resp.Payload.ID, nil
+}
+
+// UpdateTeamName renames a team.
+func (a *portainerAPIAdapter) UpdateTeamName(ctx context.Context, id int, name string) error {
+	params := teams.NewTeamUpdateParamsWithContext(ctx).WithID(int64(id)).WithBody(&apimodels.TeamsTeamUpdatePayload{
+		Name: name,
+	})
+	_, err := a.swagger.Teams.TeamUpdate(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update team name: %w", classifyError(err))
+	}
+	return nil
+}
+
+// DeleteTeamMembership deletes a team membership by ID.
+func (a *portainerAPIAdapter) DeleteTeamMembership(ctx context.Context, id int) error {
+	params := team_memberships.NewTeamMembershipDeleteParamsWithContext(ctx).WithID(int64(id))
+	_, err := a.swagger.TeamMemberships.TeamMembershipDelete(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete team membership: %w", classifyError(err))
+	}
+	return nil
+}
+
+// CreateTeamMembership adds a user to a team as a regular member.
+func (a *portainerAPIAdapter) CreateTeamMembership(ctx context.Context, teamId int, userId int) error {
+	teamID := int64(teamId)
+	userID := int64(userId)
+	role := teamMemberRole
+	params := team_memberships.NewTeamMembershipCreateParamsWithContext(ctx).WithBody(&apimodels.TeammembershipsTeamMembershipCreatePayload{
+		Role:   &role,
+		TeamID: &teamID,
+		UserID: &userID,
+	})
+	_, err := a.swagger.TeamMemberships.TeamMembershipCreate(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create team membership: %w", classifyError(err))
+	}
+	return nil
+}
+
+// ListUsers lists all users.
+func (a *portainerAPIAdapter) ListUsers(ctx context.Context) ([]*apimodels.PortainereeUser, error) {
+	params := users.NewUserListParamsWithContext(ctx)
+	resp, err := a.swagger.Users.UserList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// CreateUser creates a new user with the given role ID.
+func (a *portainerAPIAdapter) CreateUser(ctx context.Context, username, password string, role int64) (int64, error) {
+	params := users.NewUserCreateParamsWithContext(ctx).WithBody(&apimodels.UsersUserCreatePayload{
+		Username: &username,
+		Password: &password,
+		Role:     &role,
+	})
+	resp, err := a.swagger.Users.UserCreate(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create user: %w", classifyError(err))
+	}
+	return resp.Payload.ID, nil
+}
+
+// GetUser retrieves a user by ID.
+func (a *portainerAPIAdapter) GetUser(ctx context.Context, id int) (*apimodels.PortainereeUser, error) {
+	params := users.NewUserInspectParamsWithContext(ctx).WithID(int64(id))
+	resp, err := a.swagger.Users.UserInspect(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", classifyError(err))
+	}
+	return resp.Payload, nil
+}
+
+// UpdateUserRole updates the role ID of a user.
+func (a *portainerAPIAdapter) UpdateUserRole(ctx context.Context, id int, role int64) error {
+	params := users.NewUserUpdateParamsWithContext(ctx).WithID(int64(id)).WithBody(&apimodels.UsersUserUpdatePayload{
+		Role: &role,
+	})
+	_, err := a.swagger.Users.UserUpdate(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update user role: %w", classifyError(err))
+	}
+	return nil
+}
+
+// buildAccessPolicies converts a map of user or team ID to access level name
+// into the Portainer access policy map. Unknown access levels are ignored.
+func buildAccessPolicies[T apimodels.PortainerTeamAccessPolicies | apimodels.PortainerUserAccessPolicies](accesses map[int64]string) T {
+	policies := make(T)
+	for id, access := range accesses {
+		roleID := accessLevelRoleID(access)
+		if roleID != 0 {
+			policies[strconv.FormatInt(id, 10)] = apimodels.PortainerAccessPolicy{RoleID: roleID}
+		}
+	}
+	return policies
+}
+
+// accessLevelRoleID maps an access level name to its Portainer role ID, or 0 if unknown.
+func accessLevelRoleID(access string) int64 {
+	switch access {
+	case "environment_administrator":
+		return 1
+	case "helpdesk_user":
+		return 2
+	case "standard_user":
+		return 3
+	case "readonly_user":
+		return 4
+	case "operator_user":
+		return 5
+	default:
+		return 0
+	}
+}
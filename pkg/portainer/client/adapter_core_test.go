@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdapterCoreMethods covers the adapter methods ported from the SDK
+// high-level client, checking both the success path and the wrapped error
+// returned on transport failures.
+func TestAdapterCoreMethods(t *testing.T) {
+	name := "renamed"
+	ids := []int64{1, 2}
+	accesses := map[int64]string{1: "standard_user", 2: "unknown_role"}
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		call        func(a *portainerAPIAdapter) error
+		errContains string
+	}{
+		{"ListEdgeGroups", 200, `[{"Id":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListEdgeGroups(ctx); return err }, "failed to list edge groups"},
+		{"CreateEdgeGroup", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.CreateEdgeGroup(ctx, "g", ids); return err }, "failed to create edge group"},
+		{"UpdateEdgeGroup", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { return a.UpdateEdgeGroup(ctx, 1, &name, &ids, &ids) }, "failed to update edge group"},
+		{"ListEdgeStacks", 200, `[{"Id":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListEdgeStacks(ctx); return err }, "failed to list edge stacks"},
+		{"CreateEdgeStack", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.CreateEdgeStack(ctx, "s", "f", ids); return err }, "failed to create edge stack"},
+		{"UpdateEdgeStack", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { return a.UpdateEdgeStack(ctx, 1, "f", ids) }, "failed to update edge stack"},
+		{"GetEdgeStackFile", 200, `{"StackFileContent":"x"}`, func(a *portainerAPIAdapter) error { _, err := a.GetEdgeStackFile(ctx, 1); return err }, "failed to get edge stack file"},
+		{"ListEndpointGroups", 200, `[{"Id":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListEndpointGroups(ctx); return err }, "failed to list endpoint groups"},
+		{"CreateEndpointGroup", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.CreateEndpointGroup(ctx, "g", ids); return err }, "failed to create endpoint group"},
+		{"UpdateEndpointGroup", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { return a.UpdateEndpointGroup(ctx, 1, &name, &accesses, &accesses) }, "failed to update endpoint group"},
+		{"AddEnvironmentToEndpointGroup", 204, ``, func(a *portainerAPIAdapter) error { return a.AddEnvironmentToEndpointGroup(ctx, 1, 2) }, "failed to add environment to endpoint group"},
+		{"RemoveEnvironmentFromEndpointGroup", 204, ``, func(a *portainerAPIAdapter) error { return a.RemoveEnvironmentFromEndpointGroup(ctx, 1, 2) }, "failed to remove environment from endpoint group"},
+		{"ListEndpoints", 200, `[{"Id":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListEndpoints(ctx); return err }, "failed to list endpoints"},
+		{"GetEndpoint", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.GetEndpoint(ctx, 1); return err }, "failed to get endpoint"},
+		{"UpdateEndpoint", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { return a.UpdateEndpoint(ctx, 1, &ids, &accesses, &accesses) }, "failed to update endpoint"},
+		{"GetSettings", 200, `{}`, func(a *portainerAPIAdapter) error { _, err := a.GetSettings(ctx); return err }, "failed to get settings"},
+		{"GetSystemStatus", 200, `{"Version":"2.31.2"}`, func(a *portainerAPIAdapter) error { _, err := a.GetSystemStatus(ctx); return err }, "failed to get system status"},
+		{"GetVersion", 200, `{"Version":"2.31.2"}`, func(a *portainerAPIAdapter) error { _, err := a.GetVersion(ctx); return err }, "failed to get version"},
+		{"ListTags", 200, `[{"ID":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListTags(ctx); return err }, "failed to list tags"},
+		{"CreateTag", 200, `{"ID":1}`, func(a *portainerAPIAdapter) error { _, err := a.CreateTag(ctx, "t"); return err }, "failed to create tag"},
+		{"ListTeams", 200, `[{"Id":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListTeams(ctx); return err }, "failed to list teams"},
+		{"GetTeam", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.GetTeam(ctx, 1); return err }, "failed to get team"},
+		{"ListTeamMemberships", 200, `[{"Id":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListTeamMemberships(ctx); return err }, "failed to list team memberships"},
+		{"CreateTeam", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.CreateTeam(ctx, "t"); return err }, "failed to create team"},
+		{"UpdateTeamName", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { return a.UpdateTeamName(ctx, 1, "t") }, "failed to update team name"},
+		{"DeleteTeamMembership", 204, ``, func(a *portainerAPIAdapter) error { return a.DeleteTeamMembership(ctx, 1) }, "failed to delete team membership"},
+		{"CreateTeamMembership", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { return a.CreateTeamMembership(ctx, 1, 2) }, "failed to create team membership"},
+		{"ListUsers", 200, `[{"Id":1}]`, func(a *portainerAPIAdapter) error { _, err := a.ListUsers(ctx); return err }, "failed to list users"},
+		{"CreateUser", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.CreateUser(ctx, "u", "p", 1); return err }, "failed to create user"},
+		{"GetUser", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { _, err := a.GetUser(ctx, 1); return err }, "failed to get user"},
+		{"UpdateUserRole", 200, `{"Id":1}`, func(a *portainerAPIAdapter) error { return a.UpdateUserRole(ctx, 1, 2) }, "failed to update user role"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name+"/success", func(t *testing.T) {
+			a := newTestAdapter(&mockRoundTripper{statusCode: tc.status, body: tc.body})
+			assert.NoError(t, tc.call(a))
+		})
+		t.Run(tc.name+"/transport error", func(t *testing.T) {
+			a := newTestAdapter(&mockRoundTripper{err: errTransport})
+			err := tc.call(a)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+}
+
+// TestBuildAccessPolicies verifies access level names map to Portainer role IDs
+// and that unknown access levels are dropped.
+func TestBuildAccessPolicies(t *testing.T) {
+	policies := buildAccessPolicies[apimodels.PortainerUserAccessPolicies](map[int64]string{7: "readonly_user", 8: "bogus"})
+	require.Len(t, policies, 1)
+	assert.Equal(t, int64(4), policies["7"].RoleID)
+
+	levels := map[string]int64{
+		"environment_administrator": 1,
+		"helpdesk_user":             2,
+		"standard_user":             3,
+		"readonly_user":             4,
+		"operator_user":             5,
+		"unknown":                   0,
+	}
+	for level, want := range levels {
+		assert.Equal(t, want, accessLevelRoleID(level), level)
+	}
+}
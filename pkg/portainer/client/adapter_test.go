@@ -5,6 +5,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"testing"
 
 	httptransport "github.com/go-openapi/runtime/client"
+	sdkclient "github.com/portainer/client-api-go/v2/client"
 	swaggerclient "github.com/portainer/client-api-go/v2/pkg/client"
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -96,19 +98,95 @@ func TestNewHTTPTransport(t *testing.T) {
 
 func TestNewPortainerAPIAdapter(t *testing.T) {
 	t.Run("https host", func(t *testing.T) {
-		a := newPortainerAPIAdapter("portainer.example.com", "test-key", false)
+		a := newPortainerAPIAdapter("portainer.example.com", "test-key", clientOptions{})
 		require.NotNil(t, a)
 		assert.NotNil(t, a.swagger)
 		assert.NotNil(t, a.httpTransport)
-		assert.NotNil(t, a.PortainerClient)
+		assert.Equal(t, "https", a.scheme)
 	})
 	t.Run("http host", func(t *testing.T) {
-		a := newPortainerAPIAdapter("http://portainer.local", "test-key", true)
+		a := newPortainerAPIAdapter("http://portainer.local", "test-key", clientOptions{skipTLSVerify: true})
 		require.NotNil(t, a)
 		assert.NotNil(t, a.swagger)
 	})
 }
 
+func TestAdapterAuthentication(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    clientOptions
+		wantHeader string
+		wantValue  string
+	}{
+		{"api key", clientOptions{}, "X-Api-Key", "secret"},
+		{"jwt", clientOptions{useJWT: true}, "Authorization", "Bearer secret"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := &mockRoundTripper{statusCode: 200, body: `[]`}
+			a := newPortainerAPIAdapter("http://portainer.local", "secret", tc.options)
+			a.proxyClient.Transport = rt
+
+			_, err := a.ListTags(context.Background())
+			require.NoError(t, err)
+			require.NotNil(t, rt.lastReq)
+			assert.Equal(t, tc.wantValue, rt.lastReq.Header.Get(tc.wantHeader))
+
+			_, err = a.ProxyDockerRequest(context.Background(), 1, sdkclient.ProxyRequestOptions{Method: "GET", APIPath: "/info"})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantValue, rt.lastReq.Header.Get(tc.wantHeader))
+		})
+	}
+}
+
+// ctxKey is a context key used to check that the caller's context reaches the transport.
+type ctxKey struct{}
+
+func TestAdapterContextPropagation(t *testing.T) {
+	calls := map[string]func(a *portainerAPIAdapter, ctx context.Context) error{
+		"swagger operation": func(a *portainerAPIAdapter, ctx context.Context) error {
+			_, err := a.ListTags(ctx)
+			return err
+		},
+		"raw operation": func(a *portainerAPIAdapter, ctx context.Context) error {
+			_, err := a.GetMOTD(ctx)
+			return err
+		},
+		"docker proxy": func(a *portainerAPIAdapter, ctx context.Context) error {
+			_, err := a.ProxyDockerRequest(ctx, 1, sdkclient.ProxyRequestOptions{Method: "GET", APIPath: "/info"})
+			return err
+		},
+		"kubernetes proxy": func(a *portainerAPIAdapter, ctx context.Context) error {
+			_, err := a.ProxyKubernetesRequest(ctx, 1, sdkclient.ProxyRequestOptions{Method: "GET", APIPath: "/api/v1/pods"})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			rt := &mockRoundTripper{statusCode: 200, body: `{}`}
+			a := newPortainerAPIAdapter("http://portainer.local", "secret", clientOptions{})
+			a.proxyClient.Transport = rt
+
+			ctx := context.WithValue(context.Background(), ctxKey{}, "call-1")
+			// The canned body does not fit every response type; only the outgoing request matters here.
+			_ = call(a, ctx)
+			require.NotNil(t, rt.lastReq)
+			assert.Equal(t, "call-1", rt.lastReq.Context().Value(ctxKey{}))
+		})
+
+		t.Run(name+" cancelled", func(t *testing.T) {
+			a := newPortainerAPIAdapter("http://portainer.local", "secret", clientOptions{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := call(a, ctx)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Tag operations
 // ---------------------------------------------------------------------------
@@ -116,12 +194,12 @@ func TestNewPortainerAPIAdapter(t *testing.T) {
 func TestAdapterDeleteTag(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteTag(1)
+		err := a.DeleteTag(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteTag(1)
+		err := a.DeleteTag(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to delete tag")
 	})
@@ -134,12 +212,12 @@ func TestAdapterDeleteTag(t *testing.T) {
 func TestAdapterDeleteTeam(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteTeam(1)
+		err := a.DeleteTeam(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteTeam(1)
+		err := a.DeleteTeam(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to delete team")
 	})
@@ -152,12 +230,12 @@ func TestAdapterDeleteTeam(t *testing.T) {
 func TestAdapterDeleteUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteUser(1)
+		err := a.DeleteUser(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteUser(1)
+		err := a.DeleteUser(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to delete user")
 	})
@@ -170,12 +248,12 @@ func TestAdapterDeleteUser(t *testing.T) {
 func TestAdapterDeleteEndpoint(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteEndpoint(1)
+		err := a.DeleteEndpoint(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteEndpoint(1)
+		err := a.DeleteEndpoint(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to delete endpoint")
 	})
@@ -184,12 +262,12 @@ func TestAdapterDeleteEndpoint(t *testing.T) {
 func TestAdapterSnapshotEndpoint(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.SnapshotEndpoint(1)
+		err := a.SnapshotEndpoint(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.SnapshotEndpoint(1)
+		err := a.SnapshotEndpoint(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to snapshot endpoint")
 	})
@@ -198,12 +276,12 @@ func TestAdapterSnapshotEndpoint(t *testing.T) {
 func TestAdapterSnapshotAllEndpoints(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.SnapshotAllEndpoints()
+		err := a.SnapshotAllEndpoints(context.Background())
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.SnapshotAllEndpoints()
+		err := a.SnapshotAllEndpoints(context.Background())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to snapshot all endpoints")
 	})
@@ -216,14 +294,14 @@ func TestAdapterSnapshotAllEndpoints(t *testing.T) {
 func TestAdapterListWebhooks(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{"Id":1}]`})
-		result, err := a.ListWebhooks()
+		result, err := a.ListWebhooks(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 		assert.Equal(t, int64(1), result[0].ID)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListWebhooks()
+		result, err := a.ListWebhooks(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "failed to list webhooks")
@@ -233,13 +311,13 @@ func TestAdapterListWebhooks(t *testing.T) {
 func TestAdapterCreateWebhook(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":42}`})
-		id, err := a.CreateWebhook("res-1", 1, 1)
+		id, err := a.CreateWebhook(context.Background(), "res-1", 1, 1)
 		assert.NoError(t, err)
 		assert.Equal(t, int64(42), id)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		id, err := a.CreateWebhook("res-1", 1, 1)
+		id, err := a.CreateWebhook(context.Background(), "res-1", 1, 1)
 		assert.Error(t, err)
 		assert.Equal(t, int64(0), id)
 	})
@@ -248,12 +326,12 @@ func TestAdapterCreateWebhook(t *testing.T) {
 func TestAdapterDeleteWebhook(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 202, body: ""})
-		err := a.DeleteWebhook(1)
+		err := a.DeleteWebhook(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteWebhook(1)
+		err := a.DeleteWebhook(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to delete webhook")
 	})
@@ -266,13 +344,13 @@ func TestAdapterDeleteWebhook(t *testing.T) {
 func TestAdapterListCustomTemplates(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{"Id":1}]`})
-		result, err := a.ListCustomTemplates()
+		result, err := a.ListCustomTemplates(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListCustomTemplates()
+		result, err := a.ListCustomTemplates(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -281,14 +359,14 @@ func TestAdapterListCustomTemplates(t *testing.T) {
 func TestAdapterGetCustomTemplate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":5}`})
-		result, err := a.GetCustomTemplate(5)
+		result, err := a.GetCustomTemplate(context.Background(), 5)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		assert.Equal(t, int64(5), result.ID)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetCustomTemplate(5)
+		result, err := a.GetCustomTemplate(context.Background(), 5)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -297,13 +375,13 @@ func TestAdapterGetCustomTemplate(t *testing.T) {
 func TestAdapterGetCustomTemplateFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"FileContent":"version: '3'"}`})
-		content, err := a.GetCustomTemplateFile(1)
+		content, err := a.GetCustomTemplateFile(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, "version: '3'", content)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		content, err := a.GetCustomTemplateFile(1)
+		content, err := a.GetCustomTemplateFile(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Empty(t, content)
 	})
@@ -313,14 +391,14 @@ func TestAdapterCreateCustomTemplate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":10}`})
 		payload := &apimodels.CustomtemplatesCustomTemplateFromFileContentPayload{}
-		result, err := a.CreateCustomTemplate(payload)
+		result, err := a.CreateCustomTemplate(context.Background(), payload)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		assert.Equal(t, int64(10), result.ID)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.CreateCustomTemplate(&apimodels.CustomtemplatesCustomTemplateFromFileContentPayload{})
+		result, err := a.CreateCustomTemplate(context.Background(), &apimodels.CustomtemplatesCustomTemplateFromFileContentPayload{})
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -329,12 +407,12 @@ func TestAdapterCreateCustomTemplate(t *testing.T) {
 func TestAdapterDeleteCustomTemplate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteCustomTemplate(1)
+		err := a.DeleteCustomTemplate(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteCustomTemplate(1)
+		err := a.DeleteCustomTemplate(context.Background(), 1)
 		assert.Error(t, err)
 	})
 }
@@ -346,13 +424,13 @@ func TestAdapterDeleteCustomTemplate(t *testing.T) {
 func TestAdapterListRegistries(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{"Id":1}]`})
-		result, err := a.ListRegistries()
+		result, err := a.ListRegistries(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListRegistries()
+		result, err := a.ListRegistries(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -361,14 +439,14 @@ func TestAdapterListRegistries(t *testing.T) {
 func TestAdapterGetRegistryByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":3}`})
-		result, err := a.GetRegistryByID(3)
+		result, err := a.GetRegistryByID(context.Background(), 3)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		assert.Equal(t, int64(3), result.ID)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetRegistryByID(3)
+		result, err := a.GetRegistryByID(context.Background(), 3)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -377,13 +455,13 @@ func TestAdapterGetRegistryByID(t *testing.T) {
 func TestAdapterCreateRegistry(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":7}`})
-		id, err := a.CreateRegistry(&apimodels.RegistriesRegistryCreatePayload{})
+		id, err := a.CreateRegistry(context.Background(), &apimodels.RegistriesRegistryCreatePayload{})
 		assert.NoError(t, err)
 		assert.Equal(t, int64(7), id)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		id, err := a.CreateRegistry(&apimodels.RegistriesRegistryCreatePayload{})
+		id, err := a.CreateRegistry(context.Background(), &apimodels.RegistriesRegistryCreatePayload{})
 		assert.Error(t, err)
 		assert.Equal(t, int64(0), id)
 	})
@@ -392,12 +470,12 @@ func TestAdapterCreateRegistry(t *testing.T) {
 func TestAdapterUpdateRegistry(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		err := a.UpdateRegistry(1, &apimodels.RegistriesRegistryUpdatePayload{})
+		err := a.UpdateRegistry(context.Background(), 1, &apimodels.RegistriesRegistryUpdatePayload{})
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.UpdateRegistry(1, &apimodels.RegistriesRegistryUpdatePayload{})
+		err := a.UpdateRegistry(context.Background(), 1, &apimodels.RegistriesRegistryUpdatePayload{})
 		assert.Error(t, err)
 	})
 }
@@ -405,12 +483,12 @@ func TestAdapterUpdateRegistry(t *testing.T) {
 func TestAdapterDeleteRegistry(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteRegistry(1)
+		err := a.DeleteRegistry(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteRegistry(1)
+		err := a.DeleteRegistry(context.Background(), 1)
 		assert.Error(t, err)
 	})
 }
@@ -422,13 +500,13 @@ func TestAdapterDeleteRegistry(t *testing.T) {
 func TestAdapterGetBackupStatus(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.GetBackupStatus()
+		result, err := a.GetBackupStatus(context.Background())
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetBackupStatus()
+		result, err := a.GetBackupStatus(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -437,13 +515,13 @@ func TestAdapterGetBackupStatus(t *testing.T) {
 func TestAdapterGetBackupSettings(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.GetBackupSettings()
+		result, err := a.GetBackupSettings(context.Background())
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetBackupSettings()
+		result, err := a.GetBackupSettings(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -452,12 +530,12 @@ func TestAdapterGetBackupSettings(t *testing.T) {
 func TestAdapterCreateBackup(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		err := a.CreateBackup("password")
+		err := a.CreateBackup(context.Background(), "password")
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.CreateBackup("password")
+		err := a.CreateBackup(context.Background(), "password")
 		assert.Error(t, err)
 	})
 }
@@ -465,12 +543,12 @@ func TestAdapterCreateBackup(t *testing.T) {
 func TestAdapterBackupToS3(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.BackupToS3(&apimodels.BackupS3BackupPayload{})
+		err := a.BackupToS3(context.Background(), &apimodels.BackupS3BackupPayload{})
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.BackupToS3(&apimodels.BackupS3BackupPayload{})
+		err := a.BackupToS3(context.Background(), &apimodels.BackupS3BackupPayload{})
 		assert.Error(t, err)
 	})
 }
@@ -478,12 +556,12 @@ func TestAdapterBackupToS3(t *testing.T) {
 func TestAdapterRestoreFromS3(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		err := a.RestoreFromS3(&apimodels.BackupRestoreS3Settings{})
+		err := a.RestoreFromS3(context.Background(), &apimodels.BackupRestoreS3Settings{})
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.RestoreFromS3(&apimodels.BackupRestoreS3Settings{})
+		err := a.RestoreFromS3(context.Background(), &apimodels.BackupRestoreS3Settings{})
 		assert.Error(t, err)
 	})
 }
@@ -495,13 +573,13 @@ func TestAdapterRestoreFromS3(t *testing.T) {
 func TestAdapterListRoles(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{"Id":1}]`})
-		result, err := a.ListRoles()
+		result, err := a.ListRoles(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListRoles()
+		result, err := a.ListRoles(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -514,7 +592,7 @@ func TestAdapterListRoles(t *testing.T) {
 func TestAdapterGetMOTD(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Title":"Hello","Message":"Welcome"}`})
-		result, err := a.GetMOTD()
+		result, err := a.GetMOTD(context.Background())
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		assert.Equal(t, "Hello", result["Title"])
@@ -522,7 +600,7 @@ func TestAdapterGetMOTD(t *testing.T) {
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetMOTD()
+		result, err := a.GetMOTD(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "failed to get MOTD")
@@ -536,13 +614,13 @@ func TestAdapterGetMOTD(t *testing.T) {
 func TestAdapterListEdgeJobs(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{"Id":1}]`})
-		result, err := a.ListEdgeJobs()
+		result, err := a.ListEdgeJobs(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListEdgeJobs()
+		result, err := a.ListEdgeJobs(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -551,14 +629,14 @@ func TestAdapterListEdgeJobs(t *testing.T) {
 func TestAdapterGetEdgeJob(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":5}`})
-		result, err := a.GetEdgeJob(5)
+		result, err := a.GetEdgeJob(context.Background(), 5)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		assert.Equal(t, int64(5), result.ID)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetEdgeJob(5)
+		result, err := a.GetEdgeJob(context.Background(), 5)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -567,13 +645,13 @@ func TestAdapterGetEdgeJob(t *testing.T) {
 func TestAdapterGetEdgeJobFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"FileContent":"#!/bin/bash"}`})
-		content, err := a.GetEdgeJobFile(1)
+		content, err := a.GetEdgeJobFile(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, "#!/bin/bash", content)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		content, err := a.GetEdgeJobFile(1)
+		content, err := a.GetEdgeJobFile(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Empty(t, content)
 	})
@@ -582,13 +660,13 @@ func TestAdapterGetEdgeJobFile(t *testing.T) {
 func TestAdapterCreateEdgeJob(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":12}`})
-		id, err := a.CreateEdgeJob(&apimodels.EdgejobsEdgeJobCreateFromFileContentPayload{})
+		id, err := a.CreateEdgeJob(context.Background(), &apimodels.EdgejobsEdgeJobCreateFromFileContentPayload{})
 		assert.NoError(t, err)
 		assert.Equal(t, int64(12), id)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		id, err := a.CreateEdgeJob(&apimodels.EdgejobsEdgeJobCreateFromFileContentPayload{})
+		id, err := a.CreateEdgeJob(context.Background(), &apimodels.EdgejobsEdgeJobCreateFromFileContentPayload{})
 		assert.Error(t, err)
 		assert.Equal(t, int64(0), id)
 	})
@@ -597,12 +675,12 @@ func TestAdapterCreateEdgeJob(t *testing.T) {
 func TestAdapterDeleteEdgeJob(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteEdgeJob(1)
+		err := a.DeleteEdgeJob(context.Background(), 1)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteEdgeJob(1)
+		err := a.DeleteEdgeJob(context.Background(), 1)
 		assert.Error(t, err)
 	})
 }
@@ -614,12 +692,12 @@ func TestAdapterDeleteEdgeJob(t *testing.T) {
 func TestAdapterUpdateSettingsAdapter(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		err := a.UpdateSettings(&apimodels.SettingsSettingsUpdatePayload{})
+		err := a.UpdateSettings(context.Background(), &apimodels.SettingsSettingsUpdatePayload{})
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.UpdateSettings(&apimodels.SettingsSettingsUpdatePayload{})
+		err := a.UpdateSettings(context.Background(), &apimodels.SettingsSettingsUpdatePayload{})
 		assert.Error(t, err)
 	})
 }
@@ -627,13 +705,13 @@ func TestAdapterUpdateSettingsAdapter(t *testing.T) {
 func TestAdapterGetPublicSettings(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.GetPublicSettings()
+		result, err := a.GetPublicSettings(context.Background())
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetPublicSettings()
+		result, err := a.GetPublicSettings(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -646,13 +724,13 @@ func TestAdapterGetPublicSettings(t *testing.T) {
 func TestAdapterGetSSLSettings(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.GetSSLSettings()
+		result, err := a.GetSSLSettings(context.Background())
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetSSLSettings()
+		result, err := a.GetSSLSettings(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -661,12 +739,12 @@ func TestAdapterGetSSLSettings(t *testing.T) {
 func TestAdapterUpdateSSLSettings(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.UpdateSSLSettings(&apimodels.SslSslUpdatePayload{})
+		err := a.UpdateSSLSettings(context.Background(), &apimodels.SslSslUpdatePayload{})
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.UpdateSSLSettings(&apimodels.SslSslUpdatePayload{})
+		err := a.UpdateSSLSettings(context.Background(), &apimodels.SslSslUpdatePayload{})
 		assert.Error(t, err)
 	})
 }
@@ -678,13 +756,13 @@ func TestAdapterUpdateSSLSettings(t *testing.T) {
 func TestAdapterListAppTemplates(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"templates":[{"Id":1}]}`})
-		result, err := a.ListAppTemplates()
+		result, err := a.ListAppTemplates(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListAppTemplates()
+		result, err := a.ListAppTemplates(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -693,13 +771,13 @@ func TestAdapterListAppTemplates(t *testing.T) {
 func TestAdapterGetAppTemplateFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"FileContent":"services:"}`})
-		content, err := a.GetAppTemplateFile(1)
+		content, err := a.GetAppTemplateFile(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, "services:", content)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		content, err := a.GetAppTemplateFile(1)
+		content, err := a.GetAppTemplateFile(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Empty(t, content)
 	})
@@ -712,13 +790,13 @@ func TestAdapterGetAppTemplateFile(t *testing.T) {
 func TestAdapterListEdgeUpdateSchedules(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{}]`})
-		result, err := a.ListEdgeUpdateSchedules()
+		result, err := a.ListEdgeUpdateSchedules(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListEdgeUpdateSchedules()
+		result, err := a.ListEdgeUpdateSchedules(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -731,14 +809,14 @@ func TestAdapterListEdgeUpdateSchedules(t *testing.T) {
 func TestAdapterAuthenticateUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"jwt":"test-token"}`})
-		result, err := a.AuthenticateUser("admin", "password")
+		result, err := a.AuthenticateUser(context.Background(), "admin", "password")
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		assert.Equal(t, "test-token", result.Jwt)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.AuthenticateUser("admin", "password")
+		result, err := a.AuthenticateUser(context.Background(), "admin", "password")
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -747,12 +825,12 @@ func TestAdapterAuthenticateUser(t *testing.T) {
 func TestAdapterLogout(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.Logout()
+		err := a.Logout(context.Background())
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.Logout()
+		err := a.Logout(context.Background())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to logout")
 	})
@@ -765,13 +843,13 @@ func TestAdapterLogout(t *testing.T) {
 func TestAdapterListHelmRepositories(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.ListHelmRepositories(1)
+		result, err := a.ListHelmRepositories(context.Background(), 1)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListHelmRepositories(1)
+		result, err := a.ListHelmRepositories(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -780,13 +858,13 @@ func TestAdapterListHelmRepositories(t *testing.T) {
 func TestAdapterCreateHelmRepository(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.CreateHelmRepository(1, "https://charts.example.com")
+		result, err := a.CreateHelmRepository(context.Background(), 1, "https://charts.example.com")
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.CreateHelmRepository(1, "https://charts.example.com")
+		result, err := a.CreateHelmRepository(context.Background(), 1, "https://charts.example.com")
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -795,12 +873,12 @@ func TestAdapterCreateHelmRepository(t *testing.T) {
 func TestAdapterDeleteHelmRepository(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteHelmRepository(1, 2)
+		err := a.DeleteHelmRepository(context.Background(), 1, 2)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteHelmRepository(1, 2)
+		err := a.DeleteHelmRepository(context.Background(), 1, 2)
 		assert.Error(t, err)
 	})
 }
@@ -808,20 +886,20 @@ func TestAdapterDeleteHelmRepository(t *testing.T) {
 func TestAdapterSearchHelmCharts(t *testing.T) {
 	t.Run("success without chart filter", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `"chart-data"`})
-		result, err := a.SearchHelmCharts("https://charts.example.com", nil)
+		result, err := a.SearchHelmCharts(context.Background(), "https://charts.example.com", nil)
 		assert.NoError(t, err)
 		assert.Equal(t, "chart-data", result)
 	})
 	t.Run("success with chart filter", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `"nginx"`})
 		chart := "nginx"
-		result, err := a.SearchHelmCharts("https://charts.example.com", &chart)
+		result, err := a.SearchHelmCharts(context.Background(), "https://charts.example.com", &chart)
 		assert.NoError(t, err)
 		assert.Equal(t, "nginx", result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.SearchHelmCharts("https://charts.example.com", nil)
+		result, err := a.SearchHelmCharts(context.Background(), "https://charts.example.com", nil)
 		assert.Error(t, err)
 		assert.Empty(t, result)
 	})
@@ -830,13 +908,13 @@ func TestAdapterSearchHelmCharts(t *testing.T) {
 func TestAdapterInstallHelmChart(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 201, body: `{"name":"my-release"}`})
-		result, err := a.InstallHelmChart(1, &apimodels.HelmInstallChartPayload{})
+		result, err := a.InstallHelmChart(context.Background(), 1, &apimodels.HelmInstallChartPayload{})
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.InstallHelmChart(1, &apimodels.HelmInstallChartPayload{})
+		result, err := a.InstallHelmChart(context.Background(), 1, &apimodels.HelmInstallChartPayload{})
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -845,7 +923,7 @@ func TestAdapterInstallHelmChart(t *testing.T) {
 func TestAdapterListHelmReleases(t *testing.T) {
 	t.Run("success no filters", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{}]`})
-		result, err := a.ListHelmReleases(1, nil, nil, nil)
+		result, err := a.ListHelmReleases(context.Background(), 1, nil, nil, nil)
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
@@ -854,13 +932,13 @@ func TestAdapterListHelmReleases(t *testing.T) {
 		ns := "default"
 		filter := "my-release"
 		selector := "app=nginx"
-		result, err := a.ListHelmReleases(1, &ns, &filter, &selector)
+		result, err := a.ListHelmReleases(context.Background(), 1, &ns, &filter, &selector)
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListHelmReleases(1, nil, nil, nil)
+		result, err := a.ListHelmReleases(context.Background(), 1, nil, nil, nil)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -869,18 +947,18 @@ func TestAdapterListHelmReleases(t *testing.T) {
 func TestAdapterDeleteHelmRelease(t *testing.T) {
 	t.Run("success without namespace", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.DeleteHelmRelease(1, "my-release", nil)
+		err := a.DeleteHelmRelease(context.Background(), 1, "my-release", nil)
 		assert.NoError(t, err)
 	})
 	t.Run("success with namespace", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
 		ns := "production"
-		err := a.DeleteHelmRelease(1, "my-release", &ns)
+		err := a.DeleteHelmRelease(context.Background(), 1, "my-release", &ns)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.DeleteHelmRelease(1, "my-release", nil)
+		err := a.DeleteHelmRelease(context.Background(), 1, "my-release", nil)
 		assert.Error(t, err)
 	})
 }
@@ -888,20 +966,20 @@ func TestAdapterDeleteHelmRelease(t *testing.T) {
 func TestAdapterGetHelmReleaseHistory(t *testing.T) {
 	t.Run("success without namespace", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{}]`})
-		result, err := a.GetHelmReleaseHistory(1, "my-release", nil)
+		result, err := a.GetHelmReleaseHistory(context.Background(), 1, "my-release", nil)
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("success with namespace", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{}]`})
 		ns := "default"
-		result, err := a.GetHelmReleaseHistory(1, "my-release", &ns)
+		result, err := a.GetHelmReleaseHistory(context.Background(), 1, "my-release", &ns)
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetHelmReleaseHistory(1, "my-release", nil)
+		result, err := a.GetHelmReleaseHistory(context.Background(), 1, "my-release", nil)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -915,7 +993,7 @@ func TestAdapterGetDockerDashboard(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		body := `{"containers":{"running":3,"stopped":2,"healthy":1},"images":{"total":10,"size":0},"networks":3,"volumes":2,"stacks":1,"services":0}`
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: body})
-		result, err := a.GetDockerDashboard(1)
+		result, err := a.GetDockerDashboard(context.Background(), 1)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		require.NotNil(t, result.Containers)
@@ -924,7 +1002,7 @@ func TestAdapterGetDockerDashboard(t *testing.T) {
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetDockerDashboard(1)
+		result, err := a.GetDockerDashboard(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "failed to get docker dashboard")
@@ -938,13 +1016,13 @@ func TestAdapterGetDockerDashboard(t *testing.T) {
 func TestAdapterGetKubernetesDashboard(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.GetKubernetesDashboard(1)
+		result, err := a.GetKubernetesDashboard(context.Background(), 1)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetKubernetesDashboard(1)
+		result, err := a.GetKubernetesDashboard(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "failed to get kubernetes dashboard")
@@ -958,13 +1036,13 @@ func TestAdapterGetKubernetesDashboard(t *testing.T) {
 func TestAdapterGetKubernetesNamespaces(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{}]`})
-		result, err := a.GetKubernetesNamespaces(1)
+		result, err := a.GetKubernetesNamespaces(context.Background(), 1)
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetKubernetesNamespaces(1)
+		result, err := a.GetKubernetesNamespaces(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -973,13 +1051,13 @@ func TestAdapterGetKubernetesNamespaces(t *testing.T) {
 func TestAdapterGetKubernetesConfig(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{}`})
-		result, err := a.GetKubernetesConfig(1)
+		result, err := a.GetKubernetesConfig(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.GetKubernetesConfig(1)
+		result, err := a.GetKubernetesConfig(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -992,19 +1070,19 @@ func TestAdapterGetKubernetesConfig(t *testing.T) {
 func TestAdapterListRegularStacks(t *testing.T) {
 	t.Run("success with items", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `[{"Id":1}]`})
-		result, err := a.ListRegularStacks()
+		result, err := a.ListRegularStacks(context.Background())
 		assert.NoError(t, err)
 		require.Len(t, result, 1)
 	})
 	t.Run("success no content", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		result, err := a.ListRegularStacks()
+		result, err := a.ListRegularStacks(context.Background())
 		assert.NoError(t, err)
 		assert.Empty(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.ListRegularStacks()
+		result, err := a.ListRegularStacks(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -1013,14 +1091,14 @@ func TestAdapterListRegularStacks(t *testing.T) {
 func TestAdapterStackInspect(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":1}`})
-		result, err := a.StackInspect(1)
+		result, err := a.StackInspect(context.Background(), 1)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 		assert.Equal(t, int64(1), result.ID)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.StackInspect(1)
+		result, err := a.StackInspect(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -1029,12 +1107,12 @@ func TestAdapterStackInspect(t *testing.T) {
 func TestAdapterStackDelete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 204, body: ""})
-		err := a.StackDelete(1, 1, false)
+		err := a.StackDelete(context.Background(), 1, 1, false)
 		assert.NoError(t, err)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		err := a.StackDelete(1, 1, false)
+		err := a.StackDelete(context.Background(), 1, 1, false)
 		assert.Error(t, err)
 	})
 }
@@ -1042,13 +1120,13 @@ func TestAdapterStackDelete(t *testing.T) {
 func TestAdapterStackFileInspect(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"StackFileContent":"version: '3'"}`})
-		content, err := a.StackFileInspect(1)
+		content, err := a.StackFileInspect(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, "version: '3'", content)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		content, err := a.StackFileInspect(1)
+		content, err := a.StackFileInspect(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Empty(t, content)
 	})
@@ -1057,13 +1135,13 @@ func TestAdapterStackFileInspect(t *testing.T) {
 func TestAdapterStackUpdateGit(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":1}`})
-		result, err := a.StackUpdateGit(1, 1, &apimodels.StacksStackGitUpdatePayload{})
+		result, err := a.StackUpdateGit(context.Background(), 1, 1, &apimodels.StacksStackGitUpdatePayload{})
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.StackUpdateGit(1, 1, &apimodels.StacksStackGitUpdatePayload{})
+		result, err := a.StackUpdateGit(context.Background(), 1, 1, &apimodels.StacksStackGitUpdatePayload{})
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -1072,13 +1150,13 @@ func TestAdapterStackUpdateGit(t *testing.T) {
 func TestAdapterStackGitRedeploy(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":1}`})
-		result, err := a.StackGitRedeploy(1, 1, &apimodels.StacksStackGitRedployPayload{})
+		result, err := a.StackGitRedeploy(context.Background(), 1, 1, &apimodels.StacksStackGitRedployPayload{})
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.StackGitRedeploy(1, 1, &apimodels.StacksStackGitRedployPayload{})
+		result, err := a.StackGitRedeploy(context.Background(), 1, 1, &apimodels.StacksStackGitRedployPayload{})
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -1087,13 +1165,13 @@ func TestAdapterStackGitRedeploy(t *testing.T) {
 func TestAdapterStackStart(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":1}`})
-		result, err := a.StackStart(1, 1)
+		result, err := a.StackStart(context.Background(), 1, 1)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.StackStart(1, 1)
+		result, err := a.StackStart(context.Background(), 1, 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -1102,13 +1180,13 @@ func TestAdapterStackStart(t *testing.T) {
 func TestAdapterStackStop(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":1}`})
-		result, err := a.StackStop(1, 1)
+		result, err := a.StackStop(context.Background(), 1, 1)
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.StackStop(1, 1)
+		result, err := a.StackStop(context.Background(), 1, 1)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -1117,14 +1195,44 @@ func TestAdapterStackStop(t *testing.T) {
 func TestAdapterStackMigrate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{statusCode: 200, body: `{"Id":1}`})
-		result, err := a.StackMigrate(1, 1, &apimodels.StacksStackMigratePayload{})
+		result, err := a.StackMigrate(context.Background(), 1, 1, &apimodels.StacksStackMigratePayload{})
 		assert.NoError(t, err)
 		require.NotNil(t, result)
 	})
 	t.Run("transport error", func(t *testing.T) {
 		a := newTestAdapter(&mockRoundTripper{err: errTransport})
-		result, err := a.StackMigrate(1, 1, &apimodels.StacksStackMigratePayload{})
+		result, err := a.StackMigrate(context.Background(), 1, 1, &apimodels.StacksStackMigratePayload{})
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
 }
+
+// TestAdapterTransportWrapper verifies that the transport wrapper sees the
+// requests of both the Swagger client and the proxy.
+func TestAdapterTransportWrapper(t *testing.T) {
+	rt := &mockRoundTripper{statusCode: 200, body: `[]`}
+	var paths []string
+	a := newPortainerAPIAdapter("http://portainer.local", "secret", clientOptions{
+		wrapTransport: func(next http.RoundTripper) http.RoundTripper {
+			assert.IsType(t, &http.Transport{}, next)
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				paths = append(paths, req.URL.Path)
+				return rt.RoundTrip(req)
+			})
+		},
+	})
+
+	_, err := a.ListTags(context.Background())
+	require.NoError(t, err)
+	_, err = a.ProxyDockerRequest(context.Background(), 1, sdkclient.ProxyRequestOptions{Method: "GET", APIPath: "/info"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/api/tags", "/api/endpoints/1/docker/info"}, paths)
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -1,14 +1,15 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 )
 
 // GetAppTemplates retrieves all application templates.
-func (c *PortainerClient) GetAppTemplates() ([]models.AppTemplate, error) {
-	raw, err := c.cli.ListAppTemplates()
+func (c *PortainerClient) GetAppTemplates(ctx context.Context) ([]models.AppTemplate, error) {
+	raw, err := c.cli.ListAppTemplates(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get app templates: %w", err)
 	}
@@ -17,8 +18,8 @@ func (c *PortainerClient) GetAppTemplates() ([]models.AppTemplate, error) {
 }
 
 // GetAppTemplateFile retrieves the file content of an application template.
-func (c *PortainerClient) GetAppTemplateFile(id int) (string, error) {
-	content, err := c.cli.GetAppTemplateFile(int64(id))
+func (c *PortainerClient) GetAppTemplateFile(ctx context.Context, id int) (string, error) {
+	content, err := c.cli.GetAppTemplateFile(ctx, int64(id))
 	if err != nil {
 		return "", fmt.Errorf("failed to get app template file: %w", err)
 	}
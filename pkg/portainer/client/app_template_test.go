@@ -3,6 +3,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -45,7 +46,7 @@ func TestGetAppTemplates(t *testing.T) {
 			mockAPI.On("ListAppTemplates").Return(tt.mockTemplates, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetAppTemplates()
+			result, err := c.GetAppTemplates(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -87,7 +88,7 @@ func TestGetAppTemplateFile(t *testing.T) {
 			mockAPI.On("GetAppTemplateFile", int64(tt.id)).Return(tt.mockContent, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetAppTemplateFile(tt.id)
+			result, err := c.GetAppTemplateFile(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -16,8 +17,8 @@ import (
 // Returns:
 //   - An AuthResponse containing the JWT token
 //   - An error if the operation fails
-func (c *PortainerClient) AuthenticateUser(username, password string) (models.AuthResponse, error) {
-	resp, err := c.cli.AuthenticateUser(username, password)
+func (c *PortainerClient) AuthenticateUser(ctx context.Context, username, password string) (models.AuthResponse, error) {
+	resp, err := c.cli.AuthenticateUser(ctx, username, password)
 	if err != nil {
 		return models.AuthResponse{}, fmt.Errorf("failed to authenticate user: %w", err)
 	}
@@ -31,8 +32,8 @@ func (c *PortainerClient) AuthenticateUser(username, password string) (models.Au
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) Logout() error {
-	err := c.cli.Logout()
+func (c *PortainerClient) Logout(ctx context.Context) error {
+	err := c.cli.Logout(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to logout: %w", err)
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -46,7 +47,7 @@ func TestAuthenticateUser(t *testing.T) {
 			mockAPI.On("AuthenticateUser", tt.username, tt.password).Return(tt.mockResponse, tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			result, err := client.AuthenticateUser(tt.username, tt.password)
+			result, err := client.AuthenticateUser(context.Background(), tt.username, tt.password)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -83,7 +84,7 @@ func TestLogout(t *testing.T) {
 			mockAPI.On("Logout").Return(tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			err := client.Logout()
+			err := client.Logout(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
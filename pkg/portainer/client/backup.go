@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
@@ -8,8 +9,8 @@ import (
 )
 
 // GetBackupStatus retrieves the status of the last backup.
-func (c *PortainerClient) GetBackupStatus() (models.BackupStatus, error) {
-	raw, err := c.cli.GetBackupStatus()
+func (c *PortainerClient) GetBackupStatus(ctx context.Context) (models.BackupStatus, error) {
+	raw, err := c.cli.GetBackupStatus(ctx)
 	if err != nil {
 		return models.BackupStatus{}, fmt.Errorf("failed to get backup status: %w", err)
 	}
@@ -18,8 +19,8 @@ func (c *PortainerClient) GetBackupStatus() (models.BackupStatus, error) {
 }
 
 // GetBackupS3Settings retrieves the S3 backup settings.
-func (c *PortainerClient) GetBackupS3Settings() (models.S3BackupSettings, error) {
-	raw, err := c.cli.GetBackupSettings()
+func (c *PortainerClient) GetBackupS3Settings(ctx context.Context) (models.S3BackupSettings, error) {
+	raw, err := c.cli.GetBackupSettings(ctx)
 	if err != nil {
 		return models.S3BackupSettings{}, fmt.Errorf("failed to get backup S3 settings: %w", err)
 	}
@@ -28,12 +29,12 @@ func (c *PortainerClient) GetBackupS3Settings() (models.S3BackupSettings, error)
 }
 
 // CreateBackup triggers a backup with an optional password.
-func (c *PortainerClient) CreateBackup(password string) error {
-	return c.cli.CreateBackup(password)
+func (c *PortainerClient) CreateBackup(ctx context.Context, password string) error {
+	return c.cli.CreateBackup(ctx, password)
 }
 
 // BackupToS3 triggers a backup to S3.
-func (c *PortainerClient) BackupToS3(settings models.S3BackupSettings) error {
+func (c *PortainerClient) BackupToS3(ctx context.Context, settings models.S3BackupSettings) error {
 	body := &apimodels.BackupS3BackupPayload{
 		AccessKeyID:      settings.AccessKeyID,
 		BucketName:       settings.BucketName,
@@ -44,11 +45,11 @@ func (c *PortainerClient) BackupToS3(settings models.S3BackupSettings) error {
 		SecretAccessKey:  settings.SecretAccessKey,
 	}
 
-	return c.cli.BackupToS3(body)
+	return c.cli.BackupToS3(ctx, body)
 }
 
 // RestoreFromS3 triggers a restore from S3.
-func (c *PortainerClient) RestoreFromS3(accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error {
+func (c *PortainerClient) RestoreFromS3(ctx context.Context, accessKeyID, bucketName, filename, password, region, s3CompatibleHost, secretAccessKey string) error {
 	body := &apimodels.BackupRestoreS3Settings{
 		AccessKeyID:      accessKeyID,
 		BucketName:       bucketName,
@@ -59,5 +60,5 @@ func (c *PortainerClient) RestoreFromS3(accessKeyID, bucketName, filename, passw
 		SecretAccessKey:  secretAccessKey,
 	}
 
-	return c.cli.RestoreFromS3(body)
+	return c.cli.RestoreFromS3(ctx, body)
 }
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -42,7 +43,7 @@ func TestGetBackupStatus(t *testing.T) {
 			mockAPI.On("GetBackupStatus").Return(tt.mockStatus, tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			status, err := client.GetBackupStatus()
+			status, err := client.GetBackupStatus(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -90,7 +91,7 @@ func TestGetBackupS3Settings(t *testing.T) {
 			mockAPI.On("GetBackupSettings").Return(tt.mockSettings, tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			settings, err := client.GetBackupS3Settings()
+			settings, err := client.GetBackupS3Settings(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -129,7 +130,7 @@ func TestCreateBackup(t *testing.T) {
 			mockAPI.On("CreateBackup", tt.password).Return(tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			err := client.CreateBackup(tt.password)
+			err := client.CreateBackup(context.Background(), tt.password)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -185,7 +186,7 @@ func TestBackupToS3(t *testing.T) {
 			mockAPI.On("BackupToS3", expectedBody).Return(tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			err := client.BackupToS3(tt.settings)
+			err := client.BackupToS3(context.Background(), tt.settings)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -229,7 +230,7 @@ func TestRestoreFromS3(t *testing.T) {
 			mockAPI.On("RestoreFromS3", expectedBody).Return(tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			err := client.RestoreFromS3("AKID123", "my-bucket", "backup.tar.gz", "pass", "us-east-1", "minio.example.com", "secret")
+			err := client.RestoreFromS3(context.Background(), "AKID123", "my-bucket", "backup.tar.gz", "pass", "us-east-1", "minio.example.com", "secret")
 
 			if tt.expectedError {
 				assert.Error(t, err)
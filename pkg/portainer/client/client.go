@@ -6,122 +6,140 @@
 package client
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/portainer/client-api-go/v2/client"
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// PortainerAPIClient defines the interface for the underlying Portainer API client
+// PortainerAPIClient defines the interface for the underlying Portainer API client.
+// The context passed to each method is attached to the outgoing HTTP request.
 type PortainerAPIClient interface {
-	ListEdgeGroups() ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error)
-	CreateEdgeGroup(name string, environmentIds []int64) (int64, error)
-	UpdateEdgeGroup(id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error
-	ListEdgeStacks() ([]*apimodels.PortainereeEdgeStack, error)
-	ListRegularStacks() ([]*apimodels.PortainereeStack, error)
-	CreateEdgeStack(name string, file string, environmentGroupIds []int64) (int64, error)
-	UpdateEdgeStack(id int64, file string, environmentGroupIds []int64) error
-	GetEdgeStackFile(id int64) (string, error)
-	ListEndpointGroups() ([]*apimodels.PortainerEndpointGroup, error)
-	CreateEndpointGroup(name string, associatedEndpoints []int64) (int64, error)
-	UpdateEndpointGroup(id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
-	AddEnvironmentToEndpointGroup(groupId int64, environmentId int64) error
-	RemoveEnvironmentFromEndpointGroup(groupId int64, environmentId int64) error
-	ListEndpoints() ([]*apimodels.PortainereeEndpoint, error)
-	GetEndpoint(id int64) (*apimodels.PortainereeEndpoint, error)
-	UpdateEndpoint(id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
-	DeleteEndpoint(id int64) error
-	SnapshotEndpoint(id int64) error
-	SnapshotAllEndpoints() error
-	GetSettings() (*apimodels.PortainereeSettings, error)
-	UpdateSettings(payload *apimodels.SettingsSettingsUpdatePayload) error
-	GetPublicSettings() (*apimodels.SettingsPublicSettingsResponse, error)
-	GetSSLSettings() (*apimodels.PortainereeSSLSettings, error)
-	UpdateSSLSettings(payload *apimodels.SslSslUpdatePayload) error
-	ListAppTemplates() ([]*apimodels.PortainerTemplate, error)
-	GetAppTemplateFile(id int64) (string, error)
-	ListTags() ([]*apimodels.PortainerTag, error)
-	CreateTag(name string) (int64, error)
-	DeleteTag(id int64) error
-	ListTeams() ([]*apimodels.PortainerTeam, error)
-	GetTeam(id int64) (*apimodels.PortainerTeam, error)
-	ListTeamMemberships() ([]*apimodels.PortainerTeamMembership, error)
-	CreateTeam(name string) (int64, error)
-	UpdateTeamName(id int, name string) error
-	DeleteTeam(id int64) error
-	DeleteTeamMembership(id int) error
-	CreateTeamMembership(teamId int, userId int) error
-	ListUsers() ([]*apimodels.PortainereeUser, error)
-	CreateUser(username, password string, role int64) (int64, error)
-	GetUser(id int) (*apimodels.PortainereeUser, error)
-	DeleteUser(id int64) error
-	UpdateUserRole(id int, role int64) error
-	GetVersion() (string, error)
-	GetSystemStatus() (*apimodels.GithubComPortainerPortainerEeAPIHTTPHandlerSystemStatus, error)
-	ListRegistries() ([]*apimodels.PortainereeRegistry, error)
-	GetRegistryByID(id int64) (*apimodels.PortainereeRegistry, error)
-	CreateRegistry(body *apimodels.RegistriesRegistryCreatePayload) (int64, error)
-	UpdateRegistry(id int64, body *apimodels.RegistriesRegistryUpdatePayload) error
-	DeleteRegistry(id int64) error
-	ProxyDockerRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
-	ProxyKubernetesRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
-	ListCustomTemplates() ([]*apimodels.PortainereeCustomTemplate, error)
-	GetCustomTemplate(id int64) (*apimodels.PortainereeCustomTemplate, error)
-	GetCustomTemplateFile(id int64) (string, error)
-	CreateCustomTemplate(payload *apimodels.CustomtemplatesCustomTemplateFromFileContentPayload) (*apimodels.PortainereeCustomTemplate, error)
-	DeleteCustomTemplate(id int64) error
-	GetBackupStatus() (*apimodels.BackupBackupStatus, error)
-	GetBackupSettings() (*apimodels.PortainereeS3BackupSettings, error)
-	CreateBackup(password string) error
-	BackupToS3(body *apimodels.BackupS3BackupPayload) error
-	RestoreFromS3(body *apimodels.BackupRestoreS3Settings) error
-	ListRoles() ([]*apimodels.PortainereeRole, error)
-	GetMOTD() (map[string]any, error)
-	AuthenticateUser(username, password string) (*apimodels.AuthAuthenticateResponse, error)
-	Logout() error
-	ListWebhooks() ([]*apimodels.PortainerWebhook, error)
-	CreateWebhook(resourceId string, endpointId int64, webhookType int64) (int64, error)
-	DeleteWebhook(id int64) error
-	ListEdgeJobs() ([]*apimodels.PortainerEdgeJob, error)
-	GetEdgeJob(id int64) (*apimodels.PortainerEdgeJob, error)
-	GetEdgeJobFile(id int64) (string, error)
-	CreateEdgeJob(payload *apimodels.EdgejobsEdgeJobCreateFromFileContentPayload) (int64, error)
-	DeleteEdgeJob(id int64) error
-	ListEdgeUpdateSchedules() ([]*apimodels.EdgeupdateschedulesDecoratedUpdateSchedule, error)
-	ListHelmRepositories(userId int64) (*apimodels.UsersHelmUserRepositoryResponse, error)
-	CreateHelmRepository(userId int64, url string) (*apimodels.PortainerHelmUserRepository, error)
-	DeleteHelmRepository(userId int64, repositoryId int64) error
-	SearchHelmCharts(repo string, chart *string) (string, error)
-	InstallHelmChart(environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error)
-	ListHelmReleases(environmentId int64, namespace *string, filter *string, selector *string) ([]*apimodels.ReleaseReleaseElement, error)
-	DeleteHelmRelease(environmentId int64, release string, namespace *string) error
-	GetHelmReleaseHistory(environmentId int64, name string, namespace *string) ([]*apimodels.ReleaseRelease, error)
-	GetDockerDashboard(environmentId int64) (*apimodels.DockerDashboardResponse, error)
-	GetKubernetesDashboard(environmentId int64) (*apimodels.KubernetesK8sDashboard, error)
-	GetKubernetesNamespaces(environmentId int64) ([]*apimodels.PortainerK8sNamespaceInfo, error)
-	GetKubernetesConfig(environmentId int64) (interface{}, error)
-	StackInspect(id int64) (*apimodels.PortainereeStack, error)
-	StackDelete(id int64, endpointID int64, removeVolumes bool) error
-	StackFileInspect(id int64) (string, error)
-	StackUpdateGit(id int64, endpointID int64, body *apimodels.StacksStackGitUpdatePayload) (*apimodels.PortainereeStack, error)
-	StackGitRedeploy(id int64, endpointID int64, body *apimodels.StacksStackGitRedployPayload) (*apimodels.PortainereeStack, error)
-	StackStart(id int64, endpointID int64) (*apimodels.PortainereeStack, error)
-	StackStop(id int64, endpointID int64) (*apimodels.PortainereeStack, error)
-	StackMigrate(id int64, endpointID int64, body *apimodels.StacksStackMigratePayload) (*apimodels.PortainereeStack, error)
+	ListEdgeGroups(ctx context.Context) ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error)
+	CreateEdgeGroup(ctx context.Context, name string, environmentIds []int64) (int64, error)
+	UpdateEdgeGroup(ctx context.Context, id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error
+	ListEdgeStacks(ctx context.Context) ([]*apimodels.PortainereeEdgeStack, error)
+	ListRegularStacks(ctx context.Context) ([]*apimodels.PortainereeStack, error)
+	CreateEdgeStack(ctx context.Context, name string, file string, environmentGroupIds []int64) (int64, error)
+	UpdateEdgeStack(ctx context.Context, id int64, file string, environmentGroupIds []int64) error
+	GetEdgeStackFile(ctx context.Context, id int64) (string, error)
+	ListEndpointGroups(ctx context.Context) ([]*apimodels.PortainerEndpointGroup, error)
+	CreateEndpointGroup(ctx context.Context, name string, associatedEndpoints []int64) (int64, error)
+	UpdateEndpointGroup(ctx context.Context, id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
+	AddEnvironmentToEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error
+	RemoveEnvironmentFromEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error
+	ListEndpoints(ctx context.Context) ([]*apimodels.PortainereeEndpoint, error)
+	GetEndpoint(ctx context.Context, id int64) (*apimodels.PortainereeEndpoint, error)
+	UpdateEndpoint(ctx context.Context, id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
+	DeleteEndpoint(ctx context.Context, id int64) error
+	SnapshotEndpoint(ctx context.Context, id int64) error
+	SnapshotAllEndpoints(ctx context.Context) error
+	GetSettings(ctx context.Context) (*apimodels.PortainereeSettings, error)
+	UpdateSettings(ctx context.Context, payload *apimodels.SettingsSettingsUpdatePayload) error
+	GetPublicSettings(ctx context.Context) (*apimodels.SettingsPublicSettingsResponse, error)
+	GetSSLSettings(ctx context.Context) (*apimodels.PortainereeSSLSettings, error)
+	UpdateSSLSettings(ctx context.Context, payload *apimodels.SslSslUpdatePayload) error
+	ListAppTemplates(ctx context.Context) ([]*apimodels.PortainerTemplate, error)
+	GetAppTemplateFile(ctx context.Context, id int64) (string, error)
+	ListTags(ctx context.Context) ([]*apimodels.PortainerTag, error)
+	CreateTag(ctx context.Context, name string) (int64, error)
+	DeleteTag(ctx context.Context, id int64) error
+	ListTeams(ctx context.Context) ([]*apimodels.PortainerTeam, error)
+	GetTeam(ctx context.Context, id int64) (*apimodels.PortainerTeam, error)
+	ListTeamMemberships(ctx context.Context) ([]*apimodels.PortainerTeamMembership, error)
+	CreateTeam(ctx context.Context, name string) (int64, error)
+	UpdateTeamName(ctx context.Context, id int, name string) error
+	DeleteTeam(ctx context.Context, id int64) error
+	DeleteTeamMembership(ctx context.Context, id int) error
+	CreateTeamMembership(ctx context.Context, teamId int, userId int) error
+	ListUsers(ctx context.Context) ([]*apimodels.PortainereeUser, error)
+	CreateUser(ctx context.Context, username, password string, role int64) (int64, error)
+	GetUser(ctx context.Context, id int) (*apimodels.PortainereeUser, error)
+	DeleteUser(ctx context.Context, id int64) error
+	UpdateUserRole(ctx context.Context, id int, role int64) error
+	GetVersion(ctx context.Context) (string, error)
+	GetSystemStatus(ctx context.Context) (*apimodels.GithubComPortainerPortainerEeAPIHTTPHandlerSystemStatus, error)
+	ListRegistries(ctx context.Context) ([]*apimodels.PortainereeRegistry, error)
+	GetRegistryByID(ctx context.Context, id int64) (*apimodels.PortainereeRegistry, error)
+	CreateRegistry(ctx context.Context, body *apimodels.RegistriesRegistryCreatePayload) (int64, error)
+	UpdateRegistry(ctx context.Context, id int64, body *apimodels.RegistriesRegistryUpdatePayload) error
+	DeleteRegistry(ctx context.Context, id int64) error
+	ProxyDockerRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
+	ProxyKubernetesRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
+	ListCustomTemplates(ctx context.Context) ([]*apimodels.PortainereeCustomTemplate, error)
+	GetCustomTemplate(ctx context.Context, id int64) (*apimodels.PortainereeCustomTemplate, error)
+	GetCustomTemplateFile(ctx context.Context, id int64) (string, error)
+	CreateCustomTemplate(ctx context.Context, payload *apimodels.CustomtemplatesCustomTemplateFromFileContentPayload) (*apimodels.PortainereeCustomTemplate, error)
+	DeleteCustomTemplate(ctx context.Context, id int64) error
+	GetBackupStatus(ctx context.Context) (*apimodels.BackupBackupStatus, error)
+	GetBackupSettings(ctx context.Context) (*apimodels.PortainereeS3BackupSettings, error)
+	CreateBackup(ctx context.Context, password string) error
+	BackupToS3(ctx context.Context, body *apimodels.BackupS3BackupPayload) error
+	RestoreFromS3(ctx context.Context, body *apimodels.BackupRestoreS3Settings) error
+	ListRoles(ctx context.Context) ([]*apimodels.PortainereeRole, error)
+	GetMOTD(ctx context.Context) (map[string]any, error)
+	AuthenticateUser(ctx context.Context, username, password string) (*apimodels.AuthAuthenticateResponse, error)
+	Logout(ctx context.Context) error
+	ListWebhooks(ctx context.Context) ([]*apimodels.PortainerWebhook, error)
+	CreateWebhook(ctx context.Context, resourceId string, endpointId int64, webhookType int64) (int64, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListEdgeJobs(ctx context.Context) ([]*apimodels.PortainerEdgeJob, error)
+	GetEdgeJob(ctx context.Context, id int64) (*apimodels.PortainerEdgeJob, error)
+	GetEdgeJobFile(ctx context.Context, id int64) (string, error)
+	CreateEdgeJob(ctx context.Context, payload *apimodels.EdgejobsEdgeJobCreateFromFileContentPayload) (int64, error)
+	DeleteEdgeJob(ctx context.Context, id int64) error
+	ListEdgeUpdateSchedules(ctx context.Context) ([]*apimodels.EdgeupdateschedulesDecoratedUpdateSchedule, error)
+	ListHelmRepositories(ctx context.Context, userId int64) (*apimodels.UsersHelmUserRepositoryResponse, error)
+	CreateHelmRepository(ctx context.Context, userId int64, url string) (*apimodels.PortainerHelmUserRepository, error)
+	DeleteHelmRepository(ctx context.Context, userId int64, repositoryId int64) error
+	SearchHelmCharts(ctx context.Context, repo string, chart *string) (string, error)
+	InstallHelmChart(ctx context.Context, environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error)
+	ListHelmReleases(ctx context.Context, environmentId int64, namespace *string, filter *string, selector *string) ([]*apimodels.ReleaseReleaseElement, error)
+	DeleteHelmRelease(ctx context.Context, environmentId int64, release string, namespace *string) error
+	GetHelmReleaseHistory(ctx context.Context, environmentId int64, name string, namespace *string) ([]*apimodels.ReleaseRelease, error)
+	GetDockerDashboard(ctx context.Context, environmentId int64) (*apimodels.DockerDashboardResponse, error)
+	GetKubernetesDashboard(ctx context.Context, environmentId int64) (*apimodels.KubernetesK8sDashboard, error)
+	GetKubernetesNamespaces(ctx context.Context, environmentId int64) ([]*apimodels.PortainerK8sNamespaceInfo, error)
+	GetKubernetesConfig(ctx context.Context, environmentId int64) (interface{}, error)
+	StackInspect(ctx context.Context, id int64) (*apimodels.PortainereeStack, error)
+	StackDelete(ctx context.Context, id int64, endpointID int64, removeVolumes bool) error
+	StackFileInspect(ctx context.Context, id int64) (string, error)
+	StackUpdateGit(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackGitUpdatePayload) (*apimodels.PortainereeStack, error)
+	StackGitRedeploy(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackGitRedployPayload) (*apimodels.PortainereeStack, error)
+	StackStart(ctx context.Context, id int64, endpointID int64) (*apimodels.PortainereeStack, error)
+	StackStop(ctx context.Context, id int64, endpointID int64) (*apimodels.PortainereeStack, error)
+	StackMigrate(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackMigratePayload) (*apimodels.PortainereeStack, error)
 }
 
 // PortainerClient is a wrapper around the Portainer SDK client
 // that provides simplified access to Portainer API functionality.
 type PortainerClient struct {
-	cli PortainerAPIClient
+	cli               PortainerAPIClient
+	checkProxyRequest ProxyRequestCheck
 }
 
+// Proxy APIs passed to a [ProxyRequestCheck].
+const (
+	ProxyAPIDocker     = "docker"
+	ProxyAPIKubernetes = "kubernetes"
+)
+
+// ProxyRequestCheck decides whether a Docker or Kubernetes API request may be
+// sent through the Portainer proxy. It returns an error to refuse it.
+type ProxyRequestCheck func(api, method, path string) error
+
 // ClientOption defines a function that configures a PortainerClient.
 type ClientOption func(*clientOptions)
 
 // clientOptions holds configuration options for the PortainerClient.
 type clientOptions struct {
-	skipTLSVerify bool
+	skipTLSVerify  bool
+	useJWT         bool
+	wrapTransport  func(http.RoundTripper) http.RoundTripper
+	tracerProvider trace.TracerProvider
+	proxyCheck     ProxyRequestCheck
 }
 
 // WithSkipTLSVerify configures whether to skip TLS certificate verification.
@@ -132,12 +150,49 @@ func WithSkipTLSVerify(skip bool) ClientOption {
 	}
 }
 
+// WithJWT configures whether the token is a Portainer user JWT rather than an
+// API key. JWTs are sent as an "Authorization: Bearer" header, API keys as X-API-Key.
+func WithJWT(jwt bool) ClientOption {
+	return func(o *clientOptions) {
+		o.useJWT = jwt
+	}
+}
+
+// WithTransportWrapper wraps the HTTP transport that sends each request to
+// Portainer, for example to record metrics. The wrapper sees every attempt of
+// a retried request, including the failed ones.
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.wrapTransport = wrap
+	}
+}
+
+// WithTracerProvider records every request sent to Portainer, including the
+// Docker and Kubernetes proxy requests and each retry, as a client span of
+// the given tracer provider. The spans are children of the span in the
+// context passed to the client methods.
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(o *clientOptions) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithProxyRequestCheck checks every Docker and Kubernetes API request before
+// it is sent through the Portainer proxy, including the requests of the typed
+// container, exec, and log methods. A refused request is not sent, and the
+// method returns the error of the check.
+func WithProxyRequestCheck(check ProxyRequestCheck) ClientOption {
+	return func(o *clientOptions) {
+		o.proxyCheck = check
+	}
+}
+
 // NewPortainerClient creates a new PortainerClient instance with the provided
 // server URL and authentication token.
 //
 // Parameters:
 //   - serverURL: The base URL of the Portainer server
-//   - token: The authentication token for API access
+//   - token: The API key or JWT (see WithJWT) for API access
 //   - opts: Optional configuration options for the client
 //
 // Returns:
@@ -152,6 +207,7 @@ func NewPortainerClient(serverURL string, token string, opts ...ClientOption) *P
 	}
 
 	return &PortainerClient{
-		cli: newPortainerAPIAdapter(serverURL, token, options.skipTLSVerify),
+		cli:               newPortainerAPIAdapter(serverURL, token, options),
+		checkProxyRequest: options.proxyCheck,
 	}
 }
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+)
+
+// maxProxyErrorBodySize bounds how much of a failed proxied API response is read for the error message.
+const maxProxyErrorBodySize = 4096
+
+// ListDockerContainers lists the containers of a Docker environment.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - opts: Whether to include stopped containers and the Docker Engine filters to apply
+//
+// Returns:
+//   - A slice of DockerContainer summaries
+//   - An error if the operation fails
+func (c *PortainerClient) ListDockerContainers(ctx context.Context, environmentId int, opts models.DockerContainerListOptions) ([]models.DockerContainer, error) {
+	query := map[string]string{"all": strconv.FormatBool(opts.All)}
+	if len(opts.Filters) > 0 {
+		filters, err := json.Marshal(opts.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode container filters: %w", err)
+		}
+		query["filters"] = string(filters)
+	}
+
+	var raw []models.RawDockerContainerSummary
+	if err := c.dockerJSON(ctx, environmentId, http.MethodGet, "/containers/json", query, nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to list docker containers: %w", err)
+	}
+
+	containers := make([]models.DockerContainer, len(raw))
+	for i, r := range raw {
+		containers[i] = models.ConvertDockerContainer(r)
+	}
+
+	return containers, nil
+}
+
+// InspectDockerContainer retrieves the details of a Docker container.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//
+// Returns:
+//   - The DockerContainerDetails of the container
+//   - An error if the operation fails
+func (c *PortainerClient) InspectDockerContainer(ctx context.Context, environmentId int, containerId string) (models.DockerContainerDetails, error) {
+	var raw models.RawDockerContainerInspect
+	if err := c.dockerJSON(ctx, environmentId, http.MethodGet, containerPath(containerId, "json"), nil, nil, &raw); err != nil {
+		return models.DockerContainerDetails{}, fmt.Errorf("failed to inspect docker container: %w", err)
+	}
+
+	return models.ConvertDockerContainerDetails(raw), nil
+}
+
+// StartDockerContainer starts a Docker container. Starting a running container is not an error.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//
+// Returns:
+//   - An error if the operation fails
+func (c *PortainerClient) StartDockerContainer(ctx context.Context, environmentId int, containerId string) error {
+	if err := c.dockerAction(ctx, environmentId, http.MethodPost, containerPath(containerId, "start"), nil); err != nil {
+		return fmt.Errorf("failed to start docker container: %w", err)
+	}
+	return nil
+}
+
+// StopDockerContainer stops a Docker container. Stopping a stopped container is not an error.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//   - timeout: Optional number of seconds to wait before killing the container (nil uses the container default)
+//
+// Returns:
+//   - An error if the operation fails
+func (c *PortainerClient) StopDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error {
+	if err := c.dockerAction(ctx, environmentId, http.MethodPost, containerPath(containerId, "stop"), timeoutQuery(timeout)); err != nil {
+		return fmt.Errorf("failed to stop docker container: %w", err)
+	}
+	return nil
+}
+
+// RestartDockerContainer restarts a Docker container.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//   - timeout: Optional number of seconds to wait before killing the container (nil uses the container default)
+//
+// Returns:
+//   - An error if the operation fails
+func (c *PortainerClient) RestartDockerContainer(ctx context.Context, environmentId int, containerId string, timeout *int) error {
+	if err := c.dockerAction(ctx, environmentId, http.MethodPost, containerPath(containerId, "restart"), timeoutQuery(timeout)); err != nil {
+		return fmt.Errorf("failed to restart docker container: %w", err)
+	}
+	return nil
+}
+
+// KillDockerContainer sends a signal to a running Docker container.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//   - signal: Optional signal name (e.g. "SIGTERM"); empty sends SIGKILL
+//
+// Returns:
+//   - An error if the operation fails
+func (c *PortainerClient) KillDockerContainer(ctx context.Context, environmentId int, containerId string, signal string) error {
+	var query map[string]string
+	if signal != "" {
+		query = map[string]string{"signal": signal}
+	}
+
+	if err := c.dockerAction(ctx, environmentId, http.MethodPost, containerPath(containerId, "kill"), query); err != nil {
+		return fmt.Errorf("failed to kill docker container: %w", err)
+	}
+	return nil
+}
+
+// RemoveDockerContainer removes a Docker container.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//   - force: Whether to kill the container first if it is running
+//   - removeVolumes: Whether to remove the anonymous volumes of the container
+//
+// Returns:
+//   - An error if the operation fails
+func (c *PortainerClient) RemoveDockerContainer(ctx context.Context, environmentId int, containerId string, force bool, removeVolumes bool) error {
+	query := map[string]string{
+		"force": strconv.FormatBool(force),
+		"v":     strconv.FormatBool(removeVolumes),
+	}
+
+	if err := c.dockerAction(ctx, environmentId, http.MethodDelete, containerPath(containerId, ""), query); err != nil {
+		return fmt.Errorf("failed to remove docker container: %w", err)
+	}
+	return nil
+}
+
+// RenameDockerContainer renames a Docker container.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//   - name: The new container name
+//
+// Returns:
+//   - An error if the operation fails
+func (c *PortainerClient) RenameDockerContainer(ctx context.Context, environmentId int, containerId string, name string) error {
+	query := map[string]string{"name": name}
+
+	if err := c.dockerAction(ctx, environmentId, http.MethodPost, containerPath(containerId, "rename"), query); err != nil {
+		return fmt.Errorf("failed to rename docker container: %w", err)
+	}
+	return nil
+}
+
+// containerPath builds the Docker Engine API path of a container, optionally followed by an operation.
+func containerPath(containerId, operation string) string {
+	path := "/containers/" + url.PathEscape(containerId)
+	if operation != "" {
+		path += "/" + operation
+	}
+	return path
+}
+
+// timeoutQuery returns the "t" query parameter used by the stop and restart endpoints.
+func timeoutQuery(timeout *int) map[string]string {
+	if timeout == nil {
+		return nil
+	}
+	return map[string]string{"t": strconv.Itoa(*timeout)}
+}
+
+// dockerJSON sends a Docker API request through the Portainer proxy and decodes the JSON response into out.
+// A non-nil body is sent JSON-encoded.
+func (c *PortainerClient) dockerJSON(ctx context.Context, environmentId int, method, path string, query map[string]string, body, out any) error {
+	resp, err := c.dockerRequest(ctx, environmentId, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode docker response: %w", err)
+	}
+	return nil
+}
+
+// dockerAction sends a Docker API request that has no response body of interest.
+// A 304 Not Modified (e.g. starting a running container) is treated as success.
+func (c *PortainerClient) dockerAction(ctx context.Context, environmentId int, method, path string, query map[string]string) error {
+	resp, err := c.dockerRequest(ctx, environmentId, method, path, query, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// dockerRequest sends a Docker API request through the Portainer proxy, with a
+// JSON-encoded body when body is not nil. Responses with a status of 400 or
+// above are converted into an error carrying the Docker error message, and
+// their body is closed.
+func (c *PortainerClient) dockerRequest(ctx context.Context, environmentId int, method, path string, query map[string]string, body any) (*http.Response, error) {
+	opts := models.DockerProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Method:        method,
+		Path:          path,
+		QueryParams:   query,
+	}
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode docker request body: %w", err)
+		}
+		opts.Body = bytes.NewReader(payload)
+		opts.Headers = map[string]string{"Content-Type": "application/json"}
+	}
+
+	resp, err := c.ProxyDockerRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, proxyError("docker", resp)
+	}
+
+	return resp, nil
+}
+
+// proxyError builds an *APIError from a failed Docker or Kubernetes API
+// response, using the "message" field of the error body when present. Both
+// APIs return their errors in that field.
+func proxyError(api string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxProxyErrorBodySize))
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	message := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+		message = apiErr.Message
+	}
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Kind:       statusKind(resp.StatusCode),
+		Err:        fmt.Errorf("%s API returned status %d: %s", api, resp.StatusCode, message),
+	}
+}
@@ -0,0 +1,254 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+)
+
+const (
+	// maxDockerLogSize bounds how much log output is kept from the Docker API.
+	// Older output beyond it is dropped, so that the newest lines are kept.
+	maxDockerLogSize = 1 << 20
+
+	// dockerLogReadSize is the size of the chunks in which log output is read.
+	dockerLogReadSize = 32 << 10
+
+	// dockerStreamHeaderSize is the size of the header that prefixes every frame
+	// of a multiplexed Docker stream: one byte for the stream type, three
+	// padding bytes, and a big-endian uint32 payload length.
+	dockerStreamHeaderSize = 8
+
+	// Stream labels used for decoded Docker output.
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
+
+// GetDockerContainerLogs retrieves the logs of a Docker container and decodes
+// the multiplexed stdout/stderr stream into labelled lines.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//   - opts: The tail, time range, and timestamp options
+//
+// Returns:
+//   - The DockerContainerLogs of the container
+//   - An error if the operation fails
+func (c *PortainerClient) GetDockerContainerLogs(ctx context.Context, environmentId int, containerId string, opts models.DockerContainerLogOptions) (models.DockerContainerLogs, error) {
+	query := map[string]string{
+		"stdout":     "true",
+		"stderr":     "true",
+		"tail":       "all",
+		"timestamps": strconv.FormatBool(opts.Timestamps),
+	}
+	if opts.Tail > 0 {
+		query["tail"] = strconv.Itoa(opts.Tail)
+	}
+	if opts.Since > 0 {
+		query["since"] = strconv.FormatInt(opts.Since, 10)
+	}
+	if opts.Until > 0 {
+		query["until"] = strconv.FormatInt(opts.Until, 10)
+	}
+
+	resp, err := c.dockerRequest(ctx, environmentId, http.MethodGet, containerPath(containerId, "logs"), query, nil)
+	if err != nil {
+		return models.DockerContainerLogs{}, fmt.Errorf("failed to get docker container logs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, multiplexed, truncated, err := readDockerLogTail(resp.Body, maxDockerLogSize)
+	if err != nil {
+		return models.DockerContainerLogs{}, fmt.Errorf("failed to read docker container logs: %w", err)
+	}
+
+	logs := models.DockerContainerLogs{Truncated: truncated}
+	if truncated {
+		data = resyncDockerStream(data, multiplexed)
+	}
+	logs.Lines = demuxDockerStream(data)
+
+	return logs, nil
+}
+
+// readDockerLogTail reads Docker output to the end and returns at most its
+// last limit bytes, whether the output is a multiplexed stream, and whether
+// older output was dropped. The multiplexed check is made on the start of the
+// output, which the returned bytes no longer hold once output was dropped.
+func readDockerLogTail(r io.Reader, limit int) ([]byte, bool, bool, error) {
+	var (
+		data        []byte
+		multiplexed bool
+		decided     bool
+		truncated   bool
+	)
+	chunk := make([]byte, dockerLogReadSize)
+	for {
+		n, err := r.Read(chunk)
+		data = append(data, chunk[:n]...)
+		if !decided && len(data) >= dockerStreamHeaderSize {
+			multiplexed = isDockerStreamHeader(data)
+			decided = true
+		}
+		// Compact only once twice the limit is buffered, to copy less often.
+		if len(data) > 2*limit {
+			data = append(data[:0], data[len(data)-limit:]...)
+			truncated = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, false, err
+		}
+	}
+
+	if !decided {
+		multiplexed = isDockerStreamHeader(data)
+	}
+	if len(data) > limit {
+		data = data[len(data)-limit:]
+		truncated = true
+	}
+	return data, multiplexed, truncated, nil
+}
+
+// resyncDockerStream drops the partial frame or line at the start of Docker
+// output whose older part was dropped. A multiplexed stream resumes at the
+// first header from which the frames run exactly to the end of the output, as
+// payload bytes can look like a header. Raw output resumes after the first
+// line break.
+func resyncDockerStream(data []byte, multiplexed bool) []byte {
+	if multiplexed {
+		for i := range data {
+			if isDockerFrameSequence(data[i:]) {
+				return data[i:]
+			}
+		}
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return data[i+1:]
+	}
+	return data
+}
+
+// isDockerFrameSequence reports whether data is made of whole frames of a
+// multiplexed Docker stream.
+func isDockerFrameSequence(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	for len(data) > 0 {
+		if !isDockerStreamHeader(data) {
+			return false
+		}
+		size := int(binary.BigEndian.Uint32(data[4:dockerStreamHeaderSize]))
+		data = data[dockerStreamHeaderSize:]
+		if size > len(data) {
+			return false
+		}
+		data = data[size:]
+	}
+	return true
+}
+
+// demuxDockerStream splits Docker output into lines labelled with their stream.
+func demuxDockerStream(data []byte) []models.DockerLogLine {
+	splitter := newLogLineSplitter()
+	forEachDockerFrame(data, splitter.write)
+	return splitter.finish()
+}
+
+// forEachDockerFrame calls fn with the stream and payload of every frame of
+// Docker output.
+//
+// Containers without a TTY produce a multiplexed stream made of frames, each
+// prefixed with an 8-byte header. Containers with a TTY produce raw output,
+// which is passed to fn as stdout. A truncated trailing frame is passed as far
+// as its payload goes.
+func forEachDockerFrame(data []byte, fn func(stream string, payload []byte)) {
+	if !isDockerStreamHeader(data) {
+		if len(data) > 0 {
+			fn(streamStdout, data)
+		}
+		return
+	}
+
+	for len(data) > 0 {
+		if !isDockerStreamHeader(data) {
+			// Not a frame boundary: keep the remaining bytes rather than dropping them.
+			fn(streamStdout, data)
+			return
+		}
+
+		stream := streamStdout
+		if data[0] == 2 {
+			stream = streamStderr
+		}
+		size := int(binary.BigEndian.Uint32(data[4:dockerStreamHeaderSize]))
+		data = data[dockerStreamHeaderSize:]
+		if size > len(data) {
+			size = len(data)
+		}
+
+		fn(stream, data[:size])
+		data = data[size:]
+	}
+}
+
+// isDockerStreamHeader reports whether data starts with a valid multiplexed
+// stream header: a stream type of stdin (0), stdout (1), or stderr (2)
+// followed by three zero bytes.
+func isDockerStreamHeader(data []byte) bool {
+	return len(data) >= dockerStreamHeaderSize && data[0] <= 2 && data[1] == 0 && data[2] == 0 && data[3] == 0
+}
+
+// logLineSplitter accumulates stream payloads and emits complete lines in the
+// order they are terminated. Payloads are not guaranteed to end on a line
+// boundary, so partial lines are buffered per stream.
+type logLineSplitter struct {
+	lines   []models.DockerLogLine
+	pending map[string][]byte
+}
+
+func newLogLineSplitter() *logLineSplitter {
+	return &logLineSplitter{pending: map[string][]byte{}}
+}
+
+func (s *logLineSplitter) write(stream string, p []byte) {
+	buf := append(s.pending[stream], p...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		s.emit(stream, buf[:i])
+		buf = buf[i+1:]
+	}
+	s.pending[stream] = buf
+}
+
+// finish flushes any unterminated lines and returns all lines.
+func (s *logLineSplitter) finish() []models.DockerLogLine {
+	for _, stream := range []string{streamStdout, streamStderr} {
+		if len(s.pending[stream]) > 0 {
+			s.emit(stream, s.pending[stream])
+		}
+	}
+	return s.lines
+}
+
+func (s *logLineSplitter) emit(stream string, line []byte) {
+	s.lines = append(s.lines, models.DockerLogLine{
+		Stream: stream,
+		Text:   strings.TrimSuffix(string(line), "\r"),
+	})
+}
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/portainer/client-api-go/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dockerFrame encodes a payload as a frame of a multiplexed Docker stream.
+func dockerFrame(stream byte, payload string) string {
+	header := make([]byte, dockerStreamHeaderSize)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return string(header) + payload
+}
+
+// TestDemuxDockerStream verifies decoding of multiplexed and raw Docker output.
+func TestDemuxDockerStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []models.DockerLogLine
+	}{
+		{
+			name:     "empty",
+			data:     "",
+			expected: nil,
+		},
+		{
+			name: "interleaved stdout and stderr",
+			data: dockerFrame(1, "starting\n") + dockerFrame(2, "warning\n") + dockerFrame(1, "ready\n"),
+			expected: []models.DockerLogLine{
+				{Stream: "stdout", Text: "starting"},
+				{Stream: "stderr", Text: "warning"},
+				{Stream: "stdout", Text: "ready"},
+			},
+		},
+		{
+			name: "line split across frames",
+			data: dockerFrame(1, "hello ") + dockerFrame(2, "oops\n") + dockerFrame(1, "world\n"),
+			expected: []models.DockerLogLine{
+				{Stream: "stderr", Text: "oops"},
+				{Stream: "stdout", Text: "hello world"},
+			},
+		},
+		{
+			name: "several lines in one frame and unterminated last line",
+			data: dockerFrame(1, "a\r\nb\nc"),
+			expected: []models.DockerLogLine{
+				{Stream: "stdout", Text: "a"},
+				{Stream: "stdout", Text: "b"},
+				{Stream: "stdout", Text: "c"},
+			},
+		},
+		{
+			name: "truncated frame",
+			data: dockerFrame(2, "complete\n") + dockerFrame(1, "partial line")[:dockerStreamHeaderSize+7],
+			expected: []models.DockerLogLine{
+				{Stream: "stderr", Text: "complete"},
+				{Stream: "stdout", Text: "partial"},
+			},
+		},
+		{
+			name: "tty output is not multiplexed",
+			data: "plain output\nsecond line\n",
+			expected: []models.DockerLogLine{
+				{Stream: "stdout", Text: "plain output"},
+				{Stream: "stdout", Text: "second line"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, demuxDockerStream([]byte(tt.data)))
+		})
+	}
+}
+
+// TestGetDockerContainerLogs verifies the log query parameters and size limit.
+func TestGetDockerContainerLogs(t *testing.T) {
+	// Output of over 1 MiB, as 20-byte frames and as 12-byte raw lines, of
+	// which the newest whole frames and lines within the limit are kept.
+	var frames, rawLines strings.Builder
+	var newestFrames, newestRawLines []models.DockerLogLine
+	for i := range 60000 {
+		frames.WriteString(dockerFrame(1, fmt.Sprintf("line %06d\n", i)))
+		if i >= 60000-maxDockerLogSize/20 {
+			newestFrames = append(newestFrames, models.DockerLogLine{Stream: "stdout", Text: fmt.Sprintf("line %06d", i)})
+		}
+	}
+	for i := range 100000 {
+		fmt.Fprintf(&rawLines, "row %07d\n", i)
+		if i >= 100000-maxDockerLogSize/12 {
+			newestRawLines = append(newestRawLines, models.DockerLogLine{Stream: "stdout", Text: fmt.Sprintf("row %07d", i)})
+		}
+	}
+
+	tests := []struct {
+		name          string
+		opts          models.DockerContainerLogOptions
+		expectedQuery map[string]string
+		body          string
+		status        int
+		expected      models.DockerContainerLogs
+		expectedError string
+	}{
+		{
+			name:          "all lines",
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "tail": "all", "timestamps": "false"},
+			body:          dockerFrame(1, "ok\n"),
+			status:        http.StatusOK,
+			expected:      models.DockerContainerLogs{Lines: []models.DockerLogLine{{Stream: "stdout", Text: "ok"}}},
+		},
+		{
+			name: "tail and time range",
+			opts: models.DockerContainerLogOptions{Tail: 50, Since: 100, Until: 200, Timestamps: true},
+			expectedQuery: map[string]string{
+				"stdout": "true", "stderr": "true", "tail": "50", "timestamps": "true", "since": "100", "until": "200",
+			},
+			body:     dockerFrame(2, "boom\n"),
+			status:   http.StatusOK,
+			expected: models.DockerContainerLogs{Lines: []models.DockerLogLine{{Stream: "stderr", Text: "boom"}}},
+		},
+		{
+			name:          "output larger than the limit keeps its end",
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "tail": "all", "timestamps": "false"},
+			body:          strings.Repeat("x", maxDockerLogSize+10),
+			status:        http.StatusOK,
+			expected: models.DockerContainerLogs{
+				Lines:     []models.DockerLogLine{{Stream: "stdout", Text: strings.Repeat("x", maxDockerLogSize)}},
+				Truncated: true,
+			},
+		},
+		{
+			name:          "frames larger than the limit keep the newest whole frames",
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "tail": "all", "timestamps": "false"},
+			body:          frames.String(),
+			status:        http.StatusOK,
+			expected:      models.DockerContainerLogs{Lines: newestFrames, Truncated: true},
+		},
+		{
+			name:          "raw output larger than the limit keeps the newest whole lines",
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "tail": "all", "timestamps": "false"},
+			body:          rawLines.String(),
+			status:        http.StatusOK,
+			expected:      models.DockerContainerLogs{Lines: newestRawLines, Truncated: true},
+		},
+		{
+			name:          "container not found",
+			expectedQuery: map[string]string{"stdout": "true", "stderr": "true", "tail": "all", "timestamps": "false"},
+			body:          `{"message":"No such container: web"}`,
+			status:        http.StatusNotFound,
+			expectedError: "failed to get docker container logs: docker API returned status 404: No such container: web",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ProxyDockerRequest", 1, client.ProxyRequestOptions{
+				Method:      http.MethodGet,
+				APIPath:     "/containers/web/logs",
+				QueryParams: tt.expectedQuery,
+			}).Return(proxyResponse(tt.status, tt.body), nil)
+
+			c := &PortainerClient{cli: mockAPI}
+			logs, err := c.GetDockerContainerLogs(context.Background(), 1, "web", tt.opts)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, logs)
+			}
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+// TestResyncDockerStream verifies that output whose start was dropped resumes
+// at a frame or line boundary.
+func TestResyncDockerStream(t *testing.T) {
+	frame := dockerFrame(1, "ready\n")
+	// A payload holding bytes that look like a frame header.
+	fake := dockerFrame(2, "x"+dockerFrame(1, "fake")[:dockerStreamHeaderSize]+"y\n")
+
+	tests := []struct {
+		name        string
+		data        string
+		multiplexed bool
+		expected    string
+	}{
+		{
+			name:        "partial frame",
+			data:        frame[3:] + frame,
+			multiplexed: true,
+			expected:    frame,
+		},
+		{
+			name:        "header bytes inside a payload",
+			data:        fake[1:] + frame,
+			multiplexed: true,
+			expected:    frame,
+		},
+		{
+			name:     "partial line",
+			data:     "ial line\nnext line\n",
+			expected: "next line\n",
+		},
+		{
+			name:     "no line break",
+			data:     "partial",
+			expected: "partial",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(resyncDockerStream([]byte(tt.data), tt.multiplexed)))
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/portainer/client-api-go/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyResponse builds an HTTP response with the given status and body for proxy mocks.
+func proxyResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// TestListDockerContainers verifies container listing, filter encoding, and conversion.
+func TestListDockerContainers(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          models.DockerContainerListOptions
+		expectedQuery map[string]string
+		response      *http.Response
+		mockError     error
+		expected      []models.DockerContainer
+		expectedError string
+	}{
+		{
+			name:          "running containers without filters",
+			expectedQuery: map[string]string{"all": "false"},
+			response: proxyResponse(http.StatusOK, `[{"Id":"abc","Names":["/web"],"Image":"nginx","State":"running","Status":"Up 2 hours",`+
+				`"Labels":{"com.docker.compose.project":"shop"},"Ports":[{"PrivatePort":80,"PublicPort":8080,"Type":"tcp"}]}]`),
+			expected: []models.DockerContainer{
+				{
+					ID:     "abc",
+					Name:   "web",
+					Image:  "nginx",
+					State:  "running",
+					Status: "Up 2 hours",
+					Labels: map[string]string{"com.docker.compose.project": "shop"},
+					Stack:  "shop",
+					Ports:  []models.DockerContainerPort{{PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+				},
+			},
+		},
+		{
+			name: "all containers with filters",
+			opts: models.DockerContainerListOptions{
+				All:     true,
+				Filters: map[string][]string{"status": {"exited"}},
+			},
+			expectedQuery: map[string]string{"all": "true", "filters": `{"status":["exited"]}`},
+			response:      proxyResponse(http.StatusOK, `[]`),
+			expected:      []models.DockerContainer{},
+		},
+		{
+			name:          "docker error message",
+			expectedQuery: map[string]string{"all": "false"},
+			response:      proxyResponse(http.StatusInternalServerError, `{"message":"daemon unavailable"}`),
+			expectedError: "failed to list docker containers: docker API returned status 500: daemon unavailable",
+		},
+		{
+			name:          "proxy error",
+			expectedQuery: map[string]string{"all": "false"},
+			mockError:     errors.New("connection refused"),
+			expectedError: "failed to list docker containers: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ProxyDockerRequest", 1, client.ProxyRequestOptions{
+				Method:      http.MethodGet,
+				APIPath:     "/containers/json",
+				QueryParams: tt.expectedQuery,
+			}).Return(tt.response, tt.mockError)
+
+			c := &PortainerClient{cli: mockAPI}
+			containers, err := c.ListDockerContainers(context.Background(), 1, tt.opts)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, containers)
+			}
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+// TestInspectDockerContainer verifies container inspection and path escaping.
+func TestInspectDockerContainer(t *testing.T) {
+	mockAPI := new(MockPortainerAPI)
+	mockAPI.On("ProxyDockerRequest", 1, client.ProxyRequestOptions{
+		Method:  http.MethodGet,
+		APIPath: "/containers/my%2Fapp/json",
+	}).Return(proxyResponse(http.StatusOK, `{"Id":"abc","Name":"/my-app","Image":"sha256:1",`+
+		`"State":{"Status":"exited","ExitCode":1},"Config":{"Image":"nginx:latest"},`+
+		`"NetworkSettings":{"Networks":{"frontend":{},"backend":{}}}}`), nil)
+
+	c := &PortainerClient{cli: mockAPI}
+	details, err := c.InspectDockerContainer(context.Background(), 1, "my/app")
+
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", details.Name)
+	assert.Equal(t, "nginx:latest", details.Image)
+	assert.Equal(t, "sha256:1", details.ImageID)
+	assert.Equal(t, 1, details.State.ExitCode)
+	assert.Equal(t, []string{"backend", "frontend"}, details.Networks)
+	mockAPI.AssertExpectations(t)
+}
+
+// TestDockerContainerActions verifies the request sent by each lifecycle operation.
+func TestDockerContainerActions(t *testing.T) {
+	timeout := 5
+
+	tests := []struct {
+		name          string
+		call          func(c *PortainerClient) error
+		expectedOpts  client.ProxyRequestOptions
+		response      *http.Response
+		expectedError string
+	}{
+		{
+			name:         "start",
+			call:         func(c *PortainerClient) error { return c.StartDockerContainer(context.Background(), 1, "abc") },
+			expectedOpts: client.ProxyRequestOptions{Method: http.MethodPost, APIPath: "/containers/abc/start"},
+			response:     proxyResponse(http.StatusNoContent, ""),
+		},
+		{
+			name:         "start already running is not an error",
+			call:         func(c *PortainerClient) error { return c.StartDockerContainer(context.Background(), 1, "abc") },
+			expectedOpts: client.ProxyRequestOptions{Method: http.MethodPost, APIPath: "/containers/abc/start"},
+			response:     proxyResponse(http.StatusNotModified, ""),
+		},
+		{
+			name: "stop with timeout",
+			call: func(c *PortainerClient) error {
+				return c.StopDockerContainer(context.Background(), 1, "abc", &timeout)
+			},
+			expectedOpts: client.ProxyRequestOptions{Method: http.MethodPost, APIPath: "/containers/abc/stop", QueryParams: map[string]string{"t": "5"}},
+			response:     proxyResponse(http.StatusNoContent, ""),
+		},
+		{
+			name:         "restart without timeout",
+			call:         func(c *PortainerClient) error { return c.RestartDockerContainer(context.Background(), 1, "abc", nil) },
+			expectedOpts: client.ProxyRequestOptions{Method: http.MethodPost, APIPath: "/containers/abc/restart"},
+			response:     proxyResponse(http.StatusNoContent, ""),
+		},
+		{
+			name: "kill with signal",
+			call: func(c *PortainerClient) error {
+				return c.KillDockerContainer(context.Background(), 1, "abc", "SIGTERM")
+			},
+			expectedOpts: client.ProxyRequestOptions{Method: http.MethodPost, APIPath: "/containers/abc/kill", QueryParams: map[string]string{"signal": "SIGTERM"}},
+			response:     proxyResponse(http.StatusNoContent, ""),
+		},
+		{
+			name: "kill not running",
+			call: func(c *PortainerClient) error { return c.KillDockerContainer(context.Background(), 1, "abc", "") },
+			expectedOpts: client.ProxyRequestOptions{
+				Method:  http.MethodPost,
+				APIPath: "/containers/abc/kill",
+			},
+			response:      proxyResponse(http.StatusConflict, `{"message":"container abc is not running"}`),
+			expectedError: "failed to kill docker container: docker API returned status 409: container abc is not running",
+		},
+		{
+			name: "remove with force and volumes",
+			call: func(c *PortainerClient) error {
+				return c.RemoveDockerContainer(context.Background(), 1, "abc", true, true)
+			},
+			expectedOpts: client.ProxyRequestOptions{Method: http.MethodDelete, APIPath: "/containers/abc", QueryParams: map[string]string{"force": "true", "v": "true"}},
+			response:     proxyResponse(http.StatusNoContent, ""),
+		},
+		{
+			name: "remove not found without message",
+			call: func(c *PortainerClient) error {
+				return c.RemoveDockerContainer(context.Background(), 1, "abc", false, false)
+			},
+			expectedOpts:  client.ProxyRequestOptions{Method: http.MethodDelete, APIPath: "/containers/abc", QueryParams: map[string]string{"force": "false", "v": "false"}},
+			response:      proxyResponse(http.StatusNotFound, ""),
+			expectedError: "failed to remove docker container: docker API returned status 404: Not Found",
+		},
+		{
+			name: "rename",
+			call: func(c *PortainerClient) error {
+				return c.RenameDockerContainer(context.Background(), 1, "abc", "new-name")
+			},
+			expectedOpts: client.ProxyRequestOptions{Method: http.MethodPost, APIPath: "/containers/abc/rename", QueryParams: map[string]string{"name": "new-name"}},
+			response:     proxyResponse(http.StatusNoContent, ""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ProxyDockerRequest", 1, tt.expectedOpts).Return(tt.response, nil)
+
+			err := tt.call(&PortainerClient{cli: mockAPI})
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
@@ -12,8 +13,8 @@ import (
 // Returns:
 //   - A slice of CustomTemplate objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetCustomTemplates() ([]models.CustomTemplate, error) {
-	rawTemplates, err := c.cli.ListCustomTemplates()
+func (c *PortainerClient) GetCustomTemplates(ctx context.Context) ([]models.CustomTemplate, error) {
+	rawTemplates, err := c.cli.ListCustomTemplates(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list custom templates: %w", err)
 	}
@@ -34,8 +35,8 @@ func (c *PortainerClient) GetCustomTemplates() ([]models.CustomTemplate, error)
 // Returns:
 //   - A CustomTemplate object
 //   - An error if the operation fails
-func (c *PortainerClient) GetCustomTemplate(id int) (models.CustomTemplate, error) {
-	raw, err := c.cli.GetCustomTemplate(int64(id))
+func (c *PortainerClient) GetCustomTemplate(ctx context.Context, id int) (models.CustomTemplate, error) {
+	raw, err := c.cli.GetCustomTemplate(ctx, int64(id))
 	if err != nil {
 		return models.CustomTemplate{}, fmt.Errorf("failed to get custom template: %w", err)
 	}
@@ -51,8 +52,8 @@ func (c *PortainerClient) GetCustomTemplate(id int) (models.CustomTemplate, erro
 // Returns:
 //   - The file content as a string
 //   - An error if the operation fails
-func (c *PortainerClient) GetCustomTemplateFile(id int) (string, error) {
-	content, err := c.cli.GetCustomTemplateFile(int64(id))
+func (c *PortainerClient) GetCustomTemplateFile(ctx context.Context, id int) (string, error) {
+	content, err := c.cli.GetCustomTemplateFile(ctx, int64(id))
 	if err != nil {
 		return "", fmt.Errorf("failed to get custom template file: %w", err)
 	}
@@ -74,7 +75,7 @@ func (c *PortainerClient) GetCustomTemplateFile(id int) (string, error) {
 // Returns:
 //   - The ID of the created custom template
 //   - An error if the operation fails
-func (c *PortainerClient) CreateCustomTemplate(title, description, note, logo, fileContent string, platform, templateType int) (int, error) {
+func (c *PortainerClient) CreateCustomTemplate(ctx context.Context, title, description, note, logo, fileContent string, platform, templateType int) (int, error) {
 	tType := int64(templateType)
 	payload := &apimodels.CustomtemplatesCustomTemplateFromFileContentPayload{
 		Title:       &title,
@@ -86,7 +87,7 @@ func (c *PortainerClient) CreateCustomTemplate(title, description, note, logo, f
 		Platform:    int64(platform),
 	}
 
-	raw, err := c.cli.CreateCustomTemplate(payload)
+	raw, err := c.cli.CreateCustomTemplate(ctx, payload)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create custom template: %w", err)
 	}
@@ -101,8 +102,8 @@ func (c *PortainerClient) CreateCustomTemplate(title, description, note, logo, f
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteCustomTemplate(id int) error {
-	err := c.cli.DeleteCustomTemplate(int64(id))
+func (c *PortainerClient) DeleteCustomTemplate(ctx context.Context, id int) error {
+	err := c.cli.DeleteCustomTemplate(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete custom template: %w", err)
 	}
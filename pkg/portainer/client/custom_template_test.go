@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -56,7 +57,7 @@ func TestGetCustomTemplates(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			templates, err := client.GetCustomTemplates()
+			templates, err := client.GetCustomTemplates(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -106,7 +107,7 @@ func TestGetCustomTemplate(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			template, err := client.GetCustomTemplate(tt.id)
+			template, err := client.GetCustomTemplate(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -154,7 +155,7 @@ func TestGetCustomTemplateFile(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			content, err := client.GetCustomTemplateFile(tt.id)
+			content, err := client.GetCustomTemplateFile(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -217,7 +218,7 @@ func TestCreateCustomTemplate(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateCustomTemplate(tt.title, tt.description, tt.note, tt.logo, tt.fileContent, tt.platform, tt.templateType)
+			id, err := client.CreateCustomTemplate(context.Background(), tt.title, tt.description, tt.note, tt.logo, tt.fileContent, tt.platform, tt.templateType)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -258,7 +259,7 @@ func TestDeleteCustomTemplate(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.DeleteCustomTemplate(tt.id)
+			err := client.DeleteCustomTemplate(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
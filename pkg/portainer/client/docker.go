@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -16,8 +17,8 @@ import (
 // Returns:
 //   - A DockerDashboard object with container, image, network, volume, stack, and service counts
 //   - An error if the operation fails
-func (c *PortainerClient) GetDockerDashboard(environmentId int) (models.DockerDashboard, error) {
-	raw, err := c.cli.GetDockerDashboard(int64(environmentId))
+func (c *PortainerClient) GetDockerDashboard(ctx context.Context, environmentId int) (models.DockerDashboard, error) {
+	raw, err := c.cli.GetDockerDashboard(ctx, int64(environmentId))
 	if err != nil {
 		return models.DockerDashboard{}, fmt.Errorf("failed to get docker dashboard: %w", err)
 	}
@@ -32,8 +33,15 @@ func (c *PortainerClient) GetDockerDashboard(environmentId int) (models.DockerDa
 //
 // Returns:
 //   - *http.Response: The response from the Docker API
-//   - error: Any error that occurred during the request
-func (c *PortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestOptions) (*http.Response, error) {
+//   - error: Any error that occurred during the request, or the error of the
+//     proxy request check that refused it
+func (c *PortainerClient) ProxyDockerRequest(ctx context.Context, opts models.DockerProxyRequestOptions) (*http.Response, error) {
+	if c.checkProxyRequest != nil {
+		if err := c.checkProxyRequest(ProxyAPIDocker, opts.Method, opts.Path); err != nil {
+			return nil, err
+		}
+	}
+
 	proxyOpts := client.ProxyRequestOptions{
 		Method:  opts.Method,
 		APIPath: opts.Path,
@@ -48,5 +56,5 @@ func (c *PortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestOptio
 		proxyOpts.Headers = opts.Headers
 	}
 
-	return c.cli.ProxyDockerRequest(opts.EnvironmentID, proxyOpts)
+	return c.cli.ProxyDockerRequest(ctx, opts.EnvironmentID, proxyOpts)
 }
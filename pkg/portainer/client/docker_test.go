@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestProxyDockerRequest verifies proxy docker request behavior.
@@ -90,7 +92,7 @@ func TestProxyDockerRequest(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			resp, err := client.ProxyDockerRequest(tt.opts)
+			resp, err := client.ProxyDockerRequest(context.Background(), tt.opts)
 			if tt.expectedError {
 				assert.Error(t, err)
 				assert.EqualError(t, err, tt.mockError.Error())
@@ -147,7 +149,7 @@ func TestGetDockerDashboard(t *testing.T) {
 			mockAPI.On("GetDockerDashboard", int64(tt.envID)).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetDockerDashboard(tt.envID)
+			result, err := c.GetDockerDashboard(context.Background(), tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -160,3 +162,40 @@ func TestGetDockerDashboard(t *testing.T) {
 		})
 	}
 }
+
+// TestProxyRequestCheck verifies that the proxy request check applies to the
+// raw proxy methods and to the typed methods built on them, and that refused
+// requests are not sent.
+func TestProxyRequestCheck(t *testing.T) {
+	var checked []string
+	check := func(api, method, path string) error {
+		checked = append(checked, api+" "+method+" "+path)
+		if strings.Contains(path, "/exec") {
+			return errors.New("exec is denied")
+		}
+		return nil
+	}
+
+	mockAPI := new(MockPortainerAPI)
+	mockAPI.On("ProxyDockerRequest", 1, client.ProxyRequestOptions{Method: "GET", APIPath: "/version"}).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+	}, nil)
+	c := &PortainerClient{cli: mockAPI, checkProxyRequest: check}
+
+	resp, err := c.ProxyDockerRequest(context.Background(), models.DockerProxyRequestOptions{EnvironmentID: 1, Method: "GET", Path: "/version"})
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = c.ExecDockerContainer(context.Background(), 1, "web", models.ExecOptions{Command: []string{"id"}})
+	assert.ErrorContains(t, err, "exec is denied")
+
+	_, err = c.ExecKubernetesPod(context.Background(), 2, "default", "web", "", models.ExecOptions{Command: []string{"id"}})
+	assert.ErrorContains(t, err, "exec is denied")
+
+	assert.Equal(t, ProxyAPIDocker+" GET /version", checked[0])
+	assert.Contains(t, checked[1], ProxyAPIDocker+" POST /containers/web/exec")
+	assert.Contains(t, checked[2], ProxyAPIKubernetes+" GET /api/v1/namespaces/default/pods/web/exec?")
+	mockAPI.AssertNumberOfCalls(t, "ProxyDockerRequest", 1)
+	mockAPI.AssertNotCalled(t, "ProxyKubernetesRequest", mock.Anything, mock.Anything)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
@@ -12,8 +13,8 @@ import (
 // Returns:
 //   - A slice of EdgeJob objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
-	rawJobs, err := c.cli.ListEdgeJobs()
+func (c *PortainerClient) GetEdgeJobs(ctx context.Context) ([]models.EdgeJob, error) {
+	rawJobs, err := c.cli.ListEdgeJobs(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list edge jobs: %w", err)
 	}
@@ -34,8 +35,8 @@ func (c *PortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
 // Returns:
 //   - An EdgeJob object
 //   - An error if the operation fails
-func (c *PortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
-	raw, err := c.cli.GetEdgeJob(int64(id))
+func (c *PortainerClient) GetEdgeJob(ctx context.Context, id int) (models.EdgeJob, error) {
+	raw, err := c.cli.GetEdgeJob(ctx, int64(id))
 	if err != nil {
 		return models.EdgeJob{}, fmt.Errorf("failed to get edge job: %w", err)
 	}
@@ -51,8 +52,8 @@ func (c *PortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
 // Returns:
 //   - The file content as a string
 //   - An error if the operation fails
-func (c *PortainerClient) GetEdgeJobFile(id int) (string, error) {
-	content, err := c.cli.GetEdgeJobFile(int64(id))
+func (c *PortainerClient) GetEdgeJobFile(ctx context.Context, id int) (string, error) {
+	content, err := c.cli.GetEdgeJobFile(ctx, int64(id))
 	if err != nil {
 		return "", fmt.Errorf("failed to get edge job file: %w", err)
 	}
@@ -73,7 +74,7 @@ func (c *PortainerClient) GetEdgeJobFile(id int) (string, error) {
 // Returns:
 //   - The ID of the created edge job
 //   - An error if the operation fails
-func (c *PortainerClient) CreateEdgeJob(name, cronExpression, fileContent string, endpoints []int, edgeGroups []int, recurring bool) (int, error) {
+func (c *PortainerClient) CreateEdgeJob(ctx context.Context, name, cronExpression, fileContent string, endpoints []int, edgeGroups []int, recurring bool) (int, error) {
 	endpointIds := make([]int64, len(endpoints))
 	for i, e := range endpoints {
 		endpointIds[i] = int64(e)
@@ -93,7 +94,7 @@ func (c *PortainerClient) CreateEdgeJob(name, cronExpression, fileContent string
 		Recurring:      recurring,
 	}
 
-	id, err := c.cli.CreateEdgeJob(payload)
+	id, err := c.cli.CreateEdgeJob(ctx, payload)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create edge job: %w", err)
 	}
@@ -108,8 +109,8 @@ func (c *PortainerClient) CreateEdgeJob(name, cronExpression, fileContent string
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteEdgeJob(id int) error {
-	err := c.cli.DeleteEdgeJob(int64(id))
+func (c *PortainerClient) DeleteEdgeJob(ctx context.Context, id int) error {
+	err := c.cli.DeleteEdgeJob(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete edge job: %w", err)
 	}
@@ -122,8 +123,8 @@ func (c *PortainerClient) DeleteEdgeJob(id int) error {
 // Returns:
 //   - A slice of EdgeUpdateSchedule objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetEdgeUpdateSchedules() ([]models.EdgeUpdateSchedule, error) {
-	rawSchedules, err := c.cli.ListEdgeUpdateSchedules()
+func (c *PortainerClient) GetEdgeUpdateSchedules(ctx context.Context) ([]models.EdgeUpdateSchedule, error) {
+	rawSchedules, err := c.cli.ListEdgeUpdateSchedules(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list edge update schedules: %w", err)
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -56,7 +57,7 @@ func TestGetEdgeJobs(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			jobs, err := client.GetEdgeJobs()
+			jobs, err := client.GetEdgeJobs(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -106,7 +107,7 @@ func TestGetEdgeJob(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			job, err := client.GetEdgeJob(tt.id)
+			job, err := client.GetEdgeJob(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -154,7 +155,7 @@ func TestGetEdgeJobFile(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			content, err := client.GetEdgeJobFile(tt.id)
+			content, err := client.GetEdgeJobFile(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -215,7 +216,7 @@ func TestCreateEdgeJob(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateEdgeJob(tt.jobName, tt.cron, tt.fileContent, tt.endpoints, tt.edgeGroups, tt.recurring)
+			id, err := client.CreateEdgeJob(context.Background(), tt.jobName, tt.cron, tt.fileContent, tt.endpoints, tt.edgeGroups, tt.recurring)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -256,7 +257,7 @@ func TestDeleteEdgeJob(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.DeleteEdgeJob(tt.id)
+			err := client.DeleteEdgeJob(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -313,7 +314,7 @@ func TestGetEdgeUpdateSchedules(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			schedules, err := client.GetEdgeUpdateSchedules()
+			schedules, err := client.GetEdgeUpdateSchedules(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
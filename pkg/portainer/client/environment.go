@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -12,8 +13,8 @@ import (
 // Returns:
 //   - A slice of Environment objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetEnvironments() ([]models.Environment, error) {
-	endpoints, err := c.cli.ListEndpoints()
+func (c *PortainerClient) GetEnvironments(ctx context.Context) ([]models.Environment, error) {
+	endpoints, err := c.cli.ListEndpoints(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list endpoints: %w", err)
 	}
@@ -34,8 +35,8 @@ func (c *PortainerClient) GetEnvironments() ([]models.Environment, error) {
 // Returns:
 //   - An Environment object
 //   - An error if the operation fails
-func (c *PortainerClient) GetEnvironment(id int) (models.Environment, error) {
-	endpoint, err := c.cli.GetEndpoint(int64(id))
+func (c *PortainerClient) GetEnvironment(ctx context.Context, id int) (models.Environment, error) {
+	endpoint, err := c.cli.GetEndpoint(ctx, int64(id))
 	if err != nil {
 		return models.Environment{}, fmt.Errorf("failed to get endpoint: %w", err)
 	}
@@ -50,8 +51,8 @@ func (c *PortainerClient) GetEnvironment(id int) (models.Environment, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteEnvironment(id int) error {
-	err := c.cli.DeleteEndpoint(int64(id))
+func (c *PortainerClient) DeleteEnvironment(ctx context.Context, id int) error {
+	err := c.cli.DeleteEndpoint(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete endpoint: %w", err)
 	}
@@ -65,8 +66,8 @@ func (c *PortainerClient) DeleteEnvironment(id int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) SnapshotEnvironment(id int) error {
-	err := c.cli.SnapshotEndpoint(int64(id))
+func (c *PortainerClient) SnapshotEnvironment(ctx context.Context, id int) error {
+	err := c.cli.SnapshotEndpoint(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to snapshot endpoint: %w", err)
 	}
@@ -77,8 +78,8 @@ func (c *PortainerClient) SnapshotEnvironment(id int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) SnapshotAllEnvironments() error {
-	err := c.cli.SnapshotAllEndpoints()
+func (c *PortainerClient) SnapshotAllEnvironments(ctx context.Context) error {
+	err := c.cli.SnapshotAllEndpoints(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to snapshot all endpoints: %w", err)
 	}
@@ -93,9 +94,9 @@ func (c *PortainerClient) SnapshotAllEnvironments() error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentTags(id int, tagIds []int) error {
+func (c *PortainerClient) UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error {
 	tags := utils.IntToInt64Slice(tagIds)
-	err := c.cli.UpdateEndpoint(int64(id),
+	err := c.cli.UpdateEndpoint(ctx, int64(id),
 		&tags,
 		nil,
 		nil,
@@ -121,9 +122,9 @@ func (c *PortainerClient) UpdateEnvironmentTags(id int, tagIds []int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentUserAccesses(id int, userAccesses map[int]string) error {
+func (c *PortainerClient) UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
 	uac := utils.IntToInt64Map(userAccesses)
-	err := c.cli.UpdateEndpoint(int64(id),
+	err := c.cli.UpdateEndpoint(ctx, int64(id),
 		nil,
 		&uac,
 		nil,
@@ -149,9 +150,9 @@ func (c *PortainerClient) UpdateEnvironmentUserAccesses(id int, userAccesses map
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentTeamAccesses(id int, teamAccesses map[int]string) error {
+func (c *PortainerClient) UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
 	tac := utils.IntToInt64Map(teamAccesses)
-	err := c.cli.UpdateEndpoint(int64(id),
+	err := c.cli.UpdateEndpoint(ctx, int64(id),
 		nil,
 		nil,
 		&tac,
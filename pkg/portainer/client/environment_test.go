@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -120,7 +121,7 @@ func TestGetEnvironments(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			environments, err := client.GetEnvironments()
+			environments, err := client.GetEnvironments(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -178,7 +179,7 @@ func TestGetEnvironment(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			environment, err := client.GetEnvironment(tt.envID)
+			environment, err := client.GetEnvironment(context.Background(), tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -218,7 +219,7 @@ func TestDeleteEnvironment(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.DeleteEnvironment(tt.envID)
+			err := client.DeleteEnvironment(context.Background(), tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -257,7 +258,7 @@ func TestSnapshotEnvironment(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.SnapshotEnvironment(tt.envID)
+			err := client.SnapshotEnvironment(context.Background(), tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -293,7 +294,7 @@ func TestSnapshotAllEnvironments(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.SnapshotAllEnvironments()
+			err := client.SnapshotAllEnvironments(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -340,7 +341,7 @@ func TestUpdateEnvironmentTags(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentTags(tt.envID, tt.tagIds)
+			err := client.UpdateEnvironmentTags(context.Background(), tt.envID, tt.tagIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -395,7 +396,7 @@ func TestUpdateEnvironmentUserAccesses(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentUserAccesses(tt.envID, tt.userAccesses)
+			err := client.UpdateEnvironmentUserAccesses(context.Background(), tt.envID, tt.userAccesses)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -450,7 +451,7 @@ func TestUpdateEnvironmentTeamAccesses(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentTeamAccesses(tt.envID, tt.teamAccesses)
+			err := client.UpdateEnvironmentTeamAccesses(context.Background(), tt.envID, tt.teamAccesses)
 
 			if tt.expectedError {
 				assert.Error(t, err)
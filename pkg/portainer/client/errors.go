@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+)
+
+// Errors that classify a failed Portainer API call. They are matched with
+// errors.Is on the errors returned by the client.
+var (
+	// ErrNotFound means that the requested resource does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden means that the token is invalid or not allowed to perform the call.
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict means that the call conflicts with the current state of the resource.
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable means that Portainer could not be reached, or was
+	// overloaded or failing, even after retries.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// APIError is a failed Portainer API call. It wraps the original error and,
+// when the failure is one of the known kinds, the matching Err* sentinel.
+type APIError struct {
+	// StatusCode is the HTTP status returned by Portainer, or 0 when no
+	// response was received.
+	StatusCode int
+	// Kind is one of ErrNotFound, ErrForbidden, ErrConflict, and
+	// ErrUnavailable, or nil when the status has no specific kind.
+	Kind error
+	// Err is the original error.
+	Err error
+}
+
+// Error returns the message of the original error.
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error and the kind, so that errors.Is matches both.
+func (e *APIError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Kind}
+}
+
+// statusKind returns the kind of error an HTTP status represents, or nil.
+func statusKind(status int) error {
+	switch {
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrForbidden
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests, status >= http.StatusInternalServerError:
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// classifyError turns an error of the Swagger client into an *APIError
+// carrying the HTTP status and its kind. Network errors and timeouts are
+// classified as ErrUnavailable; a canceled context, errors that are already
+// classified, and errors that did not come from the API are returned unchanged.
+func classifyError(err error) error {
+	var classified *APIError
+	if err == nil || errors.Is(err, context.Canceled) || errors.As(err, &classified) {
+		return err
+	}
+
+	// The generated response types of the Swagger client report their status with Code().
+	var coded interface{ Code() int }
+	var apiErr *runtime.APIError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &coded):
+		return &APIError{StatusCode: coded.Code(), Kind: statusKind(coded.Code()), Err: err}
+	case errors.As(err, &apiErr):
+		return &APIError{StatusCode: apiErr.Code, Kind: statusKind(apiErr.Code), Err: err}
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return &APIError{Kind: ErrUnavailable, Err: err}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/portainer/client-api-go/v2/pkg/client/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClassifyError verifies that API and network errors are classified by kind.
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedKind   error
+		unchanged      bool
+	}{
+		{
+			name:           "generated response",
+			err:            tags.NewTagDeleteNotFound(),
+			expectedStatus: http.StatusNotFound,
+			expectedKind:   ErrNotFound,
+		},
+		{
+			name:           "unauthorized",
+			err:            runtime.NewAPIError("tagList", nil, http.StatusUnauthorized),
+			expectedStatus: http.StatusUnauthorized,
+			expectedKind:   ErrForbidden,
+		},
+		{
+			name:           "conflict",
+			err:            runtime.NewAPIError("tagCreate", nil, http.StatusConflict),
+			expectedStatus: http.StatusConflict,
+			expectedKind:   ErrConflict,
+		},
+		{
+			name:           "server error",
+			err:            runtime.NewAPIError("tagList", nil, http.StatusBadGateway),
+			expectedStatus: http.StatusBadGateway,
+			expectedKind:   ErrUnavailable,
+		},
+		{
+			name:           "bad request",
+			err:            runtime.NewAPIError("tagCreate", nil, http.StatusBadRequest),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "network error",
+			err:          &url.Error{Op: "Get", URL: "https://portainer.local/api/tags", Err: errTransport},
+			expectedKind: ErrUnavailable,
+		},
+		{
+			name:         "timeout",
+			err:          fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			expectedKind: ErrUnavailable,
+		},
+		{
+			name:      "canceled",
+			err:       &url.Error{Op: "Get", URL: "https://portainer.local/api/tags", Err: context.Canceled},
+			unchanged: true,
+		},
+		{
+			name:      "other error",
+			err:       errors.New("invalid payload"),
+			unchanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+
+			if tt.unchanged {
+				assert.Same(t, tt.err, err)
+				return
+			}
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.expectedStatus, apiErr.StatusCode)
+			assert.Equal(t, tt.expectedKind, apiErr.Kind)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.err.Error(), err.Error())
+			if tt.expectedKind != nil {
+				assert.ErrorIs(t, err, tt.expectedKind)
+			}
+			assert.Same(t, err, classifyError(err), "classified errors are not wrapped again")
+		})
+	}
+	assert.NoError(t, classifyError(nil))
+}
+
+// TestAdapterTypedErrors verifies that adapter and proxy errors can be matched by kind.
+func TestAdapterTypedErrors(t *testing.T) {
+	a := newTestAdapter(&mockRoundTripper{statusCode: http.StatusNotFound, body: `{"message":"Unable to find a tag with the specified identifier"}`})
+	err := a.DeleteTag(context.Background(), 1)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "failed to delete tag")
+
+	a = newTestAdapter(&mockRoundTripper{err: errTransport})
+	_, err = a.ListTags(context.Background())
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	err = proxyError("docker", &http.Response{StatusCode: http.StatusConflict, Body: io.NopCloser(strings.NewReader(`{"message":"container name already in use"}`))})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.EqualError(t, err, "docker API returned status 409: container name already in use")
+}
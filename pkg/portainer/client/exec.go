@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+)
+
+// maxExecOutputSize bounds how much stdout and stderr output is kept from a command.
+const maxExecOutputSize = 1 << 20
+
+// ExecDockerContainer runs a one-shot command inside a running Docker container
+// and waits for it to finish.
+//
+// The command is run through the Docker exec API: an exec instance is created,
+// started without a TTY so that stdout and stderr arrive as a multiplexed
+// stream, and inspected once the stream ends to read the exit code. The command
+// keeps running in the container if ctx is cancelled before it finishes.
+//
+// Parameters:
+//   - environmentId: The ID of the Docker environment
+//   - containerId: The ID or name of the container
+//   - opts: The command, working directory, and user
+//
+// Returns:
+//   - The ExecResult with the command output and exit code
+//   - An error if the operation fails
+func (c *PortainerClient) ExecDockerContainer(ctx context.Context, environmentId int, containerId string, opts models.ExecOptions) (models.ExecResult, error) {
+	createBody := map[string]any{
+		"AttachStdout": true,
+		"AttachStderr": true,
+		"Tty":          false,
+		"Cmd":          opts.Command,
+	}
+	if opts.WorkingDir != "" {
+		createBody["WorkingDir"] = opts.WorkingDir
+	}
+	if opts.User != "" {
+		createBody["User"] = opts.User
+	}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := c.dockerJSON(ctx, environmentId, http.MethodPost, containerPath(containerId, "exec"), nil, createBody, &created); err != nil {
+		return models.ExecResult{}, fmt.Errorf("failed to create docker exec instance: %w", err)
+	}
+
+	execPath := "/exec/" + url.PathEscape(created.ID)
+	resp, err := c.dockerRequest(ctx, environmentId, http.MethodPost, execPath+"/start", nil, map[string]any{"Detach": false, "Tty": false})
+	if err != nil {
+		return models.ExecResult{}, fmt.Errorf("failed to start docker exec instance: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxExecOutputSize+1))
+	if err != nil {
+		return models.ExecResult{}, fmt.Errorf("failed to read docker exec output: %w", err)
+	}
+	result := models.ExecResult{}
+	if len(data) > maxExecOutputSize {
+		data = data[:maxExecOutputSize]
+		result.Truncated = true
+		// Drain the rest of the stream so that the command can run to completion.
+		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+			return models.ExecResult{}, fmt.Errorf("failed to read docker exec output: %w", err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	forEachDockerFrame(data, func(stream string, payload []byte) {
+		if stream == streamStderr {
+			stderr.Write(payload)
+		} else {
+			stdout.Write(payload)
+		}
+	})
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	var inspect struct {
+		Running  bool `json:"Running"`
+		ExitCode int  `json:"ExitCode"`
+	}
+	if err := c.dockerJSON(ctx, environmentId, http.MethodGet, execPath+"/json", nil, nil, &inspect); err != nil {
+		return models.ExecResult{}, fmt.Errorf("failed to inspect docker exec instance: %w", err)
+	}
+	if inspect.Running {
+		return models.ExecResult{}, fmt.Errorf("docker exec instance %s is still running after its output ended", created.ID)
+	}
+	result.ExitCode = inspect.ExitCode
+
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/portainer/client-api-go/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyJSONBody matches a proxy request with the given method and path whose body is the JSON encoding of want.
+func proxyJSONBody(t *testing.T, method, path string, want map[string]any) any {
+	return mock.MatchedBy(func(opts client.ProxyRequestOptions) bool {
+		if opts.Method != method || opts.APIPath != path || opts.Body == nil {
+			return false
+		}
+		var got map[string]any
+		if err := json.NewDecoder(opts.Body).Decode(&got); err != nil {
+			return false
+		}
+		wantJSON, _ := json.Marshal(want)
+		gotJSON, _ := json.Marshal(got)
+		return assert.JSONEq(t, string(wantJSON), string(gotJSON)) && opts.Headers["Content-Type"] == "application/json"
+	})
+}
+
+// TestExecDockerContainer verifies the exec create, start, and inspect sequence.
+func TestExecDockerContainer(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          models.ExecOptions
+		expectedBody  map[string]any
+		startResponse *http.Response
+		inspectBody   string
+		expected      models.ExecResult
+		expectedError string
+	}{
+		{
+			name: "stdout and stderr with exit code",
+			opts: models.ExecOptions{Command: []string{"sh", "-c", "echo out; echo err >&2; exit 3"}, WorkingDir: "/app", User: "nobody"},
+			expectedBody: map[string]any{
+				"AttachStdout": true,
+				"AttachStderr": true,
+				"Tty":          false,
+				"Cmd":          []string{"sh", "-c", "echo out; echo err >&2; exit 3"},
+				"WorkingDir":   "/app",
+				"User":         "nobody",
+			},
+			startResponse: proxyResponse(http.StatusOK, dockerFrame(1, "out\n")+dockerFrame(2, "err\n")),
+			inspectBody:   `{"Running":false,"ExitCode":3}`,
+			expected:      models.ExecResult{Stdout: "out\n", Stderr: "err\n", ExitCode: 3},
+		},
+		{
+			name:          "no output",
+			opts:          models.ExecOptions{Command: []string{"true"}},
+			expectedBody:  map[string]any{"AttachStdout": true, "AttachStderr": true, "Tty": false, "Cmd": []string{"true"}},
+			startResponse: proxyResponse(http.StatusOK, ""),
+			inspectBody:   `{"Running":false,"ExitCode":0}`,
+			expected:      models.ExecResult{},
+		},
+		{
+			name:          "still running after output ended",
+			opts:          models.ExecOptions{Command: []string{"true"}},
+			expectedBody:  map[string]any{"AttachStdout": true, "AttachStderr": true, "Tty": false, "Cmd": []string{"true"}},
+			startResponse: proxyResponse(http.StatusOK, ""),
+			inspectBody:   `{"Running":true,"ExitCode":0}`,
+			expectedError: "docker exec instance exec-1 is still running after its output ended",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ProxyDockerRequest", 1, proxyJSONBody(t, http.MethodPost, "/containers/web/exec", tt.expectedBody)).
+				Return(proxyResponse(http.StatusCreated, `{"Id":"exec-1"}`), nil).Once()
+			mockAPI.On("ProxyDockerRequest", 1, proxyJSONBody(t, http.MethodPost, "/exec/exec-1/start", map[string]any{"Detach": false, "Tty": false})).
+				Return(tt.startResponse, nil).Once()
+			mockAPI.On("ProxyDockerRequest", 1, client.ProxyRequestOptions{Method: http.MethodGet, APIPath: "/exec/exec-1/json"}).
+				Return(proxyResponse(http.StatusOK, tt.inspectBody), nil).Once()
+
+			c := &PortainerClient{cli: mockAPI}
+			result, err := c.ExecDockerContainer(context.Background(), 1, "web", tt.opts)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+// TestExecDockerContainerCreateError verifies that a failed exec create is reported with the Docker message.
+func TestExecDockerContainerCreateError(t *testing.T) {
+	mockAPI := new(MockPortainerAPI)
+	mockAPI.On("ProxyDockerRequest", 1, mock.Anything).
+		Return(proxyResponse(http.StatusConflict, `{"message":"container web is not running"}`), nil).Once()
+
+	c := &PortainerClient{cli: mockAPI}
+	_, err := c.ExecDockerContainer(context.Background(), 1, "web", models.ExecOptions{Command: []string{"env"}})
+
+	assert.EqualError(t, err, "failed to create docker exec instance: docker API returned status 409: container web is not running")
+	mockAPI.AssertExpectations(t)
+}
+
+// Ensure the start response body is drained and closed even when output is truncated.
+func TestExecDockerContainerTruncatesOutput(t *testing.T) {
+	large := make([]byte, maxExecOutputSize+100)
+	for i := range large {
+		large[i] = 'x'
+	}
+
+	mockAPI := new(MockPortainerAPI)
+	mockAPI.On("ProxyDockerRequest", 1, mock.MatchedBy(func(opts client.ProxyRequestOptions) bool { return opts.APIPath == "/containers/web/exec" })).
+		Return(proxyResponse(http.StatusCreated, `{"Id":"exec-1"}`), nil).Once()
+	mockAPI.On("ProxyDockerRequest", 1, mock.MatchedBy(func(opts client.ProxyRequestOptions) bool { return opts.APIPath == "/exec/exec-1/start" })).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(&repeatReader{data: large})}, nil).Once()
+	mockAPI.On("ProxyDockerRequest", 1, client.ProxyRequestOptions{Method: http.MethodGet, APIPath: "/exec/exec-1/json"}).
+		Return(proxyResponse(http.StatusOK, `{"Running":false,"ExitCode":0}`), nil).Once()
+
+	c := &PortainerClient{cli: mockAPI}
+	result, err := c.ExecDockerContainer(context.Background(), 1, "web", models.ExecOptions{Command: []string{"yes"}})
+
+	require.NoError(t, err)
+	assert.True(t, result.Truncated)
+	assert.Len(t, result.Stdout, maxExecOutputSize)
+	mockAPI.AssertExpectations(t)
+}
+
+// repeatReader returns data once and then io.EOF.
+type repeatReader struct {
+	data []byte
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -13,8 +14,8 @@ import (
 // Returns:
 //   - A slice of Group objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetEnvironmentGroups() ([]models.Group, error) {
-	edgeGroups, err := c.cli.ListEdgeGroups()
+func (c *PortainerClient) GetEnvironmentGroups(ctx context.Context) ([]models.Group, error) {
+	edgeGroups, err := c.cli.ListEdgeGroups(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list edge groups: %w", err)
 	}
@@ -36,8 +37,8 @@ func (c *PortainerClient) GetEnvironmentGroups() ([]models.Group, error) {
 // Returns:
 //   - The ID of the created environment group
 //   - An error if the operation fails
-func (c *PortainerClient) CreateEnvironmentGroup(name string, environmentIds []int) (int, error) {
-	id, err := c.cli.CreateEdgeGroup(name, utils.IntToInt64Slice(environmentIds))
+func (c *PortainerClient) CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	id, err := c.cli.CreateEdgeGroup(ctx, name, utils.IntToInt64Slice(environmentIds))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create environment group: %w", err)
 	}
@@ -54,8 +55,8 @@ func (c *PortainerClient) CreateEnvironmentGroup(name string, environmentIds []i
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentGroupName(id int, name string) error {
-	err := c.cli.UpdateEdgeGroup(int64(id), &name, nil, nil)
+func (c *PortainerClient) UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error {
+	err := c.cli.UpdateEdgeGroup(ctx, int64(id), &name, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to update environment group name: %w", err)
 	}
@@ -71,9 +72,9 @@ func (c *PortainerClient) UpdateEnvironmentGroupName(id int, name string) error
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentGroupEnvironments(id int, environmentIds []int) error {
+func (c *PortainerClient) UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error {
 	envs := utils.IntToInt64Slice(environmentIds)
-	err := c.cli.UpdateEdgeGroup(int64(id), nil, &envs, nil)
+	err := c.cli.UpdateEdgeGroup(ctx, int64(id), nil, &envs, nil)
 	if err != nil {
 		return fmt.Errorf("failed to update environment group environments: %w", err)
 	}
@@ -89,9 +90,9 @@ func (c *PortainerClient) UpdateEnvironmentGroupEnvironments(id int, environment
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentGroupTags(id int, tagIds []int) error {
+func (c *PortainerClient) UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error {
 	tags := utils.IntToInt64Slice(tagIds)
-	err := c.cli.UpdateEdgeGroup(int64(id), nil, nil, &tags)
+	err := c.cli.UpdateEdgeGroup(ctx, int64(id), nil, nil, &tags)
 	if err != nil {
 		return fmt.Errorf("failed to update environment group tags: %w", err)
 	}
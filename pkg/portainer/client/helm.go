@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
@@ -8,8 +9,8 @@ import (
 )
 
 // GetHelmRepositories retrieves all Helm repositories for a user.
-func (c *PortainerClient) GetHelmRepositories(userId int) (models.HelmRepositoryList, error) {
-	raw, err := c.cli.ListHelmRepositories(int64(userId))
+func (c *PortainerClient) GetHelmRepositories(ctx context.Context, userId int) (models.HelmRepositoryList, error) {
+	raw, err := c.cli.ListHelmRepositories(ctx, int64(userId))
 	if err != nil {
 		return models.HelmRepositoryList{}, fmt.Errorf("failed to list helm repositories: %w", err)
 	}
@@ -18,8 +19,8 @@ func (c *PortainerClient) GetHelmRepositories(userId int) (models.HelmRepository
 }
 
 // CreateHelmRepository creates a Helm repository for a user.
-func (c *PortainerClient) CreateHelmRepository(userId int, url string) (models.HelmRepository, error) {
-	raw, err := c.cli.CreateHelmRepository(int64(userId), url)
+func (c *PortainerClient) CreateHelmRepository(ctx context.Context, userId int, url string) (models.HelmRepository, error) {
+	raw, err := c.cli.CreateHelmRepository(ctx, int64(userId), url)
 	if err != nil {
 		return models.HelmRepository{}, fmt.Errorf("failed to create helm repository: %w", err)
 	}
@@ -28,8 +29,8 @@ func (c *PortainerClient) CreateHelmRepository(userId int, url string) (models.H
 }
 
 // DeleteHelmRepository deletes a Helm repository for a user.
-func (c *PortainerClient) DeleteHelmRepository(userId int, repositoryId int) error {
-	err := c.cli.DeleteHelmRepository(int64(userId), int64(repositoryId))
+func (c *PortainerClient) DeleteHelmRepository(ctx context.Context, userId int, repositoryId int) error {
+	err := c.cli.DeleteHelmRepository(ctx, int64(userId), int64(repositoryId))
 	if err != nil {
 		return fmt.Errorf("failed to delete helm repository: %w", err)
 	}
@@ -38,13 +39,13 @@ func (c *PortainerClient) DeleteHelmRepository(userId int, repositoryId int) err
 }
 
 // SearchHelmCharts searches for Helm charts in a repository.
-func (c *PortainerClient) SearchHelmCharts(repo string, chart string) (string, error) {
+func (c *PortainerClient) SearchHelmCharts(ctx context.Context, repo string, chart string) (string, error) {
 	var chartPtr *string
 	if chart != "" {
 		chartPtr = &chart
 	}
 
-	result, err := c.cli.SearchHelmCharts(repo, chartPtr)
+	result, err := c.cli.SearchHelmCharts(ctx, repo, chartPtr)
 	if err != nil {
 		return "", fmt.Errorf("failed to search helm charts: %w", err)
 	}
@@ -53,7 +54,7 @@ func (c *PortainerClient) SearchHelmCharts(repo string, chart string) (string, e
 }
 
 // InstallHelmChart installs a Helm chart on an environment.
-func (c *PortainerClient) InstallHelmChart(environmentId int, chart, name, namespace, repo, values, version string) (models.HelmReleaseDetails, error) {
+func (c *PortainerClient) InstallHelmChart(ctx context.Context, environmentId int, chart, name, namespace, repo, values, version string) (models.HelmReleaseDetails, error) {
 	payload := &apimodels.HelmInstallChartPayload{
 		Chart:     chart,
 		Name:      name,
@@ -63,7 +64,7 @@ func (c *PortainerClient) InstallHelmChart(environmentId int, chart, name, names
 		Version:   version,
 	}
 
-	raw, err := c.cli.InstallHelmChart(int64(environmentId), payload)
+	raw, err := c.cli.InstallHelmChart(ctx, int64(environmentId), payload)
 	if err != nil {
 		return models.HelmReleaseDetails{}, fmt.Errorf("failed to install helm chart: %w", err)
 	}
@@ -72,7 +73,7 @@ func (c *PortainerClient) InstallHelmChart(environmentId int, chart, name, names
 }
 
 // GetHelmReleases retrieves all Helm releases on an environment.
-func (c *PortainerClient) GetHelmReleases(environmentId int, namespace, filter, selector string) ([]models.HelmRelease, error) {
+func (c *PortainerClient) GetHelmReleases(ctx context.Context, environmentId int, namespace, filter, selector string) ([]models.HelmRelease, error) {
 	var nsPtr, filterPtr, selectorPtr *string
 	if namespace != "" {
 		nsPtr = &namespace
@@ -84,7 +85,7 @@ func (c *PortainerClient) GetHelmReleases(environmentId int, namespace, filter,
 		selectorPtr = &selector
 	}
 
-	raw, err := c.cli.ListHelmReleases(int64(environmentId), nsPtr, filterPtr, selectorPtr)
+	raw, err := c.cli.ListHelmReleases(ctx, int64(environmentId), nsPtr, filterPtr, selectorPtr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list helm releases: %w", err)
 	}
@@ -98,13 +99,13 @@ func (c *PortainerClient) GetHelmReleases(environmentId int, namespace, filter,
 }
 
 // DeleteHelmRelease deletes a Helm release from an environment.
-func (c *PortainerClient) DeleteHelmRelease(environmentId int, release, namespace string) error {
+func (c *PortainerClient) DeleteHelmRelease(ctx context.Context, environmentId int, release, namespace string) error {
 	var nsPtr *string
 	if namespace != "" {
 		nsPtr = &namespace
 	}
 
-	err := c.cli.DeleteHelmRelease(int64(environmentId), release, nsPtr)
+	err := c.cli.DeleteHelmRelease(ctx, int64(environmentId), release, nsPtr)
 	if err != nil {
 		return fmt.Errorf("failed to delete helm release: %w", err)
 	}
@@ -113,13 +114,13 @@ func (c *PortainerClient) DeleteHelmRelease(environmentId int, release, namespac
 }
 
 // GetHelmReleaseHistory retrieves the history of a Helm release.
-func (c *PortainerClient) GetHelmReleaseHistory(environmentId int, name, namespace string) ([]models.HelmReleaseDetails, error) {
+func (c *PortainerClient) GetHelmReleaseHistory(ctx context.Context, environmentId int, name, namespace string) ([]models.HelmReleaseDetails, error) {
 	var nsPtr *string
 	if namespace != "" {
 		nsPtr = &namespace
 	}
 
-	raw, err := c.cli.GetHelmReleaseHistory(int64(environmentId), name, nsPtr)
+	raw, err := c.cli.GetHelmReleaseHistory(ctx, int64(environmentId), name, nsPtr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get helm release history: %w", err)
 	}
@@ -3,6 +3,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -44,7 +45,7 @@ func TestGetHelmRepositories(t *testing.T) {
 			mockAPI.On("ListHelmRepositories", int64(tt.userId)).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetHelmRepositories(tt.userId)
+			result, err := c.GetHelmRepositories(context.Background(), tt.userId)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -88,7 +89,7 @@ func TestCreateHelmRepository(t *testing.T) {
 			mockAPI.On("CreateHelmRepository", int64(tt.userId), tt.url).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.CreateHelmRepository(tt.userId, tt.url)
+			result, err := c.CreateHelmRepository(context.Background(), tt.userId, tt.url)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -130,7 +131,7 @@ func TestDeleteHelmRepository(t *testing.T) {
 			mockAPI.On("DeleteHelmRepository", int64(tt.userId), int64(tt.repoId)).Return(tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			err := c.DeleteHelmRepository(tt.userId, tt.repoId)
+			err := c.DeleteHelmRepository(context.Background(), tt.userId, tt.repoId)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -183,7 +184,7 @@ func TestSearchHelmCharts(t *testing.T) {
 			mockAPI.On("SearchHelmCharts", tt.repo, chartPtr).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.SearchHelmCharts(tt.repo, tt.chart)
+			result, err := c.SearchHelmCharts(context.Background(), tt.repo, tt.chart)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -245,7 +246,7 @@ func TestInstallHelmChart(t *testing.T) {
 			mockAPI.On("InstallHelmChart", int64(tt.envId), mock.AnythingOfType("*models.HelmInstallChartPayload")).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.InstallHelmChart(tt.envId, tt.chart, tt.releaseName, tt.namespace, tt.repo, tt.values, tt.version)
+			result, err := c.InstallHelmChart(context.Background(), tt.envId, tt.chart, tt.releaseName, tt.namespace, tt.repo, tt.values, tt.version)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -315,7 +316,7 @@ func TestGetHelmReleases(t *testing.T) {
 			mockAPI.On("ListHelmReleases", int64(tt.envId), nsPtr, filterPtr, selectorPtr).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetHelmReleases(tt.envId, tt.namespace, tt.filter, tt.selector)
+			result, err := c.GetHelmReleases(context.Background(), tt.envId, tt.namespace, tt.filter, tt.selector)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -369,7 +370,7 @@ func TestDeleteHelmRelease(t *testing.T) {
 			mockAPI.On("DeleteHelmRelease", int64(tt.envId), tt.release, nsPtr).Return(tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			err := c.DeleteHelmRelease(tt.envId, tt.release, tt.namespace)
+			err := c.DeleteHelmRelease(context.Background(), tt.envId, tt.release, tt.namespace)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -432,7 +433,7 @@ func TestGetHelmReleaseHistory(t *testing.T) {
 			mockAPI.On("GetHelmReleaseHistory", int64(tt.envId), tt.releaseName, nsPtr).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetHelmReleaseHistory(tt.envId, tt.releaseName, tt.namespace)
+			result, err := c.GetHelmReleaseHistory(context.Background(), tt.envId, tt.releaseName, tt.namespace)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -15,8 +16,15 @@ import (
 //
 // Returns:
 //   - *http.Response: The response from the Kubernetes API
-//   - error: Any error that occurred during the request
-func (c *PortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
+//   - error: Any error that occurred during the request, or the error of the
+//     proxy request check that refused it
+func (c *PortainerClient) ProxyKubernetesRequest(ctx context.Context, opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
+	if c.checkProxyRequest != nil {
+		if err := c.checkProxyRequest(ProxyAPIKubernetes, opts.Method, opts.Path); err != nil {
+			return nil, err
+		}
+	}
+
 	proxyOpts := client.ProxyRequestOptions{
 		Method:  opts.Method,
 		APIPath: opts.Path,
@@ -31,7 +39,7 @@ func (c *PortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxyRequ
 		proxyOpts.Headers = opts.Headers
 	}
 
-	return c.cli.ProxyKubernetesRequest(opts.EnvironmentID, proxyOpts)
+	return c.cli.ProxyKubernetesRequest(ctx, opts.EnvironmentID, proxyOpts)
 }
 
 // GetKubernetesDashboard retrieves the Kubernetes dashboard summary for a specific environment.
@@ -42,8 +50,8 @@ func (c *PortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxyRequ
 // Returns:
 //   - A KubernetesDashboard with resource counts
 //   - An error if the operation fails
-func (c *PortainerClient) GetKubernetesDashboard(environmentId int) (models.KubernetesDashboard, error) {
-	dashboard, err := c.cli.GetKubernetesDashboard(int64(environmentId))
+func (c *PortainerClient) GetKubernetesDashboard(ctx context.Context, environmentId int) (models.KubernetesDashboard, error) {
+	dashboard, err := c.cli.GetKubernetesDashboard(ctx, int64(environmentId))
 	if err != nil {
 		return models.KubernetesDashboard{}, fmt.Errorf("failed to get kubernetes dashboard: %w", err)
 	}
@@ -59,8 +67,8 @@ func (c *PortainerClient) GetKubernetesDashboard(environmentId int) (models.Kube
 // Returns:
 //   - A slice of KubernetesNamespace objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetKubernetesNamespaces(environmentId int) ([]models.KubernetesNamespace, error) {
-	rawNamespaces, err := c.cli.GetKubernetesNamespaces(int64(environmentId))
+func (c *PortainerClient) GetKubernetesNamespaces(ctx context.Context, environmentId int) ([]models.KubernetesNamespace, error) {
+	rawNamespaces, err := c.cli.GetKubernetesNamespaces(ctx, int64(environmentId))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kubernetes namespaces: %w", err)
 	}
@@ -81,8 +89,8 @@ func (c *PortainerClient) GetKubernetesNamespaces(environmentId int) ([]models.K
 // Returns:
 //   - The kubeconfig content as an interface{}
 //   - An error if the operation fails
-func (c *PortainerClient) GetKubernetesConfig(environmentId int) (interface{}, error) {
-	config, err := c.cli.GetKubernetesConfig(int64(environmentId))
+func (c *PortainerClient) GetKubernetesConfig(ctx context.Context, environmentId int) (interface{}, error) {
+	config, err := c.cli.GetKubernetesConfig(ctx, int64(environmentId))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
 	}
@@ -0,0 +1,327 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+)
+
+const (
+	// execChannelProtocol is the Kubernetes exec websocket subprotocol. Every
+	// binary message starts with a byte identifying its channel.
+	execChannelProtocol = "v4.channel.k8s.io"
+
+	execChannelStdout = 1
+	execChannelStderr = 2
+	execChannelError  = 3
+
+	// Websocket opcodes (RFC 6455, section 5.2).
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// ExecKubernetesPod runs a one-shot command inside a Kubernetes pod container
+// and waits for it to finish.
+//
+// The Kubernetes exec API is only served over a streaming protocol, so the
+// request is upgraded to a websocket through the Portainer Kubernetes proxy
+// and the stdout, stderr, and status channels are read until the server closes
+// the stream. Kubernetes cannot run a command as another user, so opts.User
+// must be empty. A working directory is applied by wrapping the command in
+// /bin/sh, which must therefore exist in the container.
+//
+// Parameters:
+//   - environmentId: The ID of the Kubernetes environment
+//   - namespace: The namespace of the pod
+//   - pod: The name of the pod
+//   - container: The container to run the command in; empty uses the pod's default container
+//   - opts: The command and working directory
+//
+// Returns:
+//   - The ExecResult with the command output and exit code
+//   - An error if the operation fails
+func (c *PortainerClient) ExecKubernetesPod(ctx context.Context, environmentId int, namespace, pod, container string, opts models.ExecOptions) (models.ExecResult, error) {
+	if opts.User != "" {
+		return models.ExecResult{}, errors.New("running a command as a specific user is not supported for Kubernetes pods")
+	}
+
+	command := opts.Command
+	if opts.WorkingDir != "" {
+		command = append([]string{"/bin/sh", "-c", `cd -- "$0" && exec "$@"`, opts.WorkingDir}, command...)
+	}
+
+	query := url.Values{}
+	for _, arg := range command {
+		query.Add("command", arg)
+	}
+	query.Set("stdout", "true")
+	query.Set("stderr", "true")
+	if container != "" {
+		query.Set("container", container)
+	}
+
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return models.ExecResult{}, fmt.Errorf("failed to generate websocket key: %w", err)
+	}
+
+	// The command is a repeated query parameter, which the proxy's query map
+	// cannot express, so the query string is part of the path.
+	resp, err := c.ProxyKubernetesRequest(ctx, models.KubernetesProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Method:        http.MethodGet,
+		Path:          "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods/" + url.PathEscape(pod) + "/exec?" + query.Encode(),
+		Headers: map[string]string{
+			"Connection":             "Upgrade",
+			"Upgrade":                "websocket",
+			"Sec-WebSocket-Version":  "13",
+			"Sec-WebSocket-Key":      base64.StdEncoding.EncodeToString(key),
+			"Sec-WebSocket-Protocol": execChannelProtocol,
+		},
+	})
+	if err != nil {
+		return models.ExecResult{}, fmt.Errorf("failed to exec in kubernetes pod: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return models.ExecResult{}, fmt.Errorf("failed to exec in kubernetes pod: %w", proxyError("kubernetes", resp))
+		}
+		return models.ExecResult{}, fmt.Errorf("failed to exec in kubernetes pod: expected a websocket upgrade, got status %d", resp.StatusCode)
+	}
+
+	result, err := readExecStream(resp.Body)
+	if err != nil {
+		return models.ExecResult{}, fmt.Errorf("failed to exec in kubernetes pod: %w", err)
+	}
+	return result, nil
+}
+
+// readExecStream reads the channels of a Kubernetes exec websocket stream until
+// the server closes it, and builds the result from the output and the final
+// status message.
+func readExecStream(conn io.ReadCloser) (models.ExecResult, error) {
+	ws := &wsConn{r: bufio.NewReader(conn)}
+	if w, ok := conn.(io.Writer); ok {
+		ws.w = w
+	}
+
+	var stdout, stderr, status []byte
+	result := models.ExecResult{}
+	for {
+		message, err := ws.readMessage()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return models.ExecResult{}, err
+		}
+		if len(message) < 2 {
+			// Kubernetes opens each channel with a message carrying only the channel byte.
+			continue
+		}
+
+		channel, payload := message[0], message[1:]
+		switch channel {
+		case execChannelStdout, execChannelStderr:
+			if len(stdout)+len(stderr)+len(payload) > maxExecOutputSize {
+				payload = payload[:max(0, maxExecOutputSize-len(stdout)-len(stderr))]
+				result.Truncated = true
+			}
+			if channel == execChannelStdout {
+				stdout = append(stdout, payload...)
+			} else {
+				stderr = append(stderr, payload...)
+			}
+		case execChannelError:
+			status = append(status, payload...)
+		}
+	}
+
+	result.Stdout = string(stdout)
+	result.Stderr = string(stderr)
+
+	exitCode, err := execExitCode(status)
+	if err != nil {
+		return models.ExecResult{}, err
+	}
+	result.ExitCode = exitCode
+
+	return result, nil
+}
+
+// execExitCode extracts the exit code from the status sent on the error
+// channel. A successful command reports a "Success" status; a command exiting
+// with a non-zero code reports it as an "ExitCode" cause. Any other failure,
+// such as an unknown executable, is returned as an error.
+func execExitCode(status []byte) (int, error) {
+	if len(status) == 0 {
+		return 0, nil
+	}
+
+	var s struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Reason  string `json:"reason"`
+		Details struct {
+			Causes []struct {
+				Reason  string `json:"reason"`
+				Message string `json:"message"`
+			} `json:"causes"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(status, &s); err != nil {
+		return 0, fmt.Errorf("failed to decode exec status: %w", err)
+	}
+
+	if s.Status == "Success" {
+		return 0, nil
+	}
+	if s.Reason == "NonZeroExitCode" {
+		for _, cause := range s.Details.Causes {
+			if cause.Reason == "ExitCode" {
+				code, err := strconv.Atoi(cause.Message)
+				if err != nil {
+					return 0, fmt.Errorf("invalid exit code %q in exec status", cause.Message)
+				}
+				return code, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("command failed: %s", s.Message)
+}
+
+// wsConn is a minimal client side of a websocket connection: it reads the
+// messages sent by the server and answers pings. It does not send data.
+type wsConn struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// readMessage returns the payload of the next data message, reassembling
+// fragmented messages. It returns io.EOF once the server closes the connection.
+func (ws *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if errors.Is(err, io.EOF) && message != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpClose:
+			return nil, io.EOF
+		case wsOpPing:
+			if err := ws.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpText, wsOpBinary, wsOpContinuation:
+			message = append(message, payload...)
+		default:
+			return nil, fmt.Errorf("unexpected websocket opcode %d", opcode)
+		}
+
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads a single websocket frame. It returns io.EOF when the
+// connection ends on a frame boundary.
+func (ws *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxExecOutputSize {
+		return false, 0, nil, fmt.Errorf("websocket frame of %d bytes exceeds the limit of %d bytes", length, maxExecOutputSize)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(ws.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single masked control frame, as clients must mask every frame they send.
+// Nothing is written when the connection is read-only.
+func (ws *wsConn) writeFrame(opcode byte, payload []byte) error {
+	if ws.w == nil {
+		return nil
+	}
+	if len(payload) > 125 {
+		return fmt.Errorf("websocket control frame payload of %d bytes is too large", len(payload))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return fmt.Errorf("failed to generate websocket mask: %w", err)
+	}
+
+	frame := make([]byte, 0, 6+len(payload))
+	frame = append(frame, 0x80|opcode, 0x80|byte(len(payload)))
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := ws.w.Write(frame)
+	return err
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/portainer/client-api-go/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// wsFrame encodes an unmasked server websocket frame.
+func wsFrame(fin bool, opcode byte, payload string) string {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+	return string(frame) + payload
+}
+
+// execMessage encodes a binary websocket message on a Kubernetes exec channel.
+func execMessage(channel byte, payload string) string {
+	return wsFrame(true, wsOpBinary, string([]byte{channel})+payload)
+}
+
+// wsStream is a websocket connection body that records the frames written by the client.
+type wsStream struct {
+	io.Reader
+	written bytes.Buffer
+}
+
+func (s *wsStream) Write(p []byte) (int, error) { return s.written.Write(p) }
+func (s *wsStream) Close() error                { return nil }
+
+// TestExecKubernetesPod verifies the exec request and the decoding of the websocket stream.
+func TestExecKubernetesPod(t *testing.T) {
+	tests := []struct {
+		name          string
+		container     string
+		opts          models.ExecOptions
+		expectedPath  string
+		response      *http.Response
+		expected      models.ExecResult
+		expectedError string
+	}{
+		{
+			name:         "successful command",
+			container:    "app",
+			opts:         models.ExecOptions{Command: []string{"ls", "-l"}},
+			expectedPath: "/api/v1/namespaces/default/pods/web-1/exec?command=ls&command=-l&container=app&stderr=true&stdout=true",
+			response: &http.Response{
+				StatusCode: http.StatusSwitchingProtocols,
+				Body: &wsStream{Reader: strings.NewReader(
+					execMessage(execChannelStdout, "") +
+						execMessage(execChannelStdout, "file\n") +
+						execMessage(execChannelStderr, "warning\n") +
+						execMessage(execChannelError, `{"status":"Success"}`) +
+						wsFrame(true, wsOpClose, ""),
+				)},
+			},
+			expected: models.ExecResult{Stdout: "file\n", Stderr: "warning\n"},
+		},
+		{
+			name:         "non-zero exit code with working directory",
+			opts:         models.ExecOptions{Command: []string{"false"}, WorkingDir: "/srv"},
+			expectedPath: "/api/v1/namespaces/default/pods/web-1/exec?command=%2Fbin%2Fsh&command=-c&command=cd+--+%22%240%22+%26%26+exec+%22%24%40%22&command=%2Fsrv&command=false&stderr=true&stdout=true",
+			response: &http.Response{
+				StatusCode: http.StatusSwitchingProtocols,
+				Body: &wsStream{Reader: strings.NewReader(
+					execMessage(execChannelError, `{"status":"Failure","reason":"NonZeroExitCode","details":{"causes":[{"reason":"ExitCode","message":"1"}]}}`),
+				)},
+			},
+			expected: models.ExecResult{ExitCode: 1},
+		},
+		{
+			name:          "pod not found",
+			opts:          models.ExecOptions{Command: []string{"ls"}},
+			expectedPath:  "/api/v1/namespaces/default/pods/web-1/exec?command=ls&stderr=true&stdout=true",
+			response:      proxyResponse(http.StatusNotFound, `{"message":"pods \"web-1\" not found"}`),
+			expectedError: `failed to exec in kubernetes pod: kubernetes API returned status 404: pods "web-1" not found`,
+		},
+		{
+			name:          "upgrade refused",
+			opts:          models.ExecOptions{Command: []string{"ls"}},
+			expectedPath:  "/api/v1/namespaces/default/pods/web-1/exec?command=ls&stderr=true&stdout=true",
+			response:      proxyResponse(http.StatusOK, ""),
+			expectedError: "failed to exec in kubernetes pod: expected a websocket upgrade, got status 200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ProxyKubernetesRequest", 1, mock.MatchedBy(func(opts client.ProxyRequestOptions) bool {
+				return opts.Method == http.MethodGet &&
+					opts.APIPath == tt.expectedPath &&
+					opts.Headers["Upgrade"] == "websocket" &&
+					opts.Headers["Sec-WebSocket-Protocol"] == execChannelProtocol &&
+					opts.Headers["Sec-WebSocket-Key"] != ""
+			})).Return(tt.response, nil).Once()
+
+			c := &PortainerClient{cli: mockAPI}
+			result, err := c.ExecKubernetesPod(context.Background(), 1, "default", "web-1", tt.container, tt.opts)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+// TestExecKubernetesPodRejectsUser verifies that no request is sent when a user is requested.
+func TestExecKubernetesPodRejectsUser(t *testing.T) {
+	mockAPI := new(MockPortainerAPI)
+	c := &PortainerClient{cli: mockAPI}
+
+	_, err := c.ExecKubernetesPod(context.Background(), 1, "default", "web-1", "", models.ExecOptions{Command: []string{"id"}, User: "root"})
+
+	assert.EqualError(t, err, "running a command as a specific user is not supported for Kubernetes pods")
+	mockAPI.AssertNotCalled(t, "ProxyKubernetesRequest", mock.Anything, mock.Anything)
+}
+
+// TestReadExecStream verifies fragmentation, control frames, truncation, and stream errors.
+func TestReadExecStream(t *testing.T) {
+	t.Run("fragmented message and ping", func(t *testing.T) {
+		conn := &wsStream{Reader: strings.NewReader(
+			wsFrame(false, wsOpBinary, "\x01hel") +
+				wsFrame(true, wsOpPing, "hi") +
+				wsFrame(true, wsOpContinuation, "lo") +
+				wsFrame(true, wsOpClose, ""),
+		)}
+
+		result, err := readExecStream(conn)
+
+		require.NoError(t, err)
+		assert.Equal(t, "hello", result.Stdout)
+
+		// The pong is masked and echoes the ping payload.
+		fin, opcode, payload, err := (&wsConn{r: bufio.NewReader(&conn.written)}).readFrame()
+		require.NoError(t, err)
+		assert.True(t, fin)
+		assert.Equal(t, byte(wsOpPong), opcode)
+		assert.Equal(t, "hi", string(payload))
+	})
+
+	t.Run("output above the limit is truncated", func(t *testing.T) {
+		chunk := strings.Repeat("x", maxExecOutputSize/2+10)
+		conn := &wsStream{Reader: strings.NewReader(
+			execMessage(execChannelStdout, chunk) + execMessage(execChannelStderr, chunk),
+		)}
+
+		result, err := readExecStream(conn)
+
+		require.NoError(t, err)
+		assert.True(t, result.Truncated)
+		assert.Equal(t, maxExecOutputSize, len(result.Stdout)+len(result.Stderr))
+	})
+
+	t.Run("connection closed inside a fragmented message", func(t *testing.T) {
+		conn := &wsStream{Reader: strings.NewReader(wsFrame(false, wsOpBinary, "\x01partial"))}
+
+		_, err := readExecStream(conn)
+
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("unknown opcode", func(t *testing.T) {
+		conn := &wsStream{Reader: strings.NewReader(wsFrame(true, 0x3, ""))}
+
+		_, err := readExecStream(conn)
+
+		assert.EqualError(t, err, "unexpected websocket opcode 3")
+	})
+}
+
+// TestExecExitCode verifies the decoding of the exec status channel.
+func TestExecExitCode(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		expected      int
+		expectedError string
+	}{
+		{name: "no status", status: "", expected: 0},
+		{name: "success", status: `{"status":"Success"}`, expected: 0},
+		{
+			name:     "non-zero exit code",
+			status:   `{"status":"Failure","reason":"NonZeroExitCode","details":{"causes":[{"reason":"ExitCode","message":"137"}]}}`,
+			expected: 137,
+		},
+		{
+			name:          "invalid exit code",
+			status:        `{"status":"Failure","reason":"NonZeroExitCode","details":{"causes":[{"reason":"ExitCode","message":"abc"}]}}`,
+			expectedError: `invalid exit code "abc" in exec status`,
+		},
+		{
+			name:          "command not found",
+			status:        `{"status":"Failure","message":"exec: \"nope\": executable file not found in $PATH"}`,
+			expectedError: `command failed: exec: "nope": executable file not found in $PATH`,
+		},
+		{
+			name:          "malformed status",
+			status:        `{`,
+			expectedError: "failed to decode exec status: unexpected end of JSON input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := execExitCode([]byte(tt.status))
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+)
+
+const (
+	// maxPodLogSize bounds how much log output is read for each pod.
+	maxPodLogSize = 1 << 20
+
+	// maxPodLogFanOut is the maximum number of pods read for a label selector.
+	maxPodLogFanOut = 20
+
+	// podLogConcurrency is the number of pod log requests sent in parallel during a fan-out.
+	podLogConcurrency = 5
+)
+
+// GetKubernetesPodLogs retrieves the logs of a single pod, or of every pod in
+// the namespace matching a label selector.
+//
+// For a single pod, a failure is returned as an error. For a label selector,
+// failures are reported per pod in the Error field so that one failing pod does
+// not hide the logs of the others. A selector matching more than
+// maxPodLogFanOut pods is rejected.
+//
+// Parameters:
+//   - environmentId: The ID of the Kubernetes environment
+//   - namespace: The namespace of the pods
+//   - opts: The pod selection, container, and log range options
+//
+// Returns:
+//   - The KubernetesPodLogs of each selected pod, sorted by pod name
+//   - An error if the operation fails
+func (c *PortainerClient) GetKubernetesPodLogs(ctx context.Context, environmentId int, namespace string, opts models.KubernetesPodLogOptions) ([]models.KubernetesPodLogs, error) {
+	if opts.LabelSelector == "" {
+		logs, err := c.getPodLogs(ctx, environmentId, namespace, opts.Pod, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kubernetes pod logs: %w", err)
+		}
+		return []models.KubernetesPodLogs{logs}, nil
+	}
+
+	pods, err := c.listPodNames(ctx, environmentId, namespace, opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kubernetes pods: %w", err)
+	}
+	if len(pods) > maxPodLogFanOut {
+		return nil, fmt.Errorf("label selector %q matches %d pods, more than the limit of %d; use a more specific selector", opts.LabelSelector, len(pods), maxPodLogFanOut)
+	}
+
+	results := make([]models.KubernetesPodLogs, len(pods))
+	sem := make(chan struct{}, podLogConcurrency)
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		wg.Add(1)
+		go func(i int, pod string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			logs, err := c.getPodLogs(ctx, environmentId, namespace, pod, opts)
+			if err != nil {
+				logs = models.KubernetesPodLogs{Pod: pod, Container: opts.Container, Error: err.Error()}
+			}
+			results[i] = logs
+		}(i, pod)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// getPodLogs reads the logs of one pod container as plain text.
+func (c *PortainerClient) getPodLogs(ctx context.Context, environmentId int, namespace, pod string, opts models.KubernetesPodLogOptions) (models.KubernetesPodLogs, error) {
+	query := map[string]string{}
+	if opts.Container != "" {
+		query["container"] = opts.Container
+	}
+	if opts.Previous {
+		query["previous"] = "true"
+	}
+	if opts.TailLines > 0 {
+		query["tailLines"] = strconv.Itoa(opts.TailLines)
+	}
+	if opts.SinceSeconds > 0 {
+		query["sinceSeconds"] = strconv.Itoa(opts.SinceSeconds)
+	}
+
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods/" + url.PathEscape(pod) + "/log"
+	resp, err := c.kubernetesRequest(ctx, environmentId, path, query)
+	if err != nil {
+		return models.KubernetesPodLogs{}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPodLogSize+1))
+	if err != nil {
+		return models.KubernetesPodLogs{}, fmt.Errorf("failed to read pod logs: %w", err)
+	}
+
+	logs := models.KubernetesPodLogs{Pod: pod, Container: opts.Container}
+	if len(data) > maxPodLogSize {
+		data = data[:maxPodLogSize]
+		logs.Truncated = true
+	}
+	logs.Logs = string(data)
+
+	return logs, nil
+}
+
+// listPodNames returns the sorted names of the pods in a namespace that match a label selector.
+func (c *PortainerClient) listPodNames(ctx context.Context, environmentId int, namespace, labelSelector string) ([]string, error) {
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods"
+	resp, err := c.kubernetesRequest(ctx, environmentId, path, map[string]string{"labelSelector": labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode pod list: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.Metadata.Name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// kubernetesRequest sends a GET Kubernetes API request through the Portainer
+// proxy. Responses with a status of 400 or above are converted into an error
+// carrying the Kubernetes error message, and their body is closed.
+func (c *PortainerClient) kubernetesRequest(ctx context.Context, environmentId int, path string, query map[string]string) (*http.Response, error) {
+	resp, err := c.ProxyKubernetesRequest(ctx, models.KubernetesProxyRequestOptions{
+		EnvironmentID: environmentId,
+		Method:        http.MethodGet,
+		Path:          path,
+		QueryParams:   query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, proxyError("kubernetes", resp)
+	}
+
+	return resp, nil
+}
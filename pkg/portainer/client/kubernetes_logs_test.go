@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
+	"github.com/portainer/client-api-go/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// podLogRequest returns the proxy options of a pod log request.
+func podLogRequest(pod string, query map[string]string) client.ProxyRequestOptions {
+	return client.ProxyRequestOptions{
+		Method:      http.MethodGet,
+		APIPath:     "/api/v1/namespaces/default/pods/" + pod + "/log",
+		QueryParams: query,
+	}
+}
+
+// podListRequest returns the proxy options of a pod list request filtered by a label selector.
+func podListRequest(selector string) client.ProxyRequestOptions {
+	return client.ProxyRequestOptions{
+		Method:      http.MethodGet,
+		APIPath:     "/api/v1/namespaces/default/pods",
+		QueryParams: map[string]string{"labelSelector": selector},
+	}
+}
+
+// TestGetKubernetesPodLogsSinglePod verifies the query sent for a single pod and error handling.
+func TestGetKubernetesPodLogsSinglePod(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          models.KubernetesPodLogOptions
+		expectedQuery map[string]string
+		response      *http.Response
+		mockError     error
+		expected      []models.KubernetesPodLogs
+		expectedError string
+	}{
+		{
+			name:     "all lines of the default container",
+			opts:     models.KubernetesPodLogOptions{Pod: "web-1"},
+			response: proxyResponse(http.StatusOK, "line 1\nline 2\n"),
+			expected: []models.KubernetesPodLogs{{Pod: "web-1", Logs: "line 1\nline 2\n"}},
+		},
+		{
+			name: "previous container with tail and since",
+			opts: models.KubernetesPodLogOptions{Pod: "web-1", Container: "app", Previous: true, TailLines: 50, SinceSeconds: 300},
+			expectedQuery: map[string]string{
+				"container": "app", "previous": "true", "tailLines": "50", "sinceSeconds": "300",
+			},
+			response: proxyResponse(http.StatusOK, "panic: boom\n"),
+			expected: []models.KubernetesPodLogs{{Pod: "web-1", Container: "app", Logs: "panic: boom\n"}},
+		},
+		{
+			name:     "output larger than the limit is truncated",
+			opts:     models.KubernetesPodLogOptions{Pod: "web-1"},
+			response: proxyResponse(http.StatusOK, strings.Repeat("x", maxPodLogSize+1)),
+			expected: []models.KubernetesPodLogs{{Pod: "web-1", Logs: strings.Repeat("x", maxPodLogSize), Truncated: true}},
+		},
+		{
+			name:          "kubernetes error status",
+			opts:          models.KubernetesPodLogOptions{Pod: "web-1"},
+			response:      proxyResponse(http.StatusBadRequest, `{"kind":"Status","message":"previous terminated container \"app\" not found"}`),
+			expectedError: `failed to get kubernetes pod logs: kubernetes API returned status 400: previous terminated container "app" not found`,
+		},
+		{
+			name:          "proxy error",
+			opts:          models.KubernetesPodLogOptions{Pod: "web-1"},
+			mockError:     errors.New("connection refused"),
+			expectedError: "failed to get kubernetes pod logs: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("ProxyKubernetesRequest", 1, podLogRequest("web-1", tt.expectedQuery)).Return(tt.response, tt.mockError)
+
+			c := &PortainerClient{cli: mockAPI}
+			logs, err := c.GetKubernetesPodLogs(context.Background(), 1, "default", tt.opts)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, logs)
+			}
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetKubernetesPodLogsLabelSelector verifies the fan-out across pods matching a label selector.
+func TestGetKubernetesPodLogsLabelSelector(t *testing.T) {
+	t.Run("logs of every matching pod sorted by name", func(t *testing.T) {
+		mockAPI := new(MockPortainerAPI)
+		mockAPI.On("ProxyKubernetesRequest", 1, podListRequest("app=web")).
+			Return(proxyResponse(http.StatusOK, `{"items":[{"metadata":{"name":"web-2"}},{"metadata":{"name":"web-1"}}]}`), nil)
+		query := map[string]string{"container": "app", "tailLines": "10"}
+		mockAPI.On("ProxyKubernetesRequest", 1, podLogRequest("web-1", query)).
+			Return(proxyResponse(http.StatusOK, "one\n"), nil)
+		mockAPI.On("ProxyKubernetesRequest", 1, podLogRequest("web-2", query)).
+			Return(proxyResponse(http.StatusBadRequest, `{"message":"container \"app\" is waiting to start"}`), nil)
+
+		c := &PortainerClient{cli: mockAPI}
+		logs, err := c.GetKubernetesPodLogs(context.Background(), 1, "default", models.KubernetesPodLogOptions{
+			LabelSelector: "app=web",
+			Container:     "app",
+			TailLines:     10,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.KubernetesPodLogs{
+			{Pod: "web-1", Container: "app", Logs: "one\n"},
+			{Pod: "web-2", Container: "app", Error: `kubernetes API returned status 400: container "app" is waiting to start`},
+		}, logs)
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("no matching pods", func(t *testing.T) {
+		mockAPI := new(MockPortainerAPI)
+		mockAPI.On("ProxyKubernetesRequest", 1, podListRequest("app=none")).
+			Return(proxyResponse(http.StatusOK, `{"items":[]}`), nil)
+
+		c := &PortainerClient{cli: mockAPI}
+		logs, err := c.GetKubernetesPodLogs(context.Background(), 1, "default", models.KubernetesPodLogOptions{LabelSelector: "app=none"})
+
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("too many matching pods", func(t *testing.T) {
+		items := make([]string, maxPodLogFanOut+1)
+		for i := range items {
+			items[i] = fmt.Sprintf(`{"metadata":{"name":"web-%d"}}`, i)
+		}
+		mockAPI := new(MockPortainerAPI)
+		mockAPI.On("ProxyKubernetesRequest", 1, podListRequest("app=web")).
+			Return(proxyResponse(http.StatusOK, `{"items":[`+strings.Join(items, ",")+`]}`), nil)
+
+		c := &PortainerClient{cli: mockAPI}
+		_, err := c.GetKubernetesPodLogs(context.Background(), 1, "default", models.KubernetesPodLogOptions{LabelSelector: "app=web"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "matches 21 pods")
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("pod list error", func(t *testing.T) {
+		mockAPI := new(MockPortainerAPI)
+		mockAPI.On("ProxyKubernetesRequest", 1, podListRequest("app=web")).
+			Return(proxyResponse(http.StatusForbidden, `{"message":"pods is forbidden"}`), nil)
+
+		c := &PortainerClient{cli: mockAPI}
+		_, err := c.GetKubernetesPodLogs(context.Background(), 1, "default", models.KubernetesPodLogOptions{LabelSelector: "app=web"})
+
+		assert.EqualError(t, err, "failed to list kubernetes pods: kubernetes API returned status 403: pods is forbidden")
+		mockAPI.AssertExpectations(t)
+	})
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -105,7 +106,7 @@ func TestProxyKubernetesRequest(t *testing.T) {
 
 			portainerClient := &PortainerClient{cli: mockAPI}
 
-			resp, err := portainerClient.ProxyKubernetesRequest(tt.opts)
+			resp, err := portainerClient.ProxyKubernetesRequest(context.Background(), tt.opts)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -168,7 +169,7 @@ func TestGetKubernetesDashboard(t *testing.T) {
 			mockAPI.On("GetKubernetesDashboard", int64(tt.envID)).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetKubernetesDashboard(tt.envID)
+			result, err := c.GetKubernetesDashboard(context.Background(), tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -221,7 +222,7 @@ func TestGetKubernetesNamespaces(t *testing.T) {
 			mockAPI.On("GetKubernetesNamespaces", int64(tt.envID)).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetKubernetesNamespaces(tt.envID)
+			result, err := c.GetKubernetesNamespaces(context.Background(), tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -263,7 +264,7 @@ func TestGetKubernetesConfig(t *testing.T) {
 			mockAPI.On("GetKubernetesConfig", int64(tt.envID)).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetKubernetesConfig(tt.envID)
+			result, err := c.GetKubernetesConfig(context.Background(), tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
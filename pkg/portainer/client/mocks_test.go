@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/portainer/client-api-go/v2/client"
@@ -17,7 +18,7 @@ import (
 //    - Uses m.Called() to record the method call and get mock behavior
 //    - Includes nil check on first return value to avoid type assertion panics
 //    - Example:
-//      func (m *Mock) Method() (T, error) {
+//      func (m *Mock) Method(ctx context.Context) (T, error) {
 //          args := m.Called()
 //          if args.Get(0) == nil {
 //              return nil, args.Error(1)
@@ -29,7 +30,7 @@ import (
 //    - Uses m.Called() with any parameters
 //    - Returns only the error value
 //    - Example:
-//      func (m *Mock) Method(param string) error {
+//      func (m *Mock) Method(ctx context.Context, param string) error {
 //          args := m.Called(param)
 //          return args.Error(0)
 //      }
@@ -37,15 +38,18 @@ import (
 // 3. Methods with primitive return types:
 //    - Uses type-specific getters (e.g., Int64, String)
 //    - Example:
-//      func (m *Mock) Method() (int64, error) {
+//      func (m *Mock) Method(ctx context.Context) (int64, error) {
 //          args := m.Called()
 //          return args.Get(0).(int64), args.Error(1)
 //      }
 //
+// The context argument is accepted but not recorded, so expectations are set
+// on the remaining arguments only.
+//
 // Usage in Tests:
 //   mock := new(MockPortainerAPI)
 //   mock.On("MethodName").Return(expectedValue, nil)
-//   result, err := mock.MethodName()
+//   result, err := mock.MethodName(ctx)
 //   mock.AssertExpectations(t)
 
 // MockPortainerAPI is a mock of the PortainerAPIClient interface
@@ -54,7 +58,7 @@ type MockPortainerAPI struct {
 }
 
 // ListEdgeGroups mocks the ListEdgeGroups method
-func (m *MockPortainerAPI) ListEdgeGroups() ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error) {
+func (m *MockPortainerAPI) ListEdgeGroups(ctx context.Context) ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -63,19 +67,19 @@ func (m *MockPortainerAPI) ListEdgeGroups() ([]*apimodels.EdgegroupsDecoratedEdg
 }
 
 // CreateEdgeGroup mocks the CreateEdgeGroup method
-func (m *MockPortainerAPI) CreateEdgeGroup(name string, environmentIds []int64) (int64, error) {
+func (m *MockPortainerAPI) CreateEdgeGroup(ctx context.Context, name string, environmentIds []int64) (int64, error) {
 	args := m.Called(name, environmentIds)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // UpdateEdgeGroup mocks the UpdateEdgeGroup method
-func (m *MockPortainerAPI) UpdateEdgeGroup(id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error {
+func (m *MockPortainerAPI) UpdateEdgeGroup(ctx context.Context, id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error {
 	args := m.Called(id, name, environmentIds, tagIds)
 	return args.Error(0)
 }
 
 // ListEdgeStacks mocks the ListEdgeStacks method
-func (m *MockPortainerAPI) ListEdgeStacks() ([]*apimodels.PortainereeEdgeStack, error) {
+func (m *MockPortainerAPI) ListEdgeStacks(ctx context.Context) ([]*apimodels.PortainereeEdgeStack, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -84,7 +88,7 @@ func (m *MockPortainerAPI) ListEdgeStacks() ([]*apimodels.PortainereeEdgeStack,
 }
 
 // ListRegularStacks mocks the ListRegularStacks method
-func (m *MockPortainerAPI) ListRegularStacks() ([]*apimodels.PortainereeStack, error) {
+func (m *MockPortainerAPI) ListRegularStacks(ctx context.Context) ([]*apimodels.PortainereeStack, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -93,25 +97,25 @@ func (m *MockPortainerAPI) ListRegularStacks() ([]*apimodels.PortainereeStack, e
 }
 
 // CreateEdgeStack mocks the CreateEdgeStack method
-func (m *MockPortainerAPI) CreateEdgeStack(name string, file string, environmentGroupIds []int64) (int64, error) {
+func (m *MockPortainerAPI) CreateEdgeStack(ctx context.Context, name string, file string, environmentGroupIds []int64) (int64, error) {
 	args := m.Called(name, file, environmentGroupIds)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // UpdateEdgeStack mocks the UpdateEdgeStack method
-func (m *MockPortainerAPI) UpdateEdgeStack(id int64, file string, environmentGroupIds []int64) error {
+func (m *MockPortainerAPI) UpdateEdgeStack(ctx context.Context, id int64, file string, environmentGroupIds []int64) error {
 	args := m.Called(id, file, environmentGroupIds)
 	return args.Error(0)
 }
 
 // GetEdgeStackFile mocks the GetEdgeStackFile method
-func (m *MockPortainerAPI) GetEdgeStackFile(id int64) (string, error) {
+func (m *MockPortainerAPI) GetEdgeStackFile(ctx context.Context, id int64) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
 // ListEndpointGroups mocks the ListEndpointGroups method
-func (m *MockPortainerAPI) ListEndpointGroups() ([]*apimodels.PortainerEndpointGroup, error) {
+func (m *MockPortainerAPI) ListEndpointGroups(ctx context.Context) ([]*apimodels.PortainerEndpointGroup, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -120,31 +124,31 @@ func (m *MockPortainerAPI) ListEndpointGroups() ([]*apimodels.PortainerEndpointG
 }
 
 // CreateEndpointGroup mocks the CreateEndpointGroup method
-func (m *MockPortainerAPI) CreateEndpointGroup(name string, associatedEndpoints []int64) (int64, error) {
+func (m *MockPortainerAPI) CreateEndpointGroup(ctx context.Context, name string, associatedEndpoints []int64) (int64, error) {
 	args := m.Called(name, associatedEndpoints)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // UpdateEndpointGroup mocks the UpdateEndpointGroup method
-func (m *MockPortainerAPI) UpdateEndpointGroup(id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+func (m *MockPortainerAPI) UpdateEndpointGroup(ctx context.Context, id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
 	args := m.Called(id, name, userAccesses, teamAccesses)
 	return args.Error(0)
 }
 
 // AddEnvironmentToEndpointGroup mocks the AddEnvironmentToEndpointGroup method
-func (m *MockPortainerAPI) AddEnvironmentToEndpointGroup(groupId int64, environmentId int64) error {
+func (m *MockPortainerAPI) AddEnvironmentToEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
 	args := m.Called(groupId, environmentId)
 	return args.Error(0)
 }
 
 // RemoveEnvironmentFromEndpointGroup mocks the RemoveEnvironmentFromEndpointGroup method
-func (m *MockPortainerAPI) RemoveEnvironmentFromEndpointGroup(groupId int64, environmentId int64) error {
+func (m *MockPortainerAPI) RemoveEnvironmentFromEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
 	args := m.Called(groupId, environmentId)
 	return args.Error(0)
 }
 
 // ListEndpoints mocks the ListEndpoints method
-func (m *MockPortainerAPI) ListEndpoints() ([]*apimodels.PortainereeEndpoint, error) {
+func (m *MockPortainerAPI) ListEndpoints(ctx context.Context) ([]*apimodels.PortainereeEndpoint, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -153,7 +157,7 @@ func (m *MockPortainerAPI) ListEndpoints() ([]*apimodels.PortainereeEndpoint, er
 }
 
 // GetEndpoint mocks the GetEndpoint method
-func (m *MockPortainerAPI) GetEndpoint(id int64) (*apimodels.PortainereeEndpoint, error) {
+func (m *MockPortainerAPI) GetEndpoint(ctx context.Context, id int64) (*apimodels.PortainereeEndpoint, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -162,31 +166,31 @@ func (m *MockPortainerAPI) GetEndpoint(id int64) (*apimodels.PortainereeEndpoint
 }
 
 // UpdateEndpoint mocks the UpdateEndpoint method
-func (m *MockPortainerAPI) UpdateEndpoint(id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+func (m *MockPortainerAPI) UpdateEndpoint(ctx context.Context, id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
 	args := m.Called(id, tagIds, userAccesses, teamAccesses)
 	return args.Error(0)
 }
 
 // DeleteEndpoint mocks the DeleteEndpoint method
-func (m *MockPortainerAPI) DeleteEndpoint(id int64) error {
+func (m *MockPortainerAPI) DeleteEndpoint(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // SnapshotEndpoint mocks the SnapshotEndpoint method
-func (m *MockPortainerAPI) SnapshotEndpoint(id int64) error {
+func (m *MockPortainerAPI) SnapshotEndpoint(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // SnapshotAllEndpoints mocks the SnapshotAllEndpoints method
-func (m *MockPortainerAPI) SnapshotAllEndpoints() error {
+func (m *MockPortainerAPI) SnapshotAllEndpoints(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
 // GetSettings mocks the GetSettings method
-func (m *MockPortainerAPI) GetSettings() (*apimodels.PortainereeSettings, error) {
+func (m *MockPortainerAPI) GetSettings(ctx context.Context) (*apimodels.PortainereeSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -194,12 +198,12 @@ func (m *MockPortainerAPI) GetSettings() (*apimodels.PortainereeSettings, error)
 	return args.Get(0).(*apimodels.PortainereeSettings), args.Error(1)
 }
 
-func (m *MockPortainerAPI) UpdateSettings(payload *apimodels.SettingsSettingsUpdatePayload) error {
+func (m *MockPortainerAPI) UpdateSettings(ctx context.Context, payload *apimodels.SettingsSettingsUpdatePayload) error {
 	args := m.Called(payload)
 	return args.Error(0)
 }
 
-func (m *MockPortainerAPI) GetPublicSettings() (*apimodels.SettingsPublicSettingsResponse, error) {
+func (m *MockPortainerAPI) GetPublicSettings(ctx context.Context) (*apimodels.SettingsPublicSettingsResponse, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -207,7 +211,7 @@ func (m *MockPortainerAPI) GetPublicSettings() (*apimodels.SettingsPublicSetting
 	return args.Get(0).(*apimodels.SettingsPublicSettingsResponse), args.Error(1)
 }
 
-func (m *MockPortainerAPI) GetSSLSettings() (*apimodels.PortainereeSSLSettings, error) {
+func (m *MockPortainerAPI) GetSSLSettings(ctx context.Context) (*apimodels.PortainereeSSLSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -215,12 +219,12 @@ func (m *MockPortainerAPI) GetSSLSettings() (*apimodels.PortainereeSSLSettings,
 	return args.Get(0).(*apimodels.PortainereeSSLSettings), args.Error(1)
 }
 
-func (m *MockPortainerAPI) UpdateSSLSettings(payload *apimodels.SslSslUpdatePayload) error {
+func (m *MockPortainerAPI) UpdateSSLSettings(ctx context.Context, payload *apimodels.SslSslUpdatePayload) error {
 	args := m.Called(payload)
 	return args.Error(0)
 }
 
-func (m *MockPortainerAPI) ListAppTemplates() ([]*apimodels.PortainerTemplate, error) {
+func (m *MockPortainerAPI) ListAppTemplates(ctx context.Context) ([]*apimodels.PortainerTemplate, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -228,13 +232,13 @@ func (m *MockPortainerAPI) ListAppTemplates() ([]*apimodels.PortainerTemplate, e
 	return args.Get(0).([]*apimodels.PortainerTemplate), args.Error(1)
 }
 
-func (m *MockPortainerAPI) GetAppTemplateFile(id int64) (string, error) {
+func (m *MockPortainerAPI) GetAppTemplateFile(ctx context.Context, id int64) (string, error) {
 	args := m.Called(id)
 	return args.Get(0).(string), args.Error(1)
 }
 
 // ListTags mocks the ListTags method
-func (m *MockPortainerAPI) ListTags() ([]*apimodels.PortainerTag, error) {
+func (m *MockPortainerAPI) ListTags(ctx context.Context) ([]*apimodels.PortainerTag, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -243,19 +247,19 @@ func (m *MockPortainerAPI) ListTags() ([]*apimodels.PortainerTag, error) {
 }
 
 // CreateTag mocks the CreateTag method
-func (m *MockPortainerAPI) CreateTag(name string) (int64, error) {
+func (m *MockPortainerAPI) CreateTag(ctx context.Context, name string) (int64, error) {
 	args := m.Called(name)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // DeleteTag mocks the DeleteTag method
-func (m *MockPortainerAPI) DeleteTag(id int64) error {
+func (m *MockPortainerAPI) DeleteTag(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // ListTeams mocks the ListTeams method
-func (m *MockPortainerAPI) ListTeams() ([]*apimodels.PortainerTeam, error) {
+func (m *MockPortainerAPI) ListTeams(ctx context.Context) ([]*apimodels.PortainerTeam, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -264,7 +268,7 @@ func (m *MockPortainerAPI) ListTeams() ([]*apimodels.PortainerTeam, error) {
 }
 
 // ListTeamMemberships mocks the ListTeamMemberships method
-func (m *MockPortainerAPI) ListTeamMemberships() ([]*apimodels.PortainerTeamMembership, error) {
+func (m *MockPortainerAPI) ListTeamMemberships(ctx context.Context) ([]*apimodels.PortainerTeamMembership, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -273,13 +277,13 @@ func (m *MockPortainerAPI) ListTeamMemberships() ([]*apimodels.PortainerTeamMemb
 }
 
 // CreateTeam mocks the CreateTeam method
-func (m *MockPortainerAPI) CreateTeam(name string) (int64, error) {
+func (m *MockPortainerAPI) CreateTeam(ctx context.Context, name string) (int64, error) {
 	args := m.Called(name)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // GetTeam mocks the GetTeam method
-func (m *MockPortainerAPI) GetTeam(id int64) (*apimodels.PortainerTeam, error) {
+func (m *MockPortainerAPI) GetTeam(ctx context.Context, id int64) (*apimodels.PortainerTeam, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -288,31 +292,31 @@ func (m *MockPortainerAPI) GetTeam(id int64) (*apimodels.PortainerTeam, error) {
 }
 
 // DeleteTeam mocks the DeleteTeam method
-func (m *MockPortainerAPI) DeleteTeam(id int64) error {
+func (m *MockPortainerAPI) DeleteTeam(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // UpdateTeamName mocks the UpdateTeamName method
-func (m *MockPortainerAPI) UpdateTeamName(id int, name string) error {
+func (m *MockPortainerAPI) UpdateTeamName(ctx context.Context, id int, name string) error {
 	args := m.Called(id, name)
 	return args.Error(0)
 }
 
 // DeleteTeamMembership mocks the DeleteTeamMembership method
-func (m *MockPortainerAPI) DeleteTeamMembership(id int) error {
+func (m *MockPortainerAPI) DeleteTeamMembership(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // CreateTeamMembership mocks the CreateTeamMembership method
-func (m *MockPortainerAPI) CreateTeamMembership(teamId int, userId int) error {
+func (m *MockPortainerAPI) CreateTeamMembership(ctx context.Context, teamId int, userId int) error {
 	args := m.Called(teamId, userId)
 	return args.Error(0)
 }
 
 // ListUsers mocks the ListUsers method
-func (m *MockPortainerAPI) ListUsers() ([]*apimodels.PortainereeUser, error) {
+func (m *MockPortainerAPI) ListUsers(ctx context.Context) ([]*apimodels.PortainereeUser, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -321,19 +325,19 @@ func (m *MockPortainerAPI) ListUsers() ([]*apimodels.PortainereeUser, error) {
 }
 
 // UpdateUserRole mocks the UpdateUserRole method
-func (m *MockPortainerAPI) UpdateUserRole(id int, role int64) error {
+func (m *MockPortainerAPI) UpdateUserRole(ctx context.Context, id int, role int64) error {
 	args := m.Called(id, role)
 	return args.Error(0)
 }
 
 // CreateUser mocks the CreateUser method
-func (m *MockPortainerAPI) CreateUser(username, password string, role int64) (int64, error) {
+func (m *MockPortainerAPI) CreateUser(ctx context.Context, username, password string, role int64) (int64, error) {
 	args := m.Called(username, password, role)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // GetUser mocks the GetUser method
-func (m *MockPortainerAPI) GetUser(id int) (*apimodels.PortainereeUser, error) {
+func (m *MockPortainerAPI) GetUser(ctx context.Context, id int) (*apimodels.PortainereeUser, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -342,13 +346,13 @@ func (m *MockPortainerAPI) GetUser(id int) (*apimodels.PortainereeUser, error) {
 }
 
 // DeleteUser mocks the DeleteUser method
-func (m *MockPortainerAPI) DeleteUser(id int64) error {
+func (m *MockPortainerAPI) DeleteUser(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // GetSystemStatus mocks the GetSystemStatus method
-func (m *MockPortainerAPI) GetSystemStatus() (*apimodels.GithubComPortainerPortainerEeAPIHTTPHandlerSystemStatus, error) {
+func (m *MockPortainerAPI) GetSystemStatus(ctx context.Context) (*apimodels.GithubComPortainerPortainerEeAPIHTTPHandlerSystemStatus, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -357,13 +361,13 @@ func (m *MockPortainerAPI) GetSystemStatus() (*apimodels.GithubComPortainerPorta
 }
 
 // GetVersion mocks the GetVersion method
-func (m *MockPortainerAPI) GetVersion() (string, error) {
+func (m *MockPortainerAPI) GetVersion(ctx context.Context) (string, error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
 
 // ProxyDockerRequest mocks the ProxyDockerRequest method
-func (m *MockPortainerAPI) ProxyDockerRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
+func (m *MockPortainerAPI) ProxyDockerRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
 	args := m.Called(environmentId, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -372,7 +376,7 @@ func (m *MockPortainerAPI) ProxyDockerRequest(environmentId int, opts client.Pro
 }
 
 // ProxyKubernetesRequest mocks the ProxyKubernetesRequest method
-func (m *MockPortainerAPI) ProxyKubernetesRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
+func (m *MockPortainerAPI) ProxyKubernetesRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
 	args := m.Called(environmentId, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -381,7 +385,7 @@ func (m *MockPortainerAPI) ProxyKubernetesRequest(environmentId int, opts client
 }
 
 // ListCustomTemplates mocks the ListCustomTemplates method
-func (m *MockPortainerAPI) ListCustomTemplates() ([]*apimodels.PortainereeCustomTemplate, error) {
+func (m *MockPortainerAPI) ListCustomTemplates(ctx context.Context) ([]*apimodels.PortainereeCustomTemplate, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -390,7 +394,7 @@ func (m *MockPortainerAPI) ListCustomTemplates() ([]*apimodels.PortainereeCustom
 }
 
 // GetCustomTemplate mocks the GetCustomTemplate method
-func (m *MockPortainerAPI) GetCustomTemplate(id int64) (*apimodels.PortainereeCustomTemplate, error) {
+func (m *MockPortainerAPI) GetCustomTemplate(ctx context.Context, id int64) (*apimodels.PortainereeCustomTemplate, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -399,13 +403,13 @@ func (m *MockPortainerAPI) GetCustomTemplate(id int64) (*apimodels.PortainereeCu
 }
 
 // GetCustomTemplateFile mocks the GetCustomTemplateFile method
-func (m *MockPortainerAPI) GetCustomTemplateFile(id int64) (string, error) {
+func (m *MockPortainerAPI) GetCustomTemplateFile(ctx context.Context, id int64) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
 // CreateCustomTemplate mocks the CreateCustomTemplate method
-func (m *MockPortainerAPI) CreateCustomTemplate(payload *apimodels.CustomtemplatesCustomTemplateFromFileContentPayload) (*apimodels.PortainereeCustomTemplate, error) {
+func (m *MockPortainerAPI) CreateCustomTemplate(ctx context.Context, payload *apimodels.CustomtemplatesCustomTemplateFromFileContentPayload) (*apimodels.PortainereeCustomTemplate, error) {
 	args := m.Called(payload)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -414,13 +418,13 @@ func (m *MockPortainerAPI) CreateCustomTemplate(payload *apimodels.Customtemplat
 }
 
 // DeleteCustomTemplate mocks the DeleteCustomTemplate method
-func (m *MockPortainerAPI) DeleteCustomTemplate(id int64) error {
+func (m *MockPortainerAPI) DeleteCustomTemplate(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // ListWebhooks mocks the ListWebhooks method
-func (m *MockPortainerAPI) ListWebhooks() ([]*apimodels.PortainerWebhook, error) {
+func (m *MockPortainerAPI) ListWebhooks(ctx context.Context) ([]*apimodels.PortainerWebhook, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -429,19 +433,19 @@ func (m *MockPortainerAPI) ListWebhooks() ([]*apimodels.PortainerWebhook, error)
 }
 
 // CreateWebhook mocks the CreateWebhook method
-func (m *MockPortainerAPI) CreateWebhook(resourceId string, endpointId int64, webhookType int64) (int64, error) {
+func (m *MockPortainerAPI) CreateWebhook(ctx context.Context, resourceId string, endpointId int64, webhookType int64) (int64, error) {
 	args := m.Called(resourceId, endpointId, webhookType)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // DeleteWebhook mocks the DeleteWebhook method
-func (m *MockPortainerAPI) DeleteWebhook(id int64) error {
+func (m *MockPortainerAPI) DeleteWebhook(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // ListRegistries mocks the ListRegistries method
-func (m *MockPortainerAPI) ListRegistries() ([]*apimodels.PortainereeRegistry, error) {
+func (m *MockPortainerAPI) ListRegistries(ctx context.Context) ([]*apimodels.PortainereeRegistry, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -450,7 +454,7 @@ func (m *MockPortainerAPI) ListRegistries() ([]*apimodels.PortainereeRegistry, e
 }
 
 // GetRegistryByID mocks the GetRegistryByID method
-func (m *MockPortainerAPI) GetRegistryByID(id int64) (*apimodels.PortainereeRegistry, error) {
+func (m *MockPortainerAPI) GetRegistryByID(ctx context.Context, id int64) (*apimodels.PortainereeRegistry, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -459,25 +463,25 @@ func (m *MockPortainerAPI) GetRegistryByID(id int64) (*apimodels.PortainereeRegi
 }
 
 // CreateRegistry mocks the CreateRegistry method
-func (m *MockPortainerAPI) CreateRegistry(body *apimodels.RegistriesRegistryCreatePayload) (int64, error) {
+func (m *MockPortainerAPI) CreateRegistry(ctx context.Context, body *apimodels.RegistriesRegistryCreatePayload) (int64, error) {
 	args := m.Called(body)
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // UpdateRegistry mocks the UpdateRegistry method
-func (m *MockPortainerAPI) UpdateRegistry(id int64, body *apimodels.RegistriesRegistryUpdatePayload) error {
+func (m *MockPortainerAPI) UpdateRegistry(ctx context.Context, id int64, body *apimodels.RegistriesRegistryUpdatePayload) error {
 	args := m.Called(id, body)
 	return args.Error(0)
 }
 
 // DeleteRegistry mocks the DeleteRegistry method
-func (m *MockPortainerAPI) DeleteRegistry(id int64) error {
+func (m *MockPortainerAPI) DeleteRegistry(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // GetBackupStatus mocks the GetBackupStatus method
-func (m *MockPortainerAPI) GetBackupStatus() (*apimodels.BackupBackupStatus, error) {
+func (m *MockPortainerAPI) GetBackupStatus(ctx context.Context) (*apimodels.BackupBackupStatus, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -486,7 +490,7 @@ func (m *MockPortainerAPI) GetBackupStatus() (*apimodels.BackupBackupStatus, err
 }
 
 // GetBackupSettings mocks the GetBackupSettings method
-func (m *MockPortainerAPI) GetBackupSettings() (*apimodels.PortainereeS3BackupSettings, error) {
+func (m *MockPortainerAPI) GetBackupSettings(ctx context.Context) (*apimodels.PortainereeS3BackupSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -495,25 +499,25 @@ func (m *MockPortainerAPI) GetBackupSettings() (*apimodels.PortainereeS3BackupSe
 }
 
 // CreateBackup mocks the CreateBackup method
-func (m *MockPortainerAPI) CreateBackup(password string) error {
+func (m *MockPortainerAPI) CreateBackup(ctx context.Context, password string) error {
 	args := m.Called(password)
 	return args.Error(0)
 }
 
 // BackupToS3 mocks the BackupToS3 method
-func (m *MockPortainerAPI) BackupToS3(body *apimodels.BackupS3BackupPayload) error {
+func (m *MockPortainerAPI) BackupToS3(ctx context.Context, body *apimodels.BackupS3BackupPayload) error {
 	args := m.Called(body)
 	return args.Error(0)
 }
 
 // RestoreFromS3 mocks the RestoreFromS3 method
-func (m *MockPortainerAPI) RestoreFromS3(body *apimodels.BackupRestoreS3Settings) error {
+func (m *MockPortainerAPI) RestoreFromS3(ctx context.Context, body *apimodels.BackupRestoreS3Settings) error {
 	args := m.Called(body)
 	return args.Error(0)
 }
 
 // ListRoles mocks the ListRoles method
-func (m *MockPortainerAPI) ListRoles() ([]*apimodels.PortainereeRole, error) {
+func (m *MockPortainerAPI) ListRoles(ctx context.Context) ([]*apimodels.PortainereeRole, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -522,7 +526,7 @@ func (m *MockPortainerAPI) ListRoles() ([]*apimodels.PortainereeRole, error) {
 }
 
 // GetMOTD mocks the GetMOTD method
-func (m *MockPortainerAPI) GetMOTD() (map[string]any, error) {
+func (m *MockPortainerAPI) GetMOTD(ctx context.Context) (map[string]any, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -531,7 +535,7 @@ func (m *MockPortainerAPI) GetMOTD() (map[string]any, error) {
 }
 
 // ListEdgeJobs mocks the ListEdgeJobs method
-func (m *MockPortainerAPI) ListEdgeJobs() ([]*apimodels.PortainerEdgeJob, error) {
+func (m *MockPortainerAPI) ListEdgeJobs(ctx context.Context) ([]*apimodels.PortainerEdgeJob, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -540,7 +544,7 @@ func (m *MockPortainerAPI) ListEdgeJobs() ([]*apimodels.PortainerEdgeJob, error)
 }
 
 // GetEdgeJob mocks the GetEdgeJob method
-func (m *MockPortainerAPI) GetEdgeJob(id int64) (*apimodels.PortainerEdgeJob, error) {
+func (m *MockPortainerAPI) GetEdgeJob(ctx context.Context, id int64) (*apimodels.PortainerEdgeJob, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -549,25 +553,25 @@ func (m *MockPortainerAPI) GetEdgeJob(id int64) (*apimodels.PortainerEdgeJob, er
 }
 
 // GetEdgeJobFile mocks the GetEdgeJobFile method
-func (m *MockPortainerAPI) GetEdgeJobFile(id int64) (string, error) {
+func (m *MockPortainerAPI) GetEdgeJobFile(ctx context.Context, id int64) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
 // CreateEdgeJob mocks the CreateEdgeJob method
-func (m *MockPortainerAPI) CreateEdgeJob(payload *apimodels.EdgejobsEdgeJobCreateFromFileContentPayload) (int64, error) {
+func (m *MockPortainerAPI) CreateEdgeJob(ctx context.Context, payload *apimodels.EdgejobsEdgeJobCreateFromFileContentPayload) (int64, error) {
 	args := m.Called(payload)
 	return int64(args.Int(0)), args.Error(1)
 }
 
 // DeleteEdgeJob mocks the DeleteEdgeJob method
-func (m *MockPortainerAPI) DeleteEdgeJob(id int64) error {
+func (m *MockPortainerAPI) DeleteEdgeJob(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // ListEdgeUpdateSchedules mocks the ListEdgeUpdateSchedules method
-func (m *MockPortainerAPI) ListEdgeUpdateSchedules() ([]*apimodels.EdgeupdateschedulesDecoratedUpdateSchedule, error) {
+func (m *MockPortainerAPI) ListEdgeUpdateSchedules(ctx context.Context) ([]*apimodels.EdgeupdateschedulesDecoratedUpdateSchedule, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -576,7 +580,7 @@ func (m *MockPortainerAPI) ListEdgeUpdateSchedules() ([]*apimodels.Edgeupdatesch
 }
 
 // AuthenticateUser mocks the AuthenticateUser method
-func (m *MockPortainerAPI) AuthenticateUser(username, password string) (*apimodels.AuthAuthenticateResponse, error) {
+func (m *MockPortainerAPI) AuthenticateUser(ctx context.Context, username, password string) (*apimodels.AuthAuthenticateResponse, error) {
 	args := m.Called(username, password)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -585,14 +589,14 @@ func (m *MockPortainerAPI) AuthenticateUser(username, password string) (*apimode
 }
 
 // Logout mocks the Logout method
-func (m *MockPortainerAPI) Logout() error {
+func (m *MockPortainerAPI) Logout(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
 // Helm methods
 
-func (m *MockPortainerAPI) ListHelmRepositories(userId int64) (*apimodels.UsersHelmUserRepositoryResponse, error) {
+func (m *MockPortainerAPI) ListHelmRepositories(ctx context.Context, userId int64) (*apimodels.UsersHelmUserRepositoryResponse, error) {
 	args := m.Called(userId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -600,7 +604,7 @@ func (m *MockPortainerAPI) ListHelmRepositories(userId int64) (*apimodels.UsersH
 	return args.Get(0).(*apimodels.UsersHelmUserRepositoryResponse), args.Error(1)
 }
 
-func (m *MockPortainerAPI) CreateHelmRepository(userId int64, url string) (*apimodels.PortainerHelmUserRepository, error) {
+func (m *MockPortainerAPI) CreateHelmRepository(ctx context.Context, userId int64, url string) (*apimodels.PortainerHelmUserRepository, error) {
 	args := m.Called(userId, url)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -608,17 +612,17 @@ func (m *MockPortainerAPI) CreateHelmRepository(userId int64, url string) (*apim
 	return args.Get(0).(*apimodels.PortainerHelmUserRepository), args.Error(1)
 }
 
-func (m *MockPortainerAPI) DeleteHelmRepository(userId int64, repositoryId int64) error {
+func (m *MockPortainerAPI) DeleteHelmRepository(ctx context.Context, userId int64, repositoryId int64) error {
 	args := m.Called(userId, repositoryId)
 	return args.Error(0)
 }
 
-func (m *MockPortainerAPI) SearchHelmCharts(repo string, chart *string) (string, error) {
+func (m *MockPortainerAPI) SearchHelmCharts(ctx context.Context, repo string, chart *string) (string, error) {
 	args := m.Called(repo, chart)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerAPI) InstallHelmChart(environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error) {
+func (m *MockPortainerAPI) InstallHelmChart(ctx context.Context, environmentId int64, payload *apimodels.HelmInstallChartPayload) (*apimodels.ReleaseRelease, error) {
 	args := m.Called(environmentId, payload)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -626,7 +630,7 @@ func (m *MockPortainerAPI) InstallHelmChart(environmentId int64, payload *apimod
 	return args.Get(0).(*apimodels.ReleaseRelease), args.Error(1)
 }
 
-func (m *MockPortainerAPI) ListHelmReleases(environmentId int64, namespace *string, filter *string, selector *string) ([]*apimodels.ReleaseReleaseElement, error) {
+func (m *MockPortainerAPI) ListHelmReleases(ctx context.Context, environmentId int64, namespace *string, filter *string, selector *string) ([]*apimodels.ReleaseReleaseElement, error) {
 	args := m.Called(environmentId, namespace, filter, selector)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -634,12 +638,12 @@ func (m *MockPortainerAPI) ListHelmReleases(environmentId int64, namespace *stri
 	return args.Get(0).([]*apimodels.ReleaseReleaseElement), args.Error(1)
 }
 
-func (m *MockPortainerAPI) DeleteHelmRelease(environmentId int64, release string, namespace *string) error {
+func (m *MockPortainerAPI) DeleteHelmRelease(ctx context.Context, environmentId int64, release string, namespace *string) error {
 	args := m.Called(environmentId, release, namespace)
 	return args.Error(0)
 }
 
-func (m *MockPortainerAPI) GetHelmReleaseHistory(environmentId int64, name string, namespace *string) ([]*apimodels.ReleaseRelease, error) {
+func (m *MockPortainerAPI) GetHelmReleaseHistory(ctx context.Context, environmentId int64, name string, namespace *string) ([]*apimodels.ReleaseRelease, error) {
 	args := m.Called(environmentId, name, namespace)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -647,7 +651,7 @@ func (m *MockPortainerAPI) GetHelmReleaseHistory(environmentId int64, name strin
 	return args.Get(0).([]*apimodels.ReleaseRelease), args.Error(1)
 }
 
-func (m *MockPortainerAPI) GetDockerDashboard(environmentId int64) (*apimodels.DockerDashboardResponse, error) {
+func (m *MockPortainerAPI) GetDockerDashboard(ctx context.Context, environmentId int64) (*apimodels.DockerDashboardResponse, error) {
 	args := m.Called(environmentId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -655,7 +659,7 @@ func (m *MockPortainerAPI) GetDockerDashboard(environmentId int64) (*apimodels.D
 	return args.Get(0).(*apimodels.DockerDashboardResponse), args.Error(1)
 }
 
-func (m *MockPortainerAPI) GetKubernetesConfig(environmentId int64) (interface{}, error) {
+func (m *MockPortainerAPI) GetKubernetesConfig(ctx context.Context, environmentId int64) (interface{}, error) {
 	args := m.Called(environmentId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -663,7 +667,7 @@ func (m *MockPortainerAPI) GetKubernetesConfig(environmentId int64) (interface{}
 	return args.Get(0), args.Error(1)
 }
 
-func (m *MockPortainerAPI) GetKubernetesDashboard(environmentId int64) (*apimodels.KubernetesK8sDashboard, error) {
+func (m *MockPortainerAPI) GetKubernetesDashboard(ctx context.Context, environmentId int64) (*apimodels.KubernetesK8sDashboard, error) {
 	args := m.Called(environmentId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -671,7 +675,7 @@ func (m *MockPortainerAPI) GetKubernetesDashboard(environmentId int64) (*apimode
 	return args.Get(0).(*apimodels.KubernetesK8sDashboard), args.Error(1)
 }
 
-func (m *MockPortainerAPI) GetKubernetesNamespaces(environmentId int64) ([]*apimodels.PortainerK8sNamespaceInfo, error) {
+func (m *MockPortainerAPI) GetKubernetesNamespaces(ctx context.Context, environmentId int64) ([]*apimodels.PortainerK8sNamespaceInfo, error) {
 	args := m.Called(environmentId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -679,7 +683,7 @@ func (m *MockPortainerAPI) GetKubernetesNamespaces(environmentId int64) ([]*apim
 	return args.Get(0).([]*apimodels.PortainerK8sNamespaceInfo), args.Error(1)
 }
 
-func (m *MockPortainerAPI) StackInspect(id int64) (*apimodels.PortainereeStack, error) {
+func (m *MockPortainerAPI) StackInspect(ctx context.Context, id int64) (*apimodels.PortainereeStack, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -687,17 +691,17 @@ func (m *MockPortainerAPI) StackInspect(id int64) (*apimodels.PortainereeStack,
 	return args.Get(0).(*apimodels.PortainereeStack), args.Error(1)
 }
 
-func (m *MockPortainerAPI) StackDelete(id int64, endpointID int64, removeVolumes bool) error {
+func (m *MockPortainerAPI) StackDelete(ctx context.Context, id int64, endpointID int64, removeVolumes bool) error {
 	args := m.Called(id, endpointID, removeVolumes)
 	return args.Error(0)
 }
 
-func (m *MockPortainerAPI) StackFileInspect(id int64) (string, error) {
+func (m *MockPortainerAPI) StackFileInspect(ctx context.Context, id int64) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerAPI) StackUpdateGit(id int64, endpointID int64, body *apimodels.StacksStackGitUpdatePayload) (*apimodels.PortainereeStack, error) {
+func (m *MockPortainerAPI) StackUpdateGit(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackGitUpdatePayload) (*apimodels.PortainereeStack, error) {
 	args := m.Called(id, endpointID, body)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -705,7 +709,7 @@ func (m *MockPortainerAPI) StackUpdateGit(id int64, endpointID int64, body *apim
 	return args.Get(0).(*apimodels.PortainereeStack), args.Error(1)
 }
 
-func (m *MockPortainerAPI) StackGitRedeploy(id int64, endpointID int64, body *apimodels.StacksStackGitRedployPayload) (*apimodels.PortainereeStack, error) {
+func (m *MockPortainerAPI) StackGitRedeploy(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackGitRedployPayload) (*apimodels.PortainereeStack, error) {
 	args := m.Called(id, endpointID, body)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -713,7 +717,7 @@ func (m *MockPortainerAPI) StackGitRedeploy(id int64, endpointID int64, body *ap
 	return args.Get(0).(*apimodels.PortainereeStack), args.Error(1)
 }
 
-func (m *MockPortainerAPI) StackStart(id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
+func (m *MockPortainerAPI) StackStart(ctx context.Context, id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
 	args := m.Called(id, endpointID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -721,7 +725,7 @@ func (m *MockPortainerAPI) StackStart(id int64, endpointID int64) (*apimodels.Po
 	return args.Get(0).(*apimodels.PortainereeStack), args.Error(1)
 }
 
-func (m *MockPortainerAPI) StackStop(id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
+func (m *MockPortainerAPI) StackStop(ctx context.Context, id int64, endpointID int64) (*apimodels.PortainereeStack, error) {
 	args := m.Called(id, endpointID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -729,7 +733,7 @@ func (m *MockPortainerAPI) StackStop(id int64, endpointID int64) (*apimodels.Por
 	return args.Get(0).(*apimodels.PortainereeStack), args.Error(1)
 }
 
-func (m *MockPortainerAPI) StackMigrate(id int64, endpointID int64, body *apimodels.StacksStackMigratePayload) (*apimodels.PortainereeStack, error) {
+func (m *MockPortainerAPI) StackMigrate(ctx context.Context, id int64, endpointID int64, body *apimodels.StacksStackMigratePayload) (*apimodels.PortainereeStack, error) {
 	args := m.Called(id, endpointID, body)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
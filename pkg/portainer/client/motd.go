@@ -1,12 +1,13 @@
 package client
 
 import (
+	"context"
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 )
 
 // GetMOTD retrieves the message of the day from the Portainer server.
-func (c *PortainerClient) GetMOTD() (models.MOTD, error) {
-	raw, err := c.cli.GetMOTD()
+func (c *PortainerClient) GetMOTD(ctx context.Context) (models.MOTD, error) {
+	raw, err := c.cli.GetMOTD(ctx)
 	if err != nil {
 		return models.MOTD{}, err
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"testing"
@@ -52,7 +53,7 @@ func TestGetMOTD(t *testing.T) {
 			mockAPI.On("GetMOTD").Return(tt.mockMOTD, tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			motd, err := client.GetMOTD()
+			motd, err := client.GetMOTD(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
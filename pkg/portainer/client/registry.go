@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
@@ -12,8 +13,8 @@ import (
 // Returns:
 //   - A slice of Registry objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetRegistries() ([]models.Registry, error) {
-	rawRegistries, err := c.cli.ListRegistries()
+func (c *PortainerClient) GetRegistries(ctx context.Context) ([]models.Registry, error) {
+	rawRegistries, err := c.cli.ListRegistries(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list registries: %w", err)
 	}
@@ -34,8 +35,8 @@ func (c *PortainerClient) GetRegistries() ([]models.Registry, error) {
 // Returns:
 //   - A Registry object
 //   - An error if the operation fails
-func (c *PortainerClient) GetRegistry(id int) (models.Registry, error) {
-	rawRegistry, err := c.cli.GetRegistryByID(int64(id))
+func (c *PortainerClient) GetRegistry(ctx context.Context, id int) (models.Registry, error) {
+	rawRegistry, err := c.cli.GetRegistryByID(ctx, int64(id))
 	if err != nil {
 		return models.Registry{}, fmt.Errorf("failed to get registry: %w", err)
 	}
@@ -57,7 +58,7 @@ func (c *PortainerClient) GetRegistry(id int) (models.Registry, error) {
 // Returns:
 //   - The ID of the created registry
 //   - An error if the operation fails
-func (c *PortainerClient) CreateRegistry(name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error) {
+func (c *PortainerClient) CreateRegistry(ctx context.Context, name string, registryType int, url string, authentication bool, username string, password string, baseURL string) (int, error) {
 	regType := int64(registryType)
 	body := &apimodels.RegistriesRegistryCreatePayload{
 		Name:           &name,
@@ -69,7 +70,7 @@ func (c *PortainerClient) CreateRegistry(name string, registryType int, url stri
 		BaseURL:        baseURL,
 	}
 
-	id, err := c.cli.CreateRegistry(body)
+	id, err := c.cli.CreateRegistry(ctx, body)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create registry: %w", err)
 	}
@@ -92,8 +93,8 @@ func (c *PortainerClient) CreateRegistry(name string, registryType int, url stri
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateRegistry(id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error {
-	existing, err := c.cli.GetRegistryByID(int64(id))
+func (c *PortainerClient) UpdateRegistry(ctx context.Context, id int, name *string, url *string, authentication *bool, username *string, password *string, baseURL *string) error {
+	existing, err := c.cli.GetRegistryByID(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to get registry for update: %w", err)
 	}
@@ -137,7 +138,7 @@ func (c *PortainerClient) UpdateRegistry(id int, name *string, url *string, auth
 		BaseURL:        updatedBaseURL,
 	}
 
-	err = c.cli.UpdateRegistry(int64(id), body)
+	err = c.cli.UpdateRegistry(ctx, int64(id), body)
 	if err != nil {
 		return fmt.Errorf("failed to update registry: %w", err)
 	}
@@ -152,8 +153,8 @@ func (c *PortainerClient) UpdateRegistry(id int, name *string, url *string, auth
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteRegistry(id int) error {
-	err := c.cli.DeleteRegistry(int64(id))
+func (c *PortainerClient) DeleteRegistry(ctx context.Context, id int) error {
+	err := c.cli.DeleteRegistry(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete registry: %w", err)
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -56,7 +57,7 @@ func TestGetRegistries(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			registries, err := client.GetRegistries()
+			registries, err := client.GetRegistries(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -106,7 +107,7 @@ func TestGetRegistry(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			registry, err := client.GetRegistry(tt.registryID)
+			registry, err := client.GetRegistry(context.Background(), tt.registryID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -170,7 +171,7 @@ func TestCreateRegistry(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateRegistry(tt.regName, tt.regType, tt.url, tt.auth, tt.username, tt.password, tt.baseURL)
+			id, err := client.CreateRegistry(context.Background(), tt.regName, tt.regType, tt.url, tt.auth, tt.username, tt.password, tt.baseURL)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -253,7 +254,7 @@ func TestUpdateRegistry(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateRegistry(tt.registryID, tt.nameParam, tt.urlParam, tt.authParam, tt.usernameParam, tt.passwordParam, tt.baseURLParam)
+			err := client.UpdateRegistry(context.Background(), tt.registryID, tt.nameParam, tt.urlParam, tt.authParam, tt.usernameParam, tt.passwordParam, tt.baseURLParam)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -293,7 +294,7 @@ func TestDeleteRegistry(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.DeleteRegistry(tt.registryID)
+			err := client.DeleteRegistry(context.Background(), tt.registryID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
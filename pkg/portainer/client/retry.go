@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultMaxRetries is the number of times an idempotent request is
+	// retried after a transient failure.
+	defaultMaxRetries = 3
+	// defaultRetryBaseDelay is the backoff before the first retry; it doubles
+	// with every retry up to defaultRetryMaxDelay.
+	defaultRetryBaseDelay = 250 * time.Millisecond
+	// defaultRetryMaxDelay caps the backoff between two retries.
+	defaultRetryMaxDelay = 4 * time.Second
+	// maxRetryAfter is the longest Retry-After the transport waits for. Longer
+	// waits are left to the caller, which gets the failed response.
+	maxRetryAfter = 10 * time.Second
+)
+
+// retryTransport is an http.RoundTripper that retries idempotent requests
+// (GET, HEAD, and OPTIONS) after network errors, 429 responses, and 5xx
+// responses, with jittered exponential backoff. Connection upgrades and
+// exec or attach requests are never retried, as they run commands. A Retry-After header on the
+// response replaces the backoff when it is longer. Retries stop when the
+// request context is done, so they are bounded by the client timeout.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	// now, jitter, and sleep are replaced in tests.
+	now    func() time.Time
+	jitter func(d time.Duration) time.Duration
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// newRetryTransport wraps next with the default retry settings.
+func newRetryTransport(next http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		next:       next,
+		maxRetries: defaultMaxRetries,
+		baseDelay:  defaultRetryBaseDelay,
+		maxDelay:   defaultRetryMaxDelay,
+		now:        time.Now,
+		jitter:     equalJitter,
+		sleep:      sleepContext,
+	}
+}
+
+// sleepContext waits for d, or returns the context error if it is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// equalJitter returns a random duration between d/2 and d, so that clients
+// failing at the same time do not retry at the same time.
+func equalJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half+1)
+}
+
+// RoundTrip sends the request, retrying it while it is idempotent, the
+// failure is transient, and retries are left.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isRetryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if attempt == t.maxRetries || !isTransient(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := t.jitter(min(t.baseDelay<<attempt, t.maxDelay))
+		if resp != nil {
+			retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now())
+			if ok && retryAfter > maxRetryAfter {
+				return resp, nil
+			}
+			wait = max(wait, retryAfter)
+			// Drain the body so that the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryable reports whether a request may be sent again: its method must be
+// idempotent and its body, if any, must be replayable. A GET that upgrades the
+// connection, such as the websocket of a Kubernetes pod exec, or that targets
+// an exec or attach path is not idempotent, whatever its method.
+func isRetryable(req *http.Request) bool {
+	if isUpgrade(req.Header) || hasExecPath(req.URL.Path) {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	default:
+		return false
+	}
+}
+
+// isUpgrade reports whether a request asks to upgrade its connection.
+func isUpgrade(header http.Header) bool {
+	if header.Get("Upgrade") != "" {
+		return true
+	}
+	for _, value := range header.Values("Connection") {
+		for token := range strings.SplitSeq(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasExecPath reports whether a path runs or attaches to a process, such as
+// /containers/{id}/attach or /api/v1/namespaces/{ns}/pods/{pod}/exec.
+func hasExecPath(path string) bool {
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == "exec" || segment == "attach" {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransient reports whether a failed round trip is worth retrying.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as
+// an HTTP date, into the time to wait.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
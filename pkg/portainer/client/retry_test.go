@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceRoundTripper returns the given responses in order, one per request.
+// A response with status 0 is returned as a transport error.
+type sequenceRoundTripper struct {
+	responses []sequenceResponse
+	requests  int
+}
+
+type sequenceResponse struct {
+	status     int
+	retryAfter string
+}
+
+func (s *sequenceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := s.responses[min(s.requests, len(s.responses)-1)]
+	s.requests++
+	if r.status == 0 {
+		return nil, errTransport
+	}
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if r.retryAfter != "" {
+		header.Set("Retry-After", r.retryAfter)
+	}
+	return &http.Response{StatusCode: r.status, Header: header, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+}
+
+// newTestRetryTransport returns a retry transport without jitter whose waits
+// are recorded instead of slept.
+func newTestRetryTransport(next http.RoundTripper) (*retryTransport, *[]time.Duration) {
+	var waits []time.Duration
+	t := newRetryTransport(next)
+	t.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	t.jitter = func(d time.Duration) time.Duration { return d }
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return t, &waits
+}
+
+// TestRetryTransport verifies which requests and failures are retried, and how long the transport waits.
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		header           http.Header
+		responses        []sequenceResponse
+		expectedStatus   int
+		expectedError    bool
+		expectedRequests int
+		expectedWaits    []time.Duration
+	}{
+		{
+			name:             "success is not retried",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 200}},
+			expectedStatus:   200,
+			expectedRequests: 1,
+		},
+		{
+			name:             "bad gateway then success",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 502}, {status: 503}, {status: 200}},
+			expectedStatus:   200,
+			expectedRequests: 3,
+			expectedWaits:    []time.Duration{250 * time.Millisecond, 500 * time.Millisecond},
+		},
+		{
+			name:             "network error then success",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 0}, {status: 200}},
+			expectedStatus:   200,
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{250 * time.Millisecond},
+		},
+		{
+			name:             "retry after is honored",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 429, retryAfter: "3"}, {status: 503, retryAfter: "Wed, 01 Jan 2025 00:00:02 GMT"}, {status: 200}},
+			expectedStatus:   200,
+			expectedRequests: 3,
+			expectedWaits:    []time.Duration{3 * time.Second, 2 * time.Second},
+		},
+		{
+			name:             "long retry after is left to the caller",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 429, retryAfter: "120"}},
+			expectedStatus:   429,
+			expectedRequests: 1,
+		},
+		{
+			name:             "retries are exhausted",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 503}},
+			expectedStatus:   503,
+			expectedRequests: 4,
+			expectedWaits:    []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, time.Second},
+		},
+		{
+			name:             "client errors are not retried",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 404}},
+			expectedStatus:   404,
+			expectedRequests: 1,
+		},
+		{
+			name:             "not implemented is not retried",
+			method:           http.MethodGet,
+			responses:        []sequenceResponse{{status: 501}},
+			expectedStatus:   501,
+			expectedRequests: 1,
+		},
+		{
+			name:             "POST is not retried",
+			method:           http.MethodPost,
+			responses:        []sequenceResponse{{status: 503}},
+			expectedStatus:   503,
+			expectedRequests: 1,
+		},
+		{
+			name:             "DELETE network error is not retried",
+			method:           http.MethodDelete,
+			responses:        []sequenceResponse{{status: 0}},
+			expectedError:    true,
+			expectedRequests: 1,
+		},
+		{
+			name:             "failing upgrade request is sent once",
+			method:           http.MethodGet,
+			path:             "/api/endpoints/1/kubernetes/api/v1/namespaces/default/pods/web/exec?command=ls",
+			header:           http.Header{"Connection": []string{"Upgrade"}, "Upgrade": []string{"websocket"}},
+			responses:        []sequenceResponse{{status: 502}, {status: 101}},
+			expectedStatus:   502,
+			expectedRequests: 1,
+		},
+		{
+			name:             "upgrade network error is not retried",
+			method:           http.MethodGet,
+			path:             "/api/endpoints/1/docker/containers/abc/attach/ws",
+			header:           http.Header{"Connection": []string{"keep-alive, Upgrade"}},
+			responses:        []sequenceResponse{{status: 0}},
+			expectedError:    true,
+			expectedRequests: 1,
+		},
+		{
+			name:             "exec path is not retried",
+			method:           http.MethodGet,
+			path:             "/api/endpoints/1/kubernetes/api/v1/namespaces/default/pods/web/exec",
+			responses:        []sequenceResponse{{status: 503}},
+			expectedStatus:   503,
+			expectedRequests: 1,
+		},
+		{
+			name:             "attach path is not retried",
+			method:           http.MethodGet,
+			path:             "/api/endpoints/1/docker/containers/abc/attach",
+			responses:        []sequenceResponse{{status: 503}},
+			expectedStatus:   503,
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &sequenceRoundTripper{responses: tt.responses}
+			transport, waits := newTestRetryTransport(next)
+			path := tt.path
+			if path == "" {
+				path = "/api/endpoints"
+			}
+			req, err := http.NewRequest(tt.method, "http://portainer.local"+path, nil)
+			require.NoError(t, err)
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+
+			resp, err := transport.RoundTrip(req)
+
+			if tt.expectedError {
+				assert.ErrorIs(t, err, errTransport)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			}
+			assert.Equal(t, tt.expectedRequests, next.requests)
+			assert.Equal(t, tt.expectedWaits, *waits)
+		})
+	}
+}
+
+// TestRetryTransportContext verifies that retries stop when the request context is done.
+func TestRetryTransportContext(t *testing.T) {
+	next := &sequenceRoundTripper{responses: []sequenceResponse{{status: 503}}}
+	transport, _ := newTestRetryTransport(next)
+	ctx, cancel := context.WithCancel(context.Background())
+	transport.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return context.Canceled
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://portainer.local/api/endpoints", nil)
+	require.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 1, next.requests)
+}
+
+// TestEqualJitter verifies that the jittered backoff stays between half and all of the delay.
+func TestEqualJitter(t *testing.T) {
+	for range 100 {
+		d := equalJitter(time.Second)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+	assert.Equal(t, time.Duration(1), equalJitter(1))
+}
+
+// TestParseRetryAfter verifies parsing of the Retry-After header.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2025 00:01:00 GMT", time.Minute, true},
+		{"Tue, 31 Dec 2024 23:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		d, ok := parseRetryAfter(tt.value, now)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.Equal(t, tt.expected, d, tt.value)
+	}
+}
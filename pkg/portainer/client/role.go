@@ -1,14 +1,15 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
 )
 
 // GetRoles retrieves all roles from the Portainer server.
-func (c *PortainerClient) GetRoles() ([]models.Role, error) {
-	rawRoles, err := c.cli.ListRoles()
+func (c *PortainerClient) GetRoles(ctx context.Context) ([]models.Role, error) {
+	rawRoles, err := c.cli.ListRoles(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list roles: %w", err)
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -77,7 +78,7 @@ func TestGetRoles(t *testing.T) {
 			mockAPI.On("ListRoles").Return(tt.mockRoles, tt.mockError)
 
 			client := &PortainerClient{cli: mockAPI}
-			roles, err := client.GetRoles()
+			roles, err := client.GetRoles(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
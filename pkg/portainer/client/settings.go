@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -9,8 +10,8 @@ import (
 )
 
 // GetSettings retrieves settings.
-func (c *PortainerClient) GetSettings() (models.PortainerSettings, error) {
-	settings, err := c.cli.GetSettings()
+func (c *PortainerClient) GetSettings(ctx context.Context) (models.PortainerSettings, error) {
+	settings, err := c.cli.GetSettings(ctx)
 	if err != nil {
 		return models.PortainerSettings{}, fmt.Errorf("failed to get settings: %w", err)
 	}
@@ -19,7 +20,7 @@ func (c *PortainerClient) GetSettings() (models.PortainerSettings, error) {
 }
 
 // UpdateSettings updates the Portainer settings from a JSON map.
-func (c *PortainerClient) UpdateSettings(settingsJSON map[string]interface{}) error {
+func (c *PortainerClient) UpdateSettings(ctx context.Context, settingsJSON map[string]interface{}) error {
 	data, err := json.Marshal(settingsJSON)
 	if err != nil {
 		return fmt.Errorf("failed to marshal settings: %w", err)
@@ -30,7 +31,7 @@ func (c *PortainerClient) UpdateSettings(settingsJSON map[string]interface{}) er
 		return fmt.Errorf("failed to unmarshal settings payload: %w", err)
 	}
 
-	if err := c.cli.UpdateSettings(&payload); err != nil {
+	if err := c.cli.UpdateSettings(ctx, &payload); err != nil {
 		return fmt.Errorf("failed to update settings: %w", err)
 	}
 
@@ -38,8 +39,8 @@ func (c *PortainerClient) UpdateSettings(settingsJSON map[string]interface{}) er
 }
 
 // GetPublicSettings retrieves the public settings.
-func (c *PortainerClient) GetPublicSettings() (models.PublicSettings, error) {
-	raw, err := c.cli.GetPublicSettings()
+func (c *PortainerClient) GetPublicSettings(ctx context.Context) (models.PublicSettings, error) {
+	raw, err := c.cli.GetPublicSettings(ctx)
 	if err != nil {
 		return models.PublicSettings{}, fmt.Errorf("failed to get public settings: %w", err)
 	}
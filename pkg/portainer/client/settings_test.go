@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -129,7 +130,7 @@ func TestGetSettings(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			settings, err := client.GetSettings()
+			settings, err := client.GetSettings(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -172,7 +173,7 @@ func TestUpdateSettings(t *testing.T) {
 			mockAPI.On("UpdateSettings", mock.AnythingOfType("*models.SettingsSettingsUpdatePayload")).Return(tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			err := c.UpdateSettings(tt.settings)
+			err := c.UpdateSettings(context.Background(), tt.settings)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -211,7 +212,7 @@ func TestGetPublicSettings(t *testing.T) {
 			mockAPI.On("GetPublicSettings").Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetPublicSettings()
+			result, err := c.GetPublicSettings(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
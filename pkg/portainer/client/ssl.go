@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
@@ -8,8 +9,8 @@ import (
 )
 
 // GetSSLSettings retrieves the SSL settings.
-func (c *PortainerClient) GetSSLSettings() (models.SSLSettings, error) {
-	raw, err := c.cli.GetSSLSettings()
+func (c *PortainerClient) GetSSLSettings(ctx context.Context) (models.SSLSettings, error) {
+	raw, err := c.cli.GetSSLSettings(ctx)
 	if err != nil {
 		return models.SSLSettings{}, fmt.Errorf("failed to get SSL settings: %w", err)
 	}
@@ -18,7 +19,7 @@ func (c *PortainerClient) GetSSLSettings() (models.SSLSettings, error) {
 }
 
 // UpdateSSLSettings updates the SSL settings.
-func (c *PortainerClient) UpdateSSLSettings(cert, key string, httpEnabled *bool) error {
+func (c *PortainerClient) UpdateSSLSettings(ctx context.Context, cert, key string, httpEnabled *bool) error {
 	payload := &apimodels.SslSslUpdatePayload{
 		Cert: cert,
 		Key:  key,
@@ -28,7 +29,7 @@ func (c *PortainerClient) UpdateSSLSettings(cert, key string, httpEnabled *bool)
 		payload.Httpenabled = *httpEnabled
 	}
 
-	if err := c.cli.UpdateSSLSettings(payload); err != nil {
+	if err := c.cli.UpdateSSLSettings(ctx, payload); err != nil {
 		return fmt.Errorf("failed to update SSL settings: %w", err)
 	}
 
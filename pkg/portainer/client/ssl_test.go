@@ -3,6 +3,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -41,7 +42,7 @@ func TestGetSSLSettings(t *testing.T) {
 			mockAPI.On("GetSSLSettings").Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetSSLSettings()
+			result, err := c.GetSSLSettings(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -91,7 +92,7 @@ func TestUpdateSSLSettings(t *testing.T) {
 			mockAPI.On("UpdateSSLSettings", mock.AnythingOfType("*models.SslSslUpdatePayload")).Return(tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			err := c.UpdateSSLSettings(tt.cert, tt.key, tt.httpEnabled)
+			err := c.UpdateSSLSettings(context.Background(), tt.cert, tt.key, tt.httpEnabled)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
@@ -14,8 +15,8 @@ import (
 // Returns:
 //   - A slice of Stack objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetStacks() ([]models.Stack, error) {
-	edgeStacks, err := c.cli.ListEdgeStacks()
+func (c *PortainerClient) GetStacks(ctx context.Context) ([]models.Stack, error) {
+	edgeStacks, err := c.cli.ListEdgeStacks(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list edge stacks: %w", err)
 	}
@@ -34,8 +35,8 @@ func (c *PortainerClient) GetStacks() ([]models.Stack, error) {
 // Returns:
 //   - A slice of RegularStack objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetRegularStacks() ([]models.RegularStack, error) {
-	rawStacks, err := c.cli.ListRegularStacks()
+func (c *PortainerClient) GetRegularStacks(ctx context.Context) ([]models.RegularStack, error) {
+	rawStacks, err := c.cli.ListRegularStacks(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list regular stacks: %w", err)
 	}
@@ -57,8 +58,8 @@ func (c *PortainerClient) GetRegularStacks() ([]models.RegularStack, error) {
 // Returns:
 //   - The file content of the stack (Compose file)
 //   - An error if the operation fails
-func (c *PortainerClient) GetStackFile(id int) (string, error) {
-	file, err := c.cli.GetEdgeStackFile(int64(id))
+func (c *PortainerClient) GetStackFile(ctx context.Context, id int) (string, error) {
+	file, err := c.cli.GetEdgeStackFile(ctx, int64(id))
 	if err != nil {
 		return "", fmt.Errorf("failed to get edge stack file: %w", err)
 	}
@@ -78,8 +79,8 @@ func (c *PortainerClient) GetStackFile(id int) (string, error) {
 // Returns:
 //   - The ID of the created stack
 //   - An error if the operation fails
-func (c *PortainerClient) CreateStack(name, file string, environmentGroupIds []int) (int, error) {
-	id, err := c.cli.CreateEdgeStack(name, file, utils.IntToInt64Slice(environmentGroupIds))
+func (c *PortainerClient) CreateStack(ctx context.Context, name, file string, environmentGroupIds []int) (int, error) {
+	id, err := c.cli.CreateEdgeStack(ctx, name, file, utils.IntToInt64Slice(environmentGroupIds))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create edge stack: %w", err)
 	}
@@ -98,8 +99,8 @@ func (c *PortainerClient) CreateStack(name, file string, environmentGroupIds []i
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateStack(id int, file string, environmentGroupIds []int) error {
-	err := c.cli.UpdateEdgeStack(int64(id), file, utils.IntToInt64Slice(environmentGroupIds))
+func (c *PortainerClient) UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error {
+	err := c.cli.UpdateEdgeStack(ctx, int64(id), file, utils.IntToInt64Slice(environmentGroupIds))
 	if err != nil {
 		return fmt.Errorf("failed to update edge stack: %w", err)
 	}
@@ -115,8 +116,8 @@ func (c *PortainerClient) UpdateStack(id int, file string, environmentGroupIds [
 // Returns:
 //   - A RegularStack object
 //   - An error if the operation fails
-func (c *PortainerClient) InspectStack(id int) (models.RegularStack, error) {
-	raw, err := c.cli.StackInspect(int64(id))
+func (c *PortainerClient) InspectStack(ctx context.Context, id int) (models.RegularStack, error) {
+	raw, err := c.cli.StackInspect(ctx, int64(id))
 	if err != nil {
 		return models.RegularStack{}, fmt.Errorf("failed to inspect stack: %w", err)
 	}
@@ -133,8 +134,8 @@ func (c *PortainerClient) InspectStack(id int) (models.RegularStack, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteStack(id int, endpointID int, removeVolumes bool) error {
-	err := c.cli.StackDelete(int64(id), int64(endpointID), removeVolumes)
+func (c *PortainerClient) DeleteStack(ctx context.Context, id int, endpointID int, removeVolumes bool) error {
+	err := c.cli.StackDelete(ctx, int64(id), int64(endpointID), removeVolumes)
 	if err != nil {
 		return fmt.Errorf("failed to delete stack: %w", err)
 	}
@@ -150,8 +151,8 @@ func (c *PortainerClient) DeleteStack(id int, endpointID int, removeVolumes bool
 // Returns:
 //   - The compose file content
 //   - An error if the operation fails
-func (c *PortainerClient) InspectStackFile(id int) (string, error) {
-	content, err := c.cli.StackFileInspect(int64(id))
+func (c *PortainerClient) InspectStackFile(ctx context.Context, id int) (string, error) {
+	content, err := c.cli.StackFileInspect(ctx, int64(id))
 	if err != nil {
 		return "", fmt.Errorf("failed to inspect stack file: %w", err)
 	}
@@ -170,13 +171,13 @@ func (c *PortainerClient) InspectStackFile(id int) (string, error) {
 // Returns:
 //   - The updated RegularStack
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateStackGit(id int, endpointID int, referenceName string, prune bool) (models.RegularStack, error) {
+func (c *PortainerClient) UpdateStackGit(ctx context.Context, id int, endpointID int, referenceName string, prune bool) (models.RegularStack, error) {
 	body := &apimodels.StacksStackGitUpdatePayload{
 		RepositoryReferenceName: referenceName,
 		Prune:                   prune,
 	}
 
-	raw, err := c.cli.StackUpdateGit(int64(id), int64(endpointID), body)
+	raw, err := c.cli.StackUpdateGit(ctx, int64(id), int64(endpointID), body)
 	if err != nil {
 		return models.RegularStack{}, fmt.Errorf("failed to update stack git: %w", err)
 	}
@@ -195,13 +196,13 @@ func (c *PortainerClient) UpdateStackGit(id int, endpointID int, referenceName s
 // Returns:
 //   - The redeployed RegularStack
 //   - An error if the operation fails
-func (c *PortainerClient) RedeployStackGit(id int, endpointID int, pullImage bool, prune bool) (models.RegularStack, error) {
+func (c *PortainerClient) RedeployStackGit(ctx context.Context, id int, endpointID int, pullImage bool, prune bool) (models.RegularStack, error) {
 	body := &apimodels.StacksStackGitRedployPayload{
 		PullImage: pullImage,
 		Prune:     prune,
 	}
 
-	raw, err := c.cli.StackGitRedeploy(int64(id), int64(endpointID), body)
+	raw, err := c.cli.StackGitRedeploy(ctx, int64(id), int64(endpointID), body)
 	if err != nil {
 		return models.RegularStack{}, fmt.Errorf("failed to redeploy stack: %w", err)
 	}
@@ -218,8 +219,8 @@ func (c *PortainerClient) RedeployStackGit(id int, endpointID int, pullImage boo
 // Returns:
 //   - The started RegularStack
 //   - An error if the operation fails
-func (c *PortainerClient) StartStack(id int, endpointID int) (models.RegularStack, error) {
-	raw, err := c.cli.StackStart(int64(id), int64(endpointID))
+func (c *PortainerClient) StartStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error) {
+	raw, err := c.cli.StackStart(ctx, int64(id), int64(endpointID))
 	if err != nil {
 		return models.RegularStack{}, fmt.Errorf("failed to start stack: %w", err)
 	}
@@ -236,8 +237,8 @@ func (c *PortainerClient) StartStack(id int, endpointID int) (models.RegularStac
 // Returns:
 //   - The stopped RegularStack
 //   - An error if the operation fails
-func (c *PortainerClient) StopStack(id int, endpointID int) (models.RegularStack, error) {
-	raw, err := c.cli.StackStop(int64(id), int64(endpointID))
+func (c *PortainerClient) StopStack(ctx context.Context, id int, endpointID int) (models.RegularStack, error) {
+	raw, err := c.cli.StackStop(ctx, int64(id), int64(endpointID))
 	if err != nil {
 		return models.RegularStack{}, fmt.Errorf("failed to stop stack: %w", err)
 	}
@@ -256,14 +257,14 @@ func (c *PortainerClient) StopStack(id int, endpointID int) (models.RegularStack
 // Returns:
 //   - The migrated RegularStack
 //   - An error if the operation fails
-func (c *PortainerClient) MigrateStack(id int, endpointID int, targetEndpointID int, name string) (models.RegularStack, error) {
+func (c *PortainerClient) MigrateStack(ctx context.Context, id int, endpointID int, targetEndpointID int, name string) (models.RegularStack, error) {
 	targetID := int64(targetEndpointID)
 	body := &apimodels.StacksStackMigratePayload{
 		EndpointID: &targetID,
 		Name:       name,
 	}
 
-	raw, err := c.cli.StackMigrate(int64(id), int64(endpointID), body)
+	raw, err := c.cli.StackMigrate(ctx, int64(id), int64(endpointID), body)
 	if err != nil {
 		return models.RegularStack{}, fmt.Errorf("failed to migrate stack: %w", err)
 	}
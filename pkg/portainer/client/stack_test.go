@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -72,7 +73,7 @@ func TestGetStacks(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			stacks, err := client.GetStacks()
+			stacks, err := client.GetStacks(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -116,7 +117,7 @@ func TestGetStackFile(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			file, err := client.GetStackFile(tt.stackID)
+			file, err := client.GetStackFile(context.Background(), tt.stackID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -166,7 +167,7 @@ func TestCreateStack(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateStack(tt.stackName, tt.stackFile, tt.environmentGroupIds)
+			id, err := client.CreateStack(context.Background(), tt.stackName, tt.stackFile, tt.environmentGroupIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -212,7 +213,7 @@ func TestUpdateStack(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateStack(tt.stackID, tt.stackFile, tt.environmentGroupIds)
+			err := client.UpdateStack(context.Background(), tt.stackID, tt.stackFile, tt.environmentGroupIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -260,7 +261,7 @@ func TestGetRegularStacks(t *testing.T) {
 			mockAPI.On("ListRegularStacks").Return(tt.mockStacks, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetRegularStacks()
+			result, err := c.GetRegularStacks(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -303,7 +304,7 @@ func TestInspectStack(t *testing.T) {
 			mockAPI.On("StackInspect", int64(tt.id)).Return(tt.mockStack, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.InspectStack(tt.id)
+			result, err := c.InspectStack(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -352,7 +353,7 @@ func TestDeleteStack(t *testing.T) {
 			mockAPI.On("StackDelete", int64(tt.id), int64(tt.endpointID), tt.removeVolumes).Return(tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			err := c.DeleteStack(tt.id, tt.endpointID, tt.removeVolumes)
+			err := c.DeleteStack(context.Background(), tt.id, tt.endpointID, tt.removeVolumes)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -392,7 +393,7 @@ func TestInspectStackFile(t *testing.T) {
 			mockAPI.On("StackFileInspect", int64(tt.id)).Return(tt.mockContent, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.InspectStackFile(tt.id)
+			result, err := c.InspectStackFile(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -443,7 +444,7 @@ func TestUpdateStackGit(t *testing.T) {
 			mockAPI.On("StackUpdateGit", int64(tt.id), int64(tt.endpointID), mock.AnythingOfType("*models.StacksStackGitUpdatePayload")).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.UpdateStackGit(tt.id, tt.endpointID, tt.referenceName, tt.prune)
+			result, err := c.UpdateStackGit(context.Background(), tt.id, tt.endpointID, tt.referenceName, tt.prune)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -492,7 +493,7 @@ func TestRedeployStackGit(t *testing.T) {
 			mockAPI.On("StackGitRedeploy", int64(tt.id), int64(tt.endpointID), mock.AnythingOfType("*models.StacksStackGitRedployPayload")).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.RedeployStackGit(tt.id, tt.endpointID, tt.pullImage, tt.prune)
+			result, err := c.RedeployStackGit(context.Background(), tt.id, tt.endpointID, tt.pullImage, tt.prune)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -537,7 +538,7 @@ func TestStartStack(t *testing.T) {
 			mockAPI.On("StackStart", int64(tt.id), int64(tt.endpointID)).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.StartStack(tt.id, tt.endpointID)
+			result, err := c.StartStack(context.Background(), tt.id, tt.endpointID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -582,7 +583,7 @@ func TestStopStack(t *testing.T) {
 			mockAPI.On("StackStop", int64(tt.id), int64(tt.endpointID)).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.StopStack(tt.id, tt.endpointID)
+			result, err := c.StopStack(context.Background(), tt.id, tt.endpointID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -639,7 +640,7 @@ func TestMigrateStack(t *testing.T) {
 			mockAPI.On("StackMigrate", int64(tt.id), int64(tt.endpointID), mock.AnythingOfType("*models.StacksStackMigratePayload")).Return(tt.mockResult, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.MigrateStack(tt.id, tt.endpointID, tt.targetEndpointID, tt.stackName)
+			result, err := c.MigrateStack(context.Background(), tt.id, tt.endpointID, tt.targetEndpointID, tt.stackName)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -11,8 +12,8 @@ import (
 // Returns:
 //   - A SystemStatus object containing version and instance ID
 //   - An error if the operation fails
-func (c *PortainerClient) GetSystemStatus() (models.SystemStatus, error) {
-	rawStatus, err := c.cli.GetSystemStatus()
+func (c *PortainerClient) GetSystemStatus(ctx context.Context) (models.SystemStatus, error) {
+	rawStatus, err := c.cli.GetSystemStatus(ctx)
 	if err != nil {
 		return models.SystemStatus{}, fmt.Errorf("failed to get system status: %w", err)
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -43,7 +44,7 @@ func TestGetSystemStatus(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			status, err := client.GetSystemStatus()
+			status, err := client.GetSystemStatus(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -12,8 +13,8 @@ import (
 // Returns:
 //   - A slice of EnvironmentTag objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetEnvironmentTags() ([]models.EnvironmentTag, error) {
-	tags, err := c.cli.ListTags()
+func (c *PortainerClient) GetEnvironmentTags(ctx context.Context) ([]models.EnvironmentTag, error) {
+	tags, err := c.cli.ListTags(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list environment tags: %w", err)
 	}
@@ -35,8 +36,8 @@ func (c *PortainerClient) GetEnvironmentTags() ([]models.EnvironmentTag, error)
 // Returns:
 //   - The ID of the created environment tag
 //   - An error if the operation fails
-func (c *PortainerClient) CreateEnvironmentTag(name string) (int, error) {
-	id, err := c.cli.CreateTag(name)
+func (c *PortainerClient) CreateEnvironmentTag(ctx context.Context, name string) (int, error) {
+	id, err := c.cli.CreateTag(ctx, name)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create environment tag: %w", err)
 	}
@@ -51,8 +52,8 @@ func (c *PortainerClient) CreateEnvironmentTag(name string) (int, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteEnvironmentTag(id int) error {
-	err := c.cli.DeleteTag(int64(id))
+func (c *PortainerClient) DeleteEnvironmentTag(ctx context.Context, id int) error {
+	err := c.cli.DeleteTag(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete environment tag: %w", err)
 	}
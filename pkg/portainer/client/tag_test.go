@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -57,7 +58,7 @@ func TestGetEnvironmentTags(t *testing.T) {
 				cli: mockAPI,
 			}
 
-			tags, err := client.GetEnvironmentTags()
+			tags, err := client.GetEnvironmentTags(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -108,7 +109,7 @@ func TestCreateEnvironmentTag(t *testing.T) {
 				cli: mockAPI,
 			}
 
-			id, err := client.CreateEnvironmentTag(tt.tagName)
+			id, err := client.CreateEnvironmentTag(context.Background(), tt.tagName)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -149,7 +150,7 @@ func TestDeleteEnvironmentTag(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.DeleteEnvironmentTag(tt.tagID)
+			err := client.DeleteEnvironmentTag(context.Background(), tt.tagID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -11,14 +12,14 @@ import (
 // Returns:
 //   - A slice of Team objects containing team information
 //   - An error if the operation fails
-func (c *PortainerClient) GetTeams() ([]models.Team, error) {
-	portainerTeams, err := c.cli.ListTeams()
+func (c *PortainerClient) GetTeams(ctx context.Context) ([]models.Team, error) {
+	portainerTeams, err := c.cli.ListTeams(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list teams: %w", err)
 	}
 
 	// Get team memberships to populate team members
-	memberships, err := c.cli.ListTeamMemberships()
+	memberships, err := c.cli.ListTeamMemberships(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list team memberships: %w", err)
 	}
@@ -39,13 +40,13 @@ func (c *PortainerClient) GetTeams() ([]models.Team, error) {
 // Returns:
 //   - A Team object containing team information
 //   - An error if the operation fails
-func (c *PortainerClient) GetTeam(id int) (models.Team, error) {
-	portainerTeam, err := c.cli.GetTeam(int64(id))
+func (c *PortainerClient) GetTeam(ctx context.Context, id int) (models.Team, error) {
+	portainerTeam, err := c.cli.GetTeam(ctx, int64(id))
 	if err != nil {
 		return models.Team{}, fmt.Errorf("failed to get team: %w", err)
 	}
 
-	memberships, err := c.cli.ListTeamMemberships()
+	memberships, err := c.cli.ListTeamMemberships(ctx)
 	if err != nil {
 		return models.Team{}, fmt.Errorf("failed to list team memberships: %w", err)
 	}
@@ -60,8 +61,8 @@ func (c *PortainerClient) GetTeam(id int) (models.Team, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteTeam(id int) error {
-	err := c.cli.DeleteTeam(int64(id))
+func (c *PortainerClient) DeleteTeam(ctx context.Context, id int) error {
+	err := c.cli.DeleteTeam(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete team: %w", err)
 	}
@@ -74,8 +75,8 @@ func (c *PortainerClient) DeleteTeam(id int) error {
 // Parameters:
 //   - id: The ID of the team to update
 //   - name: The new name for the team
-func (c *PortainerClient) UpdateTeamName(id int, name string) error {
-	return c.cli.UpdateTeamName(id, name)
+func (c *PortainerClient) UpdateTeamName(ctx context.Context, id int, name string) error {
+	return c.cli.UpdateTeamName(ctx, id, name)
 }
 
 // CreateTeam creates a new team.
@@ -86,8 +87,8 @@ func (c *PortainerClient) UpdateTeamName(id int, name string) error {
 // Returns:
 //   - The ID of the created team
 //   - An error if the operation fails
-func (c *PortainerClient) CreateTeam(name string) (int, error) {
-	id, err := c.cli.CreateTeam(name)
+func (c *PortainerClient) CreateTeam(ctx context.Context, name string) (int, error) {
+	id, err := c.cli.CreateTeam(ctx, name)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create team: %w", err)
 	}
@@ -100,8 +101,8 @@ func (c *PortainerClient) CreateTeam(name string) (int, error) {
 // Parameters:
 //   - teamId: The ID of the team to update
 //   - userIds: The IDs of the users associated with the team
-func (c *PortainerClient) UpdateTeamMembers(teamId int, userIds []int) error {
-	memberships, err := c.cli.ListTeamMemberships()
+func (c *PortainerClient) UpdateTeamMembers(ctx context.Context, teamId int, userIds []int) error {
+	memberships, err := c.cli.ListTeamMemberships(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list team memberships: %w", err)
 	}
@@ -126,7 +127,7 @@ func (c *PortainerClient) UpdateTeamMembers(teamId int, userIds []int) error {
 
 			// If user should not remain in the team, delete the membership
 			if !shouldKeep {
-				if err := c.cli.DeleteTeamMembership(int(membership.ID)); err != nil {
+				if err := c.cli.DeleteTeamMembership(ctx, int(membership.ID)); err != nil {
 					return fmt.Errorf("failed to delete team membership for user %d: %w", userID, err)
 				}
 			}
@@ -141,7 +142,7 @@ func (c *PortainerClient) UpdateTeamMembers(teamId int, userIds []int) error {
 		}
 
 		// Create new membership for this user
-		if err := c.cli.CreateTeamMembership(teamId, userID); err != nil {
+		if err := c.cli.CreateTeamMembership(ctx, teamId, userID); err != nil {
 			return fmt.Errorf("failed to create team membership for user %d: %w", userID, err)
 		}
 	}
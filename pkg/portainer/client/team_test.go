@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -120,7 +121,7 @@ func TestGetTeams(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			teams, err := client.GetTeams()
+			teams, err := client.GetTeams(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -163,7 +164,7 @@ func TestUpdateTeamName(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateTeamName(tt.teamID, tt.teamName)
+			err := client.UpdateTeamName(context.Background(), tt.teamID, tt.teamName)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -206,7 +207,7 @@ func TestCreateTeam(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateTeam(tt.teamName)
+			id, err := client.CreateTeam(context.Background(), tt.teamName)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -331,7 +332,7 @@ func TestUpdateTeamMembers(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateTeamMembers(tt.teamID, tt.userIDs)
+			err := client.UpdateTeamMembers(context.Background(), tt.teamID, tt.userIDs)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -401,7 +402,7 @@ func TestGetTeam(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			team, err := client.GetTeam(tt.teamID)
+			team, err := client.GetTeam(context.Background(), tt.teamID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -441,7 +442,7 @@ func TestDeleteTeam(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.DeleteTeam(tt.teamID)
+			err := client.DeleteTeam(context.Background(), tt.teamID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
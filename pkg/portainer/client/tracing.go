@@ -0,0 +1,42 @@
+package client
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// proxyPathPattern matches the prefix of the paths of Docker and Kubernetes
+// proxy requests, such as /api/endpoints/1/docker.
+var proxyPathPattern = regexp.MustCompile(`^/api/endpoints/\d+/(docker|kubernetes)(/|$)`)
+
+// newTracingTransport wraps next so that every request sent to Portainer is
+// recorded as a client span, a child of the span in the request context.
+func newTracingTransport(next http.RoundTripper, tp trace.TracerProvider) http.RoundTripper {
+	return otelhttp.NewTransport(next,
+		otelhttp.WithTracerProvider(tp),
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return req.Method + " " + spanRoute(req.URL.Path)
+		}),
+	)
+}
+
+// spanRoute returns the path of a request with its numeric IDs replaced by
+// {id}, and the Docker or Kubernetes API path of a proxy request replaced by
+// *, so that span names stay few. The full URL is kept as a span attribute.
+func spanRoute(path string) string {
+	if match := proxyPathPattern.FindStringSubmatch(path); match != nil {
+		return "/api/endpoints/{id}/" + match[1] + "/*"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && strings.Trim(segment, "0123456789") == "" {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
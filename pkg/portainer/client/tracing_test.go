@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	sdkclient "github.com/portainer/client-api-go/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestSpanRoute verifies that span names do not contain IDs or proxied paths.
+func TestSpanRoute(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/api/tags", "/api/tags"},
+		{"/api/teams/12", "/api/teams/{id}"},
+		{"/api/endpoints/3/snapshot", "/api/endpoints/{id}/snapshot"},
+		{"/api/users/admin/tokens", "/api/users/admin/tokens"},
+		{"/api/endpoints/1/docker/containers/abc123/json", "/api/endpoints/{id}/docker/*"},
+		{"/api/endpoints/1/kubernetes", "/api/endpoints/{id}/kubernetes/*"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, spanRoute(tt.path), tt.path)
+	}
+}
+
+// TestAdapterTracing verifies that Portainer API and proxy requests are
+// recorded as children of the span in the request context.
+func TestAdapterTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	rt := &mockRoundTripper{statusCode: 200, body: `[]`}
+	a := newPortainerAPIAdapter("http://portainer.local", "secret", clientOptions{
+		wrapTransport:  func(http.RoundTripper) http.RoundTripper { return rt },
+		tracerProvider: tp,
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "tools/call manage_teams")
+	_, err := a.ListTags(ctx)
+	require.NoError(t, err)
+	resp, err := a.ProxyDockerRequest(ctx, 1, sdkclient.ProxyRequestOptions{Method: "GET", APIPath: "/containers/json"})
+	require.NoError(t, err)
+	// The span of a proxy request ends when its body is closed.
+	require.NoError(t, resp.Body.Close())
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "GET /api/tags", spans[0].Name())
+	assert.Equal(t, "GET /api/endpoints/{id}/docker/*", spans[1].Name())
+	for _, span := range spans[:2] {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+}
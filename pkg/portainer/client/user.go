@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -11,8 +12,8 @@ import (
 // Returns:
 //   - A slice of User objects containing user information
 //   - An error if the operation fails
-func (c *PortainerClient) GetUsers() ([]models.User, error) {
-	portainerUsers, err := c.cli.ListUsers()
+func (c *PortainerClient) GetUsers(ctx context.Context) ([]models.User, error) {
+	portainerUsers, err := c.cli.ListUsers(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -35,13 +36,13 @@ func (c *PortainerClient) GetUsers() ([]models.User, error) {
 // Returns:
 //   - The ID of the created user
 //   - An error if the operation fails
-func (c *PortainerClient) CreateUser(username, password, role string) (int, error) {
+func (c *PortainerClient) CreateUser(ctx context.Context, username, password, role string) (int, error) {
 	roleInt := convertRole(role)
 	if roleInt == 0 {
 		return 0, fmt.Errorf("invalid role: must be admin, user or edge_admin")
 	}
 
-	id, err := c.cli.CreateUser(username, password, roleInt)
+	id, err := c.cli.CreateUser(ctx, username, password, roleInt)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -57,8 +58,8 @@ func (c *PortainerClient) CreateUser(username, password, role string) (int, erro
 // Returns:
 //   - A User object containing user information
 //   - An error if the operation fails
-func (c *PortainerClient) GetUser(id int) (models.User, error) {
-	portainerUser, err := c.cli.GetUser(id)
+func (c *PortainerClient) GetUser(ctx context.Context, id int) (models.User, error) {
+	portainerUser, err := c.cli.GetUser(ctx, id)
 	if err != nil {
 		return models.User{}, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -73,8 +74,8 @@ func (c *PortainerClient) GetUser(id int) (models.User, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteUser(id int) error {
-	err := c.cli.DeleteUser(int64(id))
+func (c *PortainerClient) DeleteUser(ctx context.Context, id int) error {
+	err := c.cli.DeleteUser(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -90,13 +91,13 @@ func (c *PortainerClient) DeleteUser(id int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateUserRole(id int, role string) error {
+func (c *PortainerClient) UpdateUserRole(ctx context.Context, id int, role string) error {
 	roleInt := convertRole(role)
 	if roleInt == 0 {
 		return fmt.Errorf("invalid role: must be admin, user or edge_admin")
 	}
 
-	return c.cli.UpdateUserRole(id, roleInt)
+	return c.cli.UpdateUserRole(ctx, id, roleInt)
 }
 
 // convertRole convert role.
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -84,7 +85,7 @@ func TestGetUsers(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			users, err := client.GetUsers()
+			users, err := client.GetUsers(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -150,7 +151,7 @@ func TestUpdateUserRole(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateUserRole(tt.userID, tt.role)
+			err := client.UpdateUserRole(context.Background(), tt.userID, tt.role)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -220,7 +221,7 @@ func TestCreateUser(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateUser(tt.username, tt.password, tt.role)
+			id, err := client.CreateUser(context.Background(), tt.username, tt.password, tt.role)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -272,7 +273,7 @@ func TestGetUser(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			user, err := client.GetUser(tt.userID)
+			user, err := client.GetUser(context.Background(), tt.userID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -312,7 +313,7 @@ func TestDeleteUser(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.DeleteUser(tt.userID)
+			err := client.DeleteUser(context.Background(), tt.userID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,10 +1,13 @@
 package client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // GetVersion retrieves version.
-func (c *PortainerClient) GetVersion() (string, error) {
-	version, err := c.cli.GetVersion()
+func (c *PortainerClient) GetVersion(ctx context.Context) (string, error) {
+	version, err := c.cli.GetVersion(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get version: %w", err)
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -41,7 +42,7 @@ func TestGetVersion(t *testing.T) {
 				cli: mockAPI,
 			}
 
-			version, err := client.GetVersion()
+			version, err := client.GetVersion(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
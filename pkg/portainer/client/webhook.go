@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmrplens/portainer-mcp-enhanced/pkg/portainer/models"
@@ -11,8 +12,8 @@ import (
 // Returns:
 //   - A slice of Webhook objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetWebhooks() ([]models.Webhook, error) {
-	rawWebhooks, err := c.cli.ListWebhooks()
+func (c *PortainerClient) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	rawWebhooks, err := c.cli.ListWebhooks(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list webhooks: %w", err)
 	}
@@ -35,8 +36,8 @@ func (c *PortainerClient) GetWebhooks() ([]models.Webhook, error) {
 // Returns:
 //   - The ID of the created webhook
 //   - An error if the operation fails
-func (c *PortainerClient) CreateWebhook(resourceId string, endpointId int, webhookType int) (int, error) {
-	id, err := c.cli.CreateWebhook(resourceId, int64(endpointId), int64(webhookType))
+func (c *PortainerClient) CreateWebhook(ctx context.Context, resourceId string, endpointId int, webhookType int) (int, error) {
+	id, err := c.cli.CreateWebhook(ctx, resourceId, int64(endpointId), int64(webhookType))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create webhook: %w", err)
 	}
@@ -51,8 +52,8 @@ func (c *PortainerClient) CreateWebhook(resourceId string, endpointId int, webho
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteWebhook(id int) error {
-	err := c.cli.DeleteWebhook(int64(id))
+func (c *PortainerClient) DeleteWebhook(ctx context.Context, id int) error {
+	err := c.cli.DeleteWebhook(ctx, int64(id))
 	if err != nil {
 		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
@@ -3,6 +3,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -45,7 +46,7 @@ func TestGetWebhooks(t *testing.T) {
 			mockAPI.On("ListWebhooks").Return(tt.mockWebhooks, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			result, err := c.GetWebhooks()
+			result, err := c.GetWebhooks(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -95,7 +96,7 @@ func TestCreateWebhook(t *testing.T) {
 			mockAPI.On("CreateWebhook", tt.resourceId, int64(tt.endpointId), int64(tt.webhookType)).Return(tt.mockId, tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			id, err := c.CreateWebhook(tt.resourceId, tt.endpointId, tt.webhookType)
+			id, err := c.CreateWebhook(context.Background(), tt.resourceId, tt.endpointId, tt.webhookType)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -135,7 +136,7 @@ func TestDeleteWebhook(t *testing.T) {
 			mockAPI.On("DeleteWebhook", int64(tt.id)).Return(tt.mockError)
 
 			c := &PortainerClient{cli: mockAPI}
-			err := c.DeleteWebhook(tt.id)
+			err := c.DeleteWebhook(context.Background(), tt.id)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -0,0 +1,275 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DockerContainer is a summary of a Docker container, as returned when listing containers.
+type DockerContainer struct {
+	ID      string                `json:"id"`
+	Name    string                `json:"name"`
+	Image   string                `json:"image"`
+	Command string                `json:"command,omitempty"`
+	State   string                `json:"state"`
+	Status  string                `json:"status"`
+	Created string                `json:"created_at,omitempty"`
+	Ports   []DockerContainerPort `json:"ports,omitempty"`
+	Labels  map[string]string     `json:"labels,omitempty"`
+	Stack   string                `json:"stack,omitempty"`
+}
+
+// DockerContainerPort is a port published or exposed by a Docker container.
+type DockerContainerPort struct {
+	IP          string `json:"ip,omitempty"`
+	PrivatePort int    `json:"private_port"`
+	PublicPort  int    `json:"public_port,omitempty"`
+	Type        string `json:"type"`
+}
+
+// DockerContainerDetails is the detailed state and configuration of a single Docker container.
+type DockerContainerDetails struct {
+	ID            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	Image         string                 `json:"image"`
+	ImageID       string                 `json:"image_id"`
+	Command       []string               `json:"command,omitempty"`
+	Entrypoint    []string               `json:"entrypoint,omitempty"`
+	WorkingDir    string                 `json:"working_dir,omitempty"`
+	User          string                 `json:"user,omitempty"`
+	Env           []string               `json:"env,omitempty"`
+	Labels        map[string]string      `json:"labels,omitempty"`
+	Created       string                 `json:"created_at,omitempty"`
+	State         DockerContainerState   `json:"state"`
+	RestartPolicy string                 `json:"restart_policy,omitempty"`
+	RestartCount  int                    `json:"restart_count"`
+	Networks      []string               `json:"networks,omitempty"`
+	Mounts        []DockerContainerMount `json:"mounts,omitempty"`
+}
+
+// DockerContainerState is the runtime state of a Docker container.
+type DockerContainerState struct {
+	Status     string `json:"status"`
+	Running    bool   `json:"running"`
+	Paused     bool   `json:"paused"`
+	Restarting bool   `json:"restarting"`
+	OOMKilled  bool   `json:"oom_killed"`
+	Dead       bool   `json:"dead"`
+	Pid        int    `json:"pid,omitempty"`
+	ExitCode   int    `json:"exit_code"`
+	Error      string `json:"error,omitempty"`
+	StartedAt  string `json:"started_at,omitempty"`
+	FinishedAt string `json:"finished_at,omitempty"`
+	Health     string `json:"health,omitempty"`
+}
+
+// DockerContainerMount is a volume or bind mount attached to a Docker container.
+type DockerContainerMount struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination"`
+	ReadWrite   bool   `json:"read_write"`
+}
+
+// DockerContainerListOptions holds the filters used when listing Docker containers.
+type DockerContainerListOptions struct {
+	// All includes stopped containers; by default only running containers are listed.
+	All bool
+	// Filters are Docker Engine container filters (e.g. "status": ["exited"], "label": ["app=web"]).
+	Filters map[string][]string
+}
+
+// DockerContainerLogOptions holds the options used when retrieving Docker container logs.
+type DockerContainerLogOptions struct {
+	// Tail is the number of lines to return from the end of the logs; 0 returns all lines.
+	Tail int
+	// Since only returns logs after this UNIX timestamp in seconds; 0 means no lower bound.
+	Since int64
+	// Until only returns logs before this UNIX timestamp in seconds; 0 means no upper bound.
+	Until int64
+	// Timestamps prefixes every line with its RFC3339Nano timestamp.
+	Timestamps bool
+}
+
+// DockerLogLine is a single line of container output together with the stream it was written to.
+type DockerLogLine struct {
+	Stream string `json:"stream"`
+	Text   string `json:"text"`
+}
+
+// DockerContainerLogs is the decoded log output of a Docker container.
+type DockerContainerLogs struct {
+	Lines []DockerLogLine `json:"lines"`
+	// Truncated is set when the log output exceeded the maximum size kept from
+	// the Docker API, and its oldest lines were dropped.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// composeProjectLabel is the label Docker Compose sets to the project (stack) name.
+const composeProjectLabel = "com.docker.compose.project"
+
+// RawDockerContainerSummary is an entry of the Docker Engine API GET /containers/json response.
+type RawDockerContainerSummary struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	Command string            `json:"Command"`
+	Created int64             `json:"Created"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []struct {
+		IP          string `json:"IP"`
+		PrivatePort int    `json:"PrivatePort"`
+		PublicPort  int    `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+}
+
+// RawDockerContainerInspect is the subset of the Docker Engine API GET /containers/{id}/json
+// response used by this server.
+type RawDockerContainerInspect struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	Image        string `json:"Image"`
+	Created      string `json:"Created"`
+	RestartCount int    `json:"RestartCount"`
+	State        *struct {
+		Status     string `json:"Status"`
+		Running    bool   `json:"Running"`
+		Paused     bool   `json:"Paused"`
+		Restarting bool   `json:"Restarting"`
+		OOMKilled  bool   `json:"OOMKilled"`
+		Dead       bool   `json:"Dead"`
+		Pid        int    `json:"Pid"`
+		ExitCode   int    `json:"ExitCode"`
+		Error      string `json:"Error"`
+		StartedAt  string `json:"StartedAt"`
+		FinishedAt string `json:"FinishedAt"`
+		Health     *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+	Config *struct {
+		Image      string            `json:"Image"`
+		Cmd        []string          `json:"Cmd"`
+		Entrypoint []string          `json:"Entrypoint"`
+		WorkingDir string            `json:"WorkingDir"`
+		User       string            `json:"User"`
+		Env        []string          `json:"Env"`
+		Labels     map[string]string `json:"Labels"`
+	} `json:"Config"`
+	HostConfig *struct {
+		RestartPolicy struct {
+			Name string `json:"Name"`
+		} `json:"RestartPolicy"`
+	} `json:"HostConfig"`
+	NetworkSettings *struct {
+		Networks map[string]any `json:"Networks"`
+	} `json:"NetworkSettings"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Name        string `json:"Name"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+		RW          bool   `json:"RW"`
+	} `json:"Mounts"`
+}
+
+// ConvertDockerContainer converts a raw Docker container summary to a local DockerContainer model.
+func ConvertDockerContainer(raw RawDockerContainerSummary) DockerContainer {
+	container := DockerContainer{
+		ID:      raw.ID,
+		Image:   raw.Image,
+		Command: raw.Command,
+		State:   raw.State,
+		Status:  raw.Status,
+		Labels:  raw.Labels,
+		Stack:   raw.Labels[composeProjectLabel],
+	}
+
+	if len(raw.Names) > 0 {
+		container.Name = strings.TrimPrefix(raw.Names[0], "/")
+	}
+
+	if raw.Created > 0 {
+		container.Created = time.Unix(raw.Created, 0).UTC().Format(time.RFC3339)
+	}
+
+	for _, p := range raw.Ports {
+		container.Ports = append(container.Ports, DockerContainerPort{
+			IP:          p.IP,
+			PrivatePort: p.PrivatePort,
+			PublicPort:  p.PublicPort,
+			Type:        p.Type,
+		})
+	}
+
+	return container
+}
+
+// ConvertDockerContainerDetails converts a raw Docker container inspect response to a
+// local DockerContainerDetails model.
+func ConvertDockerContainerDetails(raw RawDockerContainerInspect) DockerContainerDetails {
+	details := DockerContainerDetails{
+		ID:           raw.ID,
+		Name:         strings.TrimPrefix(raw.Name, "/"),
+		ImageID:      raw.Image,
+		Created:      raw.Created,
+		RestartCount: raw.RestartCount,
+	}
+
+	if raw.Config != nil {
+		details.Image = raw.Config.Image
+		details.Command = raw.Config.Cmd
+		details.Entrypoint = raw.Config.Entrypoint
+		details.WorkingDir = raw.Config.WorkingDir
+		details.User = raw.Config.User
+		details.Env = raw.Config.Env
+		details.Labels = raw.Config.Labels
+	}
+
+	if raw.State != nil {
+		details.State = DockerContainerState{
+			Status:     raw.State.Status,
+			Running:    raw.State.Running,
+			Paused:     raw.State.Paused,
+			Restarting: raw.State.Restarting,
+			OOMKilled:  raw.State.OOMKilled,
+			Dead:       raw.State.Dead,
+			Pid:        raw.State.Pid,
+			ExitCode:   raw.State.ExitCode,
+			Error:      raw.State.Error,
+			StartedAt:  raw.State.StartedAt,
+			FinishedAt: raw.State.FinishedAt,
+		}
+		if raw.State.Health != nil {
+			details.State.Health = raw.State.Health.Status
+		}
+	}
+
+	if raw.HostConfig != nil {
+		details.RestartPolicy = raw.HostConfig.RestartPolicy.Name
+	}
+
+	if raw.NetworkSettings != nil {
+		for name := range raw.NetworkSettings.Networks {
+			details.Networks = append(details.Networks, name)
+		}
+		sort.Strings(details.Networks)
+	}
+
+	for _, m := range raw.Mounts {
+		details.Mounts = append(details.Mounts, DockerContainerMount{
+			Type:        m.Type,
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			ReadWrite:   m.RW,
+		})
+	}
+
+	return details
+}
@@ -0,0 +1,20 @@
+package models
+
+// ExecOptions holds a one-shot command to run inside a container.
+type ExecOptions struct {
+	// Command is the program and its arguments; it is not run through a shell.
+	Command []string
+	// WorkingDir is the directory the command runs in; empty uses the container default.
+	WorkingDir string
+	// User is the user (name or UID[:GID]) the command runs as; empty uses the container default.
+	User string
+}
+
+// ExecResult is the output and exit code of a command run inside a container.
+type ExecResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+	// Truncated is set when the output exceeded the maximum size kept from the command.
+	Truncated bool `json:"truncated,omitempty"`
+}
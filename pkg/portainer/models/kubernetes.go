@@ -75,3 +75,30 @@ func ConvertK8sNamespace(raw *apimodels.PortainerK8sNamespaceInfo) KubernetesNam
 		IsSystem:       raw.IsSystem,
 	}
 }
+
+// KubernetesPodLogOptions holds the options used when retrieving Kubernetes pod logs.
+// Exactly one of Pod or LabelSelector selects the pods to read.
+type KubernetesPodLogOptions struct {
+	// Pod is the name of a single pod.
+	Pod string
+	// LabelSelector selects every matching pod in the namespace (e.g. "app=web").
+	LabelSelector string
+	// Container is the container to read; empty uses the pod's default container.
+	Container string
+	// Previous returns the logs of the previous terminated instance of the container.
+	Previous bool
+	// TailLines is the number of lines to return from the end of the logs; 0 returns all lines.
+	TailLines int
+	// SinceSeconds only returns logs newer than this many seconds; 0 means no limit.
+	SinceSeconds int
+}
+
+// KubernetesPodLogs is the log output of one pod container.
+type KubernetesPodLogs struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	Logs      string `json:"logs"`
+	Truncated bool   `json:"truncated,omitempty"`
+	// Error is set when the logs of this pod could not be retrieved during a label-selector fan-out.
+	Error string `json:"error,omitempty"`
+}
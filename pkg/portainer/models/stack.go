@@ -31,6 +31,12 @@ func ConvertEdgeStackToStack(rawEdgeStack *apimodels.PortainereeEdgeStack) Stack
 	}
 }
 
+// Regular stack status constants
+const (
+	RegularStackStatusActive   = 1
+	RegularStackStatusInactive = 2
+)
+
 // RegularStack represents a regular (non-edge) stack in Portainer
 type RegularStack struct {
 	ID             int    `json:"id"`
@@ -506,6 +506,73 @@ func TestGetInt(t *testing.T) {
 	}
 }
 
+// TestGetArrayOfStrings verifies get array of strings behavior.
+func TestGetArrayOfStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]any
+		param    string
+		required bool
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "valid array of strings",
+			args:     map[string]any{"command": []any{"cat", "/etc/resolv.conf"}},
+			param:    "command",
+			required: true,
+			want:     []string{"cat", "/etc/resolv.conf"},
+			wantErr:  false,
+		},
+		{
+			name:     "missing required param",
+			args:     map[string]any{},
+			param:    "command",
+			required: true,
+			want:     nil,
+			wantErr:  true,
+		},
+		{
+			name:     "missing optional param",
+			args:     map[string]any{},
+			param:    "command",
+			required: false,
+			want:     []string{},
+			wantErr:  false,
+		},
+		{
+			name:     "wrong type",
+			args:     map[string]any{"command": "env"},
+			param:    "command",
+			required: true,
+			want:     nil,
+			wantErr:  true,
+		},
+		{
+			name:     "non-string element",
+			args:     map[string]any{"command": []any{"sleep", float64(5)}},
+			param:    "command",
+			required: true,
+			want:     nil,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(tt.args)
+			got, err := p.GetArrayOfStrings(tt.param, tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetArrayOfStrings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetArrayOfStrings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestGetArrayOfIntegers verifies get array of integers behavior.
 func TestGetArrayOfIntegers(t *testing.T) {
 	tests := []struct {
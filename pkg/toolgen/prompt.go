@@ -0,0 +1,106 @@
+package toolgen
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// PromptsConfig represents the entire prompts YAML configuration
+type PromptsConfig struct {
+	Version string             `yaml:"version"`
+	Prompts []PromptDefinition `yaml:"prompts"`
+}
+
+// PromptDefinition represents a single prompt in the YAML config
+type PromptDefinition struct {
+	Name        string                     `yaml:"name"`
+	Description string                     `yaml:"description"`
+	Arguments   []PromptArgumentDefinition `yaml:"arguments"`
+	// Actions lists the meta-tool actions the prompt relies on, as
+	// "meta_tool.action" references.
+	Actions []string `yaml:"actions"`
+	// Template is the text/template source of the prompt message.
+	Template string `yaml:"template"`
+}
+
+// PromptArgumentDefinition represents a prompt argument in the YAML config
+type PromptArgumentDefinition struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+}
+
+// LoadPromptsFromYAML loads prompt definitions from a YAML file
+// Invalid definitions are skipped with a warning, as they are for tools
+func LoadPromptsFromYAML(filePath string, minimumVersion string) ([]PromptDefinition, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompts file: %w", err)
+	}
+
+	var config PromptsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse prompts YAML: %w", err)
+	}
+
+	if config.Version == "" {
+		return nil, fmt.Errorf("missing version in prompts.yaml")
+	}
+
+	if !semver.IsValid(config.Version) {
+		return nil, fmt.Errorf("invalid version in prompts.yaml: %s", config.Version)
+	}
+
+	if semver.Compare(config.Version, minimumVersion) < 0 {
+		return nil, fmt.Errorf("prompts.yaml version %s is below the minimum required version %s", config.Version, minimumVersion)
+	}
+
+	prompts := make([]PromptDefinition, 0, len(config.Prompts))
+	seen := make(map[string]bool, len(config.Prompts))
+	for _, def := range config.Prompts {
+		if err := validatePromptDefinition(def); err != nil {
+			log.Warn().Str("prompt", def.Name).Err(err).Msg("Skipping invalid prompt definition")
+			continue
+		}
+		if seen[def.Name] {
+			log.Warn().Str("prompt", def.Name).Msg("Skipping duplicate prompt definition")
+			continue
+		}
+		seen[def.Name] = true
+		prompts = append(prompts, def)
+	}
+
+	return prompts, nil
+}
+
+// validatePromptDefinition checks the required fields of a prompt definition
+func validatePromptDefinition(def PromptDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("prompt name is required")
+	}
+
+	if def.Description == "" {
+		return fmt.Errorf("prompt description is required for prompt '%s'", def.Name)
+	}
+
+	if def.Template == "" {
+		return fmt.Errorf("prompt template is required for prompt '%s'", def.Name)
+	}
+
+	arguments := make(map[string]bool, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		if arg.Name == "" {
+			return fmt.Errorf("argument name is required for prompt '%s'", def.Name)
+		}
+		if arguments[arg.Name] {
+			return fmt.Errorf("duplicate argument '%s' for prompt '%s'", arg.Name, def.Name)
+		}
+		arguments[arg.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package toolgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadPromptsFromYAML verifies the loading and validation of prompt definitions.
+func TestLoadPromptsFromYAML(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedNames []string
+		expectedError string
+	}{
+		{
+			name: "valid prompts",
+			content: `version: v1.0
+prompts:
+  - name: review_user_access
+    description: Review the access of a user
+    arguments:
+      - name: user
+        description: Username or ID
+        required: true
+    actions: [manage_users.get_user]
+    template: Review the access of {{.user}}.
+  - name: list_stacks
+    description: List the stacks
+    template: List the stacks.`,
+			expectedNames: []string{"review_user_access", "list_stacks"},
+		},
+		{
+			name: "invalid and duplicate prompts are skipped",
+			content: `version: v1.1
+prompts:
+  - name: no_description
+    template: Text.
+  - name: no_template
+    description: A prompt without template
+  - name: duplicate_argument
+    description: A prompt with a duplicate argument
+    arguments:
+      - name: user
+      - name: user
+    template: Text.
+  - name: valid
+    description: A valid prompt
+    template: Text.
+  - name: valid
+    description: The same prompt again
+    template: Other text.`,
+			expectedNames: []string{"valid"},
+		},
+		{
+			name:          "missing version",
+			content:       "prompts: []",
+			expectedError: "missing version in prompts.yaml",
+		},
+		{
+			name:          "invalid version",
+			content:       "version: one\nprompts: []",
+			expectedError: "invalid version in prompts.yaml: one",
+		},
+		{
+			name:          "version below minimum",
+			content:       "version: v0.9\nprompts: []",
+			expectedError: "prompts.yaml version v0.9 is below the minimum required version v1.0",
+		},
+		{
+			name:          "invalid YAML",
+			content:       "version: v1.0\nprompts: {",
+			expectedError: "failed to parse prompts YAML",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prompts.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			prompts, err := LoadPromptsFromYAML(path, "v1.0")
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, p := range prompts {
+				names = append(names, p.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+
+	_, err := LoadPromptsFromYAML(filepath.Join(t.TempDir(), "missing.yaml"), "v1.0")
+	assert.ErrorContains(t, err, "failed to read prompts file")
+}